@@ -0,0 +1,159 @@
+// Команда import-users загружает CSV с пользователями через API массового импорта, ждёт окончания
+// задачи и печатает построчный отчёт в формате CSV.
+//
+//	LMS_TOKEN=<access-токен администратора> go run ./cmd/import-users -api http://localhost:3030 students.csv > report.csv
+//
+// Формат файла описан у POST /api/admin/user-imports. Код выхода 1 — хотя бы одна строка не импортирована.
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"lms-system-internship/handler"
+	"lms-system-internship/pkg"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+func main() {
+	apiURL := flag.String("api", getEnv("LMS_API_URL", "http://localhost:3030"), "LMS API base URL")
+	token := flag.String("token", os.Getenv("LMS_TOKEN"), "admin access token (default $LMS_TOKEN)")
+	poll := flag.Duration("poll", 2*time.Second, "job status polling interval")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] users.csv\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || *token == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	client := &apiClient{baseURL: *apiURL, token: *token, http: &http.Client{Timeout: time.Minute}}
+	job, err := client.startImport(flag.Arg(0))
+	if err != nil {
+		fail(err)
+	}
+	fmt.Fprintf(os.Stderr, "Import job %s started, %d rows\n", job.ID, job.Total)
+
+	for job.Status == "running" {
+		time.Sleep(*poll)
+		if job, err = client.getImport(job.ID.String()); err != nil {
+			fail(err)
+		}
+		fmt.Fprintf(os.Stderr, "Processed %d of %d\n", job.Processed, job.Total)
+	}
+
+	if err := writeReport(os.Stdout, job); err != nil {
+		fail(err)
+	}
+	fmt.Fprintf(os.Stderr, "Import %s: %d created, %d failed\n", job.Status, job.Succeeded, job.Failed)
+	if job.Failed > 0 || job.Status != "completed" {
+		os.Exit(1)
+	}
+}
+
+type apiClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func (c *apiClient) startImport(path string) (*handler.UserImportJobResponse, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, err
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/api/admin/user-imports", &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return c.do(req, http.StatusAccepted)
+}
+
+func (c *apiClient) getImport(jobID string) (*handler.UserImportJobResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/api/admin/user-imports/"+jobID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req, http.StatusOK)
+}
+
+func (c *apiClient) do(req *http.Request, wantStatus int) (*handler.UserImportJobResponse, error) {
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != wantStatus {
+		var apiErr pkg.ErrorResponse
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			if len(apiErr.Fields) > 0 {
+				return nil, fmt.Errorf("%s: %s: %+v", resp.Status, apiErr.Message, apiErr.Fields)
+			}
+			return nil, fmt.Errorf("%s: %s", resp.Status, apiErr.Message)
+		}
+		return nil, fmt.Errorf("unexpected response %s", resp.Status)
+	}
+	var job handler.UserImportJobResponse
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func writeReport(w io.Writer, job *handler.UserImportJobResponse) error {
+	report := csv.NewWriter(w)
+	_ = report.Write([]string{"line", "username", "status", "user_id", "error_code", "error"})
+	for _, result := range job.Results {
+		userID := ""
+		if result.UserID != nil {
+			userID = result.UserID.String()
+		}
+		_ = report.Write([]string{
+			strconv.Itoa(result.Line), result.Username, result.Status, userID, result.ErrorCode, result.Error,
+		})
+	}
+	report.Flush()
+	return report.Error()
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "import-users:", err)
+	os.Exit(1)
+}
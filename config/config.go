@@ -1,8 +1,268 @@
 package config
 
-import "os"
+import (
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
 
 func GetKeycloakBaseURL() string  { return os.Getenv("KEYCLOAK_BASE_URL") }
 func GetKeycloakRealm() string    { return os.Getenv("KEYCLOAK_REALM") }
 func GetKeycloakAdmin() string    { return os.Getenv("KEYCLOAK_ADMIN") }
 func GetKeycloakPassword() string { return os.Getenv("KEYCLOAK_PASSWORD") }
+
+// GetKeycloakRealmURL — адрес realm, от которого строятся OpenID Connect endpoints.
+// Собирается из KEYCLOAK_BASE_URL и KEYCLOAK_REALM, а без них указывает на realm lms в docker-compose.
+func GetKeycloakRealmURL() string {
+	if base, realm := GetKeycloakBaseURL(), GetKeycloakRealm(); base != "" && realm != "" {
+		return strings.TrimSuffix(base, "/") + "/realms/" + realm
+	}
+	return "http://keycloak:8080/realms/lms"
+}
+
+// GetKeycloakClientID — клиент Keycloak, от имени которого выдаются токены пользователям.
+func GetKeycloakClientID() string { return getEnv("KEYCLOAK_CLIENT_ID", "backend-client") }
+
+// GetKeycloakClientSecret — секрет конфиденциального клиента; нужен для интроспекции токенов.
+func GetKeycloakClientSecret() string { return os.Getenv("KEYCLOAK_CLIENT_SECRET") }
+
+// GetTokenIssuer — ожидаемый iss access-токенов. По умолчанию — адрес realm; если клиенты
+// получают токены по другому адресу Keycloak (например, localhost), его нужно указать явно.
+// Значение "-" отключает проверку.
+func GetTokenIssuer() string {
+	issuer := getEnv("TOKEN_ISSUER", GetKeycloakRealmURL())
+	if issuer == "-" {
+		return ""
+	}
+	return issuer
+}
+
+// GetTokenAudiences — допустимые aud или azp access-токенов через запятую; пусто — не проверяются.
+func GetTokenAudiences() []string { return splitList(os.Getenv("TOKEN_AUDIENCES")) }
+
+// GetTokenAlgorithms — допустимые алгоритмы подписи access-токенов через запятую.
+func GetTokenAlgorithms() []string { return splitList(getEnv("TOKEN_ALGORITHMS", "RS256")) }
+
+// GetTokenClockSkew — допустимое расхождение часов с Keycloak при проверке срока токена.
+func GetTokenClockSkew() time.Duration { return getEnvDuration("TOKEN_CLOCK_SKEW", 30*time.Second) }
+
+// GetTokenIntrospectionCacheTTL — если больше нуля, каждый токен дополнительно проверяется через
+// introspection endpoint Keycloak, а ответ кэшируется на этот срок. Требует KEYCLOAK_CLIENT_SECRET.
+func GetTokenIntrospectionCacheTTL() time.Duration {
+	return getEnvDuration("TOKEN_INTROSPECTION_CACHE_TTL", 0)
+}
+
+// GetAuthProvider — провайдер идентификации: keycloak (по умолчанию) или local —
+// встроенный, для тестов и разработки без Keycloak.
+func GetAuthProvider() string { return strings.ToLower(getEnv("AUTH_PROVIDER", "keycloak")) }
+
+// GetLocalAuthSecret — ключ подписи токенов локального провайдера. Если не задан,
+// при старте генерируется случайный, и токены не переживают перезапуск.
+func GetLocalAuthSecret() string { return os.Getenv("LOCAL_AUTH_SECRET") }
+
+// GetLocalAuthUsers — пользователи локального провайдера: "логин:пароль:РОЛЬ|РОЛЬ" через запятую.
+func GetLocalAuthUsers() string { return os.Getenv("LOCAL_AUTH_USERS") }
+
+// Время жизни access- и refresh-токенов локального провайдера.
+func GetLocalAuthAccessTTL() time.Duration {
+	return getEnvDuration("LOCAL_AUTH_ACCESS_TTL", 15*time.Minute)
+}
+func GetLocalAuthRefreshTTL() time.Duration {
+	return getEnvDuration("LOCAL_AUTH_REFRESH_TTL", 24*time.Hour)
+}
+
+func GetCertificateTitle() string {
+	return getEnv("CERTIFICATE_TITLE", "Certificate of Completion")
+}
+func GetCertificateBodyTemplate() string {
+	return getEnv("CERTIFICATE_BODY_TEMPLATE", "This certifies that {{.StudentName}} has successfully completed the course \"{{.CourseName}}\" on {{.Date}}.")
+}
+func GetCertificateFontPath() string { return os.Getenv("CERTIFICATE_FONT_PATH") }
+
+// GetRevisionRetentionCount — сколько последних ревизий хранить на сущность; 0 — без ограничения.
+func GetRevisionRetentionCount() int { return getEnvInt("REVISION_RETENTION_COUNT", 50) }
+
+// GetRevisionRetentionDays — сколько дней хранить ревизии; 0 — без ограничения.
+// Последняя ревизия сущности не удаляется никогда.
+func GetRevisionRetentionDays() int { return getEnvInt("REVISION_RETENTION_DAYS", 0) }
+
+// GetUserCacheTTL — как долго список пользователей отдаётся из локального кэша, прежде чем
+// сверить его с Keycloak.
+func GetUserCacheTTL() time.Duration { return getEnvDuration("USER_CACHE_TTL", 5*time.Minute) }
+
+// GetUserImportMaxRows — сколько строк (без заголовка) принимается в одном файле импорта пользователей.
+func GetUserImportMaxRows() int { return getEnvInt("USER_IMPORT_MAX_ROWS", 1000) }
+
+// GetUserImportJobRetention — сколько хранить в базе отчёт завершённой задачи импорта.
+func GetUserImportJobRetention() time.Duration {
+	return getEnvDuration("USER_IMPORT_JOB_RETENTION", 24*time.Hour)
+}
+
+// GetMailer — способ отправки писем: smtp, file (файлы .eml в MAILER_DIR) или memory (письма
+// остаются в памяти процесса, для автотестов). Пусто — почта не настроена, и самостоятельная регистрация недоступна.
+func GetMailer() string { return strings.ToLower(os.Getenv("MAILER")) }
+
+// GetMailerDir — каталог для писем файлового отправителя.
+func GetMailerDir() string { return getEnv("MAILER_DIR", "mail") }
+
+// Подключение к SMTP-серверу. SMTP_TLS: starttls (по умолчанию), tls или none.
+func GetSMTPHost() string     { return os.Getenv("SMTP_HOST") }
+func GetSMTPPort() int        { return getEnvInt("SMTP_PORT", 587) }
+func GetSMTPUsername() string { return os.Getenv("SMTP_USERNAME") }
+func GetSMTPPassword() string { return os.Getenv("SMTP_PASSWORD") }
+func GetSMTPTLS() string      { return strings.ToLower(getEnv("SMTP_TLS", "starttls")) }
+
+// GetMailFrom — адрес отправителя писем, например "LMS <no-reply@example.com>".
+func GetMailFrom() string { return getEnv("MAIL_FROM", "LMS <no-reply@localhost>") }
+
+// GetSelfRegistrationEnabled — разрешена ли регистрация без администратора.
+func GetSelfRegistrationEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("SELF_REGISTRATION_ENABLED"))
+	return enabled
+}
+
+// GetSelfRegistrationEmailDomains — домены почты, с которых разрешена регистрация, через запятую;
+// пусто — любые.
+func GetSelfRegistrationEmailDomains() []string {
+	return splitList(strings.ToLower(os.Getenv("SELF_REGISTRATION_EMAIL_DOMAINS")))
+}
+
+// GetSelfRegistrationRoles — роли, которые получает зарегистрировавшийся сам пользователь.
+func GetSelfRegistrationRoles() []string {
+	return splitList(getEnv("SELF_REGISTRATION_ROLES", "ROLE_STUDENT"))
+}
+
+// Ссылки в письмах. {token} заменяется на токен из письма; обычно это страница фронтенда,
+// которая отправляет токен в API.
+func GetVerifyEmailURL() string {
+	return getEnv("VERIFY_EMAIL_URL", "http://localhost:3030/api/auth/verify-email?token={token}")
+}
+func GetPasswordResetURL() string {
+	return getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password?token={token}")
+}
+
+// Сроки действия ссылок из писем.
+func GetVerifyEmailTokenTTL() time.Duration {
+	return getEnvDuration("VERIFY_EMAIL_TOKEN_TTL", 24*time.Hour)
+}
+func GetPasswordResetTokenTTL() time.Duration {
+	return getEnvDuration("PASSWORD_RESET_TOKEN_TTL", time.Hour)
+}
+func GetInviteTokenTTL() time.Duration {
+	return getEnvDuration("INVITE_TOKEN_TTL", 72*time.Hour)
+}
+
+// GetEmailResendInterval — не чаще какого интервала одному пользователю отправляется письмо одного вида.
+func GetEmailResendInterval() time.Duration {
+	return getEnvDuration("EMAIL_RESEND_INTERVAL", time.Minute)
+}
+
+// GetAuthRateLimit — сколько запросов к публичным endpoints регистрации и восстановления пароля
+// принимается с одного IP за AUTH_RATE_LIMIT_WINDOW; 0 — без ограничения.
+func GetAuthRateLimit() int { return getEnvInt("AUTH_RATE_LIMIT", 10) }
+func GetAuthRateLimitWindow() time.Duration {
+	return getEnvDuration("AUTH_RATE_LIMIT_WINDOW", time.Minute)
+}
+
+// GetEmailRequestLimit — сколько запросов писем (повтор подтверждения, сброс пароля) принимается
+// для одного адреса почты за EMAIL_REQUEST_LIMIT_WINDOW, с любых IP; 0 — без ограничения.
+func GetEmailRequestLimit() int { return getEnvInt("EMAIL_REQUEST_LIMIT", 5) }
+func GetEmailRequestLimitWindow() time.Duration {
+	return getEnvDuration("EMAIL_REQUEST_LIMIT_WINDOW", time.Hour)
+}
+
+// GetDefaultLocale — локаль, на которой написаны исходные поля курсов, глав и уроков.
+func GetDefaultLocale() string { return strings.ToLower(getEnv("DEFAULT_LOCALE", "en")) }
+
+// GetSupportedLocales — локали, на которые можно переводить контент, через запятую.
+// Локаль по умолчанию входит в список всегда.
+func GetSupportedLocales() []string {
+	defaultLocale := GetDefaultLocale()
+	locales := []string{defaultLocale}
+	for _, locale := range strings.Split(getEnv("SUPPORTED_LOCALES", "en,ru"), ",") {
+		locale = strings.ToLower(strings.TrimSpace(locale))
+		if locale != "" && !slices.Contains(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// GetLogLevel — минимальный уровень логов: debug, info, warn или error.
+func GetLogLevel() string { return strings.ToLower(getEnv("LOG_LEVEL", "info")) }
+
+// GetLogFormat — формат логов: json (по умолчанию) или text для локальной разработки.
+func GetLogFormat() string { return strings.ToLower(getEnv("LOG_FORMAT", "json")) }
+
+// GetServiceName — имя сервиса в трассировке (атрибут service.name).
+func GetServiceName() string { return getEnv("OTEL_SERVICE_NAME", "lms-system") }
+
+// GetTracesExporter — куда отправлять спаны: otlp, stdout или none (трассировка выключена).
+// Адрес коллектора для otlp задаётся стандартными переменными OTEL_EXPORTER_OTLP_*.
+func GetTracesExporter() string { return strings.ToLower(getEnv("OTEL_TRACES_EXPORTER", "none")) }
+
+// GetServerAddr — адрес, на котором слушает HTTP-сервер.
+func GetServerAddr() string { return getEnv("SERVER_ADDR", ":3030") }
+
+// GetTrustedProxies — адреса или подсети прокси через запятую, которым доверяется X-Forwarded-For
+// при определении IP клиента. Пусто — IP клиента берётся из соединения.
+func GetTrustedProxies() []string { return splitList(os.Getenv("TRUSTED_PROXIES")) }
+
+// Таймауты HTTP-сервера. Чтение и запись рассчитаны на загрузку и скачивание файлов.
+func GetReadHeaderTimeout() time.Duration {
+	return getEnvDuration("HTTP_READ_HEADER_TIMEOUT", 10*time.Second)
+}
+func GetReadTimeout() time.Duration  { return getEnvDuration("HTTP_READ_TIMEOUT", 5*time.Minute) }
+func GetWriteTimeout() time.Duration { return getEnvDuration("HTTP_WRITE_TIMEOUT", 5*time.Minute) }
+func GetIdleTimeout() time.Duration  { return getEnvDuration("HTTP_IDLE_TIMEOUT", 2*time.Minute) }
+
+// GetShutdownDrainPeriod — сколько после SIGTERM /readyz отвечает 503, прежде чем сервер
+// перестанет принимать соединения: за это время балансировщик успевает убрать под.
+func GetShutdownDrainPeriod() time.Duration {
+	return getEnvDuration("SHUTDOWN_DRAIN_PERIOD", 5*time.Second)
+}
+
+// GetShutdownTimeout — сколько ждать завершения начатых запросов при остановке.
+func GetShutdownTimeout() time.Duration { return getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second) }
+
+// GetDatabaseRetryInterval — пауза между попытками подключиться к базе и применить миграции при старте.
+func GetDatabaseRetryInterval() time.Duration {
+	return getEnvDuration("DB_RETRY_INTERVAL", 5*time.Second)
+}
+
+// splitList разбирает список через запятую, пропуская пустые элементы.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvDuration читает длительность в формате time.ParseDuration, например "30s".
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
@@ -9,10 +9,15 @@ type Course struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
 	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
 	Description string    `gorm:"type:text" json:"description"`
+	CategoryID  *uint     `gorm:"index" json:"category_id"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 
+	// Version — номер опубликованной версии, из снимка которой собран ответ; 0 — текущий черновик.
+	Version int `gorm:"-" json:"version,omitempty"`
+
 	Chapters []Chapter `gorm:"foreignKey:CourseID" json:"chapters"`
+	Tags     []Tag     `gorm:"many2many:course_tags" json:"tags"`
 }
 
 type Chapter struct {
@@ -27,15 +32,64 @@ type Chapter struct {
 	Lessons []Lesson `gorm:"foreignKey:ChapterID" json:"lessons"`
 }
 
+// Форматы содержимого урока.
+const (
+	ContentFormatPlain    = "plain"
+	ContentFormatMarkdown = "markdown"
+	ContentFormatHTML     = "html"
+)
+
 type Lesson struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
-	Description string    `gorm:"type:text" json:"description"`
-	Content     string    `gorm:"type:text" json:"content"`
-	Order       int       `gorm:"not null" json:"order"`
-	ChapterID   uint      `gorm:"not null" json:"chapter_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Name          string    `gorm:"type:varchar(255);not null" json:"name"`
+	Description   string    `gorm:"type:text" json:"description"`
+	Content       string    `gorm:"type:text" json:"content"`
+	ContentFormat string    `gorm:"type:varchar(20);not null;default:plain" json:"content_format"`
+	Order         int       `gorm:"not null" json:"order"`
+	ChapterID     uint      `gorm:"not null" json:"chapter_id"`
+	CategoryID    *uint     `gorm:"index" json:"category_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// ContentHTML заполняется только при запросе с ?render=html.
+	ContentHTML string `gorm:"-" json:"content_html,omitempty"`
+
+	Tags   []Tag         `gorm:"many2many:lesson_tags" json:"tags"`
+	Blocks []LessonBlock `gorm:"foreignKey:LessonID" json:"blocks"`
+	// Attachments без внешнего ключа в базе: записи вложений удаляются вместе с уроком в
+	// lessonRepository.Delete, а файлы — в хранилище.
+	Attachments []Attachment `gorm:"foreignKey:LessonID;-:migration" json:"attachments,omitempty"`
+}
+
+// Типы блоков урока.
+const (
+	BlockTypeText    = "text"
+	BlockTypeCode    = "code"
+	BlockTypeImage   = "image"
+	BlockTypeVideo   = "video"
+	BlockTypeCallout = "callout"
+)
+
+// LessonBlock — элемент содержимого урока. Какие поля заполнены, зависит от Type:
+// text — Text и Format; code — Text и Language; image — AttachmentID и Caption;
+// video — URL и Caption; callout — Text и Variant.
+type LessonBlock struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	LessonID     uint      `gorm:"not null;index" json:"lesson_id"`
+	Type         string    `gorm:"type:varchar(20);not null" json:"type"`
+	Order        int       `gorm:"not null" json:"order"`
+	Text         string    `gorm:"type:text" json:"text,omitempty"`
+	Format       string    `gorm:"type:varchar(20)" json:"format,omitempty"`
+	Language     string    `gorm:"type:varchar(50)" json:"language,omitempty"`
+	AttachmentID *uint     `json:"attachment_id,omitempty"`
+	URL          string    `gorm:"type:varchar(1024)" json:"url,omitempty"`
+	Caption      string    `gorm:"type:varchar(255)" json:"caption,omitempty"`
+	Variant      string    `gorm:"type:varchar(20)" json:"variant,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// HTML заполняется только при запросе урока с ?render=html.
+	HTML string `gorm:"-" json:"html,omitempty"`
 }
 
 type Attachment struct {
@@ -51,3 +105,248 @@ type LessonUser struct {
 	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
 	CreatedAt time.Time
 }
+
+type Assignment struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Title        string    `gorm:"type:varchar(255);not null" json:"title"`
+	Description  string    `gorm:"type:text" json:"description"`
+	LessonID     uint      `gorm:"not null;index" json:"lesson_id"`
+	DueDate      time.Time `gorm:"not null" json:"due_date"`
+	MaxScore     int       `gorm:"not null" json:"max_score"`
+	AllowedTypes string    `gorm:"type:varchar(255)" json:"allowed_types"` // расширения через запятую, например ".pdf,.zip"; пусто — любые
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type Submission struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	AssignmentID uint       `gorm:"not null;uniqueIndex:idx_submission_assignment_user" json:"assignment_id"`
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_submission_assignment_user" json:"user_id"`
+	FileName     string     `gorm:"type:varchar(255);not null" json:"file_name"`
+	FileURL      string     `gorm:"type:varchar(255);not null" json:"-"`
+	SubmittedAt  time.Time  `gorm:"not null" json:"submitted_at"`
+	IsLate       bool       `gorm:"not null;default:false" json:"is_late"`
+	Score        *int       `json:"score"`
+	Feedback     string     `gorm:"type:text" json:"feedback"`
+	GradedBy     *uuid.UUID `gorm:"type:uuid" json:"graded_by"`
+	GradedAt     *time.Time `json:"graded_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type GradeCategory struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CourseID  uint      `gorm:"not null;index" json:"course_id"`
+	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	Weight    float64   `gorm:"not null" json:"weight"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type GradeItem struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CourseID     uint      `gorm:"not null;index" json:"course_id"`
+	CategoryID   *uint     `gorm:"index" json:"category_id"`
+	LessonID     *uint     `json:"lesson_id"`
+	AssignmentID *uint     `gorm:"index" json:"assignment_id"`
+	Name         string    `gorm:"type:varchar(255);not null" json:"name"`
+	MaxScore     float64   `gorm:"not null" json:"max_score"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type Grade struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	GradeItemID uint      `gorm:"not null;uniqueIndex:idx_grade_item_user" json:"grade_item_id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_grade_item_user" json:"user_id"`
+	Score       float64   `gorm:"not null" json:"score"`
+	Overridden  bool      `gorm:"not null;default:false" json:"overridden"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type GradeAudit struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	GradeItemID uint       `gorm:"not null;index" json:"grade_item_id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	OldScore    *float64   `json:"old_score"`
+	NewScore    float64    `gorm:"not null" json:"new_score"`
+	ChangedBy   *uuid.UUID `gorm:"type:uuid" json:"changed_by"`
+	Reason      string     `gorm:"type:text" json:"reason"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type Certificate struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	CourseID    uint       `gorm:"not null;uniqueIndex:idx_certificate_course_user" json:"course_id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_certificate_course_user" json:"user_id"`
+	Code        string     `gorm:"type:varchar(32);not null;uniqueIndex" json:"code"`
+	StudentName string     `gorm:"type:varchar(255);not null" json:"student_name"`
+	CourseName  string     `gorm:"type:varchar(255);not null" json:"course_name"`
+	FileURL     string     `gorm:"type:varchar(255);not null" json:"-"`
+	IssuedBy    *uuid.UUID `gorm:"type:uuid" json:"issued_by"`
+	IssuedAt    time.Time  `gorm:"not null" json:"issued_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CompletionRule — условие автоматической выдачи сертификата по курсу.
+type CompletionRule struct {
+	CourseID          uint       `gorm:"primaryKey" json:"course_id"`
+	Enabled           bool       `gorm:"not null;default:false" json:"enabled"`
+	RequireAllLessons bool       `gorm:"not null;default:true" json:"require_all_lessons"`
+	NotBefore         *time.Time `json:"not_before"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// Category — рубрика каталога. ParentID == nil у корневых рубрик.
+type Category struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	ParentID  *uint     `gorm:"index" json:"parent_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Children []*Category `gorm:"-" json:"children,omitempty"`
+}
+
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Типы сущностей, для которых хранятся история правок и переводы.
+const (
+	RevisionEntityCourse  = "course"
+	RevisionEntityChapter = "chapter"
+	RevisionEntityLesson  = "lesson"
+)
+
+// TranslationEntityBlock — блок урока. Истории правок у блоков нет (они входят в историю урока),
+// а переводы хранятся: Content переводит текст блока, Name — подпись.
+const TranslationEntityBlock = "block"
+
+// Revision — сохранённое состояние текстовых полей курса, главы или урока; у урока — вместе
+// с блоками в их порядке. Number растёт с единицы отдельно для каждой сущности. AuthorID пуст
+// у ревизии, снятой с содержимого, которое существовало до появления истории.
+type Revision struct {
+	ID            uint            `gorm:"primaryKey" json:"id"`
+	EntityType    string          `gorm:"type:varchar(20);not null;uniqueIndex:idx_revision_entity_number" json:"entity_type"`
+	EntityID      uint            `gorm:"not null;uniqueIndex:idx_revision_entity_number" json:"entity_id"`
+	Number        int             `gorm:"not null;uniqueIndex:idx_revision_entity_number" json:"number"`
+	Name          string          `gorm:"type:varchar(255)" json:"name"`
+	Description   string          `gorm:"type:text" json:"description"`
+	Content       string          `gorm:"type:text" json:"content,omitempty"`
+	ContentFormat string          `gorm:"type:varchar(20)" json:"content_format,omitempty"`
+	Blocks        []RevisionBlock `gorm:"type:jsonb;serializer:json" json:"blocks,omitempty"`
+	AuthorID      *uuid.UUID      `gorm:"type:uuid" json:"author_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// RevisionBlock — содержимое блока урока в ревизии. ID нужен, чтобы при откате сохранить
+// блоки, которые ещё существуют; при сравнении ревизий он не учитывается.
+type RevisionBlock struct {
+	ID           uint   `json:"id"`
+	Type         string `json:"type"`
+	Text         string `json:"text,omitempty"`
+	Format       string `json:"format,omitempty"`
+	Language     string `json:"language,omitempty"`
+	AttachmentID *uint  `json:"attachment_id,omitempty"`
+	URL          string `json:"url,omitempty"`
+	Caption      string `json:"caption,omitempty"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// CourseVersion — неизменяемый снимок опубликованного курса: главы, уроки, блоки и вложения
+// в виде JSON. Number растёт с единицы отдельно для каждого курса.
+type CourseVersion struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CourseID    uint      `gorm:"not null;uniqueIndex:idx_course_version_number" json:"course_id"`
+	Number      int       `gorm:"not null;uniqueIndex:idx_course_version_number" json:"number"`
+	Comment     string    `gorm:"type:text" json:"comment"`
+	Snapshot    string    `gorm:"type:jsonb;not null;index:idx_course_version_snapshot,type:gin" json:"-"`
+	PublishedBy uuid.UUID `gorm:"type:uuid;not null" json:"published_by"`
+	PublishedAt time.Time `gorm:"not null" json:"published_at"`
+}
+
+// CourseVersionPin — версия курса, закреплённая за студентом.
+type CourseVersionPin struct {
+	CourseID  uint      `gorm:"primaryKey" json:"course_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Version   int       `gorm:"not null" json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Translation — перевод названия, описания и содержимого курса, главы, урока или блока урока.
+// Исходные поля самих сущностей считаются текстом на локали по умолчанию (DEFAULT_LOCALE),
+// пустое поле перевода означает, что для него показывается исходный текст.
+type Translation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	EntityType  string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_translation_entity_locale" json:"entity_type"`
+	EntityID    uint      `gorm:"not null;uniqueIndex:idx_translation_entity_locale" json:"entity_id"`
+	Locale      string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_translation_entity_locale" json:"locale"`
+	Name        string    `gorm:"type:varchar(255)" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	Content     string    `gorm:"type:text" json:"content,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// UserProfile — копия учётной записи из провайдера идентификации, чтобы списки пользователей
+// не обращались к Keycloak на каждый запрос. SyncedAt — когда запись последний раз сверялась.
+type UserProfile struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Username  string    `gorm:"type:varchar(255);not null;index" json:"username"`
+	Email     string    `gorm:"type:varchar(255);index" json:"email"`
+	FirstName string    `gorm:"type:varchar(255)" json:"first_name"`
+	LastName  string    `gorm:"type:varchar(255)" json:"last_name"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	SyncedAt  time.Time `gorm:"not null;index" json:"synced_at"`
+}
+
+// Назначение одноразового токена из письма.
+const (
+	EmailTokenVerifyEmail   = "verify_email"
+	EmailTokenResetPassword = "reset_password"
+)
+
+// EmailToken — одноразовая ссылка из письма: подтверждение почты или сброс пароля. Хранится
+// только SHA-256 токена, поэтому утечка таблицы не даёт доступа к чужим учётным записям.
+type EmailToken struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"`
+	Purpose   string     `gorm:"type:varchar(32);not null;index:idx_email_token_user" json:"purpose"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_email_token_user" json:"user_id"`
+	Email     string     `gorm:"type:varchar(255);not null" json:"email"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// UserImportJob — задача массового импорта пользователей. Хранится в базе, чтобы её состояние
+// видели все экземпляры сервиса, а не только тот, что её выполняет.
+type UserImportJob struct {
+	ID         uuid.UUID          `gorm:"type:uuid;primaryKey" json:"id"`
+	Status     string             `gorm:"type:varchar(16);not null" json:"status"`
+	CreatedBy  uuid.UUID          `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt  time.Time          `json:"created_at"`
+	FinishedAt *time.Time         `gorm:"index" json:"finished_at,omitempty"`
+	Total      int                `gorm:"not null" json:"total"`
+	Succeeded  int                `gorm:"not null" json:"succeeded"`
+	Failed     int                `gorm:"not null" json:"failed"`
+	Results    []UserImportResult `gorm:"foreignKey:JobID" json:"results"`
+}
+
+// UserImportResult — итог одной строки импорта. UserID заполнен, если учётная запись создана; у строки
+// с ошибкой и UserID учётная запись есть, но доступ к курсам или приглашение выданы не полностью.
+type UserImportResult struct {
+	ID        uint       `gorm:"primaryKey" json:"-"`
+	JobID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"-"`
+	Line      int        `gorm:"not null" json:"line"`
+	Username  string     `gorm:"type:varchar(255)" json:"username"`
+	Status    string     `gorm:"type:varchar(16);not null" json:"status"`
+	UserID    *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	ErrorCode string     `gorm:"type:varchar(64)" json:"error_code,omitempty"`
+	Error     string     `gorm:"type:text" json:"error,omitempty"`
+}
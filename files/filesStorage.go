@@ -7,6 +7,10 @@ import (
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"io"
+	"lms-system-internship/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type MinIOStorage struct {
@@ -29,16 +33,49 @@ func NewMinIOStorage(endpoint, accessKey, secretKey, bucket string) (*MinIOStora
 	}, nil
 }
 
-func (s *MinIOStorage) UploadFile(ctx context.Context, filename string, data []byte) (string, error) {
+// Check — проверка готовности: MinIO доступен и бакет существует.
+func (s *MinIOStorage) Check(ctx context.Context) error {
+	exists, err := s.Client.BucketExists(ctx, s.BucketName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %q does not exist", s.BucketName)
+	}
+	return nil
+}
+
+func (s *MinIOStorage) UploadFile(ctx context.Context, filename string, data []byte) (_ string, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "minio.PutObject", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("storage.bucket", s.BucketName),
+			attribute.String("storage.object", filename),
+			attribute.Int("storage.bytes", len(data)),
+		))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	reader := bytes.NewReader(data)
-	_, err := s.Client.PutObject(ctx, s.BucketName, filename, reader, int64(len(data)), minio.PutObjectOptions{})
+	_, err = s.Client.PutObject(ctx, s.BucketName, filename, reader, int64(len(data)), minio.PutObjectOptions{})
 	if err != nil {
 		return "", err
 	}
 	return fmt.Sprintf("http://localhost:9000/%s/%s", s.BucketName, filename), nil
 }
 
-func (s *MinIOStorage) DownloadFile(ctx context.Context, objectName string) ([]byte, error) {
+func (s *MinIOStorage) DownloadFile(ctx context.Context, objectName string) (_ []byte, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "minio.GetObject", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("storage.bucket", s.BucketName),
+			attribute.String("storage.object", objectName),
+		))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
 	object, err := s.Client.GetObject(ctx, s.BucketName, objectName, minio.GetObjectOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get object from MinIO: %w", err)
@@ -49,6 +86,46 @@ func (s *MinIOStorage) DownloadFile(ctx context.Context, objectName string) ([]b
 	if err != nil {
 		return nil, fmt.Errorf("failed to read object: %w", err)
 	}
+	span.SetAttributes(attribute.Int("storage.bytes", len(data)))
 
 	return data, nil
 }
+
+func (s *MinIOStorage) DeleteFile(ctx context.Context, objectName string) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "minio.RemoveObject", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("storage.bucket", s.BucketName),
+			attribute.String("storage.object", objectName),
+		))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	// RemoveObject не возвращает ошибку для несуществующего объекта
+	if err = s.Client.RemoveObject(ctx, s.BucketName, objectName, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to remove object from MinIO: %w", err)
+	}
+	return nil
+}
+
+func (s *MinIOStorage) CopyFile(ctx context.Context, src, dst string) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "minio.CopyObject", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("storage.bucket", s.BucketName),
+			attribute.String("storage.object", dst),
+			attribute.String("storage.source", src),
+		))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	_, err = s.Client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: s.BucketName, Object: dst},
+		minio.CopySrcOptions{Bucket: s.BucketName, Object: src})
+	if err != nil {
+		return fmt.Errorf("failed to copy object in MinIO: %w", err)
+	}
+	return nil
+}
@@ -5,4 +5,8 @@ import "context"
 type FileStorage interface {
 	UploadFile(ctx context.Context, filename string, data []byte) (string, error)
 	DownloadFile(ctx context.Context, fileURL string) ([]byte, error)
+	// DeleteFile удаляет объект; отсутствующий объект ошибкой не считается.
+	DeleteFile(ctx context.Context, fileURL string) error
+	// CopyFile копирует объект src в новый объект dst внутри хранилища.
+	CopyFile(ctx context.Context, src, dst string) error
 }
@@ -0,0 +1,52 @@
+package files
+
+import (
+	"context"
+	"lms-system-internship/metrics"
+)
+
+// instrumentedStorage считает переданные байты и ошибки вызовов FileStorage.
+type instrumentedStorage struct {
+	next FileStorage
+}
+
+// NewInstrumentedStorage оборачивает хранилище метриками storage_bytes_total и storage_errors_total.
+func NewInstrumentedStorage(next FileStorage) FileStorage {
+	return &instrumentedStorage{next: next}
+}
+
+func (s *instrumentedStorage) UploadFile(ctx context.Context, filename string, data []byte) (string, error) {
+	url, err := s.next.UploadFile(ctx, filename, data)
+	if err != nil {
+		metrics.StorageErrors.WithLabelValues("upload").Inc()
+		return "", err
+	}
+	metrics.StorageBytes.WithLabelValues("upload").Add(float64(len(data)))
+	return url, nil
+}
+
+func (s *instrumentedStorage) DownloadFile(ctx context.Context, fileURL string) ([]byte, error) {
+	data, err := s.next.DownloadFile(ctx, fileURL)
+	if err != nil {
+		metrics.StorageErrors.WithLabelValues("download").Inc()
+		return nil, err
+	}
+	metrics.StorageBytes.WithLabelValues("download").Add(float64(len(data)))
+	return data, nil
+}
+
+func (s *instrumentedStorage) DeleteFile(ctx context.Context, fileURL string) error {
+	err := s.next.DeleteFile(ctx, fileURL)
+	if err != nil {
+		metrics.StorageErrors.WithLabelValues("delete").Inc()
+	}
+	return err
+}
+
+func (s *instrumentedStorage) CopyFile(ctx context.Context, src, dst string) error {
+	err := s.next.CopyFile(ctx, src, dst)
+	if err != nil {
+		metrics.StorageErrors.WithLabelValues("copy").Inc()
+	}
+	return err
+}
@@ -1,11 +1,15 @@
 package handler
 
 import (
-	"context"
-	"github.com/Nerzal/gocloak/v13"
 	"github.com/gin-gonic/gin"
-	"lms-system-internship/config"
+	"github.com/google/uuid"
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"lms-system-internship/service"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type RegisterRequest struct {
@@ -23,10 +27,47 @@ type UpdateUserRequest struct {
 }
 
 type UpdateUserRolesRequest struct {
-	UserID   string   `json:"user_id" binding:"required"`
+	UserID   string   `json:"user_id" binding:"required,uuid"`
 	NewRoles []string `json:"new_roles" binding:"required"` // Роли, которые нужно оставить
 }
 
+// SetUserEnabledRequest — включить или отключить учётную запись.
+type SetUserEnabledRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+type UserResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserListResponse — страница справочника пользователей; Total — сколько всего подходит под фильтр.
+type UserListResponse struct {
+	Items []UserResponse `json:"items"`
+	Total int64          `json:"total"`
+}
+
+// UserDetailsResponse — пользователь с ролями realm и уроками, к которым ему выдан доступ.
+type UserDetailsResponse struct {
+	UserResponse
+	Roles     []string `json:"roles"`
+	LessonIDs []uint   `json:"lesson_ids"`
+}
+
+type UserHandler struct {
+	svc   service.IdentityService
+	users service.UserAdminService
+}
+
+func NewUserHandler(svc service.IdentityService, users service.UserAdminService) *UserHandler {
+	return &UserHandler{svc: svc, users: users}
+}
+
 // RegisterUser godoc
 // @Summary Register a new user
 // @Description Creates a new user in Keycloak with optional roles
@@ -35,73 +76,31 @@ type UpdateUserRolesRequest struct {
 // @Produce json
 // @Param request body RegisterRequest true "User registration data"
 // @Success 201 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} pkg.ErrorResponse
+// @Failure 409 {object} pkg.ErrorResponse
+// @Failure 502 {object} pkg.ErrorResponse
 // @Router /api/admin/register [post]
-func RegisterUser(c *gin.Context) {
+func (h *UserHandler) RegisterUser(c *gin.Context) {
 	var req RegisterRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	ctx := context.Background()
-	client := gocloak.NewClient(config.GetKeycloakBaseURL())
-
-	token, err := client.LoginAdmin(ctx, config.GetKeycloakAdmin(), config.GetKeycloakPassword(), config.GetKeycloakRealm())
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login as admin"})
-		return
-	}
-
-	user := gocloak.User{
-		Username:      gocloak.StringP(req.Username),
-		Email:         gocloak.StringP(req.Email),
-		Enabled:       gocloak.BoolP(true),
-		EmailVerified: gocloak.BoolP(true),
-	}
-
-	userID, err := client.CreateUser(ctx, token.AccessToken, config.GetKeycloakRealm(), user)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	// Set password
-	err = client.SetPassword(ctx, token.AccessToken, userID, config.GetKeycloakRealm(), req.Password, false)
+	userID, err := h.svc.RegisterUser(c.Request.Context(), service.NewUser{
+		Username: req.Username,
+		Email:    req.Email,
+		Password: req.Password,
+		Roles:    req.Roles,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set password"})
+		requestLogger(c).WithField("username", req.Username).WithError(err).Error("Failed to register user")
+		c.Error(err)
 		return
 	}
+	requestLogger(c).WithField("target_user_id", userID).Info("User registered")
+	h.refreshProfile(c, userID)
 
-	// Assign roles
-	if len(req.Roles) > 0 {
-		rolesToAssign := []*gocloak.Role{}
-		for _, roleName := range req.Roles {
-			role, err := client.GetRealmRole(ctx, token.AccessToken, config.GetKeycloakRealm(), roleName)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role: " + roleName})
-				return
-			}
-			rolesToAssign = append(rolesToAssign, role)
-		}
-
-		var realmRoles []gocloak.Role
-		for _, r := range rolesToAssign {
-			if r != nil {
-				realmRoles = append(realmRoles, *r)
-			}
-		}
-
-		err = client.AddRealmRoleToUser(ctx, token.AccessToken, config.GetKeycloakRealm(), userID, realmRoles)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign roles"})
-			return
-		}
-	}
-
-	c.JSON(http.StatusCreated, gin.H{"message": "User created", "user_id": userID})
+	c.JSON(http.StatusCreated, gin.H{"message": "User created", "user_id": userID.String()})
 }
 
 // UpdateUserProfile godoc
@@ -113,70 +112,39 @@ func RegisterUser(c *gin.Context) {
 // @Produce json
 // @Param request body UpdateUserRequest true "User update data"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} pkg.ErrorResponse
+// @Failure 401 {object} pkg.ErrorResponse
+// @Failure 404 {object} pkg.ErrorResponse
+// @Failure 502 {object} pkg.ErrorResponse
 // @Router /api/user/profile [put]
-func UpdateUserProfile(c *gin.Context) {
-	usernameRaw, exists := c.Get("username")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No username in context"})
+func (h *UserHandler) UpdateUserProfile(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
 		return
 	}
-	username := usernameRaw.(string)
 
 	var req UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	ctx := context.Background()
-	client := gocloak.NewClient(config.GetKeycloakBaseURL())
-	token, err := client.LoginAdmin(ctx, config.GetKeycloakAdmin(), config.GetKeycloakPassword(), config.GetKeycloakRealm())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to login as admin"})
-		return
-	}
-
-	users, err := client.GetUsers(ctx, token.AccessToken, config.GetKeycloakRealm(), gocloak.GetUsersParams{
-		Username: &username,
+	err := h.svc.UpdateProfile(c.Request.Context(), userID, service.ProfileUpdate{
+		Email:     req.Email,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Password:  req.Password,
 	})
-	if err != nil || len(users) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		return
-	}
-	user := users[0]
-
-	if req.Email != "" {
-		user.Email = &req.Email
-	}
-	if req.FirstName != "" {
-		user.FirstName = &req.FirstName
-	}
-	if req.LastName != "" {
-		user.LastName = &req.LastName
-	}
-
-	err = client.UpdateUser(ctx, token.AccessToken, config.GetKeycloakRealm(), *user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		requestLogger(c).WithError(err).Error("Failed to update user profile")
+		c.Error(err)
 		return
 	}
-
-	if req.Password != "" {
-		err := client.SetPassword(ctx, token.AccessToken, *user.ID, config.GetKeycloakRealm(), req.Password, false)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set password"})
-			return
-		}
-	}
+	h.refreshProfile(c, userID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "User profile updated"})
 }
 
-// UpdateUserRolesHandler godoc
+// UpdateUserRoles godoc
 // @Summary Update user roles (admin only)
 // @Description Replaces a user's roles in Keycloak with the given list
 // @Tags admin
@@ -185,75 +153,178 @@ func UpdateUserProfile(c *gin.Context) {
 // @Produce json
 // @Param request body UpdateUserRolesRequest true "User role update"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} pkg.ErrorResponse
+// @Failure 404 {object} pkg.ErrorResponse
+// @Failure 502 {object} pkg.ErrorResponse
 // @Router /api/admin/update-roles [post]
-func UpdateUserRolesHandler(c *gin.Context) {
+func (h *UserHandler) UpdateUserRoles(c *gin.Context) {
 	var req UpdateUserRolesRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	userID := uuid.MustParse(req.UserID) // формат проверен правилом uuid
+	if err := h.svc.ReplaceRoles(c.Request.Context(), userID, req.NewRoles); err != nil {
+		requestLogger(c).WithField("target_user_id", userID).WithError(err).Error("Failed to update user roles")
+		c.Error(err)
 		return
 	}
+	requestLogger(c).WithField("target_user_id", userID).WithField("roles", req.NewRoles).Info("User roles updated")
 
-	ctx := context.Background()
-	client := gocloak.NewClient(config.GetKeycloakBaseURL())
+	c.JSON(http.StatusOK, gin.H{"message": "User roles updated successfully"})
+}
+
+// ListUsers godoc
+// @Summary      List users (admin only)
+// @Description  Lists users from the local profile cache, which is synced with Keycloak when older than USER_CACHE_TTL
+// @Tags         admin
+// @Produce      json
+// @Param        q        query     string  false  "Substring of username, email, first or last name"
+// @Param        enabled  query     bool    false  "Only enabled (true) or disabled (false) users"
+// @Param        limit    query     int     false  "Page size (default 20, max 100)"
+// @Param        offset   query     int     false  "Offset"
+// @Success      200      {object}  handler.UserListResponse
+// @Failure      400      {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users [get]
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	filter := repo.UserFilter{Query: c.Query("q")}
+	if raw := c.Query("enabled"); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "enabled", Code: "boolean"}))
+			return
+		}
+		filter.Enabled = &enabled
+	}
+	var err error
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "limit", Code: "number"}))
+		return
+	}
+	if filter.Offset, err = queryInt(c, "offset"); err != nil {
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "offset", Code: "number"}))
+		return
+	}
 
-	token, err := client.LoginAdmin(ctx, config.GetKeycloakAdmin(), config.GetKeycloakPassword(), config.GetKeycloakRealm())
+	profiles, total, err := h.users.ListUsers(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Admin login failed"})
+		requestLogger(c).WithError(err).Error("Failed to list users")
+		c.Error(err)
+		return
+	}
+	items := make([]UserResponse, 0, len(profiles))
+	for _, profile := range profiles {
+		items = append(items, newUserResponse(profile))
+	}
+	c.JSON(http.StatusOK, UserListResponse{Items: items, Total: total})
+}
+
+// GetUser godoc
+// @Summary      Get a user (admin only)
+// @Description  Returns the user from Keycloak with realm roles and the lessons they have access to
+// @Tags         admin
+// @Produce      json
+// @Param        user_id  path      string  true  "User ID"
+// @Success      200      {object}  handler.UserDetailsResponse
+// @Failure      400      {object}  pkg.ErrorResponse
+// @Failure      404      {object}  pkg.ErrorResponse
+// @Failure      502      {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users/{user_id} [get]
+func (h *UserHandler) GetUser(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "user_id")
+	if !ok {
 		return
 	}
 
-	// Получаем все текущие роли пользователя
-	currentRoles, err := client.GetRealmRolesByUserID(ctx, token.AccessToken, config.GetKeycloakRealm(), req.UserID)
+	details, err := h.users.GetUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get current roles"})
+		requestLogger(c).WithField("target_user_id", userID).WithError(err).Error("Failed to get user")
+		c.Error(err)
 		return
 	}
+	c.JSON(http.StatusOK, UserDetailsResponse{
+		UserResponse: newUserResponse(&details.Profile),
+		Roles:        details.Roles,
+		LessonIDs:    details.LessonIDs,
+	})
+}
 
-	// Получаем список ролей, которые админ хочет оставить
-	var newRolesObjs []gocloak.Role
-	for _, roleName := range req.NewRoles {
-		role, err := client.GetRealmRole(ctx, token.AccessToken, config.GetKeycloakRealm(), roleName)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role: " + roleName})
-			return
-		}
-		newRolesObjs = append(newRolesObjs, *role)
+// SetUserEnabled godoc
+// @Summary      Enable or disable a user (admin only)
+// @Description  A disabled user cannot sign in; their active sessions are ended
+// @Tags         admin
+// @Accept       json
+// @Param        user_id  path  string                 true  "User ID"
+// @Param        request  body  SetUserEnabledRequest  true  "New state"
+// @Success      204
+// @Failure      400  {object}  pkg.ErrorResponse
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Failure      502  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users/{user_id}/enabled [put]
+func (h *UserHandler) SetUserEnabled(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+	var req SetUserEnabledRequest
+	if !bindJSON(c, &req) {
+		return
 	}
 
-	// Определяем роли, которые нужно удалить
-	var rolesToRemove []gocloak.Role
-	newRolesMap := map[string]bool{}
-	for _, r := range req.NewRoles {
-		newRolesMap[r] = true
+	if err := h.users.SetEnabled(c.Request.Context(), userID, *req.Enabled); err != nil {
+		requestLogger(c).WithField("target_user_id", userID).WithError(err).Error("Failed to change user state")
+		c.Error(err)
+		return
 	}
-	for _, r := range currentRoles {
-		if r == nil || r.Name == nil {
-			continue
-		}
-		if _, ok := newRolesMap[*r.Name]; !ok {
-			rolesToRemove = append(rolesToRemove, *r)
-		}
+	requestLogger(c).WithField("target_user_id", userID).WithField("enabled", *req.Enabled).Info("User state changed")
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteUser godoc
+// @Summary      Delete a user (admin only)
+// @Description  Deletes the user from Keycloak together with their lesson access, submissions, grades, certificates and uploaded files
+// @Tags         admin
+// @Param        user_id  path  string  true  "User ID"
+// @Success      204
+// @Failure      400  {object}  pkg.ErrorResponse
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Failure      502  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users/{user_id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "user_id")
+	if !ok {
+		return
 	}
 
-	// Удаляем роли, которых нет в списке новых
-	if len(rolesToRemove) > 0 {
-		err = client.DeleteRealmRoleFromUser(ctx, token.AccessToken, config.GetKeycloakRealm(), req.UserID, rolesToRemove)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove old roles"})
-			return
-		}
+	if err := h.users.DeleteUser(c.Request.Context(), userID); err != nil {
+		requestLogger(c).WithField("target_user_id", userID).WithError(err).Error("Failed to delete user")
+		c.Error(err)
+		return
 	}
+	requestLogger(c).WithField("target_user_id", userID).Info("User deleted")
+	c.Status(http.StatusNoContent)
+}
 
-	// Добавляем новые роли
-	if len(newRolesObjs) > 0 {
-		err = client.AddRealmRoleToUser(ctx, token.AccessToken, config.GetKeycloakRealm(), req.UserID, newRolesObjs)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add new roles"})
-			return
-		}
+// refreshProfile обновляет кэш профилей после изменения учётной записи. Ответ от этого не
+// зависит: при ошибке кэш догонит Keycloak при следующей сверке.
+func (h *UserHandler) refreshProfile(c *gin.Context, userID uuid.UUID) {
+	if err := h.users.RefreshUser(c.Request.Context(), userID); err != nil {
+		requestLogger(c).WithField("target_user_id", userID).WithError(err).Warn("Failed to refresh cached user profile")
 	}
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "User roles updated successfully"})
+func newUserResponse(profile *entities.UserProfile) UserResponse {
+	return UserResponse{
+		ID:        profile.ID,
+		Username:  profile.Username,
+		Email:     profile.Email,
+		FirstName: profile.FirstName,
+		LastName:  profile.LastName,
+		Enabled:   profile.Enabled,
+		CreatedAt: profile.CreatedAt,
+	}
 }
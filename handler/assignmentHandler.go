@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"io"
+	"lms-system-internship/entities"
+	"lms-system-internship/metrics"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GradeSubmissionRequest struct {
+	Score    *int   `json:"score" binding:"required"`
+	Feedback string `json:"feedback"`
+}
+
+type AssignmentHandler struct {
+	svc service.AssignmentService
+}
+
+func NewAssignmentHandler(svc service.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{svc: svc}
+}
+
+// CreateAssignment godoc
+// @Summary      Create an assignment
+// @Description  Adds an assignment with a due date, max score and allowed file types to a lesson
+// @Tags         assignments
+// @Accept       json
+// @Produce      json
+// @Param        assignment  body      entities.Assignment  true  "Assignment data"
+// @Success      201         {object}  entities.Assignment
+// @Failure      400         {object}  pkg.ErrorResponse
+// @Failure      404         {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/assignments [post]
+func (h *AssignmentHandler) CreateAssignment(c *gin.Context) {
+	var assignment entities.Assignment
+	if err := c.ShouldBindJSON(&assignment); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for new assignment")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	assignment.ID = 0
+
+	if err := h.svc.CreateAssignment(c.Request.Context(), &assignment); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to create assignment")
+		c.Error(err)
+		return
+	}
+	requestLogger(c).WithField("assignment_id", assignment.ID).Info("Assignment created successfully")
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// GetAssignmentsByLesson godoc
+// @Summary      List assignments of a lesson
+// @Description  Retrieves all assignments attached to a lesson
+// @Tags         assignments
+// @Produce      json
+// @Param        lesson_id  query     int  true  "Lesson ID"
+// @Success      200        {array}   entities.Assignment
+// @Failure      400        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/assignments [get]
+func (h *AssignmentHandler) GetAssignmentsByLesson(c *gin.Context) {
+	lessonID, err := strconv.ParseUint(c.Query("lesson_id"), 10, 64)
+	if err != nil {
+		requestLogger(c).WithField("lesson_id", c.Query("lesson_id")).Error("Invalid lesson ID format in query")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+
+	assignments, err := h.svc.GetAssignmentsByLesson(c.Request.Context(), uint(lessonID))
+	if err != nil {
+		requestLogger(c).WithError(err).Error("Failed to retrieve assignments")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, assignments)
+}
+
+// GetAssignment godoc
+// @Summary      Get an assignment by ID
+// @Tags         assignments
+// @Produce      json
+// @Param        assignment_id  path      int  true  "Assignment ID"
+// @Success      200            {object}  entities.Assignment
+// @Failure      400            {object}  pkg.ErrorResponse
+// @Failure      404            {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/assignments/{assignment_id} [get]
+func (h *AssignmentHandler) GetAssignment(c *gin.Context) {
+	id, ok := parseIDParam(c, "assignment_id")
+	if !ok {
+		return
+	}
+
+	assignment, err := h.svc.GetAssignment(c.Request.Context(), id)
+	if err != nil {
+		requestLogger(c).WithField("assignment_id", id).WithError(err).Error("Failed to retrieve assignment")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, assignment)
+}
+
+// DeleteAssignment godoc
+// @Summary      Delete an assignment
+// @Tags         assignments
+// @Param        assignment_id  path  int  true  "Assignment ID"
+// @Success      204
+// @Failure      400  {object}  pkg.ErrorResponse
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/assignments/{assignment_id} [delete]
+func (h *AssignmentHandler) DeleteAssignment(c *gin.Context) {
+	id, ok := parseIDParam(c, "assignment_id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteAssignment(c.Request.Context(), id); err != nil {
+		requestLogger(c).WithField("assignment_id", id).WithError(err).Error("Failed to delete assignment")
+		c.Error(err)
+		return
+	}
+	requestLogger(c).WithField("assignment_id", id).Info("Assignment deleted successfully")
+	c.Status(http.StatusNoContent)
+}
+
+// Submit godoc
+// @Summary      Submit work for an assignment
+// @Description  Uploads the student's file. Resubmission replaces the previous file until the due date; a first submission after the due date is accepted and flagged as late
+// @Tags         assignments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        assignment_id  path      int   true  "Assignment ID"
+// @Param        file           formData  file  true  "Submission file"
+// @Success      201            {object}  entities.Submission
+// @Failure      400            {object}  pkg.ErrorResponse
+// @Failure      403            {object}  pkg.ErrorResponse
+// @Failure      404            {object}  pkg.ErrorResponse
+// @Failure      409            {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/assignments/{assignment_id}/submissions [post]
+func (h *AssignmentHandler) Submit(c *gin.Context) {
+	id, ok := parseIDParam(c, "assignment_id")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		requestLogger(c).WithError(err).Error("Submission file is missing")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	defer file.Close()
+
+	fileBytes, err := io.ReadAll(file)
+	if err != nil {
+		requestLogger(c).WithError(err).Error("Failed to read submission file")
+		c.Error(err)
+		return
+	}
+
+	submission, err := h.svc.Submit(c.Request.Context(), userID, id, header.Filename, fileBytes)
+	if err != nil {
+		requestLogger(c).WithField("assignment_id", id).WithError(err).Error("Failed to submit assignment")
+		c.Error(err)
+		return
+	}
+	metrics.Uploads.WithLabelValues("submission").Inc()
+	requestLogger(c).WithField("submission_id", submission.ID).Info("Assignment submitted")
+	c.JSON(http.StatusCreated, submission)
+}
+
+// GetMySubmission godoc
+// @Summary      Get own submission
+// @Description  Returns the current user's submission with score and feedback
+// @Tags         assignments
+// @Produce      json
+// @Param        assignment_id  path      int  true  "Assignment ID"
+// @Success      200            {object}  entities.Submission
+// @Failure      404            {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/assignments/{assignment_id}/submissions/me [get]
+func (h *AssignmentHandler) GetMySubmission(c *gin.Context) {
+	id, ok := parseIDParam(c, "assignment_id")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	submission, err := h.svc.GetMySubmission(c.Request.Context(), userID, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, submission)
+}
+
+// GetSubmissions godoc
+// @Summary      List submissions of an assignment
+// @Tags         assignments
+// @Produce      json
+// @Param        assignment_id  path      int  true  "Assignment ID"
+// @Success      200            {array}   entities.Submission
+// @Failure      404            {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/assignments/{assignment_id}/submissions [get]
+func (h *AssignmentHandler) GetSubmissions(c *gin.Context) {
+	id, ok := parseIDParam(c, "assignment_id")
+	if !ok {
+		return
+	}
+
+	submissions, err := h.svc.GetSubmissions(c.Request.Context(), id)
+	if err != nil {
+		requestLogger(c).WithField("assignment_id", id).WithError(err).Error("Failed to retrieve submissions")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, submissions)
+}
+
+// DownloadSubmission godoc
+// @Summary      Download a submission file
+// @Tags         assignments
+// @Produce      application/octet-stream
+// @Param        submission_id  path  int  true  "Submission ID"
+// @Success      200            {file}  file
+// @Failure      404            {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/submissions/{submission_id}/download [get]
+func (h *AssignmentHandler) DownloadSubmission(c *gin.Context) {
+	id, ok := parseIDParam(c, "submission_id")
+	if !ok {
+		return
+	}
+
+	data, fileName, err := h.svc.DownloadSubmission(c.Request.Context(), id)
+	if err != nil {
+		requestLogger(c).WithField("submission_id", id).WithError(err).Error("Failed to download submission")
+		c.Error(err)
+		return
+	}
+	metrics.Downloads.WithLabelValues("submission").Inc()
+
+	c.Header("Content-Disposition", "attachment; filename=\""+fileName+"\"")
+	c.Data(http.StatusOK, "application/octet-stream", data)
+}
+
+// GradeSubmission godoc
+// @Summary      Grade a submission
+// @Description  Sets the score (0..max_score) and feedback for a submission
+// @Tags         assignments
+// @Accept       json
+// @Produce      json
+// @Param        submission_id  path      int                             true  "Submission ID"
+// @Param        grade          body      handler.GradeSubmissionRequest  true  "Score and feedback"
+// @Success      200            {object}  entities.Submission
+// @Failure      400            {object}  pkg.ErrorResponse
+// @Failure      404            {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/submissions/{submission_id}/grade [put]
+func (h *AssignmentHandler) GradeSubmission(c *gin.Context) {
+	id, ok := parseIDParam(c, "submission_id")
+	if !ok {
+		return
+	}
+
+	graderID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req GradeSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for grading")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+
+	submission, err := h.svc.GradeSubmission(c.Request.Context(), graderID, id, *req.Score, req.Feedback)
+	if err != nil {
+		requestLogger(c).WithField("submission_id", id).WithError(err).Error("Failed to grade submission")
+		c.Error(err)
+		return
+	}
+	requestLogger(c).WithField("submission_id", id).Info("Submission graded")
+	c.JSON(http.StatusOK, submission)
+}
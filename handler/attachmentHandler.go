@@ -1,8 +1,9 @@
 package handler
 
 import (
-	"github.com/google/uuid"
 	"io"
+	"lms-system-internship/entities"
+	"lms-system-internship/metrics"
 	"lms-system-internship/pkg"
 	"net/http"
 	"strconv"
@@ -13,11 +14,12 @@ import (
 )
 
 type AttachmentHandler struct {
-	service service.AttachmentService
+	service  service.AttachmentService
+	versions service.CourseVersionService
 }
 
-func NewAttachmentHandler(s service.AttachmentService) *AttachmentHandler {
-	return &AttachmentHandler{service: s}
+func NewAttachmentHandler(s service.AttachmentService, versions service.CourseVersionService) *AttachmentHandler {
+	return &AttachmentHandler{service: s, versions: versions}
 }
 
 // UploadFile godoc
@@ -29,79 +31,97 @@ func NewAttachmentHandler(s service.AttachmentService) *AttachmentHandler {
 // @Param lesson_id formData int true "ID урока"
 // @Param file formData file true "Файл для загрузки"
 // @Success 201 {object} entities.Attachment
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} pkg.ErrorResponse
+// @Failure 404 {object} pkg.ErrorResponse
+// @Failure 500 {object} pkg.ErrorResponse
 // @Security BearerAuth
 // @Router /attachments/upload [post]
 func (h *AttachmentHandler) UploadFile(c *gin.Context) {
 	lessonIDStr := c.PostForm("lesson_id")
 	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lesson_id"})
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "lesson_id", Code: "required"}))
 		return
 	}
 
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "file", Code: "required"}))
 		return
 	}
 	defer file.Close()
 
 	fileBytes, err := io.ReadAll(file)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
+		c.Error(pkg.ErrInternal.Wrap(err))
 		return
 	}
 
 	attachment, err := h.service.UploadFile(c.Request.Context(), uint(lessonID), header.Filename, fileBytes)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		requestLogger(c).WithField("lesson_id", lessonID).WithError(err).Error("Failed to upload attachment")
+		c.Error(err)
 		return
 	}
+	metrics.Uploads.WithLabelValues("attachment").Inc()
 
 	c.JSON(http.StatusCreated, attachment)
 }
 
 // DownloadFile godoc
 // @Summary Скачивание файла по ID вложения
-// @Description Отправляет файл, если у пользователя есть доступ к уроку
+// @Description Отправляет файл, если у пользователя есть доступ к уроку. Студент получает файл из закреплённой за ним версии курса
 // @Tags attachments
 // @Produce application/octet-stream
 // @Param attachment_id path int true "ID вложения"
 // @Success 200 {file} file
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 403 {object} map[string]string
+// @Failure 400 {object} pkg.ErrorResponse
+// @Failure 401 {object} pkg.ErrorResponse
+// @Failure 403 {object} pkg.ErrorResponse
+// @Failure 404 {object} pkg.ErrorResponse
 // @Security BearerAuth
 // @Router /attachments/download/{attachment_id} [get]
 func (h *AttachmentHandler) DownloadFile(c *gin.Context) {
-	attachmentIDParam := c.Param("attachment_id")
-	attachmentID, err := strconv.ParseUint(attachmentIDParam, 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment ID"})
-		return
-	}
-
-	userIDValue, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized: userID not found"})
+	attachmentID, ok := parseIDParam(c, "attachment_id")
+	if !ok {
 		return
 	}
-	userID, ok := userIDValue.(uuid.UUID)
+	userID, ok := currentUserID(c)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid userID format"})
 		return
 	}
-	pkg.Logger.Debugf("userID (parsed from context):", userID)
+
+	// Студент получает файл из закреплённой версии курса, если вложение в неё попало
+	var pinned *entities.Attachment
+	if !hasAnyRole(c, "ROLE_ADMIN", "ROLE_TEACHER") {
+		var err error
+		pinned, err = h.versions.PinnedAttachment(c.Request.Context(), userID, attachmentID)
+		if err != nil {
+			requestLogger(c).WithField("attachment_id", attachmentID).WithError(err).Error("Failed to resolve pinned attachment")
+			c.Error(err)
+			return
+		}
+	}
+
 	// Скачиваем файл
-	data, fileName, err := h.service.DownloadFile(c.Request.Context(), userID, uint(attachmentID))
+	var (
+		data     []byte
+		fileName string
+		err      error
+	)
+	if pinned != nil {
+		data, fileName, err = h.service.DownloadAttachment(c.Request.Context(), userID, pinned)
+	} else {
+		data, fileName, err = h.service.DownloadFile(c.Request.Context(), userID, attachmentID)
+	}
 	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		requestLogger(c).WithField("attachment_id", attachmentID).WithError(err).Error("Failed to download attachment")
+		c.Error(err)
 		return
 	}
 
 	// Отправляем файл
+	metrics.Downloads.WithLabelValues("attachment").Inc()
 	c.Header("Content-Disposition", "attachment; filename=\""+fileName+"\"")
 	c.Data(http.StatusOK, "application/octet-stream", data)
 }
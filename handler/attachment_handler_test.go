@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"lms-system-internship/entities"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+)
+
+func TestAttachmentHandler_DownloadFile(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(mocks.AttachmentService)
+		mockService.On("DownloadFile", mock.Anything, userID, uint(1)).Return([]byte("data"), "notes.pdf", nil)
+
+		handler := NewAttachmentHandler(mockService, unpublishedVersions())
+		router := setupRouter()
+		router.Use(withUser(userID))
+		router.GET("/api/attachments/download/:attachment_id", handler.DownloadFile)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/attachments/download/1", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "data", resp.Body.String())
+		assert.Contains(t, resp.Header().Get("Content-Disposition"), "notes.pdf")
+	})
+
+	t.Run("student gets pinned file", func(t *testing.T) {
+		mockService := new(mocks.AttachmentService)
+		versions := new(mocks.CourseVersionService)
+		pinned := &entities.Attachment{ID: 1, Name: "notes.pdf", URL: "versions/1/copy.pdf", LessonID: 2}
+		versions.On("PinnedAttachment", mock.Anything, userID, uint(1)).Return(pinned, nil)
+		mockService.On("DownloadAttachment", mock.Anything, userID, pinned).Return([]byte("published"), "notes.pdf", nil)
+
+		handler := NewAttachmentHandler(mockService, versions)
+		router := setupRouter()
+		router.Use(withUser(userID))
+		router.GET("/api/attachments/download/:attachment_id", handler.DownloadFile)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/attachments/download/1", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "published", resp.Body.String())
+		mockService.AssertNotCalled(t, "DownloadFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService := new(mocks.AttachmentService)
+		mockService.On("DownloadFile", mock.Anything, userID, uint(1)).Return(nil, "", pkg.ErrAttachmentNotFound)
+
+		handler := NewAttachmentHandler(mockService, unpublishedVersions())
+		router := setupRouter()
+		router.Use(withUser(userID))
+		router.GET("/api/attachments/download/:attachment_id", handler.DownloadFile)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/attachments/download/1", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"code":"attachment_not_found"`)
+	})
+
+	t.Run("no access", func(t *testing.T) {
+		mockService := new(mocks.AttachmentService)
+		mockService.On("DownloadFile", mock.Anything, userID, uint(1)).Return(nil, "", pkg.ErrForbidden)
+
+		handler := NewAttachmentHandler(mockService, unpublishedVersions())
+		router := setupRouter()
+		router.Use(withUser(userID))
+		router.GET("/api/attachments/download/:attachment_id", handler.DownloadFile)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/attachments/download/1", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		handler := NewAttachmentHandler(new(mocks.AttachmentService), unpublishedVersions())
+		router := setupRouter()
+		router.GET("/api/attachments/download/:attachment_id", handler.DownloadFile)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/attachments/download/1", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"code":"unauthorized"`)
+	})
+}
@@ -1,11 +1,12 @@
 package handler
 
 import (
-	"encoding/json"
-	"io"
+	"errors"
+	"lms-system-internship/metrics"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
 	"net/http"
-	"net/url"
-	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -28,9 +29,36 @@ type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// SessionResponse — активная сессия пользователя; current отмечает сессию текущего токена.
+type SessionResponse struct {
+	ID           string    `json:"id"`
+	IPAddress    string    `json:"ip_address,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+	LastAccessAt time.Time `json:"last_access_at"`
+	Clients      []string  `json:"clients"`
+	Current      bool      `json:"current"`
+}
+
+type AuthHandler struct {
+	provider service.IdentityProvider
+}
+
+func NewAuthHandler(provider service.IdentityProvider) *AuthHandler {
+	return &AuthHandler{provider: provider}
+}
+
+func newTokenResponse(tokens *service.Tokens) TokenResponse {
+	return TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		TokenType:    tokens.TokenType,
+	}
+}
+
 //
 
-// LoginHandler godoc
+// Login godoc
 // @Summary Аутентификация пользователя
 // @Description Получить JWT токены по имени пользователя и паролю
 // @Tags auth
@@ -38,55 +66,37 @@ type RefreshRequest struct {
 // @Produce json
 // @Param login body LoginRequest true "Данные для входа"
 // @Success 200 {object} TokenResponse
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} pkg.ErrorResponse
+// @Failure 401 {object} pkg.ErrorResponse
+// @Failure 502 {object} pkg.ErrorResponse
 // @Router /auth/login [post]
-func LoginHandler(c *gin.Context) {
+func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
-	form := url.Values{}
-	form.Set("grant_type", "password")
-	form.Set("client_id", "backend-client")
-	form.Set("username", req.Username)
-	form.Set("password", req.Password)
-
-	keycloakTokenURL := "http://keycloak:8080/realms/lms/protocol/openid-connect/token"
-
-	resp, err := http.Post(
-		keycloakTokenURL,
-		"application/x-www-form-urlencoded",
-		strings.NewReader(form.Encode()),
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to connect to Keycloak"})
-		return
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != 200 {
-		c.JSON(resp.StatusCode, gin.H{"error": "Login failed", "details": string(body)})
-		return
+	tokens, err := h.provider.Login(c.Request.Context(), req.Username, req.Password)
+	switch {
+	case err == nil:
+		metrics.Logins.WithLabelValues("success").Inc()
+	case errors.Is(err, pkg.ErrInvalidCredentials):
+		metrics.Logins.WithLabelValues("rejected").Inc()
+	default:
+		metrics.Logins.WithLabelValues("error").Inc()
 	}
-
-	var tokenResp TokenResponse
-	if err := json.Unmarshal(body, &tokenResp); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse token response"})
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, tokenResp)
+	c.JSON(http.StatusOK, newTokenResponse(tokens))
 }
 
 //
 
-// RefreshTokenHandler godoc
+// Refresh godoc
 // @Summary Обновление токена
 // @Description Получить новые access и refresh токены по refresh_token
 // @Tags auth
@@ -94,47 +104,136 @@ func LoginHandler(c *gin.Context) {
 // @Produce json
 // @Param refresh body RefreshRequest true "Refresh токен"
 // @Success 200 {object} TokenResponse
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} pkg.ErrorResponse
+// @Failure 401 {object} pkg.ErrorResponse
+// @Failure 502 {object} pkg.ErrorResponse
 // @Router /auth/refresh [post]
-func RefreshTokenHandler(c *gin.Context) {
+func (h *AuthHandler) Refresh(c *gin.Context) {
 	var req RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
-	form := url.Values{}
-	form.Set("grant_type", "refresh_token")
-	form.Set("client_id", "backend-client")
-	form.Set("refresh_token", req.RefreshToken)
+	tokens, err := h.provider.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-	keycloakTokenURL := "http://keycloak:8080/realms/lms/protocol/openid-connect/token"
+	c.JSON(http.StatusOK, newTokenResponse(tokens))
+}
 
-	resp, err := http.Post(
-		keycloakTokenURL,
-		"application/x-www-form-urlencoded",
-		strings.NewReader(form.Encode()),
-	)
+// Logout godoc
+// @Summary Выход
+// @Description Завершает сессию, к которой относится refresh_token; её токены перестают обновляться
+// @Tags auth
+// @Accept json
+// @Param refresh body RefreshRequest true "Refresh токен"
+// @Success 204
+// @Failure 400 {object} pkg.ErrorResponse
+// @Failure 401 {object} pkg.ErrorResponse
+// @Failure 502 {object} pkg.ErrorResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "refresh_token", Code: "required"}))
+		return
+	}
+
+	if err := h.provider.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListMySessions godoc
+// @Summary      List own sessions
+// @Description  Returns the current user's active sessions at the identity provider
+// @Tags         auth
+// @Produce      json
+// @Success      200  {array}   handler.SessionResponse
+// @Failure      401  {object}  pkg.ErrorResponse
+// @Failure      502  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me/sessions [get]
+func (h *AuthHandler) ListMySessions(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.provider.Sessions(c.Request.Context(), principal.ID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to connect to Keycloak"})
+		requestLogger(c).WithError(err).Error("Failed to list sessions")
+		c.Error(err)
 		return
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	response := make([]SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		response = append(response, SessionResponse{
+			ID:           s.ID,
+			IPAddress:    s.IPAddress,
+			StartedAt:    s.StartedAt,
+			LastAccessAt: s.LastAccessAt,
+			Clients:      s.Clients,
+			Current:      s.ID == principal.SessionID,
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
 
-	if resp.StatusCode != 200 {
-		c.JSON(resp.StatusCode, gin.H{"error": "Token refresh failed", "details": string(body)})
+// RevokeMySession godoc
+// @Summary      End own session
+// @Description  Ends one of the current user's sessions, for example on a lost device
+// @Tags         auth
+// @Param        session_id  path  string  true  "Session ID"
+// @Success      204
+// @Failure      401  {object}  pkg.ErrorResponse
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Failure      502  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me/sessions/{session_id} [delete]
+func (h *AuthHandler) RevokeMySession(c *gin.Context) {
+	principal, ok := currentPrincipal(c)
+	if !ok {
 		return
 	}
 
-	var tokenResp TokenResponse
-	if err := json.Unmarshal(body, &tokenResp); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse token response"})
+	if err := h.provider.RevokeSession(c.Request.Context(), principal.ID, c.Param("session_id")); err != nil {
+		requestLogger(c).WithField("session_id", c.Param("session_id")).WithError(err).Error("Failed to end session")
+		c.Error(err)
 		return
 	}
+	requestLogger(c).WithField("session_id", c.Param("session_id")).Info("Session ended")
+	c.Status(http.StatusNoContent)
+}
 
-	c.JSON(http.StatusOK, tokenResp)
+// RevokeUserSessions godoc
+// @Summary      End all sessions of a user (admin only)
+// @Description  Signs the user out everywhere, for example after their roles were changed
+// @Tags         admin
+// @Param        user_id  path  string  true  "User ID"
+// @Success      204
+// @Failure      400  {object}  pkg.ErrorResponse
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Failure      502  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/users/{user_id}/sessions [delete]
+func (h *AuthHandler) RevokeUserSessions(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	if err := h.provider.RevokeAllSessions(c.Request.Context(), userID); err != nil {
+		requestLogger(c).WithField("target_user_id", userID).WithError(err).Error("Failed to end user sessions")
+		c.Error(err)
+		return
+	}
+	requestLogger(c).WithField("target_user_id", userID).Info("All user sessions ended")
+	c.Status(http.StatusNoContent)
 }
@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"lms-system-internship/middleware"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthHandler_LocalProvider(t *testing.T) {
+	provider := service.NewLocalIdentityProvider([]byte("test-secret"), time.Minute, time.Hour,
+		[]service.LocalUser{{Username: "teacher", Password: "secret", Roles: []string{"ROLE_TEACHER"}}})
+	handler := NewAuthHandler(provider)
+
+	router := setupRouter()
+	router.POST("/api/auth/login", handler.Login)
+	router.POST("/api/auth/refresh", handler.Refresh)
+	protected := router.Group("/api", middleware.TokenAuthMiddleware(provider))
+	protected.GET("/whoami", func(c *gin.Context) {
+		principal, _ := pkg.PrincipalFromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"user_id": principal.ID, "roles": principal.Roles})
+	})
+
+	login := func(username, password string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(LoginRequest{Username: username, Password: password})
+		req, _ := http.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewReader(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("login and call a protected route", func(t *testing.T) {
+		resp := login("teacher", "secret")
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var tokens TokenResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &tokens))
+		assert.Equal(t, "Bearer", tokens.TokenType)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/whoami", nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+		resp = httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"user_id":"`+service.LocalUserID("teacher").String()+`","roles":["ROLE_TEACHER"]}`, resp.Body.String())
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		resp := login("teacher", "wrong")
+
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		assert.Contains(t, resp.Body.String(), "invalid_credentials")
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		var tokens TokenResponse
+		_ = json.Unmarshal(login("teacher", "secret").Body.Bytes(), &tokens)
+
+		body, _ := json.Marshal(RefreshRequest{RefreshToken: tokens.RefreshToken})
+		req, _ := http.NewRequest(http.MethodPost, "/api/auth/refresh", bytes.NewReader(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("invalid token is rejected", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/api/whoami", nil)
+		req.Header.Set("Authorization", "Bearer not-a-token")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+		assert.Contains(t, resp.Body.String(), "invalid_token")
+		assert.NotContains(t, resp.Body.String(), "segment", "the parser error stays in the log")
+		assert.Equal(t, `Bearer error="invalid_token"`, resp.Header().Get("WWW-Authenticate"))
+	})
+}
+
+func TestAuthHandler_Sessions(t *testing.T) {
+	provider := service.NewLocalIdentityProvider([]byte("test-secret"), time.Minute, time.Hour,
+		[]service.LocalUser{{Username: "alice", Password: "secret"}, {Username: "bob", Password: "secret"}})
+	handler := NewAuthHandler(provider)
+
+	router := setupRouter()
+	router.POST("/api/auth/logout", handler.Logout)
+	protected := router.Group("/api", middleware.TokenAuthMiddleware(provider))
+	protected.GET("/me/sessions", handler.ListMySessions)
+	protected.DELETE("/me/sessions/:session_id", handler.RevokeMySession)
+	router.DELETE("/api/admin/users/:user_id/sessions", handler.RevokeUserSessions)
+
+	do := func(method, path, accessToken string, body any) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewReader(payload))
+		if accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	ctx := context.Background()
+
+	t.Run("list marks the current session", func(t *testing.T) {
+		current, _ := provider.Login(ctx, "alice", "secret")
+		_, _ = provider.Login(ctx, "alice", "secret")
+
+		resp := do(http.MethodGet, "/api/me/sessions", current.AccessToken, nil)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var sessions []SessionResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &sessions))
+		assert.Len(t, sessions, 2)
+		currentCount := 0
+		for _, s := range sessions {
+			if s.Current {
+				currentCount++
+			}
+		}
+		assert.Equal(t, 1, currentCount)
+	})
+
+	t.Run("cannot end another user's session", func(t *testing.T) {
+		alice, _ := provider.Login(ctx, "alice", "secret")
+		bob, _ := provider.Login(ctx, "bob", "secret")
+		bobPrincipal, _ := provider.Verify(ctx, bob.AccessToken)
+
+		resp := do(http.MethodDelete, "/api/me/sessions/"+bobPrincipal.SessionID, alice.AccessToken, nil)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Contains(t, resp.Body.String(), "session_not_found")
+	})
+
+	t.Run("logout", func(t *testing.T) {
+		tokens, _ := provider.Login(ctx, "alice", "secret")
+
+		resp := do(http.MethodPost, "/api/auth/logout", "", RefreshRequest{RefreshToken: tokens.RefreshToken})
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+
+		resp = do(http.MethodGet, "/api/me/sessions", tokens.AccessToken, nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+
+	t.Run("logout without a token", func(t *testing.T) {
+		resp := do(http.MethodPost, "/api/auth/logout", "", RefreshRequest{})
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("admin ends all sessions of a user", func(t *testing.T) {
+		tokens, _ := provider.Login(ctx, "bob", "secret")
+
+		resp := do(http.MethodDelete, "/api/admin/users/"+service.LocalUserID("bob").String()+"/sessions", "", nil)
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+
+		_, err := provider.Verify(ctx, tokens.AccessToken)
+		assert.Error(t, err)
+	})
+}
+
+func TestRequireRoles(t *testing.T) {
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+
+	cases := []struct {
+		name      string
+		principal *pkg.Principal
+		status    int
+	}{
+		{"realm role", &pkg.Principal{ID: uuid.New(), Roles: []string{"ROLE_TEACHER"}}, http.StatusNoContent},
+		{"missing role", &pkg.Principal{ID: uuid.New(), Roles: []string{"ROLE_STUDENT"}}, http.StatusForbidden},
+		{"client role does not count as realm role", &pkg.Principal{ID: uuid.New(), ClientRoles: map[string][]string{"backend-client": {"ROLE_TEACHER"}}}, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := setupRouter()
+			router.GET("/teachers-only", withPrincipal(tc.principal), middleware.RequireRoles("ROLE_ADMIN", "ROLE_TEACHER"), ok)
+
+			req, _ := http.NewRequest(http.MethodGet, "/teachers-only", nil)
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.status, resp.Code)
+		})
+	}
+
+	t.Run("anonymous request", func(t *testing.T) {
+		router := setupRouter()
+		router.GET("/teachers-only", middleware.RequireRoles("ROLE_TEACHER"), ok)
+
+		req, _ := http.NewRequest(http.MethodGet, "/teachers-only", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
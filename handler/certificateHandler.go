@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"github.com/google/uuid"
+	"lms-system-internship/entities"
+	"lms-system-internship/metrics"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IssueCertificateRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+type CertificateVerificationResponse struct {
+	Valid       bool      `json:"valid"`
+	Code        string    `json:"code"`
+	StudentName string    `json:"student_name"`
+	CourseName  string    `json:"course_name"`
+	IssuedAt    time.Time `json:"issued_at"`
+}
+
+type CertificateHandler struct {
+	svc service.CertificateService
+}
+
+func NewCertificateHandler(svc service.CertificateService) *CertificateHandler {
+	return &CertificateHandler{svc: svc}
+}
+
+// GetRule godoc
+// @Summary      Get course completion rule
+// @Tags         certificates
+// @Produce      json
+// @Param        course_id  path      int  true  "Course ID"
+// @Success      200        {object}  entities.CompletionRule
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/completion-rule [get]
+func (h *CertificateHandler) GetRule(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	rule, err := h.svc.GetRule(c.Request.Context(), courseID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// SetRule godoc
+// @Summary      Set course completion rule
+// @Description  Configures when students may claim a certificate: all lessons accessible and/or not before a date
+// @Tags         certificates
+// @Accept       json
+// @Produce      json
+// @Param        course_id  path      int                      true  "Course ID"
+// @Param        rule       body      entities.CompletionRule  true  "Completion rule"
+// @Success      200        {object}  entities.CompletionRule
+// @Failure      400        {object}  pkg.ErrorResponse
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/completion-rule [put]
+func (h *CertificateHandler) SetRule(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	var rule entities.CompletionRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for completion rule")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	rule.CourseID = courseID
+
+	if err := h.svc.SetRule(c.Request.Context(), &rule); err != nil {
+		requestLogger(c).WithField("course_id", courseID).WithError(err).Error("Failed to save completion rule")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// IssueCertificate godoc
+// @Summary      Mark a student as completed
+// @Description  Issues a certificate for the student regardless of the completion rule
+// @Tags         certificates
+// @Accept       json
+// @Produce      json
+// @Param        course_id  path      int                              true  "Course ID"
+// @Param        request    body      handler.IssueCertificateRequest  true  "Student"
+// @Success      201        {object}  entities.Certificate
+// @Failure      400        {object}  pkg.ErrorResponse
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/certificates [post]
+func (h *CertificateHandler) IssueCertificate(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req IssueCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	studentID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		requestLogger(c).WithField("user_id", req.UserID).Error("Invalid user ID format")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+
+	certificate, err := h.svc.IssueCertificate(c.Request.Context(), teacherID, courseID, studentID)
+	if err != nil {
+		requestLogger(c).WithField("course_id", courseID).WithError(err).Error("Failed to issue certificate")
+		c.Error(err)
+		return
+	}
+	requestLogger(c).WithField("certificate_id", certificate.ID).Info("Certificate issued")
+	c.JSON(http.StatusCreated, certificate)
+}
+
+// ClaimCertificate godoc
+// @Summary      Claim a course certificate
+// @Description  Issues a certificate to the current user if the course completion rule is satisfied
+// @Tags         certificates
+// @Produce      json
+// @Param        course_id  path      int  true  "Course ID"
+// @Success      201        {object}  entities.Certificate
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Failure      409        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/certificates/claim [post]
+func (h *CertificateHandler) ClaimCertificate(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	certificate, err := h.svc.ClaimCertificate(c.Request.Context(), userID, courseID)
+	if err != nil {
+		requestLogger(c).WithField("course_id", courseID).WithError(err).Warn("Certificate claim rejected")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, certificate)
+}
+
+// GetMyCertificates godoc
+// @Summary      List own certificates
+// @Tags         certificates
+// @Produce      json
+// @Success      200  {array}  entities.Certificate
+// @Security     BearerAuth
+// @Router       /api/me/certificates [get]
+func (h *CertificateHandler) GetMyCertificates(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	certificates, err := h.svc.GetUserCertificates(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, certificates)
+}
+
+// DownloadCertificate godoc
+// @Summary      Download a certificate PDF
+// @Description  Students can download their own certificates, teachers and admins any certificate
+// @Tags         certificates
+// @Produce      application/pdf
+// @Param        certificate_id  path  int  true  "Certificate ID"
+// @Success      200  {file}    file
+// @Failure      403  {object}  pkg.ErrorResponse
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/certificates/{certificate_id}/download [get]
+func (h *CertificateHandler) DownloadCertificate(c *gin.Context) {
+	id, ok := parseIDParam(c, "certificate_id")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	privileged := hasAnyRole(c, "ROLE_ADMIN", "ROLE_TEACHER")
+	data, fileName, err := h.svc.DownloadCertificate(c.Request.Context(), userID, id, privileged)
+	if err != nil {
+		requestLogger(c).WithField("certificate_id", id).WithError(err).Error("Failed to download certificate")
+		c.Error(err)
+		return
+	}
+	metrics.Downloads.WithLabelValues("certificate").Inc()
+
+	c.Header("Content-Disposition", "attachment; filename=\""+fileName+"\"")
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// VerifyCertificate godoc
+// @Summary      Verify a certificate
+// @Description  Public endpoint: checks a verification code printed on a certificate
+// @Tags         certificates
+// @Produce      json
+// @Param        code  path      string  true  "Verification code"
+// @Success      200   {object}  handler.CertificateVerificationResponse
+// @Failure      404   {object}  pkg.ErrorResponse
+// @Router       /api/certificates/verify/{code} [get]
+func (h *CertificateHandler) VerifyCertificate(c *gin.Context) {
+	certificate, err := h.svc.VerifyCertificate(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, CertificateVerificationResponse{
+		Valid:       true,
+		Code:        certificate.Code,
+		StudentName: certificate.StudentName,
+		CourseName:  certificate.CourseName,
+		IssuedAt:    certificate.IssuedAt,
+	})
+}
@@ -10,6 +10,22 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// CreateChapterRequest — данные новой главы; курс задаётся query-параметром course_id.
+type CreateChapterRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description" binding:"max=10000"`
+	Order       int    `json:"order" binding:"min=0,max=10000"`
+}
+
+type UpdateChapterOrderRequest struct {
+	Order int `json:"order" binding:"min=0,max=10000"`
+}
+
+type UpdateChapterDetailsRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description" binding:"max=10000"`
+}
+
 type ChapterHandler struct {
 	svc service.ChapterService
 }
@@ -23,18 +39,18 @@ func NewChapterHandler(svc service.ChapterService) *ChapterHandler {
 // @Description  Retrieves a list of all chapters
 // @Tags         chapters
 // @Produce      json
-// @Success      200  {array}   entities.Chapter
+// @Success      200  {array}   handler.ChapterResponse
 // @Failure      500  {object}  pkg.ErrorResponse
 // @Router       /api/chapters [get]
 func (h *ChapterHandler) GetAllChapters(c *gin.Context) {
 	chapters, err := h.svc.GetAllChapters(c.Request.Context())
 	if err != nil {
-		pkg.Logger.WithError(err).Error("Failed to retrieve all chapters")
+		requestLogger(c).WithError(err).Error("Failed to retrieve all chapters")
 		c.Error(err)
 		return
 	}
-	pkg.Logger.Info("Retrieved all chapters")
-	c.JSON(http.StatusOK, chapters)
+	requestLogger(c).Info("Retrieved all chapters")
+	c.JSON(http.StatusOK, newChapterResponses(chapters))
 }
 
 // GetChapter godoc
@@ -43,26 +59,26 @@ func (h *ChapterHandler) GetAllChapters(c *gin.Context) {
 // @Tags         chapters
 // @Produce      json
 // @Param        chapter_id  path      int  true  "Chapter ID"
-// @Success      200  {object}  entities.Chapter
+// @Success      200  {object}  handler.ChapterResponse
 // @Failure      400  {object}  pkg.ErrorResponse
 // @Failure      404  {object}  pkg.ErrorResponse
 // @Router       /api/chapters/{chapter_id} [get]
 func (h *ChapterHandler) GetChapter(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("chapter_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("chapter_id", c.Param("chapter_id")).Error("Invalid chapter ID format")
+		requestLogger(c).WithField("chapter_id", c.Param("chapter_id")).Error("Invalid chapter ID format")
 		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
 	chapter, err := h.svc.GetChapter(c.Request.Context(), uint(id))
 	if err != nil {
-		pkg.Logger.WithField("chapter_id", id).Error("Chapter not found")
-		c.Error(pkg.ErrChapterNotFound)
+		requestLogger(c).WithField("chapter_id", id).WithError(err).Error("Failed to get chapter")
+		c.Error(err)
 		return
 	}
-	pkg.Logger.WithField("chapter_id", id).Info("Retrieved chapter details")
-	c.JSON(http.StatusOK, chapter)
+	requestLogger(c).WithField("chapter_id", id).Info("Retrieved chapter details")
+	c.JSON(http.StatusOK, newChapterResponse(chapter))
 }
 
 // CreateChapter godoc
@@ -71,37 +87,36 @@ func (h *ChapterHandler) GetChapter(c *gin.Context) {
 // @Tags         chapters
 // @Accept       json
 // @Produce      json
-// @Param        course_id  query     int               true  "Course ID"
-// @Param        chapter    body      entities.Chapter  true  "Chapter data"
-// @Success      201  {object}  entities.Chapter
+// @Param        course_id  query     int                           true  "Course ID"
+// @Param        chapter    body      handler.CreateChapterRequest  true  "Chapter data"
+// @Success      201  {object}  handler.ChapterResponse
 // @Failure      400  {object}  pkg.ErrorResponse
 // @Failure      500  {object}  pkg.ErrorResponse
 // @Router       /api/chapters [post]
 func (h *ChapterHandler) CreateChapter(c *gin.Context) {
 	courseID, err := strconv.ParseUint(c.Query("course_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("course_id", c.Query("course_id")).Error("Invalid course ID format in query")
-		c.Error(pkg.ErrInvalidInput)
+		requestLogger(c).WithField("course_id", c.Query("course_id")).Error("Invalid course ID format in query")
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "course_id", Code: "required"}))
 		return
 	}
 
-	var chapter entities.Chapter
-	if err2 := c.ShouldBindJSON(&chapter); err2 != nil {
-		pkg.Logger.Error("Invalid JSON input while creating chapter")
-		c.Error(pkg.ErrInvalidInput)
+	var req CreateChapterRequest
+	if !bindJSON(c, &req) {
 		return
 	}
+	chapter := entities.Chapter{Name: req.Name, Description: req.Description, Order: req.Order}
 
 	if err3 := h.svc.AddChapterToCourse(c.Request.Context(), uint(courseID), &chapter); err3 != nil {
-		pkg.Logger.WithError(err).Error("Failed to add chapter to course")
-		c.Error(err)
+		requestLogger(c).WithError(err3).Error("Failed to add chapter to course")
+		c.Error(err3)
 		return
 	}
-	pkg.Logger.WithFields(map[string]interface{}{
-		"course_id": courseID,
-		"chapter":   chapter,
+	requestLogger(c).WithFields(map[string]interface{}{
+		"course_id":  courseID,
+		"chapter_id": chapter.ID,
 	}).Debug("Chapter created successfully")
-	c.JSON(http.StatusCreated, chapter)
+	c.JSON(http.StatusCreated, newChapterResponse(&chapter))
 }
 
 // UpdateChapterOrder godoc
@@ -111,7 +126,7 @@ func (h *ChapterHandler) CreateChapter(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        chapter_id  path      int  true  "Chapter ID"
-// @Param        order       body      map[string]int  true  "New order value, e.g. {\"order\": 2}"
+// @Param        order       body      handler.UpdateChapterOrderRequest  true  "New order value"
 // @Success      200
 // @Failure      400  {object}  pkg.ErrorResponse
 // @Failure      404  {object}  pkg.ErrorResponse
@@ -119,27 +134,23 @@ func (h *ChapterHandler) CreateChapter(c *gin.Context) {
 func (h *ChapterHandler) UpdateChapterOrder(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("chapter_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("chapter_id", c.Param("chapter_id")).Error("Invalid chapter ID format")
+		requestLogger(c).WithField("chapter_id", c.Param("chapter_id")).Error("Invalid chapter ID format")
 		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
-	var payload struct {
-		Order int `json:"order"`
-	}
-	if err2 := c.ShouldBindJSON(&payload); err2 != nil {
-		pkg.Logger.Error("Invalid JSON input while updating chapter order")
-		c.Error(pkg.ErrInvalidInput)
+	var payload UpdateChapterOrderRequest
+	if !bindJSON(c, &payload) {
 		return
 	}
 
 	if err3 := h.svc.UpdateChapterOrder(c.Request.Context(), uint(id), payload.Order); err3 != nil {
-		pkg.Logger.WithField("chapter_id", id).Error("Chapter not found while updating order")
-		c.Error(pkg.ErrChapterNotFound)
+		requestLogger(c).WithField("chapter_id", id).WithError(err3).Error("Failed to update chapter order")
+		c.Error(err3)
 		return
 	}
 
-	pkg.Logger.WithFields(map[string]interface{}{
+	requestLogger(c).WithFields(map[string]interface{}{
 		"chapter_id": id,
 		"new_order":  payload.Order,
 	}).Info("Updated chapter order")
@@ -147,6 +158,42 @@ func (h *ChapterHandler) UpdateChapterOrder(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+// UpdateChapterDetails godoc
+// @Summary      Update chapter name and description
+// @Description  Updates the chapter text and records a revision
+// @Tags         chapters
+// @Accept       json
+// @Param        chapter_id  path  int                                  true  "Chapter ID"
+// @Param        chapter     body  handler.UpdateChapterDetailsRequest  true  "Name and description"
+// @Success      200
+// @Failure      400  {object}  pkg.ErrorResponse
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/chapters/{chapter_id}/details [put]
+func (h *ChapterHandler) UpdateChapterDetails(c *gin.Context) {
+	id, ok := parseIDParam(c, "chapter_id")
+	if !ok {
+		return
+	}
+
+	authorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var payload UpdateChapterDetailsRequest
+	if !bindJSON(c, &payload) {
+		return
+	}
+
+	if err := h.svc.UpdateChapterDetails(c.Request.Context(), authorID, id, payload.Name, payload.Description); err != nil {
+		requestLogger(c).WithField("chapter_id", id).WithError(err).Error("Failed to update chapter details")
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
 // DeleteChapter godoc
 // @Summary      Delete a chapter
 // @Description  Deletes a specific chapter by its ID
@@ -160,16 +207,16 @@ func (h *ChapterHandler) UpdateChapterOrder(c *gin.Context) {
 func (h *ChapterHandler) DeleteChapter(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("chapter_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("chapter_id", c.Param("chapter_id")).Error("Invalid chapter ID format")
+		requestLogger(c).WithField("chapter_id", c.Param("chapter_id")).Error("Invalid chapter ID format")
 		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
 	if err2 := h.svc.RemoveChapter(c.Request.Context(), uint(id)); err2 != nil {
-		pkg.Logger.WithField("chapter_id", id).Error("Chapter not found while deleting")
-		c.Error(pkg.ErrChapterNotFound)
+		requestLogger(c).WithField("chapter_id", id).WithError(err2).Error("Failed to delete chapter")
+		c.Error(err2)
 		return
 	}
-	pkg.Logger.WithField("chapter_id", id).Info("Chapter deleted successfully")
+	requestLogger(c).WithField("chapter_id", id).Info("Chapter deleted successfully")
 	c.Status(http.StatusNoContent)
 }
@@ -4,66 +4,155 @@ import (
 	"github.com/sirupsen/logrus"
 	"lms-system-internship/entities"
 	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
 	"lms-system-internship/service"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
+// CourseListResponse — страница каталога: курсы и количество курсов по каждому тегу в выборке.
+type CourseListResponse struct {
+	Items  []CourseResponse `json:"items"`
+	Facets CourseListFacets `json:"facets"`
+}
+
+type CourseListFacets struct {
+	Tags []repo.TagFacet `json:"tags"`
+}
+
+// CreateCourseRequest — данные нового курса; ID, даты и категория задаются сервером.
+type CreateCourseRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description" binding:"max=10000"`
+}
+
+// UpdateCourseRequest — новые название и описание курса. ID в теле необязателен,
+// но если передан, должен совпадать с course_id из пути.
+type UpdateCourseRequest struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description" binding:"max=10000"`
+}
+
 type CourseHandler struct {
-	svc service.CourseService
+	svc      service.CourseService
+	versions service.CourseVersionService
 }
 
-func NewCourseHandler(svc service.CourseService) *CourseHandler {
-	return &CourseHandler{svc: svc}
+func NewCourseHandler(svc service.CourseService, versions service.CourseVersionService) *CourseHandler {
+	return &CourseHandler{svc: svc, versions: versions}
 }
 
 // GetAllCourses godoc
 // @Summary      Get all courses
-// @Description  Retrieves a list of all courses
+// @Description  Retrieves courses, optionally filtered by category (including subcategories) and tags.
+// @Description  With `facets=true` the list is wrapped in handler.CourseListResponse together with tag-count facets
 // @Tags         courses
 // @Produce      json
-// @Success      200  {array}   entities.Course
+// @Param        category_id  query     int     false  "Category ID"
+// @Param        tag          query     string  false  "Tag; repeat or separate with commas to require several tags"
+// @Param        facets       query     bool    false  "Return {items, facets} instead of a plain array"
+// @Success      200  {array}   handler.CourseResponse
+// @Failure      400  {object}  pkg.ErrorResponse
 // @Failure      500  {object}  pkg.ErrorResponse
 // @Router       /api/courses [get]
 func (h *CourseHandler) GetAllCourses(c *gin.Context) {
-	courses, err := h.svc.GetAllCourses(c.Request.Context())
+	var filter repo.CourseFilter
+	if raw := c.Query("category_id"); raw != "" {
+		categoryID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			requestLogger(c).WithField("category_id", raw).Error("Invalid category ID format")
+			c.Error(pkg.ErrInvalidInput)
+			return
+		}
+		id := uint(categoryID)
+		filter.CategoryID = &id
+	}
+	for _, value := range c.QueryArray("tag") {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+	withFacets := false
+	if raw := c.Query("facets"); raw != "" {
+		var err error
+		if withFacets, err = strconv.ParseBool(raw); err != nil {
+			c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "facets", Code: "boolean"}))
+			return
+		}
+	}
+
+	courses, err := h.svc.GetAllCourses(c.Request.Context(), filter)
+	if err != nil {
+		requestLogger(c).WithError(err).Error("Failed to retrieve all courses")
+		c.Error(err)
+		return
+	}
+	// Без facets=true ответ остаётся массивом, как до появления фасетов
+	if !withFacets {
+		requestLogger(c).Info("Retrieved all courses")
+		c.JSON(http.StatusOK, newCourseResponses(courses))
+		return
+	}
+	facets, err := h.svc.GetTagFacets(c.Request.Context(), filter)
 	if err != nil {
-		pkg.Logger.WithError(err).Error("Failed to retrieve all courses")
+		requestLogger(c).WithError(err).Error("Failed to compute tag facets")
 		c.Error(err)
 		return
 	}
-	pkg.Logger.Info("Retrieved all courses")
-	c.JSON(http.StatusOK, courses)
+	requestLogger(c).Info("Retrieved all courses")
+	c.JSON(http.StatusOK, CourseListResponse{Items: newCourseResponses(courses), Facets: CourseListFacets{Tags: facets}})
 }
 
 // GetCourse godoc
 // @Summary      Get a course by ID
-// @Description  Retrieves details of a course by its ID
+// @Description  Retrieves details of a course by its ID. Students get the published version pinned to them;
+// @Description  teachers get the current draft, or the published snapshot given by `version`
 // @Tags         courses
 // @Produce      json
-// @Param        course_id  path      int  true  "Course ID"
-// @Success      200        {object}  entities.Course
+// @Param        course_id  path      int  true   "Course ID"
+// @Param        version    query     int  false  "Published version number"
+// @Success      200        {object}  handler.CourseResponse
 // @Failure      400        {object}  pkg.ErrorResponse
+// @Failure      403        {object}  pkg.ErrorResponse
 // @Failure      404        {object}  pkg.ErrorResponse
 // @Router       /api/courses/{course_id} [get]
 func (h *CourseHandler) GetCourse(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("course_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("course_id", c.Param("course_id")).Error("Invalid course ID format")
+		requestLogger(c).WithField("course_id", c.Param("course_id")).Error("Invalid course ID format")
 		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
-	course, err := h.svc.GetCourse(c.Request.Context(), uint(id))
+	var version *int
+	if raw := c.Query("version"); raw != "" {
+		number, err := strconv.Atoi(raw)
+		if err != nil || number < 1 {
+			c.Error(pkg.ErrInvalidInput)
+			return
+		}
+		version = &number
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	privileged := hasAnyRole(c, "ROLE_ADMIN", "ROLE_TEACHER")
+	course, err := h.versions.GetCourseVersion(c.Request.Context(), userID, privileged, uint(id), version)
 	if err != nil {
-		pkg.Logger.WithField("course_id", id).Error("Course not found")
-		c.Error(pkg.ErrCourseNotFound)
+		requestLogger(c).WithField("course_id", id).WithError(err).Error("Failed to get course")
+		c.Error(err)
 		return
 	}
-	pkg.Logger.WithField("course_id", id).Info("Retrieved course details")
-	c.JSON(http.StatusOK, course)
+	requestLogger(c).WithField("course_id", id).WithField("version", course.Version).Info("Retrieved course details")
+	c.JSON(http.StatusOK, newCourseResponse(course))
 }
 
 // CreateCourse godoc
@@ -72,42 +161,41 @@ func (h *CourseHandler) GetCourse(c *gin.Context) {
 // @Tags         courses
 // @Accept       json
 // @Produce      json
-// @Param        course  body      entities.Course  true  "Course data"
-// @Success      201     {object}  entities.Course
+// @Param        course  body      handler.CreateCourseRequest  true  "Course data"
+// @Success      201     {object}  handler.CourseResponse
 // @Failure      400     {object}  pkg.ErrorResponse
 // @Failure      500     {object}  pkg.ErrorResponse
 // @Router       /api/courses [post]
 func (h *CourseHandler) CreateCourse(c *gin.Context) {
-	var course entities.Course
-	if err := c.ShouldBindJSON(&course); err != nil {
-		pkg.Logger.WithError(err).Error("Failed to bind JSON for new course")
-		c.Error(pkg.ErrInvalidInput)
+	var req CreateCourseRequest
+	if !bindJSON(c, &req) {
 		return
 	}
+	course := entities.Course{Name: req.Name, Description: req.Description}
 
-	pkg.Logger.WithFields(logrus.Fields{
+	requestLogger(c).WithFields(logrus.Fields{
 		"course_name": course.Name,
 		"course_desc": course.Description,
 	}).Info("Creating new course")
 
 	if err := h.svc.CreateCourse(c.Request.Context(), &course); err != nil {
-		pkg.Logger.WithError(err).Error("Failed to create course")
+		requestLogger(c).WithError(err).Error("Failed to create course")
 		c.Error(err)
 		return
 	}
-	pkg.Logger.WithField("course", course).Debug("Course created successfully")
-	c.JSON(http.StatusCreated, course)
+	requestLogger(c).WithField("course_id", course.ID).Debug("Course created successfully")
+	c.JSON(http.StatusCreated, newCourseResponse(&course))
 }
 
 // UpdateCourse godoc
 // @Summary      Update a course
-// @Description  Updates course details by ID
+// @Description  Updates course details by ID; name and description changes are kept in the revision history
 // @Tags         courses
 // @Accept       json
 // @Produce      json
-// @Param        course_id  path      int              true  "Course ID"
-// @Param        course     body      entities.Course  true  "Updated course"
-// @Success      200        {object}  entities.Course
+// @Param        course_id  path      int                          true  "Course ID"
+// @Param        course     body      handler.UpdateCourseRequest  true  "Updated course"
+// @Success      200        {object}  handler.CourseResponse
 // @Failure      400        {object}  pkg.ErrorResponse
 // @Failure      404        {object}  pkg.ErrorResponse
 // @Router       /api/courses/{course_id} [put]
@@ -115,38 +203,42 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 	// Get ID from URL
 	id, err := strconv.ParseUint(c.Param("course_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("course_id", c.Param("course_id")).Error("Invalid course ID format")
+		requestLogger(c).WithField("course_id", c.Param("course_id")).Error("Invalid course ID format")
 		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
+	authorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
 	// Parse request body
-	var course entities.Course
-	if err2 := c.ShouldBindJSON(&course); err2 != nil {
-		pkg.Logger.WithError(err).Error("Failed to bind JSON for course update")
-		c.Error(pkg.ErrInvalidInput)
+	var req UpdateCourseRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// Verify ID consistency
-	if course.ID != uint(id) {
-		pkg.Logger.WithFields(map[string]interface{}{
+	if req.ID != 0 && req.ID != uint(id) {
+		requestLogger(c).WithFields(map[string]interface{}{
 			"url_id":  id,
-			"body_id": course.ID,
+			"body_id": req.ID,
 		}).Error("ID mismatch between URL and body")
-		c.Error(pkg.ErrInvalidInput)
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "id", Code: "invalid"}))
 		return
 	}
+	course := entities.Course{ID: uint(id), Name: req.Name, Description: req.Description}
 
 	// Call service
-	if err3 := h.svc.UpdateCourseDetails(c.Request.Context(), &course); err3 != nil {
-		pkg.Logger.WithField("course_id", id).Error("Course update failed")
+	if err3 := h.svc.UpdateCourseDetails(c.Request.Context(), authorID, &course); err3 != nil {
+		requestLogger(c).WithField("course_id", id).Error("Course update failed")
 		c.Error(err3)
 		return
 	}
 
-	pkg.Logger.WithField("course_id", id).Info("Course updated successfully")
-	c.JSON(http.StatusOK, course)
+	requestLogger(c).WithField("course_id", id).Info("Course updated successfully")
+	c.JSON(http.StatusOK, newCourseResponse(&course))
 }
 
 // DeleteCourse godoc
@@ -161,16 +253,16 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 func (h *CourseHandler) DeleteCourse(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("course_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("course_id", c.Param("course_id")).Error("Invalid course ID format")
+		requestLogger(c).WithField("course_id", c.Param("course_id")).Error("Invalid course ID format")
 		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
 	if err2 := h.svc.DeleteCourse(c.Request.Context(), uint(id)); err2 != nil {
-		pkg.Logger.WithField("course_id", id).Error("Failed to delete course")
-		c.Error(pkg.ErrCourseNotFound)
+		requestLogger(c).WithField("course_id", id).Error("Failed to delete course")
+		c.Error(err2)
 		return
 	}
-	pkg.Logger.WithField("course_id", id).Info("Course deleted successfully")
+	requestLogger(c).WithField("course_id", id).Info("Course deleted successfully")
 	c.Status(http.StatusNoContent)
 }
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"github.com/google/uuid"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PublishVersionRequest struct {
+	Comment string `json:"comment"`
+	// MigrateLearners переводит на новую версию всех студентов курса.
+	MigrateLearners bool `json:"migrate_learners"`
+}
+
+type MigrateLearnersRequest struct {
+	// UserIDs — кого перевести; пустой список означает всех студентов курса.
+	UserIDs []uuid.UUID `json:"user_ids"`
+}
+
+type MigrateLearnersResponse struct {
+	Migrated int64 `json:"migrated"`
+}
+
+type CourseVersionHandler struct {
+	svc service.CourseVersionService
+}
+
+func NewCourseVersionHandler(svc service.CourseVersionService) *CourseVersionHandler {
+	return &CourseVersionHandler{svc: svc}
+}
+
+// GetVersions godoc
+// @Summary      List published course versions
+// @Description  Returns published versions of a course, newest first, without snapshots
+// @Tags         course-versions
+// @Produce      json
+// @Param        course_id  path      int  true  "Course ID"
+// @Success      200        {array}   entities.CourseVersion
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/versions [get]
+func (h *CourseVersionHandler) GetVersions(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	versions, err := h.svc.GetVersions(c.Request.Context(), courseID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+// Publish godoc
+// @Summary      Publish a course version
+// @Description  Takes an immutable snapshot of the course chapters, lessons, blocks and attachments
+// @Tags         course-versions
+// @Accept       json
+// @Produce      json
+// @Param        course_id  path      int                    true  "Course ID"
+// @Param        request    body      PublishVersionRequest  true  "Publish options"
+// @Success      201        {object}  entities.CourseVersion
+// @Failure      400        {object}  pkg.ErrorResponse
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/versions [post]
+func (h *CourseVersionHandler) Publish(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+	var req PublishVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	authorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	version, err := h.svc.Publish(c.Request.Context(), authorID, courseID, req.Comment, req.MigrateLearners)
+	if err != nil {
+		requestLogger(c).WithField("course_id", courseID).WithError(err).Error("Failed to publish course version")
+		c.Error(err)
+		return
+	}
+	requestLogger(c).WithField("course_id", courseID).WithField("version", version.Number).Info("Course version published")
+	c.JSON(http.StatusCreated, version)
+}
+
+// MigrateLearners godoc
+// @Summary      Move learners to a course version
+// @Description  Pins the given learners (all learners of the course if none given) to the version
+// @Tags         course-versions
+// @Accept       json
+// @Produce      json
+// @Param        course_id  path      int                     true  "Course ID"
+// @Param        version    path      int                     true  "Version number"
+// @Param        request    body      MigrateLearnersRequest  false "Learners to migrate"
+// @Success      200        {object}  MigrateLearnersResponse
+// @Failure      400        {object}  pkg.ErrorResponse
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/versions/{version}/migrate [post]
+func (h *CourseVersionHandler) MigrateLearners(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+	number, ok := parseIDParam(c, "version")
+	if !ok {
+		return
+	}
+	var req MigrateLearnersRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(pkg.ErrInvalidInput)
+			return
+		}
+	}
+
+	migrated, err := h.svc.MigrateLearners(c.Request.Context(), courseID, int(number), req.UserIDs)
+	if err != nil {
+		requestLogger(c).WithField("course_id", courseID).WithField("version", number).WithError(err).Error("Failed to migrate learners")
+		c.Error(err)
+		return
+	}
+	requestLogger(c).WithField("course_id", courseID).WithField("version", number).WithField("migrated", migrated).Info("Learners migrated")
+	c.JSON(http.StatusOK, MigrateLearnersResponse{Migrated: migrated})
+}
@@ -2,18 +2,22 @@ package handler
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"lms-system-internship/middleware"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"lms-system-internship/entities"
 	"lms-system-internship/mocks"
 	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
 )
 
 func setupRouter() *gin.Engine {
@@ -23,6 +27,28 @@ func setupRouter() *gin.Engine {
 	return r
 }
 
+// withUser имитирует TokenAuthMiddleware: кладёт в контекст пользователя без ролей.
+func withUser(userID uuid.UUID) gin.HandlerFunc {
+	return withPrincipal(&pkg.Principal{ID: userID})
+}
+
+// withPrincipal имитирует TokenAuthMiddleware: кладёт в контекст заданного пользователя.
+func withPrincipal(principal *pkg.Principal) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(pkg.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// unpublishedVersions — сервис версий для курсов, которые ещё не публиковались: студентам
+// показывается текущее содержимое.
+func unpublishedVersions() *mocks.CourseVersionService {
+	versions := new(mocks.CourseVersionService)
+	versions.On("PinnedLesson", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	versions.On("PinnedAttachment", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	return versions
+}
+
 func TestCourseHandler_GetAllCourses(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.CourseService)
@@ -39,9 +65,9 @@ func TestCourseHandler_GetAllCourses(t *testing.T) {
 			},
 		}
 
-		mockService.On("GetAllCourses", mock.Anything).Return(courses, nil)
+		mockService.On("GetAllCourses", mock.Anything, repo.CourseFilter{}).Return(courses, nil)
 
-		handler := NewCourseHandler(mockService)
+		handler := NewCourseHandler(mockService, unpublishedVersions())
 		router := setupRouter()
 		router.GET("/api/courses", handler.GetAllCourses)
 
@@ -51,14 +77,18 @@ func TestCourseHandler_GetAllCourses(t *testing.T) {
 		router.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusOK, resp.Code)
+		var body []map[string]interface{}
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Len(t, body, 2)
 		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "GetTagFacets", mock.Anything, mock.Anything)
 	})
 
 	t.Run("service error", func(t *testing.T) {
 		mockService := new(mocks.CourseService)
-		mockService.On("GetAllCourses", mock.Anything).Return(nil, errors.New("service error"))
+		mockService.On("GetAllCourses", mock.Anything, repo.CourseFilter{}).Return(nil, errors.New("service error"))
 
-		handler := NewCourseHandler(mockService)
+		handler := NewCourseHandler(mockService, unpublishedVersions())
 		router := setupRouter()
 		router.GET("/api/courses", handler.GetAllCourses)
 
@@ -70,21 +100,75 @@ func TestCourseHandler_GetAllCourses(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, resp.Code)
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("filter by category and tags", func(t *testing.T) {
+		mockService := new(mocks.CourseService)
+		categoryID := uint(3)
+		filter := repo.CourseFilter{CategoryID: &categoryID, Tags: []string{"go", "web"}}
+		mockService.On("GetAllCourses", mock.Anything, filter).Return([]*entities.Course{}, nil)
+		mockService.On("GetTagFacets", mock.Anything, filter).Return([]repo.TagFacet{}, nil)
+
+		handler := NewCourseHandler(mockService, unpublishedVersions())
+		router := setupRouter()
+		router.GET("/api/courses", handler.GetAllCourses)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/courses?category_id=3&tag=Go,web&facets=true", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"items":[],"facets":{"tags":[]}}`, resp.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid category", func(t *testing.T) {
+		mockService := new(mocks.CourseService)
+
+		handler := NewCourseHandler(mockService, unpublishedVersions())
+		router := setupRouter()
+		router.GET("/api/courses", handler.GetAllCourses)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/courses?category_id=abc", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("invalid facets flag", func(t *testing.T) {
+		mockService := new(mocks.CourseService)
+
+		handler := NewCourseHandler(mockService, unpublishedVersions())
+		router := setupRouter()
+		router.GET("/api/courses", handler.GetAllCourses)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/courses?facets=maybe", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		mockService.AssertNotCalled(t, "GetAllCourses", mock.Anything, mock.Anything)
+	})
 }
 
 func TestCourseHandler_GetCourse(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		mockService := new(mocks.CourseService)
+		versions := new(mocks.CourseVersionService)
+		userID := uuid.New()
 		course := &entities.Course{
 			ID:          1,
 			Name:        "Test Course",
 			Description: "Test Description",
 		}
 
-		mockService.On("GetCourse", mock.Anything, uint(1)).Return(course, nil)
+		versions.On("GetCourseVersion", mock.Anything, userID, false, uint(1), (*int)(nil)).Return(course, nil)
 
-		handler := NewCourseHandler(mockService)
+		handler := NewCourseHandler(new(mocks.CourseService), versions)
 		router := setupRouter()
+		router.Use(withUser(userID))
 		router.GET("/api/courses/:course_id", handler.GetCourse)
 
 		req, _ := http.NewRequest(http.MethodGet, "/api/courses/1", nil)
@@ -93,11 +177,65 @@ func TestCourseHandler_GetCourse(t *testing.T) {
 		router.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusOK, resp.Code)
-		mockService.AssertExpectations(t)
+		versions.AssertExpectations(t)
+	})
+
+	t.Run("pinned version", func(t *testing.T) {
+		versions := new(mocks.CourseVersionService)
+		userID := uuid.New()
+		version := 2
+		course := &entities.Course{ID: 1, Name: "Test Course", Version: 2}
+		versions.On("GetCourseVersion", mock.Anything, userID, false, uint(1), &version).Return(course, nil)
+
+		handler := NewCourseHandler(new(mocks.CourseService), versions)
+		router := setupRouter()
+		router.Use(withUser(userID))
+		router.GET("/api/courses/:course_id", handler.GetCourse)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/courses/1?version=2", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"version":2`)
+		versions.AssertExpectations(t)
+	})
+
+	t.Run("version not pinned to student", func(t *testing.T) {
+		versions := new(mocks.CourseVersionService)
+		userID := uuid.New()
+		versions.On("GetCourseVersion", mock.Anything, userID, false, uint(1), mock.Anything).Return(nil, pkg.ErrForbidden)
+
+		handler := NewCourseHandler(new(mocks.CourseService), versions)
+		router := setupRouter()
+		router.Use(withUser(userID))
+		router.GET("/api/courses/:course_id", handler.GetCourse)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/courses/1?version=3", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		handler := NewCourseHandler(new(mocks.CourseService), unpublishedVersions())
+		router := setupRouter()
+		router.Use(withUser(uuid.New()))
+		router.GET("/api/courses/:course_id", handler.GetCourse)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/courses/1?version=0", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
-		handler := NewCourseHandler(nil)
+		handler := NewCourseHandler(nil, unpublishedVersions())
 		router := setupRouter()
 		router.GET("/api/courses/:course_id", handler.GetCourse)
 
@@ -110,11 +248,12 @@ func TestCourseHandler_GetCourse(t *testing.T) {
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mockService := new(mocks.CourseService)
-		mockService.On("GetCourse", mock.Anything, uint(1)).Return(nil, pkg.ErrCourseNotFound)
+		versions := new(mocks.CourseVersionService)
+		versions.On("GetCourseVersion", mock.Anything, mock.Anything, false, uint(1), (*int)(nil)).Return(nil, pkg.ErrCourseNotFound)
 
-		handler := NewCourseHandler(mockService)
+		handler := NewCourseHandler(new(mocks.CourseService), versions)
 		router := setupRouter()
+		router.Use(withUser(uuid.New()))
 		router.GET("/api/courses/:course_id", handler.GetCourse)
 
 		req, _ := http.NewRequest(http.MethodGet, "/api/courses/1", nil)
@@ -123,7 +262,7 @@ func TestCourseHandler_GetCourse(t *testing.T) {
 		router.ServeHTTP(resp, req)
 
 		assert.Equal(t, http.StatusNotFound, resp.Code)
-		mockService.AssertExpectations(t)
+		versions.AssertExpectations(t)
 	})
 }
 
@@ -137,7 +276,7 @@ func TestCourseHandler_CreateCourse(t *testing.T) {
 
 		mockService.On("CreateCourse", mock.Anything, course).Return(nil)
 
-		handler := NewCourseHandler(mockService)
+		handler := NewCourseHandler(mockService, unpublishedVersions())
 		router := setupRouter()
 		router.POST("/api/courses", handler.CreateCourse)
 
@@ -153,7 +292,7 @@ func TestCourseHandler_CreateCourse(t *testing.T) {
 	})
 
 	t.Run("invalid input", func(t *testing.T) {
-		handler := NewCourseHandler(nil)
+		handler := NewCourseHandler(nil, unpublishedVersions())
 		router := setupRouter()
 		router.POST("/api/courses", handler.CreateCourse)
 
@@ -167,6 +306,28 @@ func TestCourseHandler_CreateCourse(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, resp.Code)
 	})
 
+	t.Run("field validation errors", func(t *testing.T) {
+		handler := NewCourseHandler(nil, unpublishedVersions())
+		router := setupRouter()
+		router.POST("/api/courses", handler.CreateCourse)
+
+		body := `{"id":7,"name":"","description":"` + strings.Repeat("a", 10001) + `"}`
+		req, _ := http.NewRequest(http.MethodPost, "/api/courses", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		var errResp pkg.ErrorResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errResp))
+		assert.Equal(t, "invalid_input", errResp.Code)
+		assert.Equal(t, []pkg.FieldError{
+			{Field: "name", Code: "required", Message: "This field is required"},
+			{Field: "description", Code: "max_length", Param: "10000", Message: "Must be at most 10000 characters long"},
+		}, errResp.Fields)
+	})
+
 	t.Run("service error", func(t *testing.T) {
 		mockService := new(mocks.CourseService)
 		course := &entities.Course{
@@ -176,7 +337,7 @@ func TestCourseHandler_CreateCourse(t *testing.T) {
 
 		mockService.On("CreateCourse", mock.Anything, course).Return(errors.New("service error"))
 
-		handler := NewCourseHandler(mockService)
+		handler := NewCourseHandler(mockService, unpublishedVersions())
 		router := setupRouter()
 		router.POST("/api/courses", handler.CreateCourse)
 
@@ -201,10 +362,11 @@ func TestCourseHandler_UpdateCourse(t *testing.T) {
 			Description: "Updated Description",
 		}
 
-		mockService.On("UpdateCourseDetails", mock.Anything, course).Return(nil)
+		mockService.On("UpdateCourseDetails", mock.Anything, mock.Anything, course).Return(nil)
 
-		handler := NewCourseHandler(mockService)
+		handler := NewCourseHandler(mockService, unpublishedVersions())
 		router := setupRouter()
+		router.Use(withUser(uuid.New()))
 		router.PUT("/api/courses/:course_id", handler.UpdateCourse)
 
 		body := `{"id":1,"name":"Updated Course","description":"Updated Description"}`
@@ -219,8 +381,9 @@ func TestCourseHandler_UpdateCourse(t *testing.T) {
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
-		handler := NewCourseHandler(nil)
+		handler := NewCourseHandler(nil, unpublishedVersions())
 		router := setupRouter()
+		router.Use(withUser(uuid.New()))
 		router.PUT("/api/courses/:course_id", handler.UpdateCourse)
 
 		req, _ := http.NewRequest(http.MethodPut, "/api/courses/invalid", nil)
@@ -239,10 +402,11 @@ func TestCourseHandler_UpdateCourse(t *testing.T) {
 			Description: "Should fail",
 		}
 
-		mockService.On("UpdateCourseDetails", mock.Anything, course).Return(pkg.ErrCourseNotFound)
+		mockService.On("UpdateCourseDetails", mock.Anything, mock.Anything, course).Return(pkg.ErrCourseNotFound)
 
-		handler := NewCourseHandler(mockService)
+		handler := NewCourseHandler(mockService, unpublishedVersions())
 		router := setupRouter()
+		router.Use(withUser(uuid.New()))
 		router.PUT("/api/courses/:course_id", handler.UpdateCourse)
 
 		body := `{"id":1,"name":"Non-existent Course","description":"Should fail"}`
@@ -257,8 +421,9 @@ func TestCourseHandler_UpdateCourse(t *testing.T) {
 	})
 
 	t.Run("invalid input", func(t *testing.T) {
-		handler := NewCourseHandler(nil)
+		handler := NewCourseHandler(nil, unpublishedVersions())
 		router := setupRouter()
+		router.Use(withUser(uuid.New()))
 		router.PUT("/api/courses/:course_id", handler.UpdateCourse)
 
 		body := `{"invalid json`
@@ -272,9 +437,10 @@ func TestCourseHandler_UpdateCourse(t *testing.T) {
 	})
 
 	t.Run("id mismatch", func(t *testing.T) {
-		handler := NewCourseHandler(nil)
+		handler := NewCourseHandler(nil, unpublishedVersions())
 		router := gin.New()
 		router.Use(middleware.ErrorHandler())
+		router.Use(withUser(uuid.New()))
 		router.PUT("/api/courses/:course_id", handler.UpdateCourse)
 
 		body := `{"id":2,"name":"Course","description":"Mismatched ID"}`
@@ -289,12 +455,55 @@ func TestCourseHandler_UpdateCourse(t *testing.T) {
 	})
 }
 
+func TestCourseHandler_ErrorResponse(t *testing.T) {
+	t.Run("localized body with request id", func(t *testing.T) {
+		versions := new(mocks.CourseVersionService)
+		versions.On("GetCourseVersion", mock.Anything, mock.Anything, false, uint(1), (*int)(nil)).Return(nil, pkg.ErrCourseNotFound)
+
+		handler := NewCourseHandler(new(mocks.CourseService), versions)
+		router := gin.New()
+		router.Use(middleware.RequestID(), middleware.ErrorHandler(), middleware.Locale("en", []string{"en", "ru"}), withUser(uuid.New()))
+		router.GET("/api/courses/:course_id", handler.GetCourse)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/courses/1", nil)
+		req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en;q=0.8")
+		req.Header.Set("X-Request-ID", "req-42")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Equal(t, "req-42", resp.Header().Get("X-Request-ID"))
+		assert.JSONEq(t, `{"error":"Курс не найден","code":"course_not_found","message_key":"errors.course_not_found","request_id":"req-42"}`, resp.Body.String())
+	})
+
+	t.Run("unexpected errors are not leaked", func(t *testing.T) {
+		versions := new(mocks.CourseVersionService)
+		versions.On("GetCourseVersion", mock.Anything, mock.Anything, false, uint(1), (*int)(nil)).Return(nil, errors.New("pq: connection refused"))
+
+		handler := NewCourseHandler(new(mocks.CourseService), versions)
+		router := gin.New()
+		router.Use(middleware.RequestID(), middleware.ErrorHandler(), withUser(uuid.New()))
+		router.GET("/api/courses/:course_id", handler.GetCourse)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/courses/1", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"code":"internal_error"`)
+		assert.NotContains(t, resp.Body.String(), "pq:")
+		assert.NotEmpty(t, resp.Header().Get("X-Request-ID"))
+	})
+}
+
 func TestCourseHandler_DeleteCourse(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.CourseService)
 		mockService.On("DeleteCourse", mock.Anything, uint(1)).Return(nil)
 
-		handler := NewCourseHandler(mockService)
+		handler := NewCourseHandler(mockService, unpublishedVersions())
 		router := setupRouter()
 		router.DELETE("/api/courses/:course_id", handler.DeleteCourse)
 
@@ -311,7 +520,7 @@ func TestCourseHandler_DeleteCourse(t *testing.T) {
 		mockService := new(mocks.CourseService)
 		mockService.On("DeleteCourse", mock.Anything, uint(1)).Return(pkg.ErrCourseNotFound)
 
-		handler := NewCourseHandler(mockService)
+		handler := NewCourseHandler(mockService, unpublishedVersions())
 		router := setupRouter()
 		router.DELETE("/api/courses/:course_id", handler.DeleteCourse)
 
@@ -325,7 +534,7 @@ func TestCourseHandler_DeleteCourse(t *testing.T) {
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
-		handler := NewCourseHandler(nil)
+		handler := NewCourseHandler(nil, unpublishedVersions())
 		router := setupRouter()
 		router.DELETE("/api/courses/:course_id", handler.DeleteCourse)
 
@@ -0,0 +1,420 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OverrideGradeRequest struct {
+	Score  *float64 `json:"score" binding:"required"`
+	Reason string   `json:"reason" binding:"required"`
+}
+
+type GradebookHandler struct {
+	svc service.GradebookService
+}
+
+func NewGradebookHandler(svc service.GradebookService) *GradebookHandler {
+	return &GradebookHandler{svc: svc}
+}
+
+// GetCourseGradebook godoc
+// @Summary      Get course gradebook
+// @Description  Returns grade categories, grade items and every student's scores with weighted totals
+// @Tags         gradebook
+// @Produce      json
+// @Param        course_id  path      int  true  "Course ID"
+// @Success      200        {object}  service.Gradebook
+// @Failure      400        {object}  pkg.ErrorResponse
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/gradebook [get]
+func (h *GradebookHandler) GetCourseGradebook(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	gradebook, err := h.svc.GetCourseGradebook(c.Request.Context(), courseID)
+	if err != nil {
+		requestLogger(c).WithField("course_id", courseID).WithError(err).Error("Failed to build gradebook")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gradebook)
+}
+
+// ExportCourseGradebook godoc
+// @Summary      Export course gradebook
+// @Description  Exports the gradebook as CSV or XLSX
+// @Tags         gradebook
+// @Produce      application/octet-stream
+// @Param        course_id  path   int     true   "Course ID"
+// @Param        format     query  string  false  "csv (default) or xlsx"
+// @Success      200        {file}    file
+// @Failure      400        {object}  pkg.ErrorResponse
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/gradebook/export [get]
+func (h *GradebookHandler) ExportCourseGradebook(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", service.GradebookFormatCSV)
+	data, err := h.svc.ExportCourseGradebook(c.Request.Context(), courseID, format)
+	if err != nil {
+		requestLogger(c).WithField("course_id", courseID).WithError(err).Error("Failed to export gradebook")
+		c.Error(err)
+		return
+	}
+
+	contentType := "text/csv"
+	if format == service.GradebookFormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"gradebook-%d.%s\"", courseID, format))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// GetMyGrades godoc
+// @Summary      Get own grades
+// @Description  Returns the current user's grades and weighted totals for every course
+// @Tags         gradebook
+// @Produce      json
+// @Success      200  {array}   service.StudentGrades
+// @Failure      401  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/me/grades [get]
+func (h *GradebookHandler) GetMyGrades(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	grades, err := h.svc.GetUserGrades(c.Request.Context(), userID)
+	if err != nil {
+		requestLogger(c).WithError(err).Error("Failed to retrieve user grades")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, grades)
+}
+
+// GetCategories godoc
+// @Summary      List grade categories of a course
+// @Tags         gradebook
+// @Produce      json
+// @Param        course_id  path      int  true  "Course ID"
+// @Success      200        {array}   entities.GradeCategory
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/grade-categories [get]
+func (h *GradebookHandler) GetCategories(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	categories, err := h.svc.GetCategories(c.Request.Context(), courseID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// CreateCategory godoc
+// @Summary      Create a grade category
+// @Tags         gradebook
+// @Accept       json
+// @Produce      json
+// @Param        course_id  path      int                     true  "Course ID"
+// @Param        category   body      entities.GradeCategory  true  "Category name and weight"
+// @Success      201        {object}  entities.GradeCategory
+// @Failure      400        {object}  pkg.ErrorResponse
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/grade-categories [post]
+func (h *GradebookHandler) CreateCategory(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	var category entities.GradeCategory
+	if err := c.ShouldBindJSON(&category); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for grade category")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	category.ID = 0
+	category.CourseID = courseID
+
+	if err := h.svc.CreateCategory(c.Request.Context(), &category); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to create grade category")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategory godoc
+// @Summary      Update a grade category
+// @Tags         gradebook
+// @Accept       json
+// @Produce      json
+// @Param        category_id  path      int                     true  "Category ID"
+// @Param        category     body      entities.GradeCategory  true  "Category name and weight"
+// @Success      200          {object}  entities.GradeCategory
+// @Failure      400          {object}  pkg.ErrorResponse
+// @Failure      404          {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/grade-categories/{category_id} [put]
+func (h *GradebookHandler) UpdateCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "category_id")
+	if !ok {
+		return
+	}
+
+	var category entities.GradeCategory
+	if err := c.ShouldBindJSON(&category); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for grade category")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	category.ID = id
+
+	if err := h.svc.UpdateCategory(c.Request.Context(), &category); err != nil {
+		requestLogger(c).WithField("category_id", id).WithError(err).Error("Failed to update grade category")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// DeleteCategory godoc
+// @Summary      Delete a grade category
+// @Description  Deletes the category; its grade items become uncategorized
+// @Tags         gradebook
+// @Param        category_id  path  int  true  "Category ID"
+// @Success      204
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/grade-categories/{category_id} [delete]
+func (h *GradebookHandler) DeleteCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "category_id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteCategory(c.Request.Context(), id); err != nil {
+		requestLogger(c).WithField("category_id", id).WithError(err).Error("Failed to delete grade category")
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetItems godoc
+// @Summary      List grade items of a course
+// @Tags         gradebook
+// @Produce      json
+// @Param        course_id  path      int  true  "Course ID"
+// @Success      200        {array}   entities.GradeItem
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/grade-items [get]
+func (h *GradebookHandler) GetItems(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	items, err := h.svc.GetItems(c.Request.Context(), courseID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// CreateItem godoc
+// @Summary      Create a grade item
+// @Description  Adds a gradable item to the course, optionally linked to a lesson, an assignment and a category
+// @Tags         gradebook
+// @Accept       json
+// @Produce      json
+// @Param        course_id  path      int                 true  "Course ID"
+// @Param        item       body      entities.GradeItem  true  "Grade item"
+// @Success      201        {object}  entities.GradeItem
+// @Failure      400        {object}  pkg.ErrorResponse
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/grade-items [post]
+func (h *GradebookHandler) CreateItem(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	var item entities.GradeItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for grade item")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	item.ID = 0
+	item.CourseID = courseID
+
+	if err := h.svc.CreateItem(c.Request.Context(), &item); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to create grade item")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+// UpdateItem godoc
+// @Summary      Update a grade item
+// @Tags         gradebook
+// @Accept       json
+// @Produce      json
+// @Param        item_id  path      int                 true  "Grade item ID"
+// @Param        item     body      entities.GradeItem  true  "Grade item"
+// @Success      200      {object}  entities.GradeItem
+// @Failure      400      {object}  pkg.ErrorResponse
+// @Failure      404      {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/grade-items/{item_id} [put]
+func (h *GradebookHandler) UpdateItem(c *gin.Context) {
+	id, ok := parseIDParam(c, "item_id")
+	if !ok {
+		return
+	}
+
+	var item entities.GradeItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for grade item")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	item.ID = id
+
+	if err := h.svc.UpdateItem(c.Request.Context(), &item); err != nil {
+		requestLogger(c).WithField("item_id", id).WithError(err).Error("Failed to update grade item")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// DeleteItem godoc
+// @Summary      Delete a grade item
+// @Description  Deletes the grade item together with its grades
+// @Tags         gradebook
+// @Param        item_id  path  int  true  "Grade item ID"
+// @Success      204
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/grade-items/{item_id} [delete]
+func (h *GradebookHandler) DeleteItem(c *gin.Context) {
+	id, ok := parseIDParam(c, "item_id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteItem(c.Request.Context(), id); err != nil {
+		requestLogger(c).WithField("item_id", id).WithError(err).Error("Failed to delete grade item")
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// OverrideGrade godoc
+// @Summary      Override a student's grade
+// @Description  Sets the score manually; the change is audited and later automatic scores no longer replace it
+// @Tags         gradebook
+// @Accept       json
+// @Produce      json
+// @Param        item_id  path      int                           true  "Grade item ID"
+// @Param        user_id  path      string                        true  "Student ID"
+// @Param        grade    body      handler.OverrideGradeRequest  true  "Score and reason"
+// @Success      200      {object}  entities.Grade
+// @Failure      400      {object}  pkg.ErrorResponse
+// @Failure      404      {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/grade-items/{item_id}/grades/{user_id} [put]
+func (h *GradebookHandler) OverrideGrade(c *gin.Context) {
+	itemID, ok := parseIDParam(c, "item_id")
+	if !ok {
+		return
+	}
+	studentID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		requestLogger(c).WithField("user_id", c.Param("user_id")).Error("Invalid user ID format")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+
+	teacherID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req OverrideGradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for grade override")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+
+	grade, err := h.svc.OverrideScore(c.Request.Context(), teacherID, itemID, studentID, *req.Score, req.Reason)
+	if err != nil {
+		requestLogger(c).WithField("item_id", itemID).WithError(err).Error("Failed to override grade")
+		c.Error(err)
+		return
+	}
+	requestLogger(c).WithFields(map[string]interface{}{
+		"item_id": itemID,
+		"user_id": studentID,
+	}).Info("Grade overridden")
+	c.JSON(http.StatusOK, grade)
+}
+
+// GetGradeHistory godoc
+// @Summary      Grade audit trail
+// @Description  Lists every change of a student's grade for an item
+// @Tags         gradebook
+// @Produce      json
+// @Param        item_id  path      int     true  "Grade item ID"
+// @Param        user_id  path      string  true  "Student ID"
+// @Success      200      {array}   entities.GradeAudit
+// @Failure      400      {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/grade-items/{item_id}/grades/{user_id}/history [get]
+func (h *GradebookHandler) GetGradeHistory(c *gin.Context) {
+	itemID, ok := parseIDParam(c, "item_id")
+	if !ok {
+		return
+	}
+	studentID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		requestLogger(c).WithField("user_id", c.Param("user_id")).Error("Invalid user ID format")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+
+	audits, err := h.svc.GetGradeHistory(c.Request.Context(), itemID, studentID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, audits)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"lms-system-internship/health"
+)
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	up := func(context.Context) error { return nil }
+
+	t.Run("all dependencies up", func(t *testing.T) {
+		handler := NewHealthHandler(health.NewChecker(time.Second,
+			health.Check{Name: "database", Run: up},
+			health.Check{Name: "storage", Run: up},
+		))
+		router := setupRouter()
+		router.GET("/readyz", handler.Readiness)
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var report health.Report
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		assert.Equal(t, health.StatusReady, report.Status)
+		assert.Equal(t, health.StatusUp, report.Checks["database"].Status)
+	})
+
+	t.Run("dependency down", func(t *testing.T) {
+		handler := NewHealthHandler(health.NewChecker(time.Second,
+			health.Check{Name: "database", Run: up},
+			health.Check{Name: "jwks", Run: func(context.Context) error { return errors.New("JWKS not loaded yet") }},
+		))
+		router := setupRouter()
+		router.GET("/readyz", handler.Readiness)
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+		var report health.Report
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		assert.Equal(t, health.StatusNotReady, report.Status)
+		assert.Equal(t, health.StatusDown, report.Checks["jwks"].Status)
+		assert.NotContains(t, resp.Body.String(), "JWKS not loaded yet")
+		assert.Equal(t, health.StatusUp, report.Checks["database"].Status)
+	})
+
+	t.Run("check times out", func(t *testing.T) {
+		handler := NewHealthHandler(health.NewChecker(10*time.Millisecond,
+			health.Check{Name: "storage", Run: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}},
+		))
+		router := setupRouter()
+		router.GET("/readyz", handler.Readiness)
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+		assert.NotContains(t, resp.Body.String(), "context deadline exceeded")
+	})
+
+	t.Run("draining", func(t *testing.T) {
+		checker := health.NewChecker(time.Second, health.Check{Name: "database", Run: up})
+		checker.SetDraining()
+		handler := NewHealthHandler(checker)
+		router := setupRouter()
+		router.GET("/readyz", handler.Readiness)
+
+		req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+		var report health.Report
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		assert.Equal(t, health.StatusNotReady, report.Status)
+		assert.True(t, report.Draining)
+	})
+}
+
+func TestHealthHandler_Liveness(t *testing.T) {
+	handler := NewHealthHandler(health.NewChecker(time.Second))
+	router := setupRouter()
+	router.GET("/healthz", handler.Liveness)
+
+	req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, resp.Body.String())
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"lms-system-internship/entities"
+	"lms-system-internship/middleware"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseIDParam разбирает числовой path-параметр и при ошибке записывает ErrInvalidInput.
+func parseIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil {
+		requestLogger(c).WithField(name, c.Param(name)).Error("Invalid ID format")
+		c.Error(pkg.ErrInvalidInput)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parseUUIDParam разбирает path-параметр с UUID и при ошибке записывает ErrInvalidInput с полем name.
+func parseUUIDParam(c *gin.Context, name string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: name, Code: "uuid"}))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// currentPrincipal достаёт пользователя, которого TokenAuthMiddleware кладёт в контекст,
+// а для анонимного запроса записывает ErrUnauthorized.
+func currentPrincipal(c *gin.Context) (*pkg.Principal, bool) {
+	principal, ok := pkg.PrincipalFromContext(c.Request.Context())
+	if !ok {
+		middleware.AbortWithError(c, pkg.ErrUnauthorized)
+		return nil, false
+	}
+	return principal, true
+}
+
+// currentUserID достаёт ID пользователя запроса.
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	principal, ok := pkg.PrincipalFromContext(c.Request.Context())
+	if !ok || principal.ID == uuid.Nil {
+		middleware.AbortWithError(c, pkg.ErrUnauthorized)
+		return uuid.Nil, false
+	}
+	return principal.ID, true
+}
+
+// pinnedLesson возвращает урок из версии курса, закреплённой за студентом. Для преподавателей
+// и для уроков, которые ни разу не публиковались, возвращает nil — тогда показывается текущий
+// урок. ok == false — ошибка уже записана в контекст.
+func pinnedLesson(c *gin.Context, versions service.CourseVersionService, lessonID uint) (*entities.Lesson, bool) {
+	if hasAnyRole(c, "ROLE_ADMIN", "ROLE_TEACHER") {
+		return nil, true
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+	lesson, err := versions.PinnedLesson(c.Request.Context(), userID, lessonID)
+	if err != nil {
+		requestLogger(c).WithField("lesson_id", lessonID).WithError(err).Error("Failed to resolve pinned lesson")
+		c.Error(err)
+		return nil, false
+	}
+	return lesson, true
+}
+
+// hasAnyRole сообщает, есть ли у пользователя запроса хотя бы одна из ролей realm.
+func hasAnyRole(c *gin.Context, roles ...string) bool {
+	principal, ok := pkg.PrincipalFromContext(c.Request.Context())
+	return ok && principal.HasAnyRole(roles...)
+}
+
+// queryInt читает необязательный числовой query-параметр; отсутствующий параметр даёт 0.
+func queryInt(c *gin.Context, name string) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(raw)
+}
+
+// requestLogger возвращает логгер текущего запроса с его ID, пользователем и маршрутом.
+func requestLogger(c *gin.Context) *logrus.Entry {
+	return pkg.LoggerFromContext(c.Request.Context())
+}
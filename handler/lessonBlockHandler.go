@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LessonBlockHandler struct {
+	svc      service.LessonBlockService
+	versions service.CourseVersionService
+}
+
+func NewLessonBlockHandler(svc service.LessonBlockService, versions service.CourseVersionService) *LessonBlockHandler {
+	return &LessonBlockHandler{svc: svc, versions: versions}
+}
+
+// GetBlocks godoc
+// @Summary      List lesson blocks
+// @Description  Returns the blocks of a lesson in display order. Students get the blocks from the course version pinned to them
+// @Tags         lesson-blocks
+// @Produce      json
+// @Param        lesson_id  path      int  true  "Lesson ID"
+// @Success      200        {array}   entities.LessonBlock
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/lessons/{lesson_id}/blocks [get]
+func (h *LessonBlockHandler) GetBlocks(c *gin.Context) {
+	lessonID, ok := parseIDParam(c, "lesson_id")
+	if !ok {
+		return
+	}
+
+	pinned, ok := pinnedLesson(c, h.versions, lessonID)
+	if !ok {
+		return
+	}
+	if pinned != nil {
+		blocks := pinned.Blocks
+		if blocks == nil {
+			blocks = []entities.LessonBlock{}
+		}
+		c.JSON(http.StatusOK, blocks)
+		return
+	}
+
+	blocks, err := h.svc.GetBlocks(c.Request.Context(), lessonID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, blocks)
+}
+
+// CreateBlock godoc
+// @Summary      Add a block to a lesson
+// @Description  Appends a block (text, code, image, video or callout) to the end of the lesson. The change is recorded in the lesson revision history
+// @Tags         lesson-blocks
+// @Accept       json
+// @Produce      json
+// @Param        lesson_id  path      int                   true  "Lesson ID"
+// @Param        block      body      entities.LessonBlock  true  "Block"
+// @Success      201        {object}  entities.LessonBlock
+// @Failure      400        {object}  pkg.ErrorResponse
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/lessons/{lesson_id}/blocks [post]
+func (h *LessonBlockHandler) CreateBlock(c *gin.Context) {
+	lessonID, ok := parseIDParam(c, "lesson_id")
+	if !ok {
+		return
+	}
+	authorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var block entities.LessonBlock
+	if err := c.ShouldBindJSON(&block); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for lesson block")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	block.ID = 0
+	block.LessonID = lessonID
+
+	if err := h.svc.CreateBlock(c.Request.Context(), authorID, &block); err != nil {
+		requestLogger(c).WithField("lesson_id", lessonID).WithError(err).Error("Failed to create lesson block")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, block)
+}
+
+// UpdateBlock godoc
+// @Summary      Update a lesson block
+// @Tags         lesson-blocks
+// @Accept       json
+// @Produce      json
+// @Param        block_id  path      int                   true  "Block ID"
+// @Param        block     body      entities.LessonBlock  true  "Block"
+// @Success      200       {object}  entities.LessonBlock
+// @Failure      400       {object}  pkg.ErrorResponse
+// @Failure      404       {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/lesson-blocks/{block_id} [put]
+func (h *LessonBlockHandler) UpdateBlock(c *gin.Context) {
+	id, ok := parseIDParam(c, "block_id")
+	if !ok {
+		return
+	}
+	authorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var block entities.LessonBlock
+	if err := c.ShouldBindJSON(&block); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for lesson block")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	block.ID = id
+
+	if err := h.svc.UpdateBlock(c.Request.Context(), authorID, &block); err != nil {
+		requestLogger(c).WithField("block_id", id).WithError(err).Error("Failed to update lesson block")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, block)
+}
+
+// DeleteBlock godoc
+// @Summary      Delete a lesson block
+// @Tags         lesson-blocks
+// @Param        block_id  path  int  true  "Block ID"
+// @Success      204
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/lesson-blocks/{block_id} [delete]
+func (h *LessonBlockHandler) DeleteBlock(c *gin.Context) {
+	id, ok := parseIDParam(c, "block_id")
+	if !ok {
+		return
+	}
+	authorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteBlock(c.Request.Context(), authorID, id); err != nil {
+		requestLogger(c).WithField("block_id", id).WithError(err).Error("Failed to delete lesson block")
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ReorderBlocks godoc
+// @Summary      Reorder lesson blocks
+// @Description  Takes all block IDs of the lesson in the new order
+// @Tags         lesson-blocks
+// @Accept       json
+// @Param        lesson_id  path  int     true  "Lesson ID"
+// @Param        ids        body  []uint  true  "Block IDs in the new order"
+// @Success      200
+// @Failure      400  {object}  pkg.ErrorResponse
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/lessons/{lesson_id}/blocks/reorder [put]
+func (h *LessonBlockHandler) ReorderBlocks(c *gin.Context) {
+	lessonID, ok := parseIDParam(c, "lesson_id")
+	if !ok {
+		return
+	}
+	authorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var ids []uint
+	if err := c.ShouldBindJSON(&ids); err != nil {
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+
+	if err := h.svc.ReorderBlocks(c.Request.Context(), authorID, lessonID, ids); err != nil {
+		requestLogger(c).WithField("lesson_id", lessonID).WithError(err).Error("Failed to reorder lesson blocks")
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"github.com/google/uuid"
 	"lms-system-internship/entities"
+	"lms-system-internship/metrics"
 	"lms-system-internship/pkg"
 	"lms-system-internship/service"
 	"net/http"
@@ -11,8 +12,18 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// CreateLessonRequest — данные нового урока; глава задаётся query-параметром chapter_id.
+type CreateLessonRequest struct {
+	Name          string `json:"name" binding:"required,max=255"`
+	Description   string `json:"description" binding:"max=10000"`
+	Content       string `json:"content" binding:"max=1000000"`
+	ContentFormat string `json:"content_format" binding:"omitempty,oneof=plain markdown html"` // пусто — plain
+	Order         int    `json:"order" binding:"min=0,max=10000"`
+}
+
 type UpdateLessonContentRequest struct {
-	Content string `json:"content"`
+	Content       string `json:"content" binding:"max=1000000"`
+	ContentFormat string `json:"content_format" binding:"omitempty,oneof=plain markdown html"` // пусто — формат не меняется
 }
 
 type GrantLessonAccessRequest struct {
@@ -21,11 +32,12 @@ type GrantLessonAccessRequest struct {
 }
 
 type LessonHandler struct {
-	svc service.LessonService
+	svc      service.LessonService
+	versions service.CourseVersionService
 }
 
-func NewLessonHandler(svc service.LessonService) *LessonHandler {
-	return &LessonHandler{svc: svc}
+func NewLessonHandler(svc service.LessonService, versions service.CourseVersionService) *LessonHandler {
+	return &LessonHandler{svc: svc, versions: versions}
 }
 
 // GetAllLessons godoc
@@ -33,90 +45,116 @@ func NewLessonHandler(svc service.LessonService) *LessonHandler {
 // @Description  Retrieves a list of all lessons
 // @Tags         lessons
 // @Produce      json
-// @Success      200  {array}   entities.Lesson
+// @Success      200  {array}   handler.LessonResponse
 // @Failure      500  {object}  pkg.ErrorResponse
 // @Router       /api/lessons [get]
 func (h *LessonHandler) GetAllLessons(c *gin.Context) {
 	lessons, err := h.svc.GetAllLessons(c.Request.Context())
 	if err != nil {
-		pkg.Logger.WithError(err).Error("Failed to retrieve all lessons")
+		requestLogger(c).WithError(err).Error("Failed to retrieve all lessons")
 		c.Error(err)
 		return
 	}
-	pkg.Logger.Info("Retrieved all lessons")
-	c.JSON(http.StatusOK, lessons)
+	requestLogger(c).Info("Retrieved all lessons")
+	c.JSON(http.StatusOK, newLessonResponses(lessons))
 }
 
 // GetLesson godoc
 // @Summary      Get lesson by ID
-// @Description  Retrieves a specific lesson by its ID
+// @Description  Retrieves a specific lesson by its ID. Students get the lesson from the course version pinned to them.
+// @Description  With render=html the response also contains sanitized content_html
 // @Tags         lessons
 // @Produce      json
-// @Param        lesson_id  path      int  true  "Lesson ID"
-// @Success      200  {object}  entities.Lesson
+// @Param        lesson_id  path      int     true   "Lesson ID"
+// @Param        render     query     string  false  "Set to html to render content server-side"
+// @Success      200  {object}  handler.LessonResponse
 // @Failure      400  {object}  pkg.ErrorResponse
 // @Failure      404  {object}  pkg.ErrorResponse
 // @Router       /api/lessons/{lesson_id} [get]
 func (h *LessonHandler) GetLesson(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("lesson_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("lesson_id", c.Param("lesson_id")).Error("Invalid lesson ID format")
+		requestLogger(c).WithField("lesson_id", c.Param("lesson_id")).Error("Invalid lesson ID format")
 		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
-	lesson, err := h.svc.GetLesson(c.Request.Context(), uint(id))
+	render := c.Query("render")
+	if render != "" && render != "html" {
+		requestLogger(c).WithField("render", render).Error("Unsupported render option")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+
+	lesson, ok := pinnedLesson(c, h.versions, uint(id))
+	if !ok {
+		return
+	}
+	switch {
+	case lesson != nil && render == "html":
+		err = h.svc.RenderLesson(lesson)
+	case lesson != nil:
+	case render == "html":
+		lesson, err = h.svc.GetRenderedLesson(c.Request.Context(), uint(id))
+	default:
+		lesson, err = h.svc.GetLesson(c.Request.Context(), uint(id))
+	}
 	if err != nil {
-		pkg.Logger.WithField("lesson_id", id).Error("Lesson not found")
-		c.Error(pkg.ErrLessonNotFound)
+		requestLogger(c).WithField("lesson_id", id).WithError(err).Error("Failed to get lesson")
+		c.Error(err)
 		return
 	}
-	pkg.Logger.WithField("lesson_id", id).Info("Retrieved lesson details")
-	c.JSON(http.StatusOK, lesson)
+	requestLogger(c).WithField("lesson_id", id).Info("Retrieved lesson details")
+	c.JSON(http.StatusOK, newLessonResponse(lesson))
 }
 
 // CreateLesson godoc
 // @Summary      Create a new lesson
-// @Description  Adds a new lesson to a specific chapter
+// @Description  Adds a new lesson to a specific chapter; non-empty content becomes the first text block
 // @Tags         lessons
 // @Accept       json
 // @Produce      json
-// @Param        chapter_id  query     int              true  "Chapter ID"
-// @Param        lesson      body      entities.Lesson  true  "Lesson data"
-// @Success      201  {object}  entities.Lesson
+// @Param        chapter_id  query     int                          true  "Chapter ID"
+// @Param        lesson      body      handler.CreateLessonRequest  true  "Lesson data"
+// @Success      201  {object}  handler.LessonResponse
 // @Failure      400  {object}  pkg.ErrorResponse
 // @Failure      500  {object}  pkg.ErrorResponse
 // @Router       /api/lessons [post]
 func (h *LessonHandler) CreateLesson(c *gin.Context) {
 	chapterID, err := strconv.ParseUint(c.Query("chapter_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("chapter_id", c.Query("chapter_id")).Error("Invalid chapter ID format in query")
-		c.Error(pkg.ErrInvalidInput)
+		requestLogger(c).WithField("chapter_id", c.Query("chapter_id")).Error("Invalid chapter ID format in query")
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "chapter_id", Code: "required"}))
 		return
 	}
 
-	var lesson entities.Lesson
-	if err2 := c.ShouldBindJSON(&lesson); err2 != nil {
-		pkg.Logger.Error("Invalid JSON input while creating lesson")
-		c.Error(pkg.ErrInvalidInput)
+	var req CreateLessonRequest
+	if !bindJSON(c, &req) {
 		return
 	}
+	lesson := entities.Lesson{
+		Name:          req.Name,
+		Description:   req.Description,
+		Content:       req.Content,
+		ContentFormat: req.ContentFormat,
+		Order:         req.Order,
+	}
 
 	if err3 := h.svc.AddLessonToChapter(c.Request.Context(), uint(chapterID), &lesson); err3 != nil {
-		pkg.Logger.WithError(err).Error("Failed to add lesson to chapter")
-		c.Error(err)
+		requestLogger(c).WithError(err3).Error("Failed to add lesson to chapter")
+		c.Error(err3)
 		return
 	}
-	pkg.Logger.WithFields(map[string]interface{}{
+	requestLogger(c).WithFields(map[string]interface{}{
 		"chapter_id": chapterID,
-		"lesson":     lesson,
+		"lesson_id":  lesson.ID,
 	}).Debug("Lesson created successfully")
-	c.JSON(http.StatusCreated, lesson)
+	c.JSON(http.StatusCreated, newLessonResponse(&lesson))
 }
 
 // UpdateLessonContent godoc
 // @Summary      Update lesson content
-// @Description  Updates the content field of a specific lesson
+// @Description  Legacy endpoint: writes the text into the first text block of the lesson (an empty text removes it) and mirrors it into content. Records a revision
 // @Tags         lessons
 // @Accept       json
 // @Produce      json
@@ -125,26 +163,29 @@ func (h *LessonHandler) CreateLesson(c *gin.Context) {
 // @Success      200
 // @Failure      400  {object}  pkg.ErrorResponse
 // @Failure      404  {object}  pkg.ErrorResponse
+// @Deprecated
 // @Router  /api/lessons/{lesson_id} [put]
 func (h *LessonHandler) UpdateLessonContent(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("lesson_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("lesson_id", c.Param("lesson_id")).Error("Invalid lesson ID format")
+		requestLogger(c).WithField("lesson_id", c.Param("lesson_id")).Error("Invalid lesson ID format")
 		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
-	var payload UpdateLessonContentRequest
+	authorID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
 
-	if err2 := c.ShouldBindJSON(&payload); err2 != nil {
-		pkg.Logger.Error("Invalid JSON input while updating lesson content")
-		c.Error(pkg.ErrInvalidInput)
+	var payload UpdateLessonContentRequest
+	if !bindJSON(c, &payload) {
 		return
 	}
 
-	if err3 := h.svc.UpdateLessonContent(c.Request.Context(), uint(id), payload.Content); err3 != nil {
-		pkg.Logger.WithField("lesson_id", id).Error("Lesson not found while updating order")
-		c.Error(pkg.ErrLessonNotFound)
+	if err3 := h.svc.UpdateLessonContent(c.Request.Context(), authorID, uint(id), payload.Content, payload.ContentFormat); err3 != nil {
+		requestLogger(c).WithField("lesson_id", id).WithField("content_format", payload.ContentFormat).WithError(err3).Error("Failed to update lesson content")
+		c.Error(err3)
 		return
 	}
 	c.Status(http.StatusOK)
@@ -165,24 +206,24 @@ func (h *LessonHandler) UpdateLessonContent(c *gin.Context) {
 func (h *LessonHandler) ReorderLessons(c *gin.Context) {
 	chapterID, err := strconv.ParseUint(c.Param("chapter_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("chapter_id", c.Query("chapter_id")).Error("Invalid chapter ID format in query")
+		requestLogger(c).WithField("chapter_id", c.Query("chapter_id")).Error("Invalid chapter ID format in query")
 		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
 	var ids []uint
 	if err2 := c.ShouldBindJSON(&ids); err2 != nil {
-		pkg.Logger.Error("Invalid JSON input while reordering lesson content")
+		requestLogger(c).Error("Invalid JSON input while reordering lesson content")
 		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
 	if err3 := h.svc.ReorderLessons(c.Request.Context(), uint(chapterID), ids); err3 != nil {
-		pkg.Logger.WithField("lesson_id", ids).Error(err3)
+		requestLogger(c).WithField("lesson_id", ids).Error(err3)
 		c.Error(err3)
 		return
 	}
-	pkg.Logger.WithFields(map[string]interface{}{
+	requestLogger(c).WithFields(map[string]interface{}{
 		"chapter_id": chapterID,
 		"lesson_ids": ids,
 	}).Debug("Lessons reordered successfully")
@@ -202,17 +243,17 @@ func (h *LessonHandler) ReorderLessons(c *gin.Context) {
 func (h *LessonHandler) DeleteLesson(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("lesson_id"), 10, 64)
 	if err != nil {
-		pkg.Logger.WithField("lesson_id", c.Param("lesson_id")).Error("Invalid lesson ID format")
+		requestLogger(c).WithField("lesson_id", c.Param("lesson_id")).Error("Invalid lesson ID format")
 		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
 	if err2 := h.svc.DeleteLesson(c.Request.Context(), uint(id)); err2 != nil {
-		pkg.Logger.WithField("lesson_id", id).Error("Lesson not found while deleting")
-		c.Error(pkg.ErrLessonNotFound)
+		requestLogger(c).WithField("lesson_id", id).WithError(err2).Error("Failed to delete lesson")
+		c.Error(err2)
 		return
 	}
-	pkg.Logger.WithField("lesson_id", id).Info("Lesson deleted successfully")
+	requestLogger(c).WithField("lesson_id", id).Info("Lesson deleted successfully")
 	c.Status(http.StatusNoContent)
 }
 
@@ -224,33 +265,35 @@ func (h *LessonHandler) DeleteLesson(c *gin.Context) {
 // @Produce      json
 // @Param        body  body  handler.GrantLessonAccessRequest  true  "Данные доступа"
 // @Success      200  {object}  map[string]string
-// @Failure      400  {object}  map[string]string
-// @Failure      500  {object}  map[string]string
+// @Failure      400  {object}  pkg.ErrorResponse
+// @Failure      404  {object}  pkg.ErrorResponse
 // @Security     BearerAuth
 // @Router       /api/lessons/grant-access [post]
 func (h *LessonHandler) GrantLessonAccess(c *gin.Context) {
 	var body GrantLessonAccessRequest
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		c.Error(pkg.ErrInvalidInput)
 		return
 	}
 
 	userUUID, err := uuid.Parse(body.UserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user_id UUID"})
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "user_id", Code: "uuid"}))
 		return
 	}
 
 	if body.LessonID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "lesson_id is required"})
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "lesson_id", Code: "required"}))
 		return
 	}
 
 	if err := h.svc.GrantAccess(c.Request.Context(), userUUID, body.LessonID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to grant access"})
+		requestLogger(c).WithField("lesson_id", body.LessonID).WithError(err).Error("Failed to grant lesson access")
+		c.Error(err)
 		return
 	}
+	metrics.AccessGrants.Inc()
 
 	c.JSON(http.StatusOK, gin.H{"message": "access granted"})
 }
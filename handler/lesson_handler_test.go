@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"lms-system-internship/entities"
@@ -36,7 +37,7 @@ func TestLessonHandler_GetAllLessons(t *testing.T) {
 
 		mockService.On("GetAllLessons", mock.Anything).Return(lessons, nil)
 
-		handler := NewLessonHandler(mockService)
+		handler := NewLessonHandler(mockService, unpublishedVersions())
 		router := setupRouter()
 		router.GET("/api/lessons", handler.GetAllLessons)
 
@@ -53,7 +54,7 @@ func TestLessonHandler_GetAllLessons(t *testing.T) {
 		mockService := new(mocks.LessonService)
 		mockService.On("GetAllLessons", mock.Anything).Return([]*entities.Lesson{}, nil)
 
-		handler := NewLessonHandler(mockService)
+		handler := NewLessonHandler(mockService, unpublishedVersions())
 		router := setupRouter()
 		router.GET("/api/lessons", handler.GetAllLessons)
 
@@ -71,7 +72,7 @@ func TestLessonHandler_GetAllLessons(t *testing.T) {
 		mockService := new(mocks.LessonService)
 		mockService.On("GetAllLessons", mock.Anything).Return(nil, errors.New("service error"))
 
-		handler := NewLessonHandler(mockService)
+		handler := NewLessonHandler(mockService, unpublishedVersions())
 		router := setupRouter()
 		router.GET("/api/lessons", handler.GetAllLessons)
 
@@ -86,6 +87,32 @@ func TestLessonHandler_GetAllLessons(t *testing.T) {
 }
 
 func TestLessonHandler_GetLesson(t *testing.T) {
+	t.Run("attachment storage keys are not exposed", func(t *testing.T) {
+		mockService := new(mocks.LessonService)
+		lesson := &entities.Lesson{
+			ID:          1,
+			Name:        "Test Lesson",
+			ChapterID:   1,
+			Attachments: []entities.Attachment{{ID: 3, Name: "slides.pdf", URL: "lessons/1/secret-key.pdf", LessonID: 1}},
+		}
+
+		mockService.On("GetLesson", mock.Anything, uint(1)).Return(lesson, nil)
+
+		handler := NewLessonHandler(mockService, unpublishedVersions())
+		router := gin.New()
+		router.Use(middleware.ErrorHandler(), withUser(uuid.New()))
+		router.GET("/api/lessons/:lesson_id", handler.GetLesson)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/lessons/1", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"attachments":[{"id":3,"name":"slides.pdf"`)
+		assert.NotContains(t, resp.Body.String(), "secret-key")
+	})
+
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.LessonService)
 		lesson := &entities.Lesson{
@@ -99,9 +126,9 @@ func TestLessonHandler_GetLesson(t *testing.T) {
 
 		mockService.On("GetLesson", mock.Anything, uint(1)).Return(lesson, nil)
 
-		handler := NewLessonHandler(mockService)
+		handler := NewLessonHandler(mockService, unpublishedVersions())
 		router := gin.New()
-		router.Use(middleware.ErrorHandler())
+		router.Use(middleware.ErrorHandler(), withUser(uuid.New()))
 		router.GET("/api/lessons/:lesson_id", handler.GetLesson)
 
 		req, _ := http.NewRequest(http.MethodGet, "/api/lessons/1", nil)
@@ -114,9 +141,9 @@ func TestLessonHandler_GetLesson(t *testing.T) {
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
-		handler := NewLessonHandler(nil)
+		handler := NewLessonHandler(nil, unpublishedVersions())
 		router := gin.New()
-		router.Use(middleware.ErrorHandler())
+		router.Use(middleware.ErrorHandler(), withUser(uuid.New()))
 		router.GET("/api/lessons/:lesson_id", handler.GetLesson)
 
 		req, _ := http.NewRequest(http.MethodGet, "/api/lessons/invalid", nil)
@@ -131,9 +158,9 @@ func TestLessonHandler_GetLesson(t *testing.T) {
 		mockService := new(mocks.LessonService)
 		mockService.On("GetLesson", mock.Anything, uint(1)).Return(nil, pkg.ErrLessonNotFound)
 
-		handler := NewLessonHandler(mockService)
+		handler := NewLessonHandler(mockService, unpublishedVersions())
 		router := gin.New()
-		router.Use(middleware.ErrorHandler())
+		router.Use(middleware.ErrorHandler(), withUser(uuid.New()))
 		router.GET("/api/lessons/:lesson_id", handler.GetLesson)
 
 		req, _ := http.NewRequest(http.MethodGet, "/api/lessons/1", nil)
@@ -144,9 +171,168 @@ func TestLessonHandler_GetLesson(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, resp.Code)
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("render html", func(t *testing.T) {
+		mockService := new(mocks.LessonService)
+		lesson := &entities.Lesson{ID: 1, Content: "# Title", ContentFormat: entities.ContentFormatMarkdown, ContentHTML: "<h1>Title</h1>"}
+		mockService.On("GetRenderedLesson", mock.Anything, uint(1)).Return(lesson, nil)
+
+		handler := NewLessonHandler(mockService, unpublishedVersions())
+		router := gin.New()
+		router.Use(middleware.ErrorHandler(), withUser(uuid.New()))
+		router.GET("/api/lessons/:lesson_id", handler.GetLesson)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/lessons/1?render=html", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"content_html":"\u003ch1\u003eTitle\u003c/h1\u003e"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unsupported render", func(t *testing.T) {
+		handler := NewLessonHandler(new(mocks.LessonService), unpublishedVersions())
+		router := gin.New()
+		router.Use(middleware.ErrorHandler(), withUser(uuid.New()))
+		router.GET("/api/lessons/:lesson_id", handler.GetLesson)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/lessons/1?render=pdf", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("student gets pinned lesson", func(t *testing.T) {
+		mockService := new(mocks.LessonService)
+		versions := new(mocks.CourseVersionService)
+		userID := uuid.New()
+		pinned := &entities.Lesson{ID: 1, Name: "Published Lesson", ChapterID: 1}
+		versions.On("PinnedLesson", mock.Anything, userID, uint(1)).Return(pinned, nil)
+
+		handler := NewLessonHandler(mockService, versions)
+		router := setupRouter()
+		router.Use(withUser(userID))
+		router.GET("/api/lessons/:lesson_id", handler.GetLesson)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/lessons/1", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"name":"Published Lesson"`)
+		versions.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "GetLesson", mock.Anything, mock.Anything)
+	})
+
+	t.Run("pinned lesson is rendered", func(t *testing.T) {
+		mockService := new(mocks.LessonService)
+		versions := new(mocks.CourseVersionService)
+		userID := uuid.New()
+		pinned := &entities.Lesson{ID: 1, Name: "Published Lesson", ChapterID: 1}
+		versions.On("PinnedLesson", mock.Anything, userID, uint(1)).Return(pinned, nil)
+		mockService.On("RenderLesson", pinned).Return(nil)
+
+		handler := NewLessonHandler(mockService, versions)
+		router := setupRouter()
+		router.Use(withUser(userID))
+		router.GET("/api/lessons/:lesson_id", handler.GetLesson)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/lessons/1?render=html", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unpublished lesson falls back to draft", func(t *testing.T) {
+		mockService := new(mocks.LessonService)
+		versions := new(mocks.CourseVersionService)
+		userID := uuid.New()
+		versions.On("PinnedLesson", mock.Anything, userID, uint(1)).Return(nil, nil)
+		mockService.On("GetLesson", mock.Anything, uint(1)).Return(&entities.Lesson{ID: 1, Name: "Draft"}, nil)
+
+		handler := NewLessonHandler(mockService, versions)
+		router := setupRouter()
+		router.Use(withUser(userID))
+		router.GET("/api/lessons/:lesson_id", handler.GetLesson)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/lessons/1", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"name":"Draft"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("teacher gets draft", func(t *testing.T) {
+		mockService := new(mocks.LessonService)
+		versions := new(mocks.CourseVersionService)
+		mockService.On("GetLesson", mock.Anything, uint(1)).Return(&entities.Lesson{ID: 1, Name: "Draft"}, nil)
+
+		handler := NewLessonHandler(mockService, versions)
+		router := setupRouter()
+		router.Use(withPrincipal(&pkg.Principal{ID: uuid.New(), Roles: []string{"ROLE_TEACHER"}}))
+		router.GET("/api/lessons/:lesson_id", handler.GetLesson)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/lessons/1", nil)
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		versions.AssertNotCalled(t, "PinnedLesson", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func TestLessonHandler_CreateLesson(t *testing.T) {
+	t.Run("client cannot set server fields", func(t *testing.T) {
+		mockService := new(mocks.LessonService)
+		mockService.On("AddLessonToChapter", mock.Anything, uint(1), mock.MatchedBy(func(l *entities.Lesson) bool {
+			return l.ID == 0 && l.ChapterID == 0 && l.CreatedAt.IsZero()
+		})).Return(nil)
+
+		handler := NewLessonHandler(mockService, unpublishedVersions())
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.POST("/api/lessons", handler.CreateLesson)
+
+		body := `{"id":99,"chapter_id":5,"created_at":"2020-01-01T00:00:00Z","name":"New Lesson"}`
+		req, _ := http.NewRequest(http.MethodPost, "/api/lessons?chapter_id=1", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unsupported content format", func(t *testing.T) {
+		handler := NewLessonHandler(nil, unpublishedVersions())
+		router := gin.New()
+		router.Use(middleware.ErrorHandler())
+		router.POST("/api/lessons", handler.CreateLesson)
+
+		body := `{"name":"New Lesson","content_format":"rtf"}`
+		req, _ := http.NewRequest(http.MethodPost, "/api/lessons?chapter_id=1", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"field":"content_format","code":"oneof"`)
+	})
+
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.LessonService)
 		lesson := &entities.Lesson{
@@ -158,7 +344,7 @@ func TestLessonHandler_CreateLesson(t *testing.T) {
 
 		mockService.On("AddLessonToChapter", mock.Anything, uint(1), lesson).Return(nil)
 
-		handler := NewLessonHandler(mockService)
+		handler := NewLessonHandler(mockService, unpublishedVersions())
 		router := gin.New()
 		router.Use(middleware.ErrorHandler())
 		router.POST("/api/lessons", handler.CreateLesson)
@@ -175,7 +361,7 @@ func TestLessonHandler_CreateLesson(t *testing.T) {
 	})
 
 	t.Run("invalid input", func(t *testing.T) {
-		handler := NewLessonHandler(nil)
+		handler := NewLessonHandler(nil, unpublishedVersions())
 		router := gin.New()
 		router.Use(middleware.ErrorHandler())
 		router.POST("/api/lessons", handler.CreateLesson)
@@ -191,7 +377,7 @@ func TestLessonHandler_CreateLesson(t *testing.T) {
 	})
 
 	t.Run("missing chapter_id", func(t *testing.T) {
-		handler := NewLessonHandler(nil)
+		handler := NewLessonHandler(nil, unpublishedVersions())
 		router := gin.New()
 		router.Use(middleware.ErrorHandler())
 		router.POST("/api/lessons", handler.CreateLesson)
@@ -210,11 +396,12 @@ func TestLessonHandler_CreateLesson(t *testing.T) {
 func TestLessonHandler_UpdateLessonContent(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockService := new(mocks.LessonService)
-		mockService.On("UpdateLessonContent", mock.Anything, uint(1), "New Content").Return(nil)
+		mockService.On("UpdateLessonContent", mock.Anything, mock.Anything, uint(1), "New Content", "").Return(nil)
 
-		handler := NewLessonHandler(mockService)
+		handler := NewLessonHandler(mockService, unpublishedVersions())
 		router := gin.New()
 		router.Use(middleware.ErrorHandler())
+		router.Use(withUser(uuid.New()))
 		router.PUT("/api/lessons/:lesson_id", handler.UpdateLessonContent) // Add parameter to route
 
 		body := `{"content":"New Content"}`
@@ -235,7 +422,7 @@ func TestLessonHandler_ReorderLessons(t *testing.T) {
 		lessonIDs := []uint{2, 1, 3}
 		mockService.On("ReorderLessons", mock.Anything, uint(1), lessonIDs).Return(nil)
 
-		handler := NewLessonHandler(mockService)
+		handler := NewLessonHandler(mockService, unpublishedVersions())
 		router := gin.New()
 		router.Use(middleware.ErrorHandler())
 		router.PUT("/api/chapters/:chapter_id/lessons/reorder", handler.ReorderLessons) // Add parameter to route
@@ -257,7 +444,7 @@ func TestLessonHandler_DeleteLesson(t *testing.T) {
 		mockService := new(mocks.LessonService)
 		mockService.On("DeleteLesson", mock.Anything, uint(1)).Return(nil)
 
-		handler := NewLessonHandler(mockService)
+		handler := NewLessonHandler(mockService, unpublishedVersions())
 		router := setupRouter()
 		router.DELETE("/api/lessons/:lesson_id", handler.DeleteLesson)
 
@@ -274,7 +461,7 @@ func TestLessonHandler_DeleteLesson(t *testing.T) {
 		mockService := new(mocks.LessonService)
 		mockService.On("DeleteLesson", mock.Anything, uint(1)).Return(pkg.ErrLessonNotFound)
 
-		handler := NewLessonHandler(mockService)
+		handler := NewLessonHandler(mockService, unpublishedVersions())
 		router := setupRouter()
 		router.DELETE("/api/lessons/:lesson_id", handler.DeleteLesson)
 
@@ -288,7 +475,7 @@ func TestLessonHandler_DeleteLesson(t *testing.T) {
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
-		handler := NewLessonHandler(nil)
+		handler := NewLessonHandler(nil, unpublishedVersions())
 		router := setupRouter()
 		router.DELETE("/api/lessons/:lesson_id", handler.DeleteLesson)
 
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SignUpRequest struct {
+	Username  string `json:"username" binding:"required"`
+	Email     string `json:"email" binding:"required,email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Password  string `json:"password" binding:"required,min=6"`
+}
+
+type SignUpResponse struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// EmailTokenRequest — токен из ссылки в письме.
+type EmailTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type EmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type RegistrationHandler struct {
+	svc service.RegistrationService
+}
+
+func NewRegistrationHandler(svc service.RegistrationService) *RegistrationHandler {
+	return &RegistrationHandler{svc: svc}
+}
+
+// SignUp godoc
+// @Summary      Sign up
+// @Description  Creates a disabled account and emails a verification link; the account is enabled once the email is verified.
+// @Description  Available only when self-registration is enabled, and only for the allowed email domains
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      SignUpRequest  true  "Account data"
+// @Success      201      {object}  handler.SignUpResponse
+// @Failure      400      {object}  pkg.ErrorResponse
+// @Failure      403      {object}  pkg.ErrorResponse
+// @Failure      409      {object}  pkg.ErrorResponse
+// @Failure      429      {object}  pkg.ErrorResponse
+// @Failure      502      {object}  pkg.ErrorResponse
+// @Router       /api/auth/register [post]
+func (h *RegistrationHandler) SignUp(c *gin.Context) {
+	var req SignUpRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	userID, err := h.svc.SignUp(c.Request.Context(), service.SignUp{
+		Username:  req.Username,
+		Email:     req.Email,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Password:  req.Password,
+	})
+	if err != nil {
+		requestLogger(c).WithField("username", req.Username).WithError(err).Warn("Sign-up failed")
+		c.Error(err)
+		return
+	}
+	requestLogger(c).WithField("target_user_id", userID).Info("User signed up")
+
+	c.JSON(http.StatusCreated, SignUpResponse{UserID: userID})
+}
+
+// VerifyEmail godoc
+// @Summary      Verify email
+// @Description  Confirms the email with the token from the verification email and enables the account
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      EmailTokenRequest  true  "Token from the email"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  pkg.ErrorResponse
+// @Failure      429      {object}  pkg.ErrorResponse
+// @Failure      502      {object}  pkg.ErrorResponse
+// @Router       /api/auth/verify-email [post]
+func (h *RegistrationHandler) VerifyEmail(c *gin.Context) {
+	var req EmailTokenRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	h.verifyEmail(c, req.Token)
+}
+
+// VerifyEmailLink godoc
+// @Summary      Verify email by link
+// @Description  Same as POST /api/auth/verify-email, for links opened straight from the email
+// @Tags         auth
+// @Produce      json
+// @Param        token  query     string  true  "Token from the email"
+// @Success      200    {object}  map[string]string
+// @Failure      400    {object}  pkg.ErrorResponse
+// @Failure      429    {object}  pkg.ErrorResponse
+// @Failure      502    {object}  pkg.ErrorResponse
+// @Router       /api/auth/verify-email [get]
+func (h *RegistrationHandler) VerifyEmailLink(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "token", Code: "required"}))
+		return
+	}
+	h.verifyEmail(c, token)
+}
+
+func (h *RegistrationHandler) verifyEmail(c *gin.Context, token string) {
+	if err := h.svc.VerifyEmail(c.Request.Context(), token); err != nil {
+		requestLogger(c).WithError(err).Warn("Email verification failed")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification email
+// @Description  Sends a new verification link if the address belongs to an account with an unverified email.
+// @Description  Always answers 202 so that the response does not reveal whether the address is registered
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      EmailRequest  true  "Email"
+// @Success      202      {object}  map[string]string
+// @Failure      400      {object}  pkg.ErrorResponse
+// @Failure      429      {object}  pkg.ErrorResponse
+// @Failure      502      {object}  pkg.ErrorResponse
+// @Router       /api/auth/verify-email/resend [post]
+func (h *RegistrationHandler) ResendVerification(c *gin.Context) {
+	var req EmailRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if err := h.svc.ResendVerification(c.Request.Context(), req.Email); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to resend verification email")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the address needs verification, an email has been sent"})
+}
+
+// ForgotPassword godoc
+// @Summary      Request a password reset
+// @Description  Emails a password reset link if the address belongs to an active account.
+// @Description  Always answers 202 so that the response does not reveal whether the address is registered
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      EmailRequest  true  "Email"
+// @Success      202      {object}  map[string]string
+// @Failure      400      {object}  pkg.ErrorResponse
+// @Failure      429      {object}  pkg.ErrorResponse
+// @Failure      502      {object}  pkg.ErrorResponse
+// @Router       /api/auth/password/forgot [post]
+func (h *RegistrationHandler) ForgotPassword(c *gin.Context) {
+	var req EmailRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if err := h.svc.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to request password reset")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the address is registered, an email has been sent"})
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Sets a new password with the token from the password reset email and signs the user out of all sessions
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      ResetPasswordRequest  true  "Token from the email and the new password"
+// @Success      200      {object}  map[string]string
+// @Failure      400      {object}  pkg.ErrorResponse
+// @Failure      429      {object}  pkg.ErrorResponse
+// @Failure      502      {object}  pkg.ErrorResponse
+// @Router       /api/auth/password/reset [post]
+func (h *RegistrationHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if err := h.svc.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		requestLogger(c).WithError(err).Warn("Password reset failed")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed"})
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"lms-system-internship/middleware"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRegistrationService запоминает аргументы и возвращает err.
+type fakeRegistrationService struct {
+	err      error
+	signUp   service.SignUp
+	userID   uuid.UUID
+	token    string
+	email    string
+	password string
+}
+
+func (f *fakeRegistrationService) SignUp(_ context.Context, signUp service.SignUp) (uuid.UUID, error) {
+	f.signUp = signUp
+	return f.userID, f.err
+}
+
+func (f *fakeRegistrationService) VerifyEmail(_ context.Context, token string) error {
+	f.token = token
+	return f.err
+}
+
+func (f *fakeRegistrationService) ResendVerification(_ context.Context, email string) error {
+	f.email = email
+	return f.err
+}
+
+func (f *fakeRegistrationService) RequestPasswordReset(_ context.Context, email string) error {
+	f.email = email
+	return f.err
+}
+
+func (f *fakeRegistrationService) ResetPassword(_ context.Context, token, password string) error {
+	f.token, f.password = token, password
+	return f.err
+}
+
+func (f *fakeRegistrationService) InviteUser(_ context.Context, account service.UserAccount) error {
+	f.email = account.Email
+	return f.err
+}
+
+func newRegistrationRouter(fake *fakeRegistrationService) http.Handler {
+	h := NewRegistrationHandler(fake)
+	router := setupRouter()
+	router.POST("/api/auth/register", h.SignUp)
+	router.POST("/api/auth/verify-email", h.VerifyEmail)
+	router.GET("/api/auth/verify-email", h.VerifyEmailLink)
+	router.POST("/api/auth/password/forgot", h.ForgotPassword)
+	router.POST("/api/auth/password/reset", h.ResetPassword)
+	return router
+}
+
+func sendJSON(router http.Handler, method, path string, body any) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestRegistrationHandler_SignUp(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		fake := &fakeRegistrationService{userID: uuid.New()}
+
+		resp := sendJSON(newRegistrationRouter(fake), http.MethodPost, "/api/auth/register", SignUpRequest{
+			Username: "ann", Email: "ann@example.com", FirstName: "Ann", Password: "secret1",
+		})
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		var body SignUpResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, fake.userID, body.UserID)
+		assert.Equal(t, "Ann", fake.signUp.FirstName)
+	})
+
+	t.Run("short password", func(t *testing.T) {
+		resp := sendJSON(newRegistrationRouter(&fakeRegistrationService{}), http.MethodPost, "/api/auth/register", SignUpRequest{
+			Username: "ann", Email: "ann@example.com", Password: "123",
+		})
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		resp := sendJSON(newRegistrationRouter(&fakeRegistrationService{err: pkg.ErrRegistrationDisabled}), http.MethodPost, "/api/auth/register", SignUpRequest{
+			Username: "ann", Email: "ann@example.com", Password: "secret1",
+		})
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
+
+func TestRegistrationHandler_VerifyEmail(t *testing.T) {
+	t.Run("link from the email", func(t *testing.T) {
+		fake := &fakeRegistrationService{}
+		req, _ := http.NewRequest(http.MethodGet, "/api/auth/verify-email?token=abc", nil)
+		resp := httptest.NewRecorder()
+
+		newRegistrationRouter(fake).ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "abc", fake.token)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		fake := &fakeRegistrationService{err: pkg.ErrInvalidEmailToken}
+
+		resp := sendJSON(newRegistrationRouter(fake), http.MethodPost, "/api/auth/verify-email", EmailTokenRequest{Token: "abc"})
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), "invalid_email_token")
+	})
+}
+
+func TestRegistrationHandler_Password(t *testing.T) {
+	fake := &fakeRegistrationService{}
+	router := newRegistrationRouter(fake)
+
+	resp := sendJSON(router, http.MethodPost, "/api/auth/password/forgot", EmailRequest{Email: "ann@example.com"})
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Equal(t, "ann@example.com", fake.email)
+
+	resp = sendJSON(router, http.MethodPost, "/api/auth/password/reset", ResetPasswordRequest{Token: "abc", Password: "new-secret"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "new-secret", fake.password)
+}
+
+func TestRegistrationHandler_RateLimit(t *testing.T) {
+	router := setupRouter()
+	router.POST("/api/auth/password/forgot", middleware.RateLimit(2, time.Minute), NewRegistrationHandler(&fakeRegistrationService{}).ForgotPassword)
+
+	for i := 0; i < 2; i++ {
+		resp := sendJSON(router, http.MethodPost, "/api/auth/password/forgot", EmailRequest{Email: "ann@example.com"})
+		assert.Equal(t, http.StatusAccepted, resp.Code)
+	}
+	resp := sendJSON(router, http.MethodPost, "/api/auth/password/forgot", EmailRequest{Email: "ann@example.com"})
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "60", resp.Header().Get("Retry-After"))
+}
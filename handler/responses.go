@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"lms-system-internship/entities"
+	"time"
+)
+
+// Ответы API для курсов, глав и уроков. Сущности GORM наружу не отдаются,
+// чтобы служебные поля (ключи хранилища вложений и т. п.) не попадали в ответ.
+
+type TagResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type AttachmentResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LessonResponse struct {
+	ID            uint                   `json:"id"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	Content       string                 `json:"content"`
+	ContentFormat string                 `json:"content_format"`
+	ContentHTML   string                 `json:"content_html,omitempty"`
+	Order         int                    `json:"order"`
+	ChapterID     uint                   `json:"chapter_id"`
+	CategoryID    *uint                  `json:"category_id"`
+	Tags          []TagResponse          `json:"tags"`
+	Blocks        []entities.LessonBlock `json:"blocks"`
+	Attachments   []AttachmentResponse   `json:"attachments,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+type ChapterResponse struct {
+	ID          uint             `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Order       int              `json:"order"`
+	CourseID    uint             `json:"course_id"`
+	Lessons     []LessonResponse `json:"lessons"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+type CourseResponse struct {
+	ID          uint              `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	CategoryID  *uint             `json:"category_id"`
+	Version     int               `json:"version,omitempty"`
+	Tags        []TagResponse     `json:"tags"`
+	Chapters    []ChapterResponse `json:"chapters"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+func newTagResponses(tags []entities.Tag) []TagResponse {
+	result := make([]TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, TagResponse{ID: tag.ID, Name: tag.Name})
+	}
+	return result
+}
+
+func newLessonResponse(lesson *entities.Lesson) LessonResponse {
+	blocks := lesson.Blocks
+	if blocks == nil {
+		blocks = []entities.LessonBlock{}
+	}
+	var attachments []AttachmentResponse
+	for _, attachment := range lesson.Attachments {
+		attachments = append(attachments, AttachmentResponse{ID: attachment.ID, Name: attachment.Name, CreatedAt: attachment.CreatedAt})
+	}
+	return LessonResponse{
+		ID:            lesson.ID,
+		Name:          lesson.Name,
+		Description:   lesson.Description,
+		Content:       lesson.Content,
+		ContentFormat: lesson.ContentFormat,
+		ContentHTML:   lesson.ContentHTML,
+		Order:         lesson.Order,
+		ChapterID:     lesson.ChapterID,
+		CategoryID:    lesson.CategoryID,
+		Tags:          newTagResponses(lesson.Tags),
+		Blocks:        blocks,
+		Attachments:   attachments,
+		CreatedAt:     lesson.CreatedAt,
+		UpdatedAt:     lesson.UpdatedAt,
+	}
+}
+
+func newLessonResponses(lessons []*entities.Lesson) []LessonResponse {
+	result := make([]LessonResponse, 0, len(lessons))
+	for _, lesson := range lessons {
+		result = append(result, newLessonResponse(lesson))
+	}
+	return result
+}
+
+func newChapterResponse(chapter *entities.Chapter) ChapterResponse {
+	lessons := make([]LessonResponse, 0, len(chapter.Lessons))
+	for i := range chapter.Lessons {
+		lessons = append(lessons, newLessonResponse(&chapter.Lessons[i]))
+	}
+	return ChapterResponse{
+		ID:          chapter.ID,
+		Name:        chapter.Name,
+		Description: chapter.Description,
+		Order:       chapter.Order,
+		CourseID:    chapter.CourseID,
+		Lessons:     lessons,
+		CreatedAt:   chapter.CreatedAt,
+		UpdatedAt:   chapter.UpdatedAt,
+	}
+}
+
+func newChapterResponses(chapters []*entities.Chapter) []ChapterResponse {
+	result := make([]ChapterResponse, 0, len(chapters))
+	for _, chapter := range chapters {
+		result = append(result, newChapterResponse(chapter))
+	}
+	return result
+}
+
+func newCourseResponse(course *entities.Course) CourseResponse {
+	chapters := make([]ChapterResponse, 0, len(course.Chapters))
+	for i := range course.Chapters {
+		chapters = append(chapters, newChapterResponse(&course.Chapters[i]))
+	}
+	return CourseResponse{
+		ID:          course.ID,
+		Name:        course.Name,
+		Description: course.Description,
+		CategoryID:  course.CategoryID,
+		Version:     course.Version,
+		Tags:        newTagResponses(course.Tags),
+		Chapters:    chapters,
+		CreatedAt:   course.CreatedAt,
+		UpdatedAt:   course.UpdatedAt,
+	}
+}
+
+func newCourseResponses(courses []*entities.Course) []CourseResponse {
+	result := make([]CourseResponse, 0, len(courses))
+	for _, course := range courses {
+		result = append(result, newCourseResponse(course))
+	}
+	return result
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RevisionHandler обслуживает историю правок курсов, глав и уроков. Методы возвращают
+// обработчик для конкретного типа сущности и имени path-параметра с её ID.
+type RevisionHandler struct {
+	svc service.RevisionService
+}
+
+func NewRevisionHandler(svc service.RevisionService) *RevisionHandler {
+	return &RevisionHandler{svc: svc}
+}
+
+// GetRevisions godoc
+// @Summary      List revisions
+// @Description  Returns the revision history of a course, chapter or lesson, newest first
+// @Tags         revisions
+// @Produce      json
+// @Param        course_id   path      int  false  "Course ID"
+// @Param        chapter_id  path      int  false  "Chapter ID"
+// @Param        lesson_id   path      int  false  "Lesson ID"
+// @Success      200         {array}   entities.Revision
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/revisions [get]
+// @Router       /api/chapters/{chapter_id}/revisions [get]
+// @Router       /api/lessons/{lesson_id}/revisions [get]
+func (h *RevisionHandler) GetRevisions(entityType, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityID, ok := parseIDParam(c, param)
+		if !ok {
+			return
+		}
+
+		revisions, err := h.svc.GetRevisions(c.Request.Context(), entityType, entityID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, revisions)
+	}
+}
+
+// GetRevision godoc
+// @Summary      Get a revision
+// @Tags         revisions
+// @Produce      json
+// @Param        course_id   path      int  false  "Course ID"
+// @Param        chapter_id  path      int  false  "Chapter ID"
+// @Param        lesson_id   path      int  false  "Lesson ID"
+// @Param        rev         path      int  true   "Revision number"
+// @Success      200         {object}  entities.Revision
+// @Failure      404         {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/revisions/{rev} [get]
+// @Router       /api/chapters/{chapter_id}/revisions/{rev} [get]
+// @Router       /api/lessons/{lesson_id}/revisions/{rev} [get]
+func (h *RevisionHandler) GetRevision(entityType, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityID, ok := parseIDParam(c, param)
+		if !ok {
+			return
+		}
+		number, ok := parseIDParam(c, "rev")
+		if !ok {
+			return
+		}
+
+		revision, err := h.svc.GetRevision(c.Request.Context(), entityType, entityID, int(number))
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, revision)
+	}
+}
+
+// Diff godoc
+// @Summary      Diff two revisions
+// @Description  Returns a unified diff from revision `from` (default: the previous one) to revision `rev`
+// @Tags         revisions
+// @Produce      plain
+// @Param        course_id   path      int  false  "Course ID"
+// @Param        chapter_id  path      int  false  "Chapter ID"
+// @Param        lesson_id   path      int  false  "Lesson ID"
+// @Param        rev         path      int  true   "Revision number"
+// @Param        from        query     int  false  "Revision to compare against"
+// @Success      200         {string}  string
+// @Failure      400         {object}  pkg.ErrorResponse
+// @Failure      404         {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/revisions/{rev}/diff [get]
+// @Router       /api/chapters/{chapter_id}/revisions/{rev}/diff [get]
+// @Router       /api/lessons/{lesson_id}/revisions/{rev}/diff [get]
+func (h *RevisionHandler) Diff(entityType, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityID, ok := parseIDParam(c, param)
+		if !ok {
+			return
+		}
+		number, ok := parseIDParam(c, "rev")
+		if !ok {
+			return
+		}
+
+		to := int(number)
+		from := to - 1
+		if raw := c.Query("from"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil {
+				c.Error(pkg.ErrInvalidInput)
+				return
+			}
+			from = parsed
+		}
+
+		diff, err := h.svc.Diff(c.Request.Context(), entityType, entityID, from, to)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(diff))
+	}
+}
+
+// Restore godoc
+// @Summary      Restore a revision
+// @Description  Restores the content of the revision, including the ordered blocks of a lesson; the restore itself is recorded as a new revision
+// @Tags         revisions
+// @Produce      json
+// @Param        course_id   path      int  false  "Course ID"
+// @Param        chapter_id  path      int  false  "Chapter ID"
+// @Param        lesson_id   path      int  false  "Lesson ID"
+// @Param        rev         path      int  true   "Revision number"
+// @Success      200         {object}  entities.Revision
+// @Failure      404         {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/revisions/{rev}/restore [post]
+// @Router       /api/chapters/{chapter_id}/revisions/{rev}/restore [post]
+// @Router       /api/lessons/{lesson_id}/revisions/{rev}/restore [post]
+func (h *RevisionHandler) Restore(entityType, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityID, ok := parseIDParam(c, param)
+		if !ok {
+			return
+		}
+		number, ok := parseIDParam(c, "rev")
+		if !ok {
+			return
+		}
+		authorID, ok := currentUserID(c)
+		if !ok {
+			return
+		}
+
+		revision, err := h.svc.Restore(c.Request.Context(), authorID, entityType, entityID, int(number))
+		if err != nil {
+			requestLogger(c).WithField(param, entityID).WithField("rev", number).WithError(err).Error("Failed to restore revision")
+			c.Error(err)
+			return
+		}
+		requestLogger(c).WithField(param, entityID).WithField("rev", number).Info("Revision restored")
+		c.JSON(http.StatusOK, revision)
+	}
+}
@@ -0,0 +1,47 @@
+// Внешний тестовый пакет: router импортирует handler, поэтому из package handler его не подключить.
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"lms-system-internship/middleware"
+	"lms-system-internship/router"
+)
+
+func TestSetupRoutes_LocalizesAPIErrors(t *testing.T) {
+	t.Setenv("AUTH_PROVIDER", "local")
+	t.Setenv("LOCAL_AUTH_SECRET", "test-secret")
+	t.Setenv("MINIO_ENDPOINT", "127.0.0.1:9000")
+	t.Setenv("MAILER", "")
+	t.Setenv("DEFAULT_LOCALE", "en")
+	t.Setenv("SUPPORTED_LOCALES", "en,ru")
+
+	// Соединение с базой открывается лениво, а запрос без токена до неё не доходит
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1 user=lms dbname=lms sslmode=disable"), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	router.SetupRoutes(ctx, db, func() bool { return true }, &sync.WaitGroup{}, r)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/courses", nil)
+	req.Header.Set("Accept-Language", "ru")
+	resp := httptest.NewRecorder()
+
+	r.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, "ru", resp.Header().Get("Content-Language"))
+	assert.Contains(t, resp.Body.String(), `"error":"Требуется аутентификация"`)
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	svc service.SearchService
+}
+
+func NewSearchHandler(svc service.SearchService) *SearchHandler {
+	return &SearchHandler{svc: svc}
+}
+
+// Search godoc
+// @Summary      Full-text search
+// @Description  Searches courses, chapters, lessons and attachments (English and Russian). Lessons and attachments are limited to those the caller has access to. Snippets are HTML: the source text is escaped and matches are wrapped in <mark>
+// @Tags         search
+// @Produce      json
+// @Param        q          query     string  true   "Search query (websearch syntax)"
+// @Param        type       query     string  false  "Comma-separated entity types: course, chapter, lesson, attachment"
+// @Param        course_id  query     int     false  "Restrict to a course"
+// @Param        limit      query     int     false  "Page size (default 20, max 100)"
+// @Param        offset     query     int     false  "Offset"
+// @Success      200        {array}   repo.SearchHit
+// @Failure      400        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	params := service.SearchParams{Query: c.Query("q")}
+	if types := c.Query("type"); types != "" {
+		params.Types = strings.Split(types, ",")
+	}
+	if raw := c.Query("course_id"); raw != "" {
+		courseID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			c.Error(pkg.ErrInvalidInput)
+			return
+		}
+		id := uint(courseID)
+		params.CourseID = &id
+	}
+	var err error
+	if params.Limit, err = queryInt(c, "limit"); err != nil {
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	if params.Offset, err = queryInt(c, "offset"); err != nil {
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+
+	privileged := hasAnyRole(c, "ROLE_ADMIN", "ROLE_TEACHER")
+	hits, err := h.svc.Search(c.Request.Context(), userID, privileged, params)
+	if err != nil {
+		requestLogger(c).WithField("q", params.Query).WithError(err).Error("Search failed")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, hits)
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaxonomyRequest задаёт рубрику и полный набор тегов курса или урока.
+type TaxonomyRequest struct {
+	CategoryID *uint    `json:"category_id"`
+	Tags       []string `json:"tags"`
+}
+
+type TaxonomyHandler struct {
+	svc service.TaxonomyService
+}
+
+func NewTaxonomyHandler(svc service.TaxonomyService) *TaxonomyHandler {
+	return &TaxonomyHandler{svc: svc}
+}
+
+// GetCategories godoc
+// @Summary      Get the category tree
+// @Description  Returns root categories with nested subcategories
+// @Tags         catalog
+// @Produce      json
+// @Success      200  {array}  entities.Category
+// @Security     BearerAuth
+// @Router       /api/categories [get]
+func (h *TaxonomyHandler) GetCategories(c *gin.Context) {
+	categories, err := h.svc.GetCategoryTree(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// CreateCategory godoc
+// @Summary      Create a category
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Param        category  body      entities.Category  true  "Name and optional parent_id"
+// @Success      201       {object}  entities.Category
+// @Failure      400       {object}  pkg.ErrorResponse
+// @Failure      404       {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/categories [post]
+func (h *TaxonomyHandler) CreateCategory(c *gin.Context) {
+	var category entities.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for category")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	category.ID = 0
+
+	if err := h.svc.CreateCategory(c.Request.Context(), &category); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to create category")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategory godoc
+// @Summary      Update a category
+// @Description  Renames the category or moves it under another parent
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Param        category_id  path      int                true  "Category ID"
+// @Param        category     body      entities.Category  true  "Name and optional parent_id"
+// @Success      200          {object}  entities.Category
+// @Failure      400          {object}  pkg.ErrorResponse
+// @Failure      404          {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/categories/{category_id} [put]
+func (h *TaxonomyHandler) UpdateCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "category_id")
+	if !ok {
+		return
+	}
+
+	var category entities.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for category")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	category.ID = id
+
+	if err := h.svc.UpdateCategory(c.Request.Context(), &category); err != nil {
+		requestLogger(c).WithField("category_id", id).WithError(err).Error("Failed to update category")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// DeleteCategory godoc
+// @Summary      Delete a category
+// @Description  Deletes a category without subcategories; its courses and lessons become uncategorized
+// @Tags         catalog
+// @Param        category_id  path  int  true  "Category ID"
+// @Success      204
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Failure      409  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/categories/{category_id} [delete]
+func (h *TaxonomyHandler) DeleteCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "category_id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteCategory(c.Request.Context(), id); err != nil {
+		requestLogger(c).WithField("category_id", id).WithError(err).Error("Failed to delete category")
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetTags godoc
+// @Summary      List tags
+// @Tags         catalog
+// @Produce      json
+// @Success      200  {array}  entities.Tag
+// @Security     BearerAuth
+// @Router       /api/tags [get]
+func (h *TaxonomyHandler) GetTags(c *gin.Context) {
+	tags, err := h.svc.GetTags(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, tags)
+}
+
+// CreateTag godoc
+// @Summary      Create a tag
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Param        tag  body      entities.Tag  true  "Tag name"
+// @Success      201  {object}  entities.Tag
+// @Failure      400  {object}  pkg.ErrorResponse
+// @Failure      409  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/tags [post]
+func (h *TaxonomyHandler) CreateTag(c *gin.Context) {
+	var tag entities.Tag
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for tag")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	tag.ID = 0
+
+	if err := h.svc.CreateTag(c.Request.Context(), &tag); err != nil {
+		requestLogger(c).WithField("tag", tag.Name).WithError(err).Error("Failed to create tag")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, tag)
+}
+
+// UpdateTag godoc
+// @Summary      Rename a tag
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Param        tag_id  path      int           true  "Tag ID"
+// @Param        tag     body      entities.Tag  true  "New tag name"
+// @Success      200     {object}  entities.Tag
+// @Failure      400     {object}  pkg.ErrorResponse
+// @Failure      404     {object}  pkg.ErrorResponse
+// @Failure      409     {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/tags/{tag_id} [put]
+func (h *TaxonomyHandler) UpdateTag(c *gin.Context) {
+	id, ok := parseIDParam(c, "tag_id")
+	if !ok {
+		return
+	}
+
+	var tag entities.Tag
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		requestLogger(c).WithError(err).Error("Failed to bind JSON for tag")
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+	tag.ID = id
+
+	if err := h.svc.UpdateTag(c.Request.Context(), &tag); err != nil {
+		requestLogger(c).WithField("tag_id", id).WithError(err).Error("Failed to update tag")
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, tag)
+}
+
+// DeleteTag godoc
+// @Summary      Delete a tag
+// @Description  Deletes the tag and removes it from all courses and lessons
+// @Tags         catalog
+// @Param        tag_id  path  int  true  "Tag ID"
+// @Success      204
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/tags/{tag_id} [delete]
+func (h *TaxonomyHandler) DeleteTag(c *gin.Context) {
+	id, ok := parseIDParam(c, "tag_id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteTag(c.Request.Context(), id); err != nil {
+		requestLogger(c).WithField("tag_id", id).WithError(err).Error("Failed to delete tag")
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SetCourseTaxonomy godoc
+// @Summary      Set course category and tags
+// @Description  Replaces the category and the full tag list of a course; unknown tags are created
+// @Tags         catalog
+// @Accept       json
+// @Param        course_id  path  int                      true  "Course ID"
+// @Param        request    body  handler.TaxonomyRequest  true  "Category and tags"
+// @Success      204
+// @Failure      400  {object}  pkg.ErrorResponse
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/taxonomy [put]
+func (h *TaxonomyHandler) SetCourseTaxonomy(c *gin.Context) {
+	id, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	var req TaxonomyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+
+	if err := h.svc.SetCourseTaxonomy(c.Request.Context(), id, req.CategoryID, req.Tags); err != nil {
+		requestLogger(c).WithField("course_id", id).WithError(err).Error("Failed to set course taxonomy")
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SetLessonTaxonomy godoc
+// @Summary      Set lesson category and tags
+// @Description  Replaces the category and the full tag list of a lesson; unknown tags are created
+// @Tags         catalog
+// @Accept       json
+// @Param        lesson_id  path  int                      true  "Lesson ID"
+// @Param        request    body  handler.TaxonomyRequest  true  "Category and tags"
+// @Success      204
+// @Failure      400  {object}  pkg.ErrorResponse
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/lessons/{lesson_id}/taxonomy [put]
+func (h *TaxonomyHandler) SetLessonTaxonomy(c *gin.Context) {
+	id, ok := parseIDParam(c, "lesson_id")
+	if !ok {
+		return
+	}
+
+	var req TaxonomyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(pkg.ErrInvalidInput)
+		return
+	}
+
+	if err := h.svc.SetLessonTaxonomy(c.Request.Context(), id, req.CategoryID, req.Tags); err != nil {
+		requestLogger(c).WithField("lesson_id", id).WithError(err).Error("Failed to set lesson taxonomy")
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"lms-system-internship/entities"
+	"lms-system-internship/middleware"
+	"lms-system-internship/mocks"
+	"lms-system-internship/tracing"
+)
+
+func TestTracing_PropagatesTraceContext(t *testing.T) {
+	var spans bytes.Buffer
+	exporter, err := tracing.NewStdoutExporter(&spans)
+	assert.NoError(t, err)
+	provider := tracing.NewProvider("lms-test", sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	versions := new(mocks.CourseVersionService)
+	versions.On("GetCourseVersion", mock.Anything, mock.Anything, false, uint(1), (*int)(nil)).Return(&entities.Course{ID: 1, Name: "Course"}, nil)
+
+	router := setupRouter()
+	router.Use(
+		otelgin.Middleware("lms-test", otelgin.WithTracerProvider(provider), otelgin.WithPropagators(propagation.TraceContext{})),
+		middleware.RequestID(),
+		withUser(uuid.New()),
+	)
+	router.GET("/api/courses/:course_id", NewCourseHandler(new(mocks.CourseService), versions).GetCourse)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/courses/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp := httptest.NewRecorder()
+
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, spans.String(), `"TraceID":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+	assert.Contains(t, spans.String(), `"Name":"/api/courses/:course_id"`)
+}
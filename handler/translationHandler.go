@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Content переводится только у уроков и блоков. У блока Name переводит подпись,
+	// Content — текст, а Description не используется.
+	Content string `json:"content"`
+}
+
+// TranslationHandler управляет переводами курсов, глав, уроков и блоков. Как и в RevisionHandler,
+// методы возвращают обработчик для конкретного типа сущности и имени path-параметра с её ID.
+type TranslationHandler struct {
+	svc service.TranslationService
+}
+
+func NewTranslationHandler(svc service.TranslationService) *TranslationHandler {
+	return &TranslationHandler{svc: svc}
+}
+
+// GetTranslations godoc
+// @Summary      List translations
+// @Tags         translations
+// @Produce      json
+// @Param        course_id   path      int  false  "Course ID"
+// @Param        chapter_id  path      int  false  "Chapter ID"
+// @Param        lesson_id   path      int  false  "Lesson ID"
+// @Param        block_id    path      int  false  "Lesson block ID"
+// @Success      200         {array}   entities.Translation
+// @Failure      404         {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/translations [get]
+// @Router       /api/chapters/{chapter_id}/translations [get]
+// @Router       /api/lessons/{lesson_id}/translations [get]
+// @Router       /api/lesson-blocks/{block_id}/translations [get]
+func (h *TranslationHandler) GetTranslations(entityType, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityID, ok := parseIDParam(c, param)
+		if !ok {
+			return
+		}
+
+		translations, err := h.svc.GetTranslations(c.Request.Context(), entityType, entityID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, translations)
+	}
+}
+
+// SetTranslation godoc
+// @Summary      Create or replace a translation
+// @Description  Stores the translation for a locale; empty fields fall back to the default locale text
+// @Tags         translations
+// @Accept       json
+// @Produce      json
+// @Param        course_id    path      int                 false  "Course ID"
+// @Param        chapter_id   path      int                 false  "Chapter ID"
+// @Param        lesson_id    path      int                 false  "Lesson ID"
+// @Param        block_id     path      int                 false  "Lesson block ID"
+// @Param        locale       path      string              true   "Locale, e.g. ru"
+// @Param        translation  body      TranslationRequest  true   "Translated fields"
+// @Success      200          {object}  entities.Translation
+// @Failure      400          {object}  pkg.ErrorResponse
+// @Failure      404          {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/translations/{locale} [put]
+// @Router       /api/chapters/{chapter_id}/translations/{locale} [put]
+// @Router       /api/lessons/{lesson_id}/translations/{locale} [put]
+// @Router       /api/lesson-blocks/{block_id}/translations/{locale} [put]
+func (h *TranslationHandler) SetTranslation(entityType, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityID, ok := parseIDParam(c, param)
+		if !ok {
+			return
+		}
+		var req TranslationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(pkg.ErrInvalidInput)
+			return
+		}
+
+		translation := &entities.Translation{
+			EntityType:  entityType,
+			EntityID:    entityID,
+			Locale:      c.Param("locale"),
+			Name:        req.Name,
+			Description: req.Description,
+			Content:     req.Content,
+		}
+		if err := h.svc.SetTranslation(c.Request.Context(), translation); err != nil {
+			requestLogger(c).WithField(param, entityID).WithField("locale", c.Param("locale")).WithError(err).Error("Failed to save translation")
+			c.Error(err)
+			return
+		}
+		requestLogger(c).WithField(param, entityID).WithField("locale", translation.Locale).Info("Translation saved")
+		c.JSON(http.StatusOK, translation)
+	}
+}
+
+// DeleteTranslation godoc
+// @Summary      Delete a translation
+// @Tags         translations
+// @Param        course_id   path  int     false  "Course ID"
+// @Param        chapter_id  path  int     false  "Chapter ID"
+// @Param        lesson_id   path  int     false  "Lesson ID"
+// @Param        block_id    path  int     false  "Lesson block ID"
+// @Param        locale      path  string  true   "Locale"
+// @Success      204
+// @Failure      404  {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/translations/{locale} [delete]
+// @Router       /api/chapters/{chapter_id}/translations/{locale} [delete]
+// @Router       /api/lessons/{lesson_id}/translations/{locale} [delete]
+// @Router       /api/lesson-blocks/{block_id}/translations/{locale} [delete]
+func (h *TranslationHandler) DeleteTranslation(entityType, param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entityID, ok := parseIDParam(c, param)
+		if !ok {
+			return
+		}
+
+		if err := h.svc.DeleteTranslation(c.Request.Context(), entityType, entityID, c.Param("locale")); err != nil {
+			c.Error(err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// MissingTranslations godoc
+// @Summary      Missing translations report
+// @Description  Lists, per supported locale, the course, chapters, lessons and lesson blocks whose non-empty fields have no translation
+// @Tags         translations
+// @Produce      json
+// @Param        course_id  path      int  true  "Course ID"
+// @Success      200        {object}  service.TranslationReport
+// @Failure      404        {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/courses/{course_id}/translations/missing [get]
+func (h *TranslationHandler) MissingTranslations(c *gin.Context) {
+	courseID, ok := parseIDParam(c, "course_id")
+	if !ok {
+		return
+	}
+
+	report, err := h.svc.MissingTranslations(c.Request.Context(), courseID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UserImportResultResponse struct {
+	Line      int        `json:"line"`
+	Username  string     `json:"username"`
+	Status    string     `json:"status"` // created или failed
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	ErrorCode string     `json:"error_code,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// UserImportJobResponse — состояние задачи импорта; Results пополняется по мере обработки строк.
+type UserImportJobResponse struct {
+	ID         uuid.UUID                  `json:"id"`
+	Status     string                     `json:"status"` // running, completed или cancelled
+	CreatedBy  uuid.UUID                  `json:"created_by"`
+	CreatedAt  time.Time                  `json:"created_at"`
+	FinishedAt *time.Time                 `json:"finished_at"`
+	Total      int                        `json:"total"`
+	Processed  int                        `json:"processed"`
+	Succeeded  int                        `json:"succeeded"`
+	Failed     int                        `json:"failed"`
+	Results    []UserImportResultResponse `json:"results"`
+}
+
+type UserImportHandler struct {
+	svc     service.UserImportService
+	maxRows int
+}
+
+func NewUserImportHandler(svc service.UserImportService, maxRows int) *UserImportHandler {
+	return &UserImportHandler{svc: svc, maxRows: maxRows}
+}
+
+// StartImport godoc
+// @Summary      Import users from CSV (admin only)
+// @Description  Creates users with roles and grants them access to every lesson of the listed courses. The first row is a header;
+// @Description  columns: username, email (required), name ("First Last"), roles and course_ids (several values separated by "|").
+// @Description  Comma and semicolon delimiters are accepted. Rows are processed in the background; poll the returned job for the per-row report.
+// @Description  Each created user gets an email with a link to set their password (valid for INVITE_TOKEN_TTL)
+// @Tags         admin
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file  formData  file  true  "CSV file"
+// @Success      202   {object}  handler.UserImportJobResponse
+// @Failure      400   {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/user-imports [post]
+func (h *UserImportHandler) StartImport(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		c.Error(pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "file", Code: "required"}))
+		return
+	}
+	defer file.Close()
+
+	rows, err := service.ParseUserImportCSV(file, h.maxRows)
+	if err != nil {
+		requestLogger(c).WithError(err).Error("Invalid user import file")
+		c.Error(err)
+		return
+	}
+
+	job, err := h.svc.StartImport(c.Request.Context(), userID, rows)
+	if err != nil {
+		requestLogger(c).WithError(err).Error("Failed to start user import")
+		c.Error(err)
+		return
+	}
+	c.Header("Location", "/api/admin/user-imports/"+job.ID.String())
+	c.JSON(http.StatusAccepted, newUserImportJobResponse(job))
+}
+
+// GetImport godoc
+// @Summary      Get a user import job (admin only)
+// @Description  Returns the job state and the report for rows processed so far. Finished jobs are kept for USER_IMPORT_JOB_RETENTION
+// @Tags         admin
+// @Produce      json
+// @Param        job_id  path      string  true  "Job ID"
+// @Success      200     {object}  handler.UserImportJobResponse
+// @Failure      400     {object}  pkg.ErrorResponse
+// @Failure      404     {object}  pkg.ErrorResponse
+// @Security     BearerAuth
+// @Router       /api/admin/user-imports/{job_id} [get]
+func (h *UserImportHandler) GetImport(c *gin.Context) {
+	jobID, ok := parseUUIDParam(c, "job_id")
+	if !ok {
+		return
+	}
+
+	job, err := h.svc.GetJob(c.Request.Context(), jobID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newUserImportJobResponse(job))
+}
+
+func newUserImportJobResponse(job *entities.UserImportJob) UserImportJobResponse {
+	resp := UserImportJobResponse{
+		ID:         job.ID,
+		Status:     job.Status,
+		CreatedBy:  job.CreatedBy,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+		Total:      job.Total,
+		Processed:  len(job.Results),
+		Succeeded:  job.Succeeded,
+		Failed:     job.Failed,
+		Results:    make([]UserImportResultResponse, 0, len(job.Results)),
+	}
+	for _, result := range job.Results {
+		resp.Results = append(resp.Results, UserImportResultResponse{
+			Line:      result.Line,
+			Username:  result.Username,
+			Status:    result.Status,
+			UserID:    result.UserID,
+			ErrorCode: result.ErrorCode,
+			Error:     result.Error,
+		})
+	}
+	return resp
+}
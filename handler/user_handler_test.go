@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"lms-system-internship/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeIdentityService запоминает последний вызов и возвращает err.
+type fakeIdentityService struct {
+	err       error
+	newUser   service.NewUser
+	userID    uuid.UUID
+	update    service.ProfileUpdate
+	roles     []string
+	createdID uuid.UUID
+}
+
+func (f *fakeIdentityService) RegisterUser(_ context.Context, user service.NewUser) (uuid.UUID, error) {
+	f.newUser = user
+	return f.createdID, f.err
+}
+
+func (f *fakeIdentityService) UpdateProfile(_ context.Context, userID uuid.UUID, update service.ProfileUpdate) error {
+	f.userID, f.update = userID, update
+	return f.err
+}
+
+func (f *fakeIdentityService) ReplaceRoles(_ context.Context, userID uuid.UUID, roles []string) error {
+	f.userID, f.roles = userID, roles
+	return f.err
+}
+
+func (f *fakeIdentityService) ListUsers(context.Context, int, int) ([]service.UserAccount, bool, error) {
+	return nil, false, f.err
+}
+
+func (f *fakeIdentityService) GetUser(context.Context, uuid.UUID) (*service.UserAccount, error) {
+	return nil, f.err
+}
+
+func (f *fakeIdentityService) SetEnabled(context.Context, uuid.UUID, bool) error { return f.err }
+
+func (f *fakeIdentityService) DeleteUser(context.Context, uuid.UUID) error { return f.err }
+
+func (f *fakeIdentityService) FindUserByEmail(context.Context, string) (*service.UserAccount, error) {
+	return nil, f.err
+}
+
+func (f *fakeIdentityService) ConfirmEmail(context.Context, uuid.UUID) error { return f.err }
+
+// fakeUserAdminService отдаёт заданные профили и запоминает, с какими аргументами его вызвали.
+type fakeUserAdminService struct {
+	err       error
+	profiles  []*entities.UserProfile
+	details   *service.UserDetails
+	filter    repo.UserFilter
+	userID    uuid.UUID
+	enabled   *bool
+	refreshed []uuid.UUID
+}
+
+func (f *fakeUserAdminService) ListUsers(_ context.Context, filter repo.UserFilter) ([]*entities.UserProfile, int64, error) {
+	f.filter = filter
+	return f.profiles, int64(len(f.profiles)), f.err
+}
+
+func (f *fakeUserAdminService) GetUser(_ context.Context, userID uuid.UUID) (*service.UserDetails, error) {
+	f.userID = userID
+	return f.details, f.err
+}
+
+func (f *fakeUserAdminService) SetEnabled(_ context.Context, userID uuid.UUID, enabled bool) error {
+	f.userID, f.enabled = userID, &enabled
+	return f.err
+}
+
+func (f *fakeUserAdminService) DeleteUser(_ context.Context, userID uuid.UUID) error {
+	f.userID = userID
+	return f.err
+}
+
+func (f *fakeUserAdminService) RefreshUser(_ context.Context, userID uuid.UUID) error {
+	f.refreshed = append(f.refreshed, userID)
+	return nil
+}
+
+func TestUserHandler_RegisterUser(t *testing.T) {
+	users := &fakeUserAdminService{}
+	post := func(fake *fakeIdentityService, body string) *httptest.ResponseRecorder {
+		router := setupRouter()
+		router.POST("/api/admin/register", NewUserHandler(fake, users).RegisterUser)
+		req, _ := http.NewRequest(http.MethodPost, "/api/admin/register", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("success", func(t *testing.T) {
+		fake := &fakeIdentityService{createdID: uuid.New()}
+
+		resp := post(fake, `{"username":"alice","email":"alice@example.com","password":"secret1","roles":["ROLE_TEACHER"]}`)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		assert.Contains(t, resp.Body.String(), fake.createdID.String())
+		assert.Equal(t, service.NewUser{Username: "alice", Email: "alice@example.com", Password: "secret1", Roles: []string{"ROLE_TEACHER"}}, fake.newUser)
+		assert.Equal(t, []uuid.UUID{fake.createdID}, users.refreshed, "new user goes to the profile cache")
+	})
+
+	t.Run("invalid email", func(t *testing.T) {
+		resp := post(&fakeIdentityService{}, `{"username":"alice","email":"nope","password":"secret1"}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		var body pkg.ErrorResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, "email", body.Fields[0].Field)
+	})
+
+	t.Run("user already exists", func(t *testing.T) {
+		resp := post(&fakeIdentityService{err: pkg.ErrUserAlreadyExists}, `{"username":"alice","email":"alice@example.com","password":"secret1"}`)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+	})
+}
+
+func TestUserHandler_UpdateUserProfile(t *testing.T) {
+	t.Run("updates the current user", func(t *testing.T) {
+		userID := uuid.New()
+		fake := &fakeIdentityService{}
+		router := setupRouter()
+		router.PUT("/api/user/profile", withUser(userID), NewUserHandler(fake, &fakeUserAdminService{}).UpdateUserProfile)
+
+		req, _ := http.NewRequest(http.MethodPut, "/api/user/profile", bytes.NewBufferString(`{"first_name":"Alice"}`))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, userID, fake.userID)
+		assert.Equal(t, service.ProfileUpdate{FirstName: "Alice"}, fake.update)
+	})
+
+	t.Run("anonymous request", func(t *testing.T) {
+		router := setupRouter()
+		router.PUT("/api/user/profile", NewUserHandler(&fakeIdentityService{}, &fakeUserAdminService{}).UpdateUserProfile)
+
+		req, _ := http.NewRequest(http.MethodPut, "/api/user/profile", bytes.NewBufferString(`{}`))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+}
+
+func TestUserHandler_UpdateUserRoles(t *testing.T) {
+	post := func(fake *fakeIdentityService, body string) *httptest.ResponseRecorder {
+		router := setupRouter()
+		router.POST("/api/admin/update-roles", NewUserHandler(fake, &fakeUserAdminService{}).UpdateUserRoles)
+		req, _ := http.NewRequest(http.MethodPost, "/api/admin/update-roles", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("success", func(t *testing.T) {
+		userID := uuid.New()
+		fake := &fakeIdentityService{}
+
+		resp := post(fake, `{"user_id":"`+userID.String()+`","new_roles":["ROLE_ADMIN"]}`)
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, userID, fake.userID)
+		assert.Equal(t, []string{"ROLE_ADMIN"}, fake.roles)
+	})
+
+	t.Run("user id is not a uuid", func(t *testing.T) {
+		resp := post(&fakeIdentityService{}, `{"user_id":"42","new_roles":[]}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"field":"user_id"`)
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		resp := post(&fakeIdentityService{err: pkg.ErrInvalidRole.WithDetails(map[string]any{"role": "ROLE_X"})},
+			`{"user_id":"`+uuid.NewString()+`","new_roles":["ROLE_X"]}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), "ROLE_X")
+	})
+}
+
+func TestUserHandler_ListUsers(t *testing.T) {
+	get := func(users *fakeUserAdminService, query string) *httptest.ResponseRecorder {
+		router := setupRouter()
+		router.GET("/api/admin/users", NewUserHandler(&fakeIdentityService{}, users).ListUsers)
+		req, _ := http.NewRequest(http.MethodGet, "/api/admin/users"+query, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("passes the filter and returns the page", func(t *testing.T) {
+		users := &fakeUserAdminService{profiles: []*entities.UserProfile{{ID: uuid.New(), Username: "alice", Enabled: true}}}
+
+		resp := get(users, "?q=ali&enabled=true&limit=10&offset=20")
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		enabled := true
+		assert.Equal(t, repo.UserFilter{Query: "ali", Enabled: &enabled, Limit: 10, Offset: 20}, users.filter)
+		var body UserListResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, int64(1), body.Total)
+		assert.Equal(t, "alice", body.Items[0].Username)
+	})
+
+	t.Run("invalid enabled flag", func(t *testing.T) {
+		resp := get(&fakeUserAdminService{}, "?enabled=maybe")
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"field":"enabled"`)
+	})
+}
+
+func TestUserHandler_GetUser(t *testing.T) {
+	get := func(users *fakeUserAdminService, id string) *httptest.ResponseRecorder {
+		router := setupRouter()
+		router.GET("/api/admin/users/:user_id", NewUserHandler(&fakeIdentityService{}, users).GetUser)
+		req, _ := http.NewRequest(http.MethodGet, "/api/admin/users/"+id, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("returns roles and lesson grants", func(t *testing.T) {
+		userID := uuid.New()
+		users := &fakeUserAdminService{details: &service.UserDetails{
+			Profile:   entities.UserProfile{ID: userID, Username: "alice"},
+			Roles:     []string{"ROLE_STUDENT"},
+			LessonIDs: []uint{3, 5},
+		}}
+
+		resp := get(users, userID.String())
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		var body UserDetailsResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, userID, body.ID)
+		assert.Equal(t, []string{"ROLE_STUDENT"}, body.Roles)
+		assert.Equal(t, []uint{3, 5}, body.LessonIDs)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		resp := get(&fakeUserAdminService{err: pkg.ErrUserNotFound}, uuid.NewString())
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("id is not a uuid", func(t *testing.T) {
+		resp := get(&fakeUserAdminService{}, "42")
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestUserHandler_SetUserEnabled(t *testing.T) {
+	put := func(users *fakeUserAdminService, body string) *httptest.ResponseRecorder {
+		router := setupRouter()
+		router.PUT("/api/admin/users/:user_id/enabled", NewUserHandler(&fakeIdentityService{}, users).SetUserEnabled)
+		req, _ := http.NewRequest(http.MethodPut, "/api/admin/users/"+uuid.NewString()+"/enabled", bytes.NewBufferString(body))
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("disables the user", func(t *testing.T) {
+		users := &fakeUserAdminService{}
+
+		resp := put(users, `{"enabled":false}`)
+
+		assert.Equal(t, http.StatusNoContent, resp.Code)
+		if assert.NotNil(t, users.enabled) {
+			assert.False(t, *users.enabled)
+		}
+	})
+
+	t.Run("enabled is required", func(t *testing.T) {
+		resp := put(&fakeUserAdminService{}, `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), `"field":"enabled"`)
+	})
+}
+
+func TestUserHandler_DeleteUser(t *testing.T) {
+	userID := uuid.New()
+	users := &fakeUserAdminService{}
+	router := setupRouter()
+	router.DELETE("/api/admin/users/:user_id", NewUserHandler(&fakeIdentityService{}, users).DeleteUser)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/api/admin/users/"+userID.String(), nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, userID, users.userID)
+}
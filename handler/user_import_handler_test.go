@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeUserImportService запоминает переданные строки и отдаёт job.
+type fakeUserImportService struct {
+	rows []service.UserImportRow
+	job  *entities.UserImportJob
+}
+
+func (f *fakeUserImportService) StartImport(_ context.Context, _ uuid.UUID, rows []service.UserImportRow) (*entities.UserImportJob, error) {
+	f.rows = rows
+	return f.job, nil
+}
+
+func (f *fakeUserImportService) GetJob(_ context.Context, jobID uuid.UUID) (*entities.UserImportJob, error) {
+	if f.job == nil || f.job.ID != jobID {
+		return nil, pkg.ErrImportJobNotFound
+	}
+	return f.job, nil
+}
+
+func TestUserImportHandler_StartImport(t *testing.T) {
+	upload := func(fake *fakeUserImportService, csv string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("file", "users.csv")
+		part.Write([]byte(csv))
+		form.Close()
+
+		router := setupRouter()
+		router.POST("/api/admin/user-imports", withUser(uuid.New()), NewUserImportHandler(fake, 100).StartImport)
+		req, _ := http.NewRequest(http.MethodPost, "/api/admin/user-imports", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("starts a job", func(t *testing.T) {
+		fake := &fakeUserImportService{job: &entities.UserImportJob{ID: uuid.New(), Status: service.UserImportRunning, Total: 1}}
+
+		resp := upload(fake, "username,email\nann,ann@example.com\n")
+
+		assert.Equal(t, http.StatusAccepted, resp.Code)
+		assert.Equal(t, "/api/admin/user-imports/"+fake.job.ID.String(), resp.Header().Get("Location"))
+		assert.Len(t, fake.rows, 1)
+		var body UserImportJobResponse
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, "running", body.Status)
+	})
+
+	t.Run("file without required columns", func(t *testing.T) {
+		fake := &fakeUserImportService{}
+
+		resp := upload(fake, "login\nann\n")
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Nil(t, fake.rows)
+	})
+}
+
+func TestUserImportHandler_GetImport(t *testing.T) {
+	userID := uuid.New()
+	fake := &fakeUserImportService{job: &entities.UserImportJob{
+		ID:        uuid.New(),
+		Status:    service.UserImportCompleted,
+		Total:     2,
+		Succeeded: 1,
+		Failed:    1,
+		Results: []entities.UserImportResult{
+			{Line: 2, Username: "ann", Status: service.UserImportRowCreated, UserID: &userID},
+			{Line: 3, Username: "bob", Status: service.UserImportRowFailed, ErrorCode: "user_already_exists", Error: "user already exists"},
+		},
+	}}
+	router := setupRouter()
+	router.GET("/api/admin/user-imports/:job_id", NewUserImportHandler(fake, 100).GetImport)
+
+	get := func(id string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/api/admin/user-imports/"+id, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := get(fake.job.ID.String())
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body UserImportJobResponse
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	assert.Equal(t, 2, body.Processed)
+	assert.Equal(t, &userID, body.Results[0].UserID)
+	assert.Equal(t, "user_already_exists", body.Results[1].ErrorCode)
+
+	assert.Equal(t, http.StatusNotFound, get(uuid.NewString()).Code)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"lms-system-internship/pkg"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// В ошибках полей нужны имена из JSON, а не имена полей Go-структуры.
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// bindJSON разбирает тело запроса в req и проверяет правила из тегов binding.
+// При ошибке записывает ErrInvalidInput с ошибками по полям и возвращает false.
+func bindJSON(c *gin.Context, req any) bool {
+	err := c.ShouldBindJSON(req)
+	if err == nil {
+		return true
+	}
+	requestLogger(c).WithError(err).Warn("Invalid request body")
+	c.Error(pkg.ErrInvalidInput.WithFields(fieldErrors(err)...))
+	return false
+}
+
+// fieldErrors переводит ошибки валидатора и декодера JSON в ошибки полей.
+func fieldErrors(err error) []pkg.FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]pkg.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, pkg.FieldError{Field: fe.Field(), Code: validationCode(fe), Param: fe.Param()})
+		}
+		return fields
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []pkg.FieldError{{Field: typeErr.Field, Code: "invalid"}}
+	}
+	return nil
+}
+
+// validationCode возвращает код правила; для строк min и max означают длину.
+func validationCode(fe validator.FieldError) string {
+	if fe.Kind() == reflect.String && (fe.Tag() == "min" || fe.Tag() == "max") {
+		return fe.Tag() + "_length"
+	}
+	return fe.Tag()
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"lms-system-internship/pkg"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Состояния проверок и сервиса в целом.
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// Check — проверка одной зависимости. Run должен уважать отмену ctx.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// CheckResult — состояние зависимости. Текст ошибки только логируется: в нём бывают адреса
+// и имена внутренних сервисов, а /readyz доступен без аутентификации.
+type CheckResult struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// Report — результат проверки готовности: общее состояние и состояние каждой зависимости.
+type Report struct {
+	Status   string                 `json:"status"`
+	Draining bool                   `json:"draining,omitempty"`
+	Checks   map[string]CheckResult `json:"checks"`
+}
+
+// Checker выполняет проверки зависимостей параллельно, каждую не дольше timeout.
+type Checker struct {
+	timeout  time.Duration
+	checks   []Check
+	draining atomic.Bool
+}
+
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	return &Checker{timeout: timeout, checks: checks}
+}
+
+// SetDraining переводит сервис в состояние остановки: с этого момента он не готов
+// независимо от зависимостей, чтобы балансировщик перестал слать новые запросы.
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+// Ready запускает все проверки. Сервис готов, только если все зависимости доступны
+// и он не останавливается.
+func (c *Checker) Ready(ctx context.Context) Report {
+	report := Report{Status: StatusReady, Checks: make(map[string]CheckResult, len(c.checks))}
+	if c.draining.Load() {
+		report.Status = StatusNotReady
+		report.Draining = true
+		return report
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range c.checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			result := c.run(ctx, check)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusNotReady
+			}
+		}(check)
+	}
+	wg.Wait()
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Run(ctx)
+	result := CheckResult{Status: StatusUp, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusDown
+		pkg.LoggerFromContext(ctx).WithError(err).WithField("check", check.Name).Warn("Readiness check failed")
+	}
+	return result
+}
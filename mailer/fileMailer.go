@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer сохраняет каждое письмо в каталог файлом .eml, который открывается почтовым клиентом.
+// Нужен для локальной разработки без SMTP-сервера.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer создаёт каталог dir, если его нет.
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(_ context.Context, msg Message) error {
+	now := time.Now()
+	data, err := formatMessage(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%d.eml", now.UTC().Format("20060102T150405"), now.UnixNano()%1e9)
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o600)
+}
@@ -10,48 +10,181 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"lms-system-internship/config"
 	_ "lms-system-internship/docs" // важно: импорт без использования
 	"lms-system-internship/entities"
+	"lms-system-internship/metrics"
 	"lms-system-internship/middleware"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
 	"lms-system-internship/router"
-	"log"
+	"lms-system-internship/tracing"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
-var db *gorm.DB
-
-func initDB() {
-	var err error
-	db, err = gorm.Open(postgres.Open("user=postgres password=qwerty dbname=goDB host=db port=5432 sslmode=disable"), &gorm.Config{})
-	//db, err = gorm.Open(postgres.Open("user=ilia password=postgres dbname=postgres host=localhost port=5432 sslmode=disable"), &gorm.Config{})
+// openDB настраивает подключение к базе, не обращаясь к ней: соединения открываются при первом запросе.
+func openDB() *gorm.DB {
+	db, err := gorm.Open(postgres.Open("user=postgres password=qwerty dbname=goDB host=db port=5432 sslmode=disable"), &gorm.Config{DisableAutomaticPing: true})
+	//db, err := gorm.Open(postgres.Open("user=ilia password=postgres dbname=postgres host=localhost port=5432 sslmode=disable"), &gorm.Config{DisableAutomaticPing: true})
 	if err != nil {
-		log.Fatal(err)
+		pkg.Logger.WithError(err).Fatal("Invalid database configuration")
+	}
+	if err = db.Use(metrics.GormPlugin{}); err != nil {
+		pkg.Logger.WithError(err).Fatal("Failed to register database metrics")
+	}
+	if err = db.Use(tracing.GormPlugin{}); err != nil {
+		pkg.Logger.WithError(err).Fatal("Failed to register database tracing")
 	}
-	err = db.AutoMigrate(&entities.Course{}, &entities.Chapter{}, &entities.Lesson{}, &entities.Attachment{}, &entities.LessonUser{})
+	return db
+}
+
+// migrateDB применяет миграции, повторяя попытки, пока база не станет доступна или не будет
+// отменён ctx. Сервер в это время уже принимает запросы, а /readyz сообщает, что база не готова.
+func migrateDB(ctx context.Context, db *gorm.DB, ready *atomic.Bool) {
+	for attempt := 1; ; attempt++ {
+		err := migrate(db.WithContext(ctx))
+		if err == nil {
+			ready.Store(true)
+			pkg.Logger.Info("Database migrated")
+			return
+		}
+		pkg.Logger.WithError(err).WithField("attempt", attempt).Warn("Failed to migrate database, retrying")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(config.GetDatabaseRetryInterval()):
+		}
+	}
+}
 
+func migrate(db *gorm.DB) error {
+	err := db.AutoMigrate(&entities.Course{}, &entities.Chapter{}, &entities.Lesson{}, &entities.Attachment{}, &entities.LessonUser{}, &entities.Assignment{}, &entities.Submission{},
+		&entities.GradeCategory{}, &entities.GradeItem{}, &entities.Grade{}, &entities.GradeAudit{},
+		&entities.Certificate{}, &entities.CompletionRule{}, &entities.Category{}, &entities.Tag{},
+		&entities.LessonBlock{}, &entities.Revision{}, &entities.CourseVersion{}, &entities.CourseVersionPin{},
+		&entities.Translation{}, &entities.UserProfile{}, &entities.EmailToken{},
+		&entities.UserImportJob{}, &entities.UserImportResult{})
+	if err != nil {
+		return err
+	}
+	if err = repo.MigrateSearch(db); err != nil {
+		return err
+	}
+	return repo.MigrateLessonBlocks(db)
 }
 
 func main() {
-	initDB()
+	if err := pkg.ConfigureLogger(config.GetLogLevel(), config.GetLogFormat()); err != nil {
+		pkg.Logger.WithError(err).Warn("Invalid logging configuration, using defaults")
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), config.GetServiceName(), config.GetTracesExporter())
+	if err != nil {
+		pkg.Logger.WithError(err).Fatal("Failed to set up tracing")
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			pkg.Logger.WithError(err).Warn("Failed to flush traces")
+		}
+	}()
+
+	db := openDB()
 	defer func() {
 		s, err := db.DB()
+		if err == nil {
+			err = s.Close()
+		}
 		if err != nil {
-			log.Fatal(err)
+			pkg.Logger.WithError(err).Error("Failed to close database")
 		}
-		s.Close()
 	}()
 
-	r := gin.Default()
+	r := gin.New()
+	if err := r.SetTrustedProxies(config.GetTrustedProxies()); err != nil {
+		pkg.Logger.WithError(err).Fatal("Invalid TRUSTED_PROXIES")
+	}
+	r.Use(otelgin.Middleware(config.GetServiceName()), middleware.RequestID(), middleware.AccessLog("/healthz", "/readyz"), middleware.Metrics(), middleware.ErrorHandler(), middleware.Recovery())
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	r.Use(middleware.ErrorHandler())
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	r.NoRoute(func(c *gin.Context) { c.Error(pkg.ErrRouteNotFound) })
+
+	// Фоновые задачи (миграции базы, обновление ключей Keycloak) живут до отмены background,
+	// которую делаем только после того, как сервер перестал обслуживать запросы. Задачи из tasks
+	// (импорт пользователей) после отмены дорабатывают текущую строку, и их дожидаемся.
+	background, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	var tasks sync.WaitGroup
+	var dbReady atomic.Bool
+	checker := router.SetupRoutes(background, db, dbReady.Load, &tasks, r)
+
+	srv := &http.Server{
+		Addr:              config.GetServerAddr(),
+		Handler:           r,
+		ReadHeaderTimeout: config.GetReadHeaderTimeout(),
+		ReadTimeout:       config.GetReadTimeout(),
+		WriteTimeout:      config.GetWriteTimeout(),
+		IdleTimeout:       config.GetIdleTimeout(),
+	}
+
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		pkg.Logger.WithField("addr", srv.Addr).Info("Server started")
+		serveErr <- srv.ListenAndServe()
+	}()
+	go migrateDB(background, db, &dbReady)
 
-	router.SetupRoutes(db, r)
-	if err := r.Run(":3030"); err != nil {
-		fmt.Printf("Error starting server: %v\n", err)
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			pkg.Logger.WithError(err).Error("Server stopped")
+		}
+		return
+	case <-signals.Done():
+		stopSignals() // повторный сигнал завершит процесс сразу
+	}
+
+	// Сначала /readyz начинает отвечать 503, и балансировщик перестаёт слать новые запросы;
+	// начатые запросы, в том числе загрузки файлов, дорабатывают до SHUTDOWN_TIMEOUT.
+	pkg.Logger.WithField("drain_period", config.GetShutdownDrainPeriod().String()).Info("Shutting down, draining traffic")
+	checker.SetDraining()
+	time.Sleep(config.GetShutdownDrainPeriod())
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.GetShutdownTimeout())
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		pkg.Logger.WithError(err).Error("Server did not shut down gracefully")
+	}
+	stopBackground()
+	waitBackground(&tasks, config.GetShutdownTimeout())
+	pkg.Logger.Info("Server stopped")
+}
+
+// waitBackground ждёт завершения фоновых задач не дольше timeout.
+func waitBackground(tasks *sync.WaitGroup, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		tasks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		pkg.Logger.WithField("timeout", timeout.String()).Warn("Background tasks did not finish before shutdown")
 	}
 }
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"lms-system-internship/pkg"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startTimeKey = "metrics:start_time"
+
+// GormPlugin записывает длительность каждого запроса GORM в DBQueryDuration.
+// Подключается через db.Use(metrics.GormPlugin{}).
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string { return "metrics" }
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	return pkg.RegisterGormCallbacks(db, "metrics", func(string) func(*gorm.DB) { return startTimer }, observe)
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(startTimeKey, time.Now())
+}
+
+func observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startTimeKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		outcome := "success"
+		if pkg.IsGormFailure(db.Error) {
+			outcome = "error"
+		}
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		DBQueryDuration.WithLabelValues(operation, table, outcome).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"io"
+	"lms-system-internship/pkg"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AccessLog пишет по одной структурированной записи на запрос: статус, длительность, размер
+// ответа и клиент. Должен стоять после RequestID, чтобы запись несла ID запроса.
+// 5xx логируются как error, 4xx — как warn. Успешные запросы к quietPaths (пробы
+// Kubernetes) не логируются.
+func AccessLog(quietPaths ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusBadRequest && slices.Contains(quietPaths, c.Request.URL.Path) {
+			return
+		}
+		entry := pkg.LoggerFromContext(c.Request.Context()).WithFields(logrus.Fields{
+			"status":      status,
+			"latency_ms":  time.Since(start).Milliseconds(),
+			"bytes":       c.Writer.Size(),
+			"path":        c.Request.URL.Path,
+			"client_ip":   c.ClientIP(),
+			"user_agent":  c.Request.UserAgent(),
+			"error_count": len(c.Errors),
+		})
+		switch {
+		case status >= 500:
+			entry.Error("Request completed")
+		case status >= 400:
+			entry.Warn("Request completed")
+		default:
+			entry.Info("Request completed")
+		}
+	}
+}
+
+// Recovery перехватывает панику обработчика, логирует её со стеком в логгер запроса
+// и отвечает pkg.ErrInternal через ErrorHandler.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		pkg.LoggerFromContext(c.Request.Context()).WithFields(logrus.Fields{
+			"panic": recovered,
+			"stack": string(debug.Stack()),
+		}).Error("Recovered from panic")
+		AbortWithError(c, pkg.ErrInternal)
+	})
+}
@@ -1,59 +1,46 @@
 package middleware
 
 import (
+	"errors"
 	"github.com/google/uuid"
-	"net/http"
+	"lms-system-internship/pkg"
+	"lms-system-internship/service"
 	"strings"
 
-	"github.com/MicahParks/keyfunc"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/sirupsen/logrus"
 )
 
-// TokenAuthMiddleware проверяет JWT токен и сохраняет роли пользователя в контексте
-func TokenAuthMiddleware(jwks *keyfunc.JWKS) gin.HandlerFunc {
+// TokenAuthMiddleware проверяет токен у провайдера идентификации и кладёт пользователя
+// (pkg.Principal) в контекст запроса. Пока провайдер не готов (например, не загружены ключи Keycloak),
+// запросы отклоняются с pkg.ErrIdentityUnavailable.
+func TokenAuthMiddleware(provider service.IdentityProvider) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid Authorization header"})
+			c.Header("WWW-Authenticate", "Bearer")
+			AbortWithError(c, pkg.ErrUnauthorized)
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		token, err := jwt.Parse(tokenString, jwks.Keyfunc)
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token", "details": err.Error()})
-			return
-		}
-
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+		principal, err := provider.Verify(c.Request.Context(), tokenString)
+		if err != nil {
+			// Причина отказа (просрочен, чужой издатель, отозван) остаётся в логе;
+			// клиент получает только код ошибки.
+			pkg.LoggerFromContext(c.Request.Context()).WithError(err).Warn("Rejected access token")
+			if errors.Is(err, pkg.ErrInvalidToken) {
+				c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			}
+			AbortWithError(c, err)
 			return
 		}
 
-		if username, ok := claims["preferred_username"].(string); ok {
-			c.Set("username", username)
+		c.Request = c.Request.WithContext(pkg.WithPrincipal(c.Request.Context(), principal))
+		if principal.ID != uuid.Nil {
+			addLogFields(c, logrus.Fields{"user_id": principal.ID.String()})
 		}
-		if sub, ok := claims["sub"].(string); ok {
-			id, err := uuid.Parse(sub)
-			if err == nil {
-				c.Set("userID", id)
-			}
-		}
-		roles := []string{}
-		if realmAccess, ok := claims["realm_access"].(map[string]interface{}); ok {
-			if rolesRaw, ok := realmAccess["roles"].([]interface{}); ok {
-				for _, role := range rolesRaw {
-					if roleStr, ok := role.(string); ok {
-						roles = append(roles, roleStr)
-					}
-				}
-			}
-		}
-
-		c.Set("roles", roles)
 		c.Next()
 	}
 }
@@ -2,45 +2,58 @@ package middleware
 
 import (
 	"errors"
-	"net/http"
 
 	"lms-system-internship/pkg"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ErrorHandler превращает последнюю ошибку из c.Errors в ErrorResponse. Ошибки вне таксономии
+// (не *pkg.AppError) логируются и отдаются как pkg.ErrInternal без подробностей.
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
-		if len(c.Errors) > 0 {
-			err := c.Errors.Last()
-
-			// Default error response
-			status := http.StatusInternalServerError
-			message := "Internal server error"
-
-			// Handle specific error types
-			switch {
-			case errors.Is(err, pkg.ErrCourseNotFound),
-				errors.Is(err, pkg.ErrChapterNotFound),
-				errors.Is(err, pkg.ErrLessonNotFound):
-				status = http.StatusNotFound
-				message = err.Error()
-
-			case errors.Is(err, pkg.ErrInvalidInput):
-				status = http.StatusBadRequest
-				message = err.Error()
-
-			default:
-				// For unexpected errors, keep the internal server error status
-				// but log the detailed error for debugging
-				pkg.Logger.WithError(err).Error("Unexpected error occurred")
-			}
-
-			c.JSON(status, pkg.ErrorResponse{
-				Message: message,
-			})
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
 		}
+		err := c.Errors.Last().Err
+
+		log := pkg.LoggerFromContext(c.Request.Context())
+		var appErr *pkg.AppError
+		if !errors.As(err, &appErr) {
+			log.WithError(err).Error("Unexpected error occurred")
+			appErr = pkg.ErrInternal
+		} else if appErr.Unwrap() != nil {
+			log.WithError(err).WithField("code", appErr.Code).Warn("Request failed")
+		}
+
+		c.JSON(appErr.Status, NewErrorResponse(c, appErr))
+	}
+}
+
+// NewErrorResponse собирает тело ответа на локали запроса и с его ID.
+func NewErrorResponse(c *gin.Context, appErr *pkg.AppError) pkg.ErrorResponse {
+	ctx := c.Request.Context()
+	locale := pkg.LocaleFromContext(ctx)
+
+	var fields []pkg.FieldError
+	for _, field := range appErr.Fields {
+		fields = append(fields, pkg.LocalizeField(locale, field))
+	}
+
+	return pkg.ErrorResponse{
+		Message:   pkg.Localize(locale, appErr.Key, appErr.Message),
+		Code:      appErr.Code,
+		Key:       appErr.Key,
+		Details:   appErr.Details,
+		Fields:    fields,
+		RequestID: pkg.RequestIDFromContext(ctx),
 	}
 }
+
+// AbortWithError прерывает цепочку обработчиков; ответ запишет ErrorHandler.
+func AbortWithError(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"lms-system-internship/pkg"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// Locale выбирает из supported локаль, лучше всего подходящую под Accept-Language,
+// и кладёт её в контекст запроса. Без заголовка или без совпадений выбирается defaultLocale.
+func Locale(defaultLocale string, supported []string) gin.HandlerFunc {
+	locales := []string{defaultLocale}
+	for _, locale := range supported {
+		if locale != defaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	tags := make([]language.Tag, len(locales))
+	for i, locale := range locales {
+		tags[i] = language.Make(locale)
+	}
+	matcher := language.NewMatcher(tags)
+
+	return func(c *gin.Context) {
+		locale := defaultLocale
+		if header := c.GetHeader("Accept-Language"); header != "" {
+			if preferred, _, err := language.ParseAcceptLanguage(header); err == nil && len(preferred) > 0 {
+				if _, index, confidence := matcher.Match(preferred...); confidence != language.No {
+					locale = locales[index]
+				}
+			}
+		}
+
+		c.Request = c.Request.WithContext(pkg.WithLocale(c.Request.Context(), locale))
+		c.Header("Content-Language", locale)
+		c.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"lms-system-internship/pkg"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit пропускает не больше limit запросов с одного IP к одному маршруту за window
+// (фиксированное окно), остальным отвечает 429 с заголовком Retry-After. IP берётся из
+// X-Forwarded-For только для прокси из TRUSTED_PROXIES, иначе это адрес соединения, и
+// клиент не может обойти лимит, подставляя заголовок. Счётчики хранятся в памяти процесса,
+// поэтому при нескольких репликах лимит действует на каждую отдельно. limit == 0 — без ограничения.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	if limit <= 0 || window <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	limiter := pkg.NewRateLimiter(limit, window)
+	return func(c *gin.Context) {
+		allowed, retryAfter := limiter.Allow(c.ClientIP() + " " + c.FullPath())
+		if !allowed {
+			pkg.LoggerFromContext(c.Request.Context()).WithField("client_ip", c.ClientIP()).Warn("Rate limit exceeded")
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			AbortWithError(c, pkg.ErrTooManyRequests)
+			return
+		}
+		c.Next()
+	}
+}
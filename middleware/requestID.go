@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"lms-system-internship/pkg"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader — заголовок, в котором ID запроса принимается от клиента и возвращается в ответе.
+const RequestIDHeader = "X-Request-ID"
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID берёт ID запроса из X-Request-ID или генерирует новый, если заголовка нет или он
+// некорректен, и сохраняет его в контексте gin и запроса. Там же появляется логгер запроса
+// с полями request_id, method, route и trace_id, если запрос трассируется.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set("requestID", requestID)
+		ctx := pkg.WithRequestID(c.Request.Context(), requestID)
+		fields := logrus.Fields{
+			"request_id": requestID,
+			"method":     c.Request.Method,
+			"route":      c.FullPath(),
+		}
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			fields["trace_id"] = span.TraceID().String()
+		}
+		c.Request = c.Request.WithContext(pkg.WithLogger(ctx, pkg.Logger.WithFields(fields)))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// addLogFields дополняет логгер запроса полями, которые стали известны по ходу обработки.
+func addLogFields(c *gin.Context, fields logrus.Fields) {
+	ctx := c.Request.Context()
+	c.Request = c.Request.WithContext(pkg.WithLogger(ctx, pkg.LoggerFromContext(ctx).WithFields(fields)))
+}
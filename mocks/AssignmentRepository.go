@@ -0,0 +1,143 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AssignmentRepository is an autogenerated mock type for the AssignmentRepository type
+type AssignmentRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *AssignmentRepository) Delete(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByID provides a mock function with given fields: ctx, id
+func (_m *AssignmentRepository) FindByID(ctx context.Context, id uint) (*entities.Assignment, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 *entities.Assignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.Assignment, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.Assignment); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Assignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByLessonID provides a mock function with given fields: ctx, lessonID
+func (_m *AssignmentRepository) FindByLessonID(ctx context.Context, lessonID uint) ([]*entities.Assignment, error) {
+	ret := _m.Called(ctx, lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByLessonID")
+	}
+
+	var r0 []*entities.Assignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.Assignment, error)); ok {
+		return rf(ctx, lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.Assignment); ok {
+		r0 = rf(ctx, lessonID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Assignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, a
+func (_m *AssignmentRepository) Save(ctx context.Context, a *entities.Assignment) error {
+	ret := _m.Called(ctx, a)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Assignment) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, a
+func (_m *AssignmentRepository) Update(ctx context.Context, a *entities.Assignment) error {
+	ret := _m.Called(ctx, a)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Assignment) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAssignmentRepository creates a new instance of AssignmentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAssignmentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AssignmentRepository {
+	mock := &AssignmentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
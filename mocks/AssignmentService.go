@@ -0,0 +1,284 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// AssignmentService is an autogenerated mock type for the AssignmentService type
+type AssignmentService struct {
+	mock.Mock
+}
+
+// CreateAssignment provides a mock function with given fields: ctx, assignment
+func (_m *AssignmentService) CreateAssignment(ctx context.Context, assignment *entities.Assignment) error {
+	ret := _m.Called(ctx, assignment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAssignment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Assignment) error); ok {
+		r0 = rf(ctx, assignment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAssignment provides a mock function with given fields: ctx, assignmentID
+func (_m *AssignmentService) DeleteAssignment(ctx context.Context, assignmentID uint) error {
+	ret := _m.Called(ctx, assignmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAssignment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, assignmentID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DownloadSubmission provides a mock function with given fields: ctx, submissionID
+func (_m *AssignmentService) DownloadSubmission(ctx context.Context, submissionID uint) ([]byte, string, error) {
+	ret := _m.Called(ctx, submissionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DownloadSubmission")
+	}
+
+	var r0 []byte
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]byte, string, error)); ok {
+		return rf(ctx, submissionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []byte); ok {
+		r0 = rf(ctx, submissionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) string); ok {
+		r1 = rf(ctx, submissionID)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint) error); ok {
+		r2 = rf(ctx, submissionID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAssignment provides a mock function with given fields: ctx, assignmentID
+func (_m *AssignmentService) GetAssignment(ctx context.Context, assignmentID uint) (*entities.Assignment, error) {
+	ret := _m.Called(ctx, assignmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAssignment")
+	}
+
+	var r0 *entities.Assignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.Assignment, error)); ok {
+		return rf(ctx, assignmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.Assignment); ok {
+		r0 = rf(ctx, assignmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Assignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, assignmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAssignmentsByLesson provides a mock function with given fields: ctx, lessonID
+func (_m *AssignmentService) GetAssignmentsByLesson(ctx context.Context, lessonID uint) ([]*entities.Assignment, error) {
+	ret := _m.Called(ctx, lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAssignmentsByLesson")
+	}
+
+	var r0 []*entities.Assignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.Assignment, error)); ok {
+		return rf(ctx, lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.Assignment); ok {
+		r0 = rf(ctx, lessonID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Assignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMySubmission provides a mock function with given fields: ctx, userID, assignmentID
+func (_m *AssignmentService) GetMySubmission(ctx context.Context, userID uuid.UUID, assignmentID uint) (*entities.Submission, error) {
+	ret := _m.Called(ctx, userID, assignmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMySubmission")
+	}
+
+	var r0 *entities.Submission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint) (*entities.Submission, error)); ok {
+		return rf(ctx, userID, assignmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint) *entities.Submission); ok {
+		r0 = rf(ctx, userID, assignmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Submission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uint) error); ok {
+		r1 = rf(ctx, userID, assignmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubmissions provides a mock function with given fields: ctx, assignmentID
+func (_m *AssignmentService) GetSubmissions(ctx context.Context, assignmentID uint) ([]*entities.Submission, error) {
+	ret := _m.Called(ctx, assignmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubmissions")
+	}
+
+	var r0 []*entities.Submission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.Submission, error)); ok {
+		return rf(ctx, assignmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.Submission); ok {
+		r0 = rf(ctx, assignmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Submission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, assignmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GradeSubmission provides a mock function with given fields: ctx, graderID, submissionID, score, feedback
+func (_m *AssignmentService) GradeSubmission(ctx context.Context, graderID uuid.UUID, submissionID uint, score int, feedback string) (*entities.Submission, error) {
+	ret := _m.Called(ctx, graderID, submissionID, score, feedback)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GradeSubmission")
+	}
+
+	var r0 *entities.Submission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint, int, string) (*entities.Submission, error)); ok {
+		return rf(ctx, graderID, submissionID, score, feedback)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint, int, string) *entities.Submission); ok {
+		r0 = rf(ctx, graderID, submissionID, score, feedback)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Submission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uint, int, string) error); ok {
+		r1 = rf(ctx, graderID, submissionID, score, feedback)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Submit provides a mock function with given fields: ctx, userID, assignmentID, fileName, fileBytes
+func (_m *AssignmentService) Submit(ctx context.Context, userID uuid.UUID, assignmentID uint, fileName string, fileBytes []byte) (*entities.Submission, error) {
+	ret := _m.Called(ctx, userID, assignmentID, fileName, fileBytes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Submit")
+	}
+
+	var r0 *entities.Submission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint, string, []byte) (*entities.Submission, error)); ok {
+		return rf(ctx, userID, assignmentID, fileName, fileBytes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint, string, []byte) *entities.Submission); ok {
+		r0 = rf(ctx, userID, assignmentID, fileName, fileBytes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Submission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uint, string, []byte) error); ok {
+		r1 = rf(ctx, userID, assignmentID, fileName, fileBytes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAssignmentService creates a new instance of AssignmentService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAssignmentService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AssignmentService {
+	mock := &AssignmentService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,165 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// AttachmentService is an autogenerated mock type for the AttachmentService type
+type AttachmentService struct {
+	mock.Mock
+}
+
+// DownloadAttachment provides a mock function with given fields: ctx, userID, attachment
+func (_m *AttachmentService) DownloadAttachment(ctx context.Context, userID uuid.UUID, attachment *entities.Attachment) ([]byte, string, error) {
+	ret := _m.Called(ctx, userID, attachment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DownloadAttachment")
+	}
+
+	var r0 []byte
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.Attachment) ([]byte, string, error)); ok {
+		return rf(ctx, userID, attachment)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.Attachment) []byte); ok {
+		r0 = rf(ctx, userID, attachment)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.Attachment) string); ok {
+		r1 = rf(ctx, userID, attachment)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, *entities.Attachment) error); ok {
+		r2 = rf(ctx, userID, attachment)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DownloadFile provides a mock function with given fields: ctx, userID, attachmentID
+func (_m *AttachmentService) DownloadFile(ctx context.Context, userID uuid.UUID, attachmentID uint) ([]byte, string, error) {
+	ret := _m.Called(ctx, userID, attachmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DownloadFile")
+	}
+
+	var r0 []byte
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint) ([]byte, string, error)); ok {
+		return rf(ctx, userID, attachmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint) []byte); ok {
+		r0 = rf(ctx, userID, attachmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uint) string); ok {
+		r1 = rf(ctx, userID, attachmentID)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, uint) error); ok {
+		r2 = rf(ctx, userID, attachmentID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAttachmentsByLesson provides a mock function with given fields: ctx, lessonID
+func (_m *AttachmentService) GetAttachmentsByLesson(ctx context.Context, lessonID uint) ([]*entities.Attachment, error) {
+	ret := _m.Called(ctx, lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAttachmentsByLesson")
+	}
+
+	var r0 []*entities.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.Attachment, error)); ok {
+		return rf(ctx, lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.Attachment); ok {
+		r0 = rf(ctx, lessonID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UploadFile provides a mock function with given fields: ctx, lessonID, fileName, fileBytes
+func (_m *AttachmentService) UploadFile(ctx context.Context, lessonID uint, fileName string, fileBytes []byte) (*entities.Attachment, error) {
+	ret := _m.Called(ctx, lessonID, fileName, fileBytes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadFile")
+	}
+
+	var r0 *entities.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, []byte) (*entities.Attachment, error)); ok {
+		return rf(ctx, lessonID, fileName, fileBytes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, []byte) *entities.Attachment); ok {
+		r0 = rf(ctx, lessonID, fileName, fileBytes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, []byte) error); ok {
+		r1 = rf(ctx, lessonID, fileName, fileBytes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAttachmentService creates a new instance of AttachmentService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAttachmentService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AttachmentService {
+	mock := &AttachmentService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
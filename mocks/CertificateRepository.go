@@ -0,0 +1,217 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// CertificateRepository is an autogenerated mock type for the CertificateRepository type
+type CertificateRepository struct {
+	mock.Mock
+}
+
+// FindByCode provides a mock function with given fields: ctx, code
+func (_m *CertificateRepository) FindByCode(ctx context.Context, code string) (*entities.Certificate, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByCode")
+	}
+
+	var r0 *entities.Certificate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entities.Certificate, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entities.Certificate); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Certificate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByCourseAndUser provides a mock function with given fields: ctx, courseID, userID
+func (_m *CertificateRepository) FindByCourseAndUser(ctx context.Context, courseID uint, userID uuid.UUID) (*entities.Certificate, error) {
+	ret := _m.Called(ctx, courseID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByCourseAndUser")
+	}
+
+	var r0 *entities.Certificate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uuid.UUID) (*entities.Certificate, error)); ok {
+		return rf(ctx, courseID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uuid.UUID) *entities.Certificate); ok {
+		r0 = rf(ctx, courseID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Certificate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uuid.UUID) error); ok {
+		r1 = rf(ctx, courseID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByID provides a mock function with given fields: ctx, id
+func (_m *CertificateRepository) FindByID(ctx context.Context, id uint) (*entities.Certificate, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 *entities.Certificate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.Certificate, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.Certificate); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Certificate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByUserID provides a mock function with given fields: ctx, userID
+func (_m *CertificateRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Certificate, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByUserID")
+	}
+
+	var r0 []*entities.Certificate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*entities.Certificate, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*entities.Certificate); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Certificate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRule provides a mock function with given fields: ctx, courseID
+func (_m *CertificateRepository) FindRule(ctx context.Context, courseID uint) (*entities.CompletionRule, error) {
+	ret := _m.Called(ctx, courseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRule")
+	}
+
+	var r0 *entities.CompletionRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.CompletionRule, error)); ok {
+		return rf(ctx, courseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.CompletionRule); ok {
+		r0 = rf(ctx, courseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.CompletionRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, courseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, certificate
+func (_m *CertificateRepository) Save(ctx context.Context, certificate *entities.Certificate) error {
+	ret := _m.Called(ctx, certificate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Certificate) error); ok {
+		r0 = rf(ctx, certificate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveRule provides a mock function with given fields: ctx, rule
+func (_m *CertificateRepository) SaveRule(ctx context.Context, rule *entities.CompletionRule) error {
+	ret := _m.Called(ctx, rule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.CompletionRule) error); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCertificateRepository creates a new instance of CertificateRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCertificateRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CertificateRepository {
+	mock := &CertificateRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,8 @@ import (
 	entities "lms-system-internship/entities"
 
 	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
 )
 
 // ChapterService is an autogenerated mock type for the ChapterService type
@@ -110,6 +112,24 @@ func (_m *ChapterService) RemoveChapter(ctx context.Context, chapterID uint) err
 	return r0
 }
 
+// UpdateChapterDetails provides a mock function with given fields: ctx, authorID, chapterID, name, description
+func (_m *ChapterService) UpdateChapterDetails(ctx context.Context, authorID uuid.UUID, chapterID uint, name string, description string) error {
+	ret := _m.Called(ctx, authorID, chapterID, name, description)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateChapterDetails")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint, string, string) error); ok {
+		r0 = rf(ctx, authorID, chapterID, name, description)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateChapterOrder provides a mock function with given fields: ctx, chapterID, newOrder
 func (_m *ChapterService) UpdateChapterOrder(ctx context.Context, chapterID uint, newOrder int) error {
 	ret := _m.Called(ctx, chapterID, newOrder)
@@ -7,6 +7,8 @@ import (
 	entities "lms-system-internship/entities"
 
 	mock "github.com/stretchr/testify/mock"
+
+	repo "lms-system-internship/repo"
 )
 
 // CourseRepository is an autogenerated mock type for the CourseRepository type
@@ -32,9 +34,9 @@ func (_m *CourseRepository) Delete(ctx context.Context, id uint) error {
 	return r0
 }
 
-// FindAll provides a mock function with given fields: ctx
-func (_m *CourseRepository) FindAll(ctx context.Context) ([]*entities.Course, error) {
-	ret := _m.Called(ctx)
+// FindAll provides a mock function with given fields: ctx, filter
+func (_m *CourseRepository) FindAll(ctx context.Context, filter repo.CourseFilter) ([]*entities.Course, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindAll")
@@ -42,19 +44,19 @@ func (_m *CourseRepository) FindAll(ctx context.Context) ([]*entities.Course, er
 
 	var r0 []*entities.Course
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*entities.Course, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CourseFilter) ([]*entities.Course, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*entities.Course); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CourseFilter) []*entities.Course); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*entities.Course)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, repo.CourseFilter) error); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -110,6 +112,36 @@ func (_m *CourseRepository) Save(ctx context.Context, course *entities.Course) e
 	return r0
 }
 
+// TagFacets provides a mock function with given fields: ctx, filter
+func (_m *CourseRepository) TagFacets(ctx context.Context, filter repo.CourseFilter) ([]repo.TagFacet, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TagFacets")
+	}
+
+	var r0 []repo.TagFacet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CourseFilter) ([]repo.TagFacet, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CourseFilter) []repo.TagFacet); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.TagFacet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repo.CourseFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, course
 func (_m *CourseRepository) Update(ctx context.Context, course *entities.Course) error {
 	ret := _m.Called(ctx, course)
@@ -7,6 +7,10 @@ import (
 	entities "lms-system-internship/entities"
 
 	mock "github.com/stretchr/testify/mock"
+
+	repo "lms-system-internship/repo"
+
+	uuid "github.com/google/uuid"
 )
 
 // CourseService is an autogenerated mock type for the CourseService type
@@ -50,9 +54,9 @@ func (_m *CourseService) DeleteCourse(ctx context.Context, courseID uint) error
 	return r0
 }
 
-// GetAllCourses provides a mock function with given fields: ctx
-func (_m *CourseService) GetAllCourses(ctx context.Context) ([]*entities.Course, error) {
-	ret := _m.Called(ctx)
+// GetAllCourses provides a mock function with given fields: ctx, filter
+func (_m *CourseService) GetAllCourses(ctx context.Context, filter repo.CourseFilter) ([]*entities.Course, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllCourses")
@@ -60,19 +64,19 @@ func (_m *CourseService) GetAllCourses(ctx context.Context) ([]*entities.Course,
 
 	var r0 []*entities.Course
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*entities.Course, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CourseFilter) ([]*entities.Course, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*entities.Course); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CourseFilter) []*entities.Course); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*entities.Course)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, repo.CourseFilter) error); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -110,17 +114,47 @@ func (_m *CourseService) GetCourse(ctx context.Context, courseID uint) (*entitie
 	return r0, r1
 }
 
-// UpdateCourseDetails provides a mock function with given fields: ctx, course
-func (_m *CourseService) UpdateCourseDetails(ctx context.Context, course *entities.Course) error {
-	ret := _m.Called(ctx, course)
+// GetTagFacets provides a mock function with given fields: ctx, filter
+func (_m *CourseService) GetTagFacets(ctx context.Context, filter repo.CourseFilter) ([]repo.TagFacet, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTagFacets")
+	}
+
+	var r0 []repo.TagFacet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CourseFilter) ([]repo.TagFacet, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repo.CourseFilter) []repo.TagFacet); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repo.TagFacet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repo.CourseFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCourseDetails provides a mock function with given fields: ctx, authorID, course
+func (_m *CourseService) UpdateCourseDetails(ctx context.Context, authorID uuid.UUID, course *entities.Course) error {
+	ret := _m.Called(ctx, authorID, course)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateCourseDetails")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entities.Course) error); ok {
-		r0 = rf(ctx, course)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.Course) error); ok {
+		r0 = rf(ctx, authorID, course)
 	} else {
 		r0 = ret.Error(0)
 	}
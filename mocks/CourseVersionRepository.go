@@ -0,0 +1,301 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// CourseVersionRepository is an autogenerated mock type for the CourseVersionRepository type
+type CourseVersionRepository struct {
+	mock.Mock
+}
+
+// FindByCourseID provides a mock function with given fields: ctx, courseID
+func (_m *CourseVersionRepository) FindByCourseID(ctx context.Context, courseID uint) ([]*entities.CourseVersion, error) {
+	ret := _m.Called(ctx, courseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByCourseID")
+	}
+
+	var r0 []*entities.CourseVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.CourseVersion, error)); ok {
+		return rf(ctx, courseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.CourseVersion); ok {
+		r0 = rf(ctx, courseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.CourseVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, courseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByNumber provides a mock function with given fields: ctx, courseID, number
+func (_m *CourseVersionRepository) FindByNumber(ctx context.Context, courseID uint, number int) (*entities.CourseVersion, error) {
+	ret := _m.Called(ctx, courseID, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByNumber")
+	}
+
+	var r0 *entities.CourseVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) (*entities.CourseVersion, error)); ok {
+		return rf(ctx, courseID, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) *entities.CourseVersion); ok {
+		r0 = rf(ctx, courseID, number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.CourseVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int) error); ok {
+		r1 = rf(ctx, courseID, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindCourseIDByAttachment provides a mock function with given fields: ctx, attachmentID
+func (_m *CourseVersionRepository) FindCourseIDByAttachment(ctx context.Context, attachmentID uint) (uint, error) {
+	ret := _m.Called(ctx, attachmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCourseIDByAttachment")
+	}
+
+	var r0 uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (uint, error)); ok {
+		return rf(ctx, attachmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) uint); ok {
+		r0 = rf(ctx, attachmentID)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, attachmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindCourseIDByLesson provides a mock function with given fields: ctx, lessonID
+func (_m *CourseVersionRepository) FindCourseIDByLesson(ctx context.Context, lessonID uint) (uint, error) {
+	ret := _m.Called(ctx, lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCourseIDByLesson")
+	}
+
+	var r0 uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (uint, error)); ok {
+		return rf(ctx, lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) uint); ok {
+		r0 = rf(ctx, lessonID)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLatest provides a mock function with given fields: ctx, courseID
+func (_m *CourseVersionRepository) FindLatest(ctx context.Context, courseID uint) (*entities.CourseVersion, error) {
+	ret := _m.Called(ctx, courseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLatest")
+	}
+
+	var r0 *entities.CourseVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.CourseVersion, error)); ok {
+		return rf(ctx, courseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.CourseVersion); ok {
+		r0 = rf(ctx, courseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.CourseVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, courseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindPin provides a mock function with given fields: ctx, courseID, userID
+func (_m *CourseVersionRepository) FindPin(ctx context.Context, courseID uint, userID uuid.UUID) (*entities.CourseVersionPin, error) {
+	ret := _m.Called(ctx, courseID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPin")
+	}
+
+	var r0 *entities.CourseVersionPin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uuid.UUID) (*entities.CourseVersionPin, error)); ok {
+		return rf(ctx, courseID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uuid.UUID) *entities.CourseVersionPin); ok {
+		r0 = rf(ctx, courseID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.CourseVersionPin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uuid.UUID) error); ok {
+		r1 = rf(ctx, courseID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadCourseTree provides a mock function with given fields: ctx, courseID
+func (_m *CourseVersionRepository) LoadCourseTree(ctx context.Context, courseID uint) (*entities.Course, error) {
+	ret := _m.Called(ctx, courseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoadCourseTree")
+	}
+
+	var r0 *entities.Course
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.Course, error)); ok {
+		return rf(ctx, courseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.Course); ok {
+		r0 = rf(ctx, courseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Course)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, courseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MovePins provides a mock function with given fields: ctx, courseID, number, userIDs
+func (_m *CourseVersionRepository) MovePins(ctx context.Context, courseID uint, number int, userIDs []uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, courseID, number, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MovePins")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int, []uuid.UUID) (int64, error)); ok {
+		return rf(ctx, courseID, number, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int, []uuid.UUID) int64); ok {
+		r0 = rf(ctx, courseID, number, userIDs)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int, []uuid.UUID) error); ok {
+		r1 = rf(ctx, courseID, number, userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, version, movePins
+func (_m *CourseVersionRepository) Publish(ctx context.Context, version *entities.CourseVersion, movePins bool) error {
+	ret := _m.Called(ctx, version, movePins)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.CourseVersion, bool) error); ok {
+		r0 = rf(ctx, version, movePins)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SavePin provides a mock function with given fields: ctx, pin
+func (_m *CourseVersionRepository) SavePin(ctx context.Context, pin *entities.CourseVersionPin) error {
+	ret := _m.Called(ctx, pin)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SavePin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.CourseVersionPin) error); ok {
+		r0 = rf(ctx, pin)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCourseVersionRepository creates a new instance of CourseVersionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCourseVersionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CourseVersionRepository {
+	mock := &CourseVersionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
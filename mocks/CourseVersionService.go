@@ -0,0 +1,209 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// CourseVersionService is an autogenerated mock type for the CourseVersionService type
+type CourseVersionService struct {
+	mock.Mock
+}
+
+// GetCourseVersion provides a mock function with given fields: ctx, userID, privileged, courseID, version
+func (_m *CourseVersionService) GetCourseVersion(ctx context.Context, userID uuid.UUID, privileged bool, courseID uint, version *int) (*entities.Course, error) {
+	ret := _m.Called(ctx, userID, privileged, courseID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCourseVersion")
+	}
+
+	var r0 *entities.Course
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool, uint, *int) (*entities.Course, error)); ok {
+		return rf(ctx, userID, privileged, courseID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool, uint, *int) *entities.Course); ok {
+		r0 = rf(ctx, userID, privileged, courseID, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Course)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, bool, uint, *int) error); ok {
+		r1 = rf(ctx, userID, privileged, courseID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVersions provides a mock function with given fields: ctx, courseID
+func (_m *CourseVersionService) GetVersions(ctx context.Context, courseID uint) ([]*entities.CourseVersion, error) {
+	ret := _m.Called(ctx, courseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVersions")
+	}
+
+	var r0 []*entities.CourseVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.CourseVersion, error)); ok {
+		return rf(ctx, courseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.CourseVersion); ok {
+		r0 = rf(ctx, courseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.CourseVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, courseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MigrateLearners provides a mock function with given fields: ctx, courseID, number, userIDs
+func (_m *CourseVersionService) MigrateLearners(ctx context.Context, courseID uint, number int, userIDs []uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, courseID, number, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MigrateLearners")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int, []uuid.UUID) (int64, error)); ok {
+		return rf(ctx, courseID, number, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int, []uuid.UUID) int64); ok {
+		r0 = rf(ctx, courseID, number, userIDs)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int, []uuid.UUID) error); ok {
+		r1 = rf(ctx, courseID, number, userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PinnedAttachment provides a mock function with given fields: ctx, userID, attachmentID
+func (_m *CourseVersionService) PinnedAttachment(ctx context.Context, userID uuid.UUID, attachmentID uint) (*entities.Attachment, error) {
+	ret := _m.Called(ctx, userID, attachmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PinnedAttachment")
+	}
+
+	var r0 *entities.Attachment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint) (*entities.Attachment, error)); ok {
+		return rf(ctx, userID, attachmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint) *entities.Attachment); ok {
+		r0 = rf(ctx, userID, attachmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Attachment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uint) error); ok {
+		r1 = rf(ctx, userID, attachmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PinnedLesson provides a mock function with given fields: ctx, userID, lessonID
+func (_m *CourseVersionService) PinnedLesson(ctx context.Context, userID uuid.UUID, lessonID uint) (*entities.Lesson, error) {
+	ret := _m.Called(ctx, userID, lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PinnedLesson")
+	}
+
+	var r0 *entities.Lesson
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint) (*entities.Lesson, error)); ok {
+		return rf(ctx, userID, lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint) *entities.Lesson); ok {
+		r0 = rf(ctx, userID, lessonID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Lesson)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uint) error); ok {
+		r1 = rf(ctx, userID, lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, authorID, courseID, comment, migrate
+func (_m *CourseVersionService) Publish(ctx context.Context, authorID uuid.UUID, courseID uint, comment string, migrate bool) (*entities.CourseVersion, error) {
+	ret := _m.Called(ctx, authorID, courseID, comment, migrate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 *entities.CourseVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint, string, bool) (*entities.CourseVersion, error)); ok {
+		return rf(ctx, authorID, courseID, comment, migrate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint, string, bool) *entities.CourseVersion); ok {
+		r0 = rf(ctx, authorID, courseID, comment, migrate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.CourseVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uint, string, bool) error); ok {
+		r1 = rf(ctx, authorID, courseID, comment, migrate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCourseVersionService creates a new instance of CourseVersionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCourseVersionService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CourseVersionService {
+	mock := &CourseVersionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,147 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// EmailTokenRepository is an autogenerated mock type for the EmailTokenRepository type
+type EmailTokenRepository struct {
+	mock.Mock
+}
+
+// FindByHash provides a mock function with given fields: ctx, purpose, tokenHash
+func (_m *EmailTokenRepository) FindByHash(ctx context.Context, purpose string, tokenHash string) (*entities.EmailToken, error) {
+	ret := _m.Called(ctx, purpose, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByHash")
+	}
+
+	var r0 *entities.EmailToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entities.EmailToken, error)); ok {
+		return rf(ctx, purpose, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entities.EmailToken); ok {
+		r0 = rf(ctx, purpose, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.EmailToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, purpose, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLatest provides a mock function with given fields: ctx, userID, purpose
+func (_m *EmailTokenRepository) FindLatest(ctx context.Context, userID uuid.UUID, purpose string) (*entities.EmailToken, error) {
+	ret := _m.Called(ctx, userID, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLatest")
+	}
+
+	var r0 *entities.EmailToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (*entities.EmailToken, error)); ok {
+		return rf(ctx, userID, purpose)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) *entities.EmailToken); ok {
+		r0 = rf(ctx, userID, purpose)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.EmailToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, userID, purpose)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InvalidateUser provides a mock function with given fields: ctx, userID, purpose, at
+func (_m *EmailTokenRepository) InvalidateUser(ctx context.Context, userID uuid.UUID, purpose string, at time.Time) error {
+	ret := _m.Called(ctx, userID, purpose, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InvalidateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, time.Time) error); ok {
+		r0 = rf(ctx, userID, purpose, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Save provides a mock function with given fields: ctx, token
+func (_m *EmailTokenRepository) Save(ctx context.Context, token *entities.EmailToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.EmailToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Use provides a mock function with given fields: ctx, id, at
+func (_m *EmailTokenRepository) Use(ctx context.Context, id uint, at time.Time) error {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Use")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) error); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEmailTokenRepository creates a new instance of EmailTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailTokenRepository {
+	mock := &EmailTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
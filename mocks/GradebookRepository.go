@@ -0,0 +1,457 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// GradebookRepository is an autogenerated mock type for the GradebookRepository type
+type GradebookRepository struct {
+	mock.Mock
+}
+
+// DeleteCategory provides a mock function with given fields: ctx, id
+func (_m *GradebookRepository) DeleteCategory(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteItem provides a mock function with given fields: ctx, id
+func (_m *GradebookRepository) DeleteItem(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAudits provides a mock function with given fields: ctx, itemID, userID
+func (_m *GradebookRepository) FindAudits(ctx context.Context, itemID uint, userID uuid.UUID) ([]*entities.GradeAudit, error) {
+	ret := _m.Called(ctx, itemID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAudits")
+	}
+
+	var r0 []*entities.GradeAudit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uuid.UUID) ([]*entities.GradeAudit, error)); ok {
+		return rf(ctx, itemID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uuid.UUID) []*entities.GradeAudit); ok {
+		r0 = rf(ctx, itemID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.GradeAudit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uuid.UUID) error); ok {
+		r1 = rf(ctx, itemID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindCategoriesByCourseID provides a mock function with given fields: ctx, courseID
+func (_m *GradebookRepository) FindCategoriesByCourseID(ctx context.Context, courseID uint) ([]*entities.GradeCategory, error) {
+	ret := _m.Called(ctx, courseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCategoriesByCourseID")
+	}
+
+	var r0 []*entities.GradeCategory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.GradeCategory, error)); ok {
+		return rf(ctx, courseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.GradeCategory); ok {
+		r0 = rf(ctx, courseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.GradeCategory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, courseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindCategoryByID provides a mock function with given fields: ctx, id
+func (_m *GradebookRepository) FindCategoryByID(ctx context.Context, id uint) (*entities.GradeCategory, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCategoryByID")
+	}
+
+	var r0 *entities.GradeCategory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.GradeCategory, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.GradeCategory); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.GradeCategory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindGrade provides a mock function with given fields: ctx, itemID, userID
+func (_m *GradebookRepository) FindGrade(ctx context.Context, itemID uint, userID uuid.UUID) (*entities.Grade, error) {
+	ret := _m.Called(ctx, itemID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindGrade")
+	}
+
+	var r0 *entities.Grade
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uuid.UUID) (*entities.Grade, error)); ok {
+		return rf(ctx, itemID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uuid.UUID) *entities.Grade); ok {
+		r0 = rf(ctx, itemID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Grade)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uuid.UUID) error); ok {
+		r1 = rf(ctx, itemID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindGradesByCourseID provides a mock function with given fields: ctx, courseID
+func (_m *GradebookRepository) FindGradesByCourseID(ctx context.Context, courseID uint) ([]*entities.Grade, error) {
+	ret := _m.Called(ctx, courseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindGradesByCourseID")
+	}
+
+	var r0 []*entities.Grade
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.Grade, error)); ok {
+		return rf(ctx, courseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.Grade); ok {
+		r0 = rf(ctx, courseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Grade)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, courseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindGradesByUserID provides a mock function with given fields: ctx, userID
+func (_m *GradebookRepository) FindGradesByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Grade, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindGradesByUserID")
+	}
+
+	var r0 []*entities.Grade
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*entities.Grade, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*entities.Grade); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Grade)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindItemByID provides a mock function with given fields: ctx, id
+func (_m *GradebookRepository) FindItemByID(ctx context.Context, id uint) (*entities.GradeItem, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindItemByID")
+	}
+
+	var r0 *entities.GradeItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.GradeItem, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.GradeItem); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.GradeItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindItemsByAssignmentID provides a mock function with given fields: ctx, assignmentID
+func (_m *GradebookRepository) FindItemsByAssignmentID(ctx context.Context, assignmentID uint) ([]*entities.GradeItem, error) {
+	ret := _m.Called(ctx, assignmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindItemsByAssignmentID")
+	}
+
+	var r0 []*entities.GradeItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.GradeItem, error)); ok {
+		return rf(ctx, assignmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.GradeItem); ok {
+		r0 = rf(ctx, assignmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.GradeItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, assignmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindItemsByCourseID provides a mock function with given fields: ctx, courseID
+func (_m *GradebookRepository) FindItemsByCourseID(ctx context.Context, courseID uint) ([]*entities.GradeItem, error) {
+	ret := _m.Called(ctx, courseID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindItemsByCourseID")
+	}
+
+	var r0 []*entities.GradeItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.GradeItem, error)); ok {
+		return rf(ctx, courseID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.GradeItem); ok {
+		r0 = rf(ctx, courseID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.GradeItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, courseID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindItemsByIDs provides a mock function with given fields: ctx, ids
+func (_m *GradebookRepository) FindItemsByIDs(ctx context.Context, ids []uint) ([]*entities.GradeItem, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindItemsByIDs")
+	}
+
+	var r0 []*entities.GradeItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) ([]*entities.GradeItem, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) []*entities.GradeItem); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.GradeItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveCategory provides a mock function with given fields: ctx, category
+func (_m *GradebookRepository) SaveCategory(ctx context.Context, category *entities.GradeCategory) error {
+	ret := _m.Called(ctx, category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.GradeCategory) error); ok {
+		r0 = rf(ctx, category)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveGradeWithAudit provides a mock function with given fields: ctx, grade, audit
+func (_m *GradebookRepository) SaveGradeWithAudit(ctx context.Context, grade *entities.Grade, audit *entities.GradeAudit) error {
+	ret := _m.Called(ctx, grade, audit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveGradeWithAudit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Grade, *entities.GradeAudit) error); ok {
+		r0 = rf(ctx, grade, audit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveItem provides a mock function with given fields: ctx, item
+func (_m *GradebookRepository) SaveItem(ctx context.Context, item *entities.GradeItem) error {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.GradeItem) error); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCategory provides a mock function with given fields: ctx, category
+func (_m *GradebookRepository) UpdateCategory(ctx context.Context, category *entities.GradeCategory) error {
+	ret := _m.Called(ctx, category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.GradeCategory) error); ok {
+		r0 = rf(ctx, category)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateItem provides a mock function with given fields: ctx, item
+func (_m *GradebookRepository) UpdateItem(ctx context.Context, item *entities.GradeItem) error {
+	ret := _m.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.GradeItem) error); ok {
+		r0 = rf(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewGradebookRepository creates a new instance of GradebookRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGradebookRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GradebookRepository {
+	mock := &GradebookRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
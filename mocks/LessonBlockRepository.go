@@ -0,0 +1,191 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LessonBlockRepository is an autogenerated mock type for the LessonBlockRepository type
+type LessonBlockRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *LessonBlockRepository) Delete(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByID provides a mock function with given fields: ctx, id
+func (_m *LessonBlockRepository) FindByID(ctx context.Context, id uint) (*entities.LessonBlock, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 *entities.LessonBlock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.LessonBlock, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.LessonBlock); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.LessonBlock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByLessonID provides a mock function with given fields: ctx, lessonID
+func (_m *LessonBlockRepository) FindByLessonID(ctx context.Context, lessonID uint) ([]*entities.LessonBlock, error) {
+	ret := _m.Called(ctx, lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByLessonID")
+	}
+
+	var r0 []*entities.LessonBlock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.LessonBlock, error)); ok {
+		return rf(ctx, lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.LessonBlock); ok {
+		r0 = rf(ctx, lessonID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.LessonBlock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByLessonIDs provides a mock function with given fields: ctx, lessonIDs
+func (_m *LessonBlockRepository) FindByLessonIDs(ctx context.Context, lessonIDs []uint) ([]*entities.LessonBlock, error) {
+	ret := _m.Called(ctx, lessonIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByLessonIDs")
+	}
+
+	var r0 []*entities.LessonBlock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) ([]*entities.LessonBlock, error)); ok {
+		return rf(ctx, lessonIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) []*entities.LessonBlock); ok {
+		r0 = rf(ctx, lessonIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.LessonBlock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint) error); ok {
+		r1 = rf(ctx, lessonIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, block
+func (_m *LessonBlockRepository) Save(ctx context.Context, block *entities.LessonBlock) error {
+	ret := _m.Called(ctx, block)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.LessonBlock) error); ok {
+		r0 = rf(ctx, block)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, block
+func (_m *LessonBlockRepository) Update(ctx context.Context, block *entities.LessonBlock) error {
+	ret := _m.Called(ctx, block)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.LessonBlock) error); ok {
+		r0 = rf(ctx, block)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateOrder provides a mock function with given fields: ctx, lessonID, orderedIDs
+func (_m *LessonBlockRepository) UpdateOrder(ctx context.Context, lessonID uint, orderedIDs []uint) error {
+	ret := _m.Called(ctx, lessonID, orderedIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateOrder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []uint) error); ok {
+		r0 = rf(ctx, lessonID, orderedIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLessonBlockRepository creates a new instance of LessonBlockRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLessonBlockRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LessonBlockRepository {
+	mock := &LessonBlockRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
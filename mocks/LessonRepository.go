@@ -158,6 +158,24 @@ func (_m *LessonRepository) Update(ctx context.Context, lesson *entities.Lesson)
 	return r0
 }
 
+// UpdateWithBlocks provides a mock function with given fields: ctx, lesson
+func (_m *LessonRepository) UpdateWithBlocks(ctx context.Context, lesson *entities.Lesson) error {
+	ret := _m.Called(ctx, lesson)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWithBlocks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Lesson) error); ok {
+		r0 = rf(ctx, lesson)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewLessonRepository creates a new instance of LessonRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewLessonRepository(t interface {
@@ -7,6 +7,8 @@ import (
 	entities "lms-system-internship/entities"
 
 	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
 )
 
 // LessonService is an autogenerated mock type for the LessonService type
@@ -110,6 +112,72 @@ func (_m *LessonService) GetLesson(ctx context.Context, lessonID uint) (*entitie
 	return r0, r1
 }
 
+// GetRenderedLesson provides a mock function with given fields: ctx, lessonID
+func (_m *LessonService) GetRenderedLesson(ctx context.Context, lessonID uint) (*entities.Lesson, error) {
+	ret := _m.Called(ctx, lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRenderedLesson")
+	}
+
+	var r0 *entities.Lesson
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.Lesson, error)); ok {
+		return rf(ctx, lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.Lesson); ok {
+		r0 = rf(ctx, lessonID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Lesson)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantAccess provides a mock function with given fields: ctx, userID, lessonID
+func (_m *LessonService) GrantAccess(ctx context.Context, userID uuid.UUID, lessonID uint) error {
+	ret := _m.Called(ctx, userID, lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantAccess")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint) error); ok {
+		r0 = rf(ctx, userID, lessonID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RenderLesson provides a mock function with given fields: lesson
+func (_m *LessonService) RenderLesson(lesson *entities.Lesson) error {
+	ret := _m.Called(lesson)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenderLesson")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.Lesson) error); ok {
+		r0 = rf(lesson)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ReorderLessons provides a mock function with given fields: ctx, chapterID, orderedLessonIDs
 func (_m *LessonService) ReorderLessons(ctx context.Context, chapterID uint, orderedLessonIDs []uint) error {
 	ret := _m.Called(ctx, chapterID, orderedLessonIDs)
@@ -128,17 +196,17 @@ func (_m *LessonService) ReorderLessons(ctx context.Context, chapterID uint, ord
 	return r0
 }
 
-// UpdateLessonContent provides a mock function with given fields: ctx, lessonID, content
-func (_m *LessonService) UpdateLessonContent(ctx context.Context, lessonID uint, content string) error {
-	ret := _m.Called(ctx, lessonID, content)
+// UpdateLessonContent provides a mock function with given fields: ctx, authorID, lessonID, content, format
+func (_m *LessonService) UpdateLessonContent(ctx context.Context, authorID uuid.UUID, lessonID uint, content string, format string) error {
+	ret := _m.Called(ctx, authorID, lessonID, content, format)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateLessonContent")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uint, string) error); ok {
-		r0 = rf(ctx, lessonID, content)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uint, string, string) error); ok {
+		r0 = rf(ctx, authorID, lessonID, content, format)
 	} else {
 		r0 = ret.Error(0)
 	}
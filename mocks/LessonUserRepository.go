@@ -0,0 +1,104 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// LessonUserRepository is an autogenerated mock type for the LessonUserRepository type
+type LessonUserRepository struct {
+	mock.Mock
+}
+
+// FindLessonIDsByUser provides a mock function with given fields: userID
+func (_m *LessonUserRepository) FindLessonIDsByUser(userID uuid.UUID) ([]uint, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLessonIDsByUser")
+	}
+
+	var r0 []uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID) ([]uint, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uuid.UUID) []uint); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uuid.UUID) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantAccess provides a mock function with given fields: userID, lessonID
+func (_m *LessonUserRepository) GrantAccess(userID uuid.UUID, lessonID uint) error {
+	ret := _m.Called(userID, lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantAccess")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uint) error); ok {
+		r0 = rf(userID, lessonID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HasAccess provides a mock function with given fields: userID, lessonID
+func (_m *LessonUserRepository) HasAccess(userID uuid.UUID, lessonID uint) (bool, error) {
+	ret := _m.Called(userID, lessonID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasAccess")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uint) (bool, error)); ok {
+		return rf(userID, lessonID)
+	}
+	if rf, ok := ret.Get(0).(func(uuid.UUID, uint) bool); ok {
+		r0 = rf(userID, lessonID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uuid.UUID, uint) error); ok {
+		r1 = rf(userID, lessonID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLessonUserRepository creates a new instance of LessonUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLessonUserRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LessonUserRepository {
+	mock := &LessonUserRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,157 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RevisionRepository is an autogenerated mock type for the RevisionRepository type
+type RevisionRepository struct {
+	mock.Mock
+}
+
+// FindByEntity provides a mock function with given fields: ctx, entityType, entityID
+func (_m *RevisionRepository) FindByEntity(ctx context.Context, entityType string, entityID uint) ([]*entities.Revision, error) {
+	ret := _m.Called(ctx, entityType, entityID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByEntity")
+	}
+
+	var r0 []*entities.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) ([]*entities.Revision, error)); ok {
+		return rf(ctx, entityType, entityID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) []*entities.Revision); ok {
+		r0 = rf(ctx, entityType, entityID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint) error); ok {
+		r1 = rf(ctx, entityType, entityID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByNumber provides a mock function with given fields: ctx, entityType, entityID, number
+func (_m *RevisionRepository) FindByNumber(ctx context.Context, entityType string, entityID uint, number int) (*entities.Revision, error) {
+	ret := _m.Called(ctx, entityType, entityID, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByNumber")
+	}
+
+	var r0 *entities.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, int) (*entities.Revision, error)); ok {
+		return rf(ctx, entityType, entityID, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, int) *entities.Revision); ok {
+		r0 = rf(ctx, entityType, entityID, number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, int) error); ok {
+		r1 = rf(ctx, entityType, entityID, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLatest provides a mock function with given fields: ctx, entityType, entityID
+func (_m *RevisionRepository) FindLatest(ctx context.Context, entityType string, entityID uint) (*entities.Revision, error) {
+	ret := _m.Called(ctx, entityType, entityID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLatest")
+	}
+
+	var r0 *entities.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) (*entities.Revision, error)); ok {
+		return rf(ctx, entityType, entityID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) *entities.Revision); ok {
+		r0 = rf(ctx, entityType, entityID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint) error); ok {
+		r1 = rf(ctx, entityType, entityID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Prune provides a mock function with given fields: ctx, entityType, entityID, keep, olderThan
+func (_m *RevisionRepository) Prune(ctx context.Context, entityType string, entityID uint, keep int, olderThan *time.Time) error {
+	ret := _m.Called(ctx, entityType, entityID, keep, olderThan)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Prune")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, int, *time.Time) error); ok {
+		r0 = rf(ctx, entityType, entityID, keep, olderThan)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Save provides a mock function with given fields: ctx, revision
+func (_m *RevisionRepository) Save(ctx context.Context, revision *entities.Revision) error {
+	ret := _m.Called(ctx, revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Revision) error); ok {
+		r0 = rf(ctx, revision)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRevisionRepository creates a new instance of RevisionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRevisionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RevisionRepository {
+	mock := &RevisionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,167 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// RevisionService is an autogenerated mock type for the RevisionService type
+type RevisionService struct {
+	mock.Mock
+}
+
+// Diff provides a mock function with given fields: ctx, entityType, entityID, from, to
+func (_m *RevisionService) Diff(ctx context.Context, entityType string, entityID uint, from int, to int) (string, error) {
+	ret := _m.Called(ctx, entityType, entityID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Diff")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, int, int) (string, error)); ok {
+		return rf(ctx, entityType, entityID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, int, int) string); ok {
+		r0 = rf(ctx, entityType, entityID, from, to)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, int, int) error); ok {
+		r1 = rf(ctx, entityType, entityID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRevision provides a mock function with given fields: ctx, entityType, entityID, number
+func (_m *RevisionService) GetRevision(ctx context.Context, entityType string, entityID uint, number int) (*entities.Revision, error) {
+	ret := _m.Called(ctx, entityType, entityID, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRevision")
+	}
+
+	var r0 *entities.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, int) (*entities.Revision, error)); ok {
+		return rf(ctx, entityType, entityID, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, int) *entities.Revision); ok {
+		r0 = rf(ctx, entityType, entityID, number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, int) error); ok {
+		r1 = rf(ctx, entityType, entityID, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRevisions provides a mock function with given fields: ctx, entityType, entityID
+func (_m *RevisionService) GetRevisions(ctx context.Context, entityType string, entityID uint) ([]*entities.Revision, error) {
+	ret := _m.Called(ctx, entityType, entityID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRevisions")
+	}
+
+	var r0 []*entities.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) ([]*entities.Revision, error)); ok {
+		return rf(ctx, entityType, entityID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) []*entities.Revision); ok {
+		r0 = rf(ctx, entityType, entityID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint) error); ok {
+		r1 = rf(ctx, entityType, entityID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordChange provides a mock function with given fields: ctx, authorID, before, after
+func (_m *RevisionService) RecordChange(ctx context.Context, authorID uuid.UUID, before *entities.Revision, after *entities.Revision) error {
+	ret := _m.Called(ctx, authorID, before, after)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordChange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.Revision, *entities.Revision) error); ok {
+		r0 = rf(ctx, authorID, before, after)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, authorID, entityType, entityID, number
+func (_m *RevisionService) Restore(ctx context.Context, authorID uuid.UUID, entityType string, entityID uint, number int) (*entities.Revision, error) {
+	ret := _m.Called(ctx, authorID, entityType, entityID, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 *entities.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, uint, int) (*entities.Revision, error)); ok {
+		return rf(ctx, authorID, entityType, entityID, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, uint, int) *entities.Revision); ok {
+		r0 = rf(ctx, authorID, entityType, entityID, number)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, uint, int) error); ok {
+		r1 = rf(ctx, authorID, entityType, entityID, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRevisionService creates a new instance of RevisionService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRevisionService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RevisionService {
+	mock := &RevisionService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,157 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// SubmissionRepository is an autogenerated mock type for the SubmissionRepository type
+type SubmissionRepository struct {
+	mock.Mock
+}
+
+// FindByAssignmentAndUser provides a mock function with given fields: ctx, assignmentID, userID
+func (_m *SubmissionRepository) FindByAssignmentAndUser(ctx context.Context, assignmentID uint, userID uuid.UUID) (*entities.Submission, error) {
+	ret := _m.Called(ctx, assignmentID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByAssignmentAndUser")
+	}
+
+	var r0 *entities.Submission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uuid.UUID) (*entities.Submission, error)); ok {
+		return rf(ctx, assignmentID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uuid.UUID) *entities.Submission); ok {
+		r0 = rf(ctx, assignmentID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Submission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uuid.UUID) error); ok {
+		r1 = rf(ctx, assignmentID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByAssignmentID provides a mock function with given fields: ctx, assignmentID
+func (_m *SubmissionRepository) FindByAssignmentID(ctx context.Context, assignmentID uint) ([]*entities.Submission, error) {
+	ret := _m.Called(ctx, assignmentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByAssignmentID")
+	}
+
+	var r0 []*entities.Submission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*entities.Submission, error)); ok {
+		return rf(ctx, assignmentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*entities.Submission); ok {
+		r0 = rf(ctx, assignmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Submission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, assignmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByID provides a mock function with given fields: ctx, id
+func (_m *SubmissionRepository) FindByID(ctx context.Context, id uint) (*entities.Submission, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 *entities.Submission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.Submission, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.Submission); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Submission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, s
+func (_m *SubmissionRepository) Save(ctx context.Context, s *entities.Submission) error {
+	ret := _m.Called(ctx, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Submission) error); ok {
+		r0 = rf(ctx, s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, s
+func (_m *SubmissionRepository) Update(ctx context.Context, s *entities.Submission) error {
+	ret := _m.Called(ctx, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Submission) error); ok {
+		r0 = rf(ctx, s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSubmissionRepository creates a new instance of SubmissionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSubmissionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SubmissionRepository {
+	mock := &SubmissionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,321 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaxonomyRepository is an autogenerated mock type for the TaxonomyRepository type
+type TaxonomyRepository struct {
+	mock.Mock
+}
+
+// CountChildCategories provides a mock function with given fields: ctx, id
+func (_m *TaxonomyRepository) CountChildCategories(ctx context.Context, id uint) (int64, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountChildCategories")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (int64, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteCategory provides a mock function with given fields: ctx, id
+func (_m *TaxonomyRepository) DeleteCategory(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTag provides a mock function with given fields: ctx, id
+func (_m *TaxonomyRepository) DeleteTag(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAllCategories provides a mock function with given fields: ctx
+func (_m *TaxonomyRepository) FindAllCategories(ctx context.Context) ([]*entities.Category, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllCategories")
+	}
+
+	var r0 []*entities.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entities.Category, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*entities.Category); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAllTags provides a mock function with given fields: ctx
+func (_m *TaxonomyRepository) FindAllTags(ctx context.Context) ([]*entities.Tag, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllTags")
+	}
+
+	var r0 []*entities.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entities.Tag, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*entities.Tag); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindCategoryByID provides a mock function with given fields: ctx, id
+func (_m *TaxonomyRepository) FindCategoryByID(ctx context.Context, id uint) (*entities.Category, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCategoryByID")
+	}
+
+	var r0 *entities.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.Category, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.Category); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindTagByID provides a mock function with given fields: ctx, id
+func (_m *TaxonomyRepository) FindTagByID(ctx context.Context, id uint) (*entities.Tag, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTagByID")
+	}
+
+	var r0 *entities.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.Tag, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.Tag); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveCategory provides a mock function with given fields: ctx, category
+func (_m *TaxonomyRepository) SaveCategory(ctx context.Context, category *entities.Category) error {
+	ret := _m.Called(ctx, category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Category) error); ok {
+		r0 = rf(ctx, category)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveTag provides a mock function with given fields: ctx, tag
+func (_m *TaxonomyRepository) SaveTag(ctx context.Context, tag *entities.Tag) error {
+	ret := _m.Called(ctx, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Tag) error); ok {
+		r0 = rf(ctx, tag)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetCourseTaxonomy provides a mock function with given fields: ctx, courseID, categoryID, tags
+func (_m *TaxonomyRepository) SetCourseTaxonomy(ctx context.Context, courseID uint, categoryID *uint, tags []string) error {
+	ret := _m.Called(ctx, courseID, categoryID, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCourseTaxonomy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *uint, []string) error); ok {
+		r0 = rf(ctx, courseID, categoryID, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetLessonTaxonomy provides a mock function with given fields: ctx, lessonID, categoryID, tags
+func (_m *TaxonomyRepository) SetLessonTaxonomy(ctx context.Context, lessonID uint, categoryID *uint, tags []string) error {
+	ret := _m.Called(ctx, lessonID, categoryID, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLessonTaxonomy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *uint, []string) error); ok {
+		r0 = rf(ctx, lessonID, categoryID, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCategory provides a mock function with given fields: ctx, category
+func (_m *TaxonomyRepository) UpdateCategory(ctx context.Context, category *entities.Category) error {
+	ret := _m.Called(ctx, category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Category) error); ok {
+		r0 = rf(ctx, category)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTag provides a mock function with given fields: ctx, tag
+func (_m *TaxonomyRepository) UpdateTag(ctx context.Context, tag *entities.Tag) error {
+	ret := _m.Called(ctx, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Tag) error); ok {
+		r0 = rf(ctx, tag)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTaxonomyRepository creates a new instance of TaxonomyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTaxonomyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TaxonomyRepository {
+	mock := &TaxonomyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
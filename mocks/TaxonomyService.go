@@ -0,0 +1,233 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaxonomyService is an autogenerated mock type for the TaxonomyService type
+type TaxonomyService struct {
+	mock.Mock
+}
+
+// CreateCategory provides a mock function with given fields: ctx, category
+func (_m *TaxonomyService) CreateCategory(ctx context.Context, category *entities.Category) error {
+	ret := _m.Called(ctx, category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Category) error); ok {
+		r0 = rf(ctx, category)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTag provides a mock function with given fields: ctx, tag
+func (_m *TaxonomyService) CreateTag(ctx context.Context, tag *entities.Tag) error {
+	ret := _m.Called(ctx, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Tag) error); ok {
+		r0 = rf(ctx, tag)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCategory provides a mock function with given fields: ctx, categoryID
+func (_m *TaxonomyService) DeleteCategory(ctx context.Context, categoryID uint) error {
+	ret := _m.Called(ctx, categoryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, categoryID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTag provides a mock function with given fields: ctx, tagID
+func (_m *TaxonomyService) DeleteTag(ctx context.Context, tagID uint) error {
+	ret := _m.Called(ctx, tagID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, tagID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetCategoryTree provides a mock function with given fields: ctx
+func (_m *TaxonomyService) GetCategoryTree(ctx context.Context) ([]*entities.Category, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCategoryTree")
+	}
+
+	var r0 []*entities.Category
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entities.Category, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*entities.Category); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Category)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTags provides a mock function with given fields: ctx
+func (_m *TaxonomyService) GetTags(ctx context.Context) ([]*entities.Tag, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTags")
+	}
+
+	var r0 []*entities.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entities.Tag, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*entities.Tag); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetCourseTaxonomy provides a mock function with given fields: ctx, courseID, categoryID, tags
+func (_m *TaxonomyService) SetCourseTaxonomy(ctx context.Context, courseID uint, categoryID *uint, tags []string) error {
+	ret := _m.Called(ctx, courseID, categoryID, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCourseTaxonomy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *uint, []string) error); ok {
+		r0 = rf(ctx, courseID, categoryID, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetLessonTaxonomy provides a mock function with given fields: ctx, lessonID, categoryID, tags
+func (_m *TaxonomyService) SetLessonTaxonomy(ctx context.Context, lessonID uint, categoryID *uint, tags []string) error {
+	ret := _m.Called(ctx, lessonID, categoryID, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLessonTaxonomy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *uint, []string) error); ok {
+		r0 = rf(ctx, lessonID, categoryID, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCategory provides a mock function with given fields: ctx, category
+func (_m *TaxonomyService) UpdateCategory(ctx context.Context, category *entities.Category) error {
+	ret := _m.Called(ctx, category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Category) error); ok {
+		r0 = rf(ctx, category)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTag provides a mock function with given fields: ctx, tag
+func (_m *TaxonomyService) UpdateTag(ctx context.Context, tag *entities.Tag) error {
+	ret := _m.Called(ctx, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Tag) error); ok {
+		r0 = rf(ctx, tag)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTaxonomyService creates a new instance of TaxonomyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTaxonomyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TaxonomyService {
+	mock := &TaxonomyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,125 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TranslationRepository is an autogenerated mock type for the TranslationRepository type
+type TranslationRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, entityType, entityID, locale
+func (_m *TranslationRepository) Delete(ctx context.Context, entityType string, entityID uint, locale string) error {
+	ret := _m.Called(ctx, entityType, entityID, locale)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, string) error); ok {
+		r0 = rf(ctx, entityType, entityID, locale)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByEntities provides a mock function with given fields: ctx, entityType, entityIDs, locale
+func (_m *TranslationRepository) FindByEntities(ctx context.Context, entityType string, entityIDs []uint, locale string) ([]*entities.Translation, error) {
+	ret := _m.Called(ctx, entityType, entityIDs, locale)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByEntities")
+	}
+
+	var r0 []*entities.Translation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []uint, string) ([]*entities.Translation, error)); ok {
+		return rf(ctx, entityType, entityIDs, locale)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []uint, string) []*entities.Translation); ok {
+		r0 = rf(ctx, entityType, entityIDs, locale)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Translation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []uint, string) error); ok {
+		r1 = rf(ctx, entityType, entityIDs, locale)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByEntity provides a mock function with given fields: ctx, entityType, entityID
+func (_m *TranslationRepository) FindByEntity(ctx context.Context, entityType string, entityID uint) ([]*entities.Translation, error) {
+	ret := _m.Called(ctx, entityType, entityID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByEntity")
+	}
+
+	var r0 []*entities.Translation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) ([]*entities.Translation, error)); ok {
+		return rf(ctx, entityType, entityID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint) []*entities.Translation); ok {
+		r0 = rf(ctx, entityType, entityID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.Translation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint) error); ok {
+		r1 = rf(ctx, entityType, entityID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Upsert provides a mock function with given fields: ctx, translation
+func (_m *TranslationRepository) Upsert(ctx context.Context, translation *entities.Translation) error {
+	ret := _m.Called(ctx, translation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upsert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Translation) error); ok {
+		r0 = rf(ctx, translation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTranslationRepository creates a new instance of TranslationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTranslationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TranslationRepository {
+	mock := &TranslationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,135 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "lms-system-internship/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// UserImportRepository is an autogenerated mock type for the UserImportRepository type
+type UserImportRepository struct {
+	mock.Mock
+}
+
+// AddResult provides a mock function with given fields: ctx, result, succeeded
+func (_m *UserImportRepository) AddResult(ctx context.Context, result *entities.UserImportResult, succeeded bool) error {
+	ret := _m.Called(ctx, result, succeeded)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddResult")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.UserImportResult, bool) error); ok {
+		r0 = rf(ctx, result, succeeded)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateJob provides a mock function with given fields: ctx, job
+func (_m *UserImportRepository) CreateJob(ctx context.Context, job *entities.UserImportJob) error {
+	ret := _m.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.UserImportJob) error); ok {
+		r0 = rf(ctx, job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteFinishedBefore provides a mock function with given fields: ctx, before
+func (_m *UserImportRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) error {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteFinishedBefore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindJob provides a mock function with given fields: ctx, jobID
+func (_m *UserImportRepository) FindJob(ctx context.Context, jobID uuid.UUID) (*entities.UserImportJob, error) {
+	ret := _m.Called(ctx, jobID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindJob")
+	}
+
+	var r0 *entities.UserImportJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.UserImportJob, error)); ok {
+		return rf(ctx, jobID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.UserImportJob); ok {
+		r0 = rf(ctx, jobID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.UserImportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, jobID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishJob provides a mock function with given fields: ctx, jobID, status, at
+func (_m *UserImportRepository) FinishJob(ctx context.Context, jobID uuid.UUID, status string, at time.Time) error {
+	ret := _m.Called(ctx, jobID, status, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishJob")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, time.Time) error); ok {
+		r0 = rf(ctx, jobID, status, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUserImportRepository creates a new instance of UserImportRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserImportRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserImportRepository {
+	mock := &UserImportRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,29 @@
+package pkg
+
+import "context"
+
+type localeKey struct{}
+
+type requestIDKey struct{}
+
+// WithLocale сохраняет в контексте локаль, выбранную по Accept-Language.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext возвращает локаль запроса или пустую строку, если она не выбиралась.
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// WithRequestID сохраняет в контексте ID запроса.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext возвращает ID запроса или пустую строку.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
@@ -1,14 +1,131 @@
 package pkg
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+)
 
+// ErrorResponse — тело ответа с ошибкой. Ключ "error" с текстом сообщения сохранён для старых клиентов,
+// остальные поля добавлены для машинной обработки.
 type ErrorResponse struct {
-	Message string `json:"error"` // JSON ключ — "error", как у тебя и было
+	Message   string         `json:"error"`
+	Code      string         `json:"code,omitempty"`
+	Key       string         `json:"message_key,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+	Fields    []FieldError   `json:"fields,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+}
+
+// AppError — ошибка API: машинный код, HTTP-статус, ключ локализованного сообщения и
+// необязательные подробности. errors.Is считает равными ошибки с одинаковым кодом, поэтому
+// копии из WithDetails, WithFields и Wrap по-прежнему совпадают со своим sentinel.
+type AppError struct {
+	Code    string
+	Status  int
+	Key     string
+	Message string
+	Details map[string]any
+	Fields  []FieldError
+	cause   error
+}
+
+// FieldError — ошибка конкретного поля запроса. Code — правило, которое не выполнено
+// (required, max, ...), Param — его параметр; Message заполняется при ответе.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// NewError создаёт ошибку с ключом сообщения "errors.<code>"; message — текст по умолчанию.
+func NewError(code string, status int, message string) *AppError {
+	return &AppError{Code: code, Status: status, Key: "errors." + code, Message: message}
+}
+
+func (e *AppError) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error { return e.cause }
+
+func (e *AppError) Is(target error) bool {
+	var other *AppError
+	return errors.As(target, &other) && other.Code == e.Code
+}
+
+// WithDetails возвращает копию ошибки с подробностями, которые уйдут клиенту.
+func (e *AppError) WithDetails(details map[string]any) *AppError {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// WithFields возвращает копию ошибки с ошибками отдельных полей.
+func (e *AppError) WithFields(fields ...FieldError) *AppError {
+	copied := *e
+	copied.Fields = append(append([]FieldError{}, e.Fields...), fields...)
+	return &copied
+}
+
+// Wrap возвращает копию ошибки с причиной. Причина попадает в логи, но не в ответ клиенту.
+func (e *AppError) Wrap(cause error) *AppError {
+	copied := *e
+	copied.cause = cause
+	return &copied
 }
 
 var (
-	ErrCourseNotFound  = errors.New("course not found")
-	ErrChapterNotFound = errors.New("chapter not found")
-	ErrLessonNotFound  = errors.New("lesson not found")
-	ErrInvalidInput    = errors.New("invalid input")
+	ErrCourseNotFound     = NewError("course_not_found", http.StatusNotFound, "course not found")
+	ErrChapterNotFound    = NewError("chapter_not_found", http.StatusNotFound, "chapter not found")
+	ErrLessonNotFound     = NewError("lesson_not_found", http.StatusNotFound, "lesson not found")
+	ErrInvalidInput       = NewError("invalid_input", http.StatusBadRequest, "invalid input")
+	ErrAssignmentNotFound = NewError("assignment_not_found", http.StatusNotFound, "assignment not found")
+	ErrSubmissionNotFound = NewError("submission_not_found", http.StatusNotFound, "submission not found")
+	ErrFileTypeNotAllowed = NewError("file_type_not_allowed", http.StatusBadRequest, "file type is not allowed")
+	ErrDeadlinePassed     = NewError("deadline_passed", http.StatusConflict, "assignment deadline has passed")
+	ErrForbidden          = NewError("forbidden", http.StatusForbidden, "access denied")
+	ErrAttachmentNotFound = NewError("attachment_not_found", http.StatusNotFound, "attachment not found")
+
+	ErrGradeCategoryNotFound = NewError("grade_category_not_found", http.StatusNotFound, "grade category not found")
+	ErrGradeItemNotFound     = NewError("grade_item_not_found", http.StatusNotFound, "grade item not found")
+
+	ErrCertificateNotFound = NewError("certificate_not_found", http.StatusNotFound, "certificate not found")
+	ErrCourseNotCompleted  = NewError("course_not_completed", http.StatusConflict, "course completion rule is not satisfied")
+
+	ErrCategoryNotFound = NewError("category_not_found", http.StatusNotFound, "category not found")
+	ErrCategoryNotEmpty = NewError("category_not_empty", http.StatusConflict, "category has subcategories")
+	ErrTagNotFound      = NewError("tag_not_found", http.StatusNotFound, "tag not found")
+	ErrTagAlreadyExists = NewError("tag_already_exists", http.StatusConflict, "tag with this name already exists")
+
+	ErrLessonBlockNotFound = NewError("lesson_block_not_found", http.StatusNotFound, "lesson block not found")
+	ErrRevisionNotFound    = NewError("revision_not_found", http.StatusNotFound, "revision not found")
+
+	ErrCourseVersionNotFound = NewError("course_version_not_found", http.StatusNotFound, "course version not found")
+	ErrTranslationNotFound   = NewError("translation_not_found", http.StatusNotFound, "translation not found")
+
+	// Аутентификация и пользователи.
+	ErrUnauthorized        = NewError("unauthorized", http.StatusUnauthorized, "authentication required")
+	ErrInvalidToken        = NewError("invalid_token", http.StatusUnauthorized, "invalid or expired token")
+	ErrInvalidCredentials  = NewError("invalid_credentials", http.StatusUnauthorized, "invalid username or password")
+	ErrUserNotFound        = NewError("user_not_found", http.StatusNotFound, "user not found")
+	ErrUserAlreadyExists   = NewError("user_already_exists", http.StatusConflict, "user with this username or email already exists")
+	ErrSessionNotFound     = NewError("session_not_found", http.StatusNotFound, "session not found")
+	ErrInvalidRole         = NewError("invalid_role", http.StatusBadRequest, "unknown role")
+	ErrImportJobNotFound   = NewError("import_job_not_found", http.StatusNotFound, "import job not found")
+	ErrIdentityUnavailable = NewError("identity_provider_unavailable", http.StatusBadGateway, "identity provider is unavailable")
+
+	// Самостоятельная регистрация и восстановление пароля.
+	ErrRegistrationDisabled  = NewError("registration_disabled", http.StatusForbidden, "self-registration is disabled")
+	ErrEmailDomainNotAllowed = NewError("email_domain_not_allowed", http.StatusBadRequest, "registration with this email domain is not allowed")
+	ErrInvalidEmailToken     = NewError("invalid_email_token", http.StatusBadRequest, "the link is invalid or has expired")
+	ErrTooManyRequests       = NewError("too_many_requests", http.StatusTooManyRequests, "too many requests, try again later")
+
+	ErrRouteNotFound = NewError("route_not_found", http.StatusNotFound, "route not found")
+
+	// ErrInternal отдаётся клиенту вместо любой ошибки, не входящей в таксономию.
+	ErrInternal = NewError("internal_error", http.StatusInternalServerError, "internal server error")
 )
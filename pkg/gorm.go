@@ -0,0 +1,39 @@
+package pkg
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// RegisterGormCallbacks оборачивает каждую операцию GORM колбэками плагина: before(operation)
+// вызывается перед запросом, after(operation) — после. Имена колбэков — "<plugin>:before_<operation>"
+// и "<plugin>:after_<operation>".
+func RegisterGormCallbacks(db *gorm.DB, plugin string, before, after func(operation string) func(*gorm.DB)) error {
+	cb := db.Callback()
+	hooks := []struct {
+		operation     string
+		before, after func(string, func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	var errs []error
+	for _, hook := range hooks {
+		errs = append(errs,
+			hook.before(plugin+":before_"+hook.operation, before(hook.operation)),
+			hook.after(plugin+":after_"+hook.operation, after(hook.operation)),
+		)
+	}
+	return errors.Join(errs...)
+}
+
+// IsGormFailure сообщает, считать ли ошибку запроса сбоем базы. «Не найдено» — штатный ответ
+// репозиториев, поэтому метрики и трассировка не отмечают его ошибкой.
+func IsGormFailure(err error) bool {
+	return err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+}
@@ -1,18 +1,59 @@
 package pkg
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/sirupsen/logrus"
 )
 
-// Logger is a global logger instance
+// Logger — корневой логгер приложения. В обработчиках запросов вместо него используется
+// LoggerFromContext: записи оттуда несут ID запроса, пользователя и маршрут.
 var Logger = logrus.New()
 
+type loggerKey struct{}
+
 func init() {
-	// Set log format to JSON or text depending on preference
-	Logger.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp: true,
-	})
+	Logger.SetOutput(os.Stdout)
+	Logger.SetFormatter(newFormatter("json"))
+	Logger.SetLevel(logrus.InfoLevel)
+}
+
+// ConfigureLogger задаёт уровень (debug, info, warn, error) и формат (json или text) логов.
+func ConfigureLogger(level, format string) error {
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	format = strings.ToLower(format)
+	if format != "json" && format != "text" {
+		return fmt.Errorf("unknown log format %q", format)
+	}
+	Logger.SetLevel(parsed)
+	Logger.SetFormatter(newFormatter(format))
+	return nil
+}
+
+func newFormatter(format string) logrus.Formatter {
+	if format == "text" {
+		return &logrus.TextFormatter{FullTimestamp: true}
+	}
+	return &logrus.JSONFormatter{
+		FieldMap: logrus.FieldMap{logrus.FieldKeyMsg: "message", logrus.FieldKeyTime: "ts"},
+	}
+}
+
+// WithLogger сохраняет в контексте логгер запроса.
+func WithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey{}, entry)
+}
 
-	// Set log level (DEBUG for development, ERROR for production)
-	Logger.SetLevel(logrus.DebugLevel)
+// LoggerFromContext возвращает логгер запроса, а вне запроса — корневой Logger.
+func LoggerFromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(loggerKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(Logger)
 }
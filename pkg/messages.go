@@ -0,0 +1,95 @@
+package pkg
+
+import "strings"
+
+// DefaultMessageLocale — локаль сообщений, если для локали запроса нет каталога.
+const DefaultMessageLocale = "en"
+
+// messages — каталог сообщений об ошибках по локалям. Ключи errors.* соответствуют AppError.Key,
+// validation.* — FieldError.Code; {param} заменяется на FieldError.Param. Английский текст
+// ошибок берётся из AppError.Message, поэтому errors.* есть только в переводах.
+var messages = map[string]map[string]string{
+	"en": {
+		"validation.required":   "This field is required",
+		"validation.invalid":    "Invalid value",
+		"validation.min":        "Must be at least {param}",
+		"validation.max":        "Must be at most {param}",
+		"validation.min_length": "Must be at least {param} characters long",
+		"validation.max_length": "Must be at most {param} characters long",
+		"validation.email":      "Must be a valid email address",
+		"validation.uuid":       "Must be a valid UUID",
+		"validation.oneof":      "Must be one of: {param}",
+		"validation.exists":     "Referenced record does not exist",
+	},
+	"ru": {
+		"errors.course_not_found":              "Курс не найден",
+		"errors.chapter_not_found":             "Глава не найдена",
+		"errors.lesson_not_found":              "Урок не найден",
+		"errors.invalid_input":                 "Некорректные данные",
+		"errors.assignment_not_found":          "Задание не найдено",
+		"errors.submission_not_found":          "Решение не найдено",
+		"errors.file_type_not_allowed":         "Такой тип файла не разрешён",
+		"errors.deadline_passed":               "Срок сдачи задания истёк",
+		"errors.forbidden":                     "Доступ запрещён",
+		"errors.attachment_not_found":          "Вложение не найдено",
+		"errors.grade_category_not_found":      "Категория оценок не найдена",
+		"errors.grade_item_not_found":          "Элемент оценивания не найден",
+		"errors.certificate_not_found":         "Сертификат не найден",
+		"errors.course_not_completed":          "Условия завершения курса не выполнены",
+		"errors.category_not_found":            "Категория не найдена",
+		"errors.category_not_empty":            "В категории есть подкатегории",
+		"errors.tag_not_found":                 "Тег не найден",
+		"errors.tag_already_exists":            "Тег с таким названием уже существует",
+		"errors.lesson_block_not_found":        "Блок урока не найден",
+		"errors.revision_not_found":            "Ревизия не найдена",
+		"errors.course_version_not_found":      "Версия курса не найдена",
+		"errors.translation_not_found":         "Перевод не найден",
+		"errors.unauthorized":                  "Требуется аутентификация",
+		"errors.invalid_token":                 "Токен недействителен или истёк",
+		"errors.invalid_credentials":           "Неверное имя пользователя или пароль",
+		"errors.user_not_found":                "Пользователь не найден",
+		"errors.user_already_exists":           "Пользователь с таким именем или адресом уже существует",
+		"errors.session_not_found":             "Сессия не найдена",
+		"errors.invalid_role":                  "Неизвестная роль",
+		"errors.import_job_not_found":          "Задача импорта не найдена",
+		"errors.registration_disabled":         "Самостоятельная регистрация отключена",
+		"errors.email_domain_not_allowed":      "Регистрация с адресами этого домена не разрешена",
+		"errors.invalid_email_token":           "Ссылка недействительна или устарела",
+		"errors.too_many_requests":             "Слишком много запросов, попробуйте позже",
+		"errors.identity_provider_unavailable": "Сервис аутентификации недоступен",
+		"errors.internal_error":                "Внутренняя ошибка сервера",
+		"errors.route_not_found":               "Маршрут не найден",
+
+		"validation.required":   "Обязательное поле",
+		"validation.invalid":    "Некорректное значение",
+		"validation.min":        "Значение должно быть не меньше {param}",
+		"validation.max":        "Значение должно быть не больше {param}",
+		"validation.min_length": "Длина должна быть не меньше {param} символов",
+		"validation.max_length": "Длина должна быть не больше {param} символов",
+		"validation.email":      "Некорректный адрес электронной почты",
+		"validation.uuid":       "Некорректный UUID",
+		"validation.oneof":      "Допустимые значения: {param}",
+		"validation.exists":     "Связанная запись не существует",
+	},
+}
+
+// Localize возвращает сообщение по ключу на локали (или на DefaultMessageLocale), иначе fallback.
+func Localize(locale, key, fallback string) string {
+	if message, ok := messages[locale][key]; ok {
+		return message
+	}
+	if message, ok := messages[DefaultMessageLocale][key]; ok {
+		return message
+	}
+	return fallback
+}
+
+// LocalizeField заполняет сообщение ошибки поля на нужной локали.
+func LocalizeField(locale string, field FieldError) FieldError {
+	message := Localize(locale, "validation."+field.Code, field.Message)
+	if message == "" {
+		message = Localize(locale, "validation.invalid", "invalid value")
+	}
+	field.Message = strings.ReplaceAll(message, "{param}", field.Param)
+	return field
+}
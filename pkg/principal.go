@@ -0,0 +1,64 @@
+package pkg
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Principal — пользователь, от имени которого выполняется запрос. Его кладёт в контекст
+// TokenAuthMiddleware после проверки токена.
+type Principal struct {
+	ID       uuid.UUID
+	Username string
+	// SessionID — сессия у провайдера, в которой выдан токен (claim sid).
+	SessionID string
+	// Roles — роли realm (realm_access.roles), по ним работает RequireRoles.
+	Roles []string
+	// ClientRoles — роли в клиентах (resource_access.<client>.roles) по имени клиента.
+	ClientRoles map[string][]string
+	// Groups — группы пользователя из claim groups, например "/teachers".
+	Groups []string
+}
+
+// HasAnyRole сообщает, есть ли у пользователя хотя бы одна из ролей realm.
+func (p *Principal) HasAnyRole(roles ...string) bool {
+	for _, role := range roles {
+		if slices.Contains(p.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasClientRole сообщает, есть ли у пользователя роль role в клиенте client.
+func (p *Principal) HasClientRole(client, role string) bool {
+	return slices.Contains(p.ClientRoles[client], role)
+}
+
+// InGroup сообщает, состоит ли пользователь в группе.
+func (p *Principal) InGroup(group string) bool {
+	return slices.Contains(p.Groups, group)
+}
+
+type principalKey struct{}
+
+// WithPrincipal сохраняет в контексте пользователя запроса.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext возвращает пользователя запроса; ok == false, если запрос анонимный.
+func PrincipalFromContext(ctx context.Context) (principal *Principal, ok bool) {
+	principal, ok = ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// UserIDFromContext возвращает ID пользователя запроса или uuid.Nil для анонимного запроса.
+func UserIDFromContext(ctx context.Context) uuid.UUID {
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		return principal.ID
+	}
+	return uuid.Nil
+}
@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"sync"
+	"time"
+)
+
+// rateWindow — счётчик запросов по ключу в текущем окне.
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimiter пропускает не больше limit запросов с одним ключом за window (фиксированное окно).
+// Счётчики хранятся в памяти процесса, поэтому при нескольких репликах лимит действует на каждую
+// отдельно. limit == 0 — без ограничения.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{limit: limit, window: window, windows: map[string]*rateWindow{}}
+}
+
+// Allow учитывает запрос с ключом key. Если лимит в текущем окне исчерпан, возвращает false и
+// время до начала следующего окна.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	if l.limit <= 0 || l.window <= 0 {
+		return true, 0
+	}
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// Раз в окно удаляем истёкшие счётчики, чтобы карта не росла с каждым новым ключом
+	if now.Sub(l.lastSweep) >= l.window {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+	w.count++
+	return w.count <= l.limit, w.start.Add(l.window).Sub(now)
+}
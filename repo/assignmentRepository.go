@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"lms-system-internship/entities"
+)
+
+type AssignmentRepository interface {
+	FindByID(ctx context.Context, id uint) (*entities.Assignment, error)
+	FindByLessonID(ctx context.Context, lessonID uint) ([]*entities.Assignment, error)
+	Save(ctx context.Context, a *entities.Assignment) error
+	Update(ctx context.Context, a *entities.Assignment) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type SubmissionRepository interface {
+	FindByID(ctx context.Context, id uint) (*entities.Submission, error)
+	FindByAssignmentID(ctx context.Context, assignmentID uint) ([]*entities.Submission, error)
+	FindByAssignmentAndUser(ctx context.Context, assignmentID uint, userID uuid.UUID) (*entities.Submission, error)
+	Save(ctx context.Context, s *entities.Submission) error
+	Update(ctx context.Context, s *entities.Submission) error
+}
+
+type assignmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAssignmentRepository(db *gorm.DB) AssignmentRepository {
+	return &assignmentRepository{db: db}
+}
+
+func (r *assignmentRepository) FindByID(ctx context.Context, id uint) (*entities.Assignment, error) {
+	var a entities.Assignment
+	err := r.db.WithContext(ctx).First(&a, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &a, err
+}
+
+func (r *assignmentRepository) FindByLessonID(ctx context.Context, lessonID uint) ([]*entities.Assignment, error) {
+	var list []*entities.Assignment
+	err := r.db.WithContext(ctx).Where("lesson_id = ?", lessonID).Order("due_date").Find(&list).Error
+	return list, err
+}
+
+func (r *assignmentRepository) Save(ctx context.Context, a *entities.Assignment) error {
+	return r.db.WithContext(ctx).Create(a).Error
+}
+
+func (r *assignmentRepository) Update(ctx context.Context, a *entities.Assignment) error {
+	return r.db.WithContext(ctx).Save(a).Error
+}
+
+func (r *assignmentRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&entities.Assignment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type submissionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubmissionRepository(db *gorm.DB) SubmissionRepository {
+	return &submissionRepository{db: db}
+}
+
+func (r *submissionRepository) FindByID(ctx context.Context, id uint) (*entities.Submission, error) {
+	var s entities.Submission
+	err := r.db.WithContext(ctx).First(&s, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &s, err
+}
+
+func (r *submissionRepository) FindByAssignmentID(ctx context.Context, assignmentID uint) ([]*entities.Submission, error) {
+	var list []*entities.Submission
+	err := r.db.WithContext(ctx).Where("assignment_id = ?", assignmentID).Order("submitted_at").Find(&list).Error
+	return list, err
+}
+
+func (r *submissionRepository) FindByAssignmentAndUser(ctx context.Context, assignmentID uint, userID uuid.UUID) (*entities.Submission, error) {
+	var s entities.Submission
+	err := r.db.WithContext(ctx).Where("assignment_id = ? AND user_id = ?", assignmentID, userID).First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &s, err
+}
+
+func (r *submissionRepository) Save(ctx context.Context, s *entities.Submission) error {
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+func (r *submissionRepository) Update(ctx context.Context, s *entities.Submission) error {
+	return r.db.WithContext(ctx).Save(s).Error
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"lms-system-internship/entities"
 
 	"gorm.io/gorm"
@@ -28,6 +29,9 @@ func (r *attachmentRepo) Save(ctx context.Context, a *entities.Attachment) error
 func (r *attachmentRepo) FindByID(ctx context.Context, id uint) (*entities.Attachment, error) {
 	var a entities.Attachment
 	err := r.db.WithContext(ctx).First(&a, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
 	return &a, err
 }
 
@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachmentRepository_FindByIDMissing(t *testing.T) {
+	db, _ := newRecordingDB(t)
+
+	attachment, err := NewAttachmentRepository(db).FindByID(context.Background(), 3)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Nil(t, attachment)
+}
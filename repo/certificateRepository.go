@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"lms-system-internship/entities"
+)
+
+type CertificateRepository interface {
+	FindByID(ctx context.Context, id uint) (*entities.Certificate, error)
+	FindByCode(ctx context.Context, code string) (*entities.Certificate, error)
+	FindByCourseAndUser(ctx context.Context, courseID uint, userID uuid.UUID) (*entities.Certificate, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Certificate, error)
+	Save(ctx context.Context, certificate *entities.Certificate) error
+
+	FindRule(ctx context.Context, courseID uint) (*entities.CompletionRule, error)
+	SaveRule(ctx context.Context, rule *entities.CompletionRule) error
+}
+
+type certificateRepository struct {
+	db *gorm.DB
+}
+
+func NewCertificateRepository(db *gorm.DB) CertificateRepository {
+	return &certificateRepository{db: db}
+}
+
+func (r *certificateRepository) FindByID(ctx context.Context, id uint) (*entities.Certificate, error) {
+	var certificate entities.Certificate
+	err := r.db.WithContext(ctx).First(&certificate, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &certificate, err
+}
+
+func (r *certificateRepository) FindByCode(ctx context.Context, code string) (*entities.Certificate, error) {
+	var certificate entities.Certificate
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&certificate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &certificate, err
+}
+
+func (r *certificateRepository) FindByCourseAndUser(ctx context.Context, courseID uint, userID uuid.UUID) (*entities.Certificate, error) {
+	var certificate entities.Certificate
+	err := r.db.WithContext(ctx).Where("course_id = ? AND user_id = ?", courseID, userID).First(&certificate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &certificate, err
+}
+
+func (r *certificateRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Certificate, error) {
+	var list []*entities.Certificate
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("issued_at DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *certificateRepository) Save(ctx context.Context, certificate *entities.Certificate) error {
+	return r.db.WithContext(ctx).Create(certificate).Error
+}
+
+func (r *certificateRepository) FindRule(ctx context.Context, courseID uint) (*entities.CompletionRule, error) {
+	var rule entities.CompletionRule
+	err := r.db.WithContext(ctx).First(&rule, "course_id = ?", courseID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &rule, err
+}
+
+func (r *certificateRepository) SaveRule(ctx context.Context, rule *entities.CompletionRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
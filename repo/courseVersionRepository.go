@@ -0,0 +1,162 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"lms-system-internship/entities"
+)
+
+type CourseVersionRepository interface {
+	// LoadCourseTree загружает курс со всем содержимым, которое попадает в снимок версии.
+	LoadCourseTree(ctx context.Context, courseID uint) (*entities.Course, error)
+
+	FindByCourseID(ctx context.Context, courseID uint) ([]*entities.CourseVersion, error)
+	FindByNumber(ctx context.Context, courseID uint, number int) (*entities.CourseVersion, error)
+	FindLatest(ctx context.Context, courseID uint) (*entities.CourseVersion, error)
+	// Publish сохраняет версию со следующим номером в пределах курса; при movePins в той же
+	// транзакции на неё переводятся все студенты курса.
+	Publish(ctx context.Context, version *entities.CourseVersion, movePins bool) error
+	// FindCourseIDByLesson и FindCourseIDByAttachment ищут курс, в опубликованных версиях которого
+	// есть урок или вложение; ErrNotFound — ни в одной версии их нет.
+	FindCourseIDByLesson(ctx context.Context, lessonID uint) (uint, error)
+	FindCourseIDByAttachment(ctx context.Context, attachmentID uint) (uint, error)
+
+	FindPin(ctx context.Context, courseID uint, userID uuid.UUID) (*entities.CourseVersionPin, error)
+	// SavePin закрепляет версию за студентом; уже существующее закрепление не меняется.
+	SavePin(ctx context.Context, pin *entities.CourseVersionPin) error
+	// MovePins переводит студентов на версию number; пустой userIDs — всех закреплённых за курсом.
+	MovePins(ctx context.Context, courseID uint, number int, userIDs []uuid.UUID) (int64, error)
+}
+
+type courseVersionRepository struct {
+	db *gorm.DB
+}
+
+func NewCourseVersionRepository(db *gorm.DB) CourseVersionRepository {
+	return &courseVersionRepository{db: db}
+}
+
+func (r *courseVersionRepository) LoadCourseTree(ctx context.Context, courseID uint) (*entities.Course, error) {
+	var course entities.Course
+	err := r.db.WithContext(ctx).
+		Preload("Tags").
+		Preload("Chapters", func(db *gorm.DB) *gorm.DB { return db.Order("\"order\"") }).
+		Preload("Chapters.Lessons", func(db *gorm.DB) *gorm.DB { return db.Order("\"order\"") }).
+		Preload("Chapters.Lessons.Tags").
+		Preload("Chapters.Lessons.Blocks", func(db *gorm.DB) *gorm.DB { return db.Order("\"order\"") }).
+		Preload("Chapters.Lessons.Attachments").
+		First(&course, courseID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &course, err
+}
+
+// FindByCourseID возвращает версии курса без снимков, новые первыми.
+func (r *courseVersionRepository) FindByCourseID(ctx context.Context, courseID uint) ([]*entities.CourseVersion, error) {
+	var list []*entities.CourseVersion
+	err := r.db.WithContext(ctx).
+		Omit("Snapshot").
+		Where("course_id = ?", courseID).
+		Order("number DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *courseVersionRepository) FindByNumber(ctx context.Context, courseID uint, number int) (*entities.CourseVersion, error) {
+	var version entities.CourseVersion
+	err := r.db.WithContext(ctx).
+		Where("course_id = ? AND number = ?", courseID, number).
+		First(&version).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &version, err
+}
+
+func (r *courseVersionRepository) FindLatest(ctx context.Context, courseID uint) (*entities.CourseVersion, error) {
+	var version entities.CourseVersion
+	err := r.db.WithContext(ctx).
+		Where("course_id = ?", courseID).
+		Order("number DESC").
+		First(&version).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &version, err
+}
+
+func (r *courseVersionRepository) Publish(ctx context.Context, version *entities.CourseVersion, movePins bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var last int
+		err := tx.Model(&entities.CourseVersion{}).
+			Where("course_id = ?", version.CourseID).
+			Select("coalesce(max(number), 0)").
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		version.Number = last + 1
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+		if !movePins {
+			return nil
+		}
+		return tx.Model(&entities.CourseVersionPin{}).
+			Where("course_id = ?", version.CourseID).
+			Updates(map[string]interface{}{"version": version.Number, "updated_at": gorm.Expr("now()")}).Error
+	})
+}
+
+func (r *courseVersionRepository) FindCourseIDByLesson(ctx context.Context, lessonID uint) (uint, error) {
+	return r.findCourseID(ctx, fmt.Sprintf(`{"chapters":[{"lessons":[{"id":%d}]}]}`, lessonID))
+}
+
+func (r *courseVersionRepository) FindCourseIDByAttachment(ctx context.Context, attachmentID uint) (uint, error) {
+	return r.findCourseID(ctx, fmt.Sprintf(`{"chapters":[{"lessons":[{"attachments":[{"ID":%d}]}]}]}`, attachmentID))
+}
+
+// findCourseID ищет версию, снимок которой содержит фрагмент JSON (оператор @>).
+func (r *courseVersionRepository) findCourseID(ctx context.Context, fragment string) (uint, error) {
+	var courseIDs []uint
+	err := r.db.WithContext(ctx).Model(&entities.CourseVersion{}).
+		Where("snapshot @> ?::jsonb", fragment).
+		Limit(1).
+		Pluck("course_id", &courseIDs).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(courseIDs) == 0 {
+		return 0, ErrNotFound
+	}
+	return courseIDs[0], nil
+}
+
+func (r *courseVersionRepository) FindPin(ctx context.Context, courseID uint, userID uuid.UUID) (*entities.CourseVersionPin, error) {
+	var pin entities.CourseVersionPin
+	err := r.db.WithContext(ctx).
+		Where("course_id = ? AND user_id = ?", courseID, userID).
+		First(&pin).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &pin, err
+}
+
+func (r *courseVersionRepository) SavePin(ctx context.Context, pin *entities.CourseVersionPin) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(pin).Error
+}
+
+func (r *courseVersionRepository) MovePins(ctx context.Context, courseID uint, number int, userIDs []uuid.UUID) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&entities.CourseVersionPin{}).Where("course_id = ?", courseID)
+	if len(userIDs) > 0 {
+		query = query.Where("user_id IN ?", userIDs)
+	}
+	result := query.Updates(map[string]interface{}{"version": number, "updated_at": gorm.Expr("now()")})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"lms-system-internship/entities"
+	"time"
+)
+
+type EmailTokenRepository interface {
+	Save(ctx context.Context, token *entities.EmailToken) error
+	FindByHash(ctx context.Context, purpose, tokenHash string) (*entities.EmailToken, error)
+	// Use отмечает токен использованным. Если токен уже использован, возвращает ErrNotFound:
+	// так одна ссылка срабатывает только один раз даже при параллельных запросах.
+	Use(ctx context.Context, id uint, at time.Time) error
+	// FindLatest возвращает последний выданный пользователю токен с назначением purpose.
+	FindLatest(ctx context.Context, userID uuid.UUID, purpose string) (*entities.EmailToken, error)
+	// InvalidateUser отмечает использованными все неиспользованные токены пользователя с назначением purpose.
+	InvalidateUser(ctx context.Context, userID uuid.UUID, purpose string, at time.Time) error
+}
+
+type emailTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailTokenRepository(db *gorm.DB) EmailTokenRepository {
+	return &emailTokenRepository{db: db}
+}
+
+func (r *emailTokenRepository) Save(ctx context.Context, token *entities.EmailToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *emailTokenRepository) FindByHash(ctx context.Context, purpose, tokenHash string) (*entities.EmailToken, error) {
+	var token entities.EmailToken
+	err := r.db.WithContext(ctx).Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &token, err
+}
+
+func (r *emailTokenRepository) Use(ctx context.Context, id uint, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entities.EmailToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *emailTokenRepository) FindLatest(ctx context.Context, userID uuid.UUID, purpose string) (*entities.EmailToken, error) {
+	var token entities.EmailToken
+	err := r.db.WithContext(ctx).Where("user_id = ? AND purpose = ?", userID, purpose).
+		Order("created_at DESC").First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &token, err
+}
+
+func (r *emailTokenRepository) InvalidateUser(ctx context.Context, userID uuid.UUID, purpose string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entities.EmailToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", at).Error
+}
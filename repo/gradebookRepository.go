@@ -0,0 +1,174 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"lms-system-internship/entities"
+)
+
+type GradebookRepository interface {
+	FindCategoryByID(ctx context.Context, id uint) (*entities.GradeCategory, error)
+	FindCategoriesByCourseID(ctx context.Context, courseID uint) ([]*entities.GradeCategory, error)
+	SaveCategory(ctx context.Context, category *entities.GradeCategory) error
+	UpdateCategory(ctx context.Context, category *entities.GradeCategory) error
+	DeleteCategory(ctx context.Context, id uint) error
+
+	FindItemByID(ctx context.Context, id uint) (*entities.GradeItem, error)
+	FindItemsByCourseID(ctx context.Context, courseID uint) ([]*entities.GradeItem, error)
+	FindItemsByIDs(ctx context.Context, ids []uint) ([]*entities.GradeItem, error)
+	FindItemsByAssignmentID(ctx context.Context, assignmentID uint) ([]*entities.GradeItem, error)
+	SaveItem(ctx context.Context, item *entities.GradeItem) error
+	UpdateItem(ctx context.Context, item *entities.GradeItem) error
+	DeleteItem(ctx context.Context, id uint) error
+
+	FindGrade(ctx context.Context, itemID uint, userID uuid.UUID) (*entities.Grade, error)
+	FindGradesByCourseID(ctx context.Context, courseID uint) ([]*entities.Grade, error)
+	FindGradesByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Grade, error)
+	SaveGradeWithAudit(ctx context.Context, grade *entities.Grade, audit *entities.GradeAudit) error
+	FindAudits(ctx context.Context, itemID uint, userID uuid.UUID) ([]*entities.GradeAudit, error)
+}
+
+type gradebookRepository struct {
+	db *gorm.DB
+}
+
+func NewGradebookRepository(db *gorm.DB) GradebookRepository {
+	return &gradebookRepository{db: db}
+}
+
+func (r *gradebookRepository) FindCategoryByID(ctx context.Context, id uint) (*entities.GradeCategory, error) {
+	var category entities.GradeCategory
+	err := r.db.WithContext(ctx).First(&category, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &category, err
+}
+
+func (r *gradebookRepository) FindCategoriesByCourseID(ctx context.Context, courseID uint) ([]*entities.GradeCategory, error) {
+	var list []*entities.GradeCategory
+	err := r.db.WithContext(ctx).Where("course_id = ?", courseID).Order("id").Find(&list).Error
+	return list, err
+}
+
+func (r *gradebookRepository) SaveCategory(ctx context.Context, category *entities.GradeCategory) error {
+	return r.db.WithContext(ctx).Create(category).Error
+}
+
+func (r *gradebookRepository) UpdateCategory(ctx context.Context, category *entities.GradeCategory) error {
+	return r.db.WithContext(ctx).Save(category).Error
+}
+
+func (r *gradebookRepository) DeleteCategory(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.GradeItem{}).Where("category_id = ?", id).Update("category_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entities.GradeCategory{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *gradebookRepository) FindItemByID(ctx context.Context, id uint) (*entities.GradeItem, error) {
+	var item entities.GradeItem
+	err := r.db.WithContext(ctx).First(&item, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &item, err
+}
+
+func (r *gradebookRepository) FindItemsByCourseID(ctx context.Context, courseID uint) ([]*entities.GradeItem, error) {
+	var list []*entities.GradeItem
+	err := r.db.WithContext(ctx).Where("course_id = ?", courseID).Order("id").Find(&list).Error
+	return list, err
+}
+
+func (r *gradebookRepository) FindItemsByIDs(ctx context.Context, ids []uint) ([]*entities.GradeItem, error) {
+	var list []*entities.GradeItem
+	if len(ids) == 0 {
+		return list, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&list).Error
+	return list, err
+}
+
+func (r *gradebookRepository) FindItemsByAssignmentID(ctx context.Context, assignmentID uint) ([]*entities.GradeItem, error) {
+	var list []*entities.GradeItem
+	err := r.db.WithContext(ctx).Where("assignment_id = ?", assignmentID).Find(&list).Error
+	return list, err
+}
+
+func (r *gradebookRepository) SaveItem(ctx context.Context, item *entities.GradeItem) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+func (r *gradebookRepository) UpdateItem(ctx context.Context, item *entities.GradeItem) error {
+	return r.db.WithContext(ctx).Save(item).Error
+}
+
+func (r *gradebookRepository) DeleteItem(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("grade_item_id = ?", id).Delete(&entities.Grade{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entities.GradeItem{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *gradebookRepository) FindGrade(ctx context.Context, itemID uint, userID uuid.UUID) (*entities.Grade, error) {
+	var grade entities.Grade
+	err := r.db.WithContext(ctx).Where("grade_item_id = ? AND user_id = ?", itemID, userID).First(&grade).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &grade, err
+}
+
+func (r *gradebookRepository) FindGradesByCourseID(ctx context.Context, courseID uint) ([]*entities.Grade, error) {
+	var list []*entities.Grade
+	err := r.db.WithContext(ctx).
+		Joins("JOIN grade_items ON grade_items.id = grades.grade_item_id").
+		Where("grade_items.course_id = ?", courseID).
+		Find(&list).Error
+	return list, err
+}
+
+func (r *gradebookRepository) FindGradesByUserID(ctx context.Context, userID uuid.UUID) ([]*entities.Grade, error) {
+	var list []*entities.Grade
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&list).Error
+	return list, err
+}
+
+func (r *gradebookRepository) SaveGradeWithAudit(ctx context.Context, grade *entities.Grade, audit *entities.GradeAudit) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(grade).Error; err != nil {
+			return err
+		}
+		return tx.Create(audit).Error
+	})
+}
+
+func (r *gradebookRepository) FindAudits(ctx context.Context, itemID uint, userID uuid.UUID) ([]*entities.GradeAudit, error) {
+	var list []*entities.GradeAudit
+	err := r.db.WithContext(ctx).
+		Where("grade_item_id = ? AND user_id = ?", itemID, userID).
+		Order("created_at").
+		Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"lms-system-internship/entities"
+)
+
+type LessonBlockRepository interface {
+	FindByLessonID(ctx context.Context, lessonID uint) ([]*entities.LessonBlock, error)
+	// FindByLessonIDs возвращает блоки нескольких уроков, упорядоченные внутри каждого урока.
+	FindByLessonIDs(ctx context.Context, lessonIDs []uint) ([]*entities.LessonBlock, error)
+	FindByID(ctx context.Context, id uint) (*entities.LessonBlock, error)
+	// Save, Update, Delete и UpdateOrder в той же транзакции обновляют Lesson.Content —
+	// копию первого текстового блока урока.
+	Save(ctx context.Context, block *entities.LessonBlock) error
+	Update(ctx context.Context, block *entities.LessonBlock) error
+	Delete(ctx context.Context, id uint) error
+	UpdateOrder(ctx context.Context, lessonID uint, orderedIDs []uint) error
+}
+
+type lessonBlockRepository struct {
+	db *gorm.DB
+}
+
+func NewLessonBlockRepository(db *gorm.DB) LessonBlockRepository {
+	return &lessonBlockRepository{db: db}
+}
+
+func (r *lessonBlockRepository) FindByLessonID(ctx context.Context, lessonID uint) ([]*entities.LessonBlock, error) {
+	var blocks []*entities.LessonBlock
+	err := r.db.WithContext(ctx).Where("lesson_id = ?", lessonID).Order("\"order\"").Find(&blocks).Error
+	return blocks, err
+}
+
+func (r *lessonBlockRepository) FindByLessonIDs(ctx context.Context, lessonIDs []uint) ([]*entities.LessonBlock, error) {
+	var blocks []*entities.LessonBlock
+	if len(lessonIDs) == 0 {
+		return blocks, nil
+	}
+	err := r.db.WithContext(ctx).Where("lesson_id IN ?", lessonIDs).Order("lesson_id, \"order\"").Find(&blocks).Error
+	return blocks, err
+}
+
+func (r *lessonBlockRepository) FindByID(ctx context.Context, id uint) (*entities.LessonBlock, error) {
+	var block entities.LessonBlock
+	err := r.db.WithContext(ctx).First(&block, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &block, err
+}
+
+func (r *lessonBlockRepository) Save(ctx context.Context, block *entities.LessonBlock) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(block).Error; err != nil {
+			return err
+		}
+		return syncLessonContent(tx, block.LessonID)
+	})
+}
+
+func (r *lessonBlockRepository) Update(ctx context.Context, block *entities.LessonBlock) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(block).Error; err != nil {
+			return err
+		}
+		return syncLessonContent(tx, block.LessonID)
+	})
+}
+
+func (r *lessonBlockRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var block entities.LessonBlock
+		err := tx.Select("id", "lesson_id").First(&block, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err = tx.Delete(&block).Error; err != nil {
+			return err
+		}
+		return syncLessonContent(tx, block.LessonID)
+	})
+}
+
+// UpdateOrder проставляет блокам урока порядок по позиции в orderedIDs (начиная с 1) одной транзакцией.
+func (r *lessonBlockRepository) UpdateOrder(ctx context.Context, lessonID uint, orderedIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range orderedIDs {
+			err := tx.Model(&entities.LessonBlock{}).
+				Where("id = ? AND lesson_id = ?", id, lessonID).
+				Update("order", i+1).Error
+			if err != nil {
+				return err
+			}
+		}
+		return syncLessonContent(tx, lessonID)
+	})
+}
+
+// syncLessonContent переписывает Lesson.Content и ContentFormat по первому текстовому блоку
+// урока, чтобы поисковый вектор и старые клиенты видели актуальный текст. Без текстовых
+// блоков содержимое пустое, а формат остаётся прежним.
+func syncLessonContent(tx *gorm.DB, lessonID uint) error {
+	first := tx.Model(&entities.LessonBlock{}).
+		Where("lesson_id = lessons.id AND type = ?", entities.BlockTypeText).
+		Order(`"order", id`).Limit(1)
+	return tx.Model(&entities.Lesson{}).Where("id = ?", lessonID).UpdateColumns(map[string]interface{}{
+		"content":        gorm.Expr("coalesce((?), '')", first.Session(&gorm.Session{}).Select("text")),
+		"content_format": gorm.Expr("coalesce((?), content_format)", first.Session(&gorm.Session{}).Select("nullif(format, '')")),
+	}).Error
+}
+
+// MigrateLessonBlocks один раз переносит старое поле Lesson.Content в текстовый блок для уроков
+// без блоков. Факт переноса записывается в data_migrations в той же транзакции, поэтому
+// урок, у которого позже удалили все блоки, при следующем запуске их обратно не получит.
+// После переноса источник содержимого урока — блоки; Content остаётся только зеркалом для старых клиентов.
+func MigrateLessonBlocks(db *gorm.DB) error {
+	err := db.Exec(`CREATE TABLE IF NOT EXISTS data_migrations (
+		name varchar(100) PRIMARY KEY,
+		applied_at timestamptz NOT NULL DEFAULT now())`).Error
+	if err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`INSERT INTO data_migrations (name) VALUES (?) ON CONFLICT DO NOTHING`, "lesson_blocks")
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Exec(`INSERT INTO lesson_blocks (lesson_id, type, "order", text, format, created_at, updated_at)
+			SELECT l.id, ?, 1, l.content, coalesce(nullif(l.content_format, ''), ?), now(), now()
+			FROM lessons l
+			WHERE coalesce(l.content, '') <> ''
+				AND NOT EXISTS (SELECT 1 FROM lesson_blocks b WHERE b.lesson_id = l.id)`,
+			entities.BlockTypeText, entities.ContentFormatPlain).Error
+	})
+}
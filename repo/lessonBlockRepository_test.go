@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"lms-system-internship/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLessonBlockRepository_SyncsLessonContent(t *testing.T) {
+	ctx := context.Background()
+	cases := map[string]func(LessonBlockRepository) error{
+		"save": func(r LessonBlockRepository) error {
+			return r.Save(ctx, &entities.LessonBlock{LessonID: 5, Type: entities.BlockTypeText, Order: 1, Text: "hello"})
+		},
+		"update": func(r LessonBlockRepository) error {
+			return r.Update(ctx, &entities.LessonBlock{ID: 3, LessonID: 5, Type: entities.BlockTypeText, Order: 1, Text: "hello"})
+		},
+		"reorder": func(r LessonBlockRepository) error {
+			return r.UpdateOrder(ctx, 5, []uint{3, 2})
+		},
+	}
+	for name, change := range cases {
+		t.Run(name, func(t *testing.T) {
+			db, rec := newRecordingDB(t)
+
+			err := change(NewLessonBlockRepository(db))
+
+			assert.NoError(t, err)
+			statements := rec.Statements()
+			sync := indexOf(statements, `UPDATE "lessons" SET "content"`)
+			assert.Equal(t, len(statements)-1, sync, "lesson content is rewritten after the blocks change: %v", statements)
+			assert.Contains(t, statements[sync], `FROM "lesson_blocks" WHERE lesson_id = lessons.id AND type = $`)
+			assert.Equal(t, "COMMIT", rec.All()[len(rec.All())-1], "in the same transaction")
+		})
+	}
+}
+
+func TestLessonBlockRepository_DeleteMissing(t *testing.T) {
+	db, rec := newRecordingDB(t)
+
+	err := NewLessonBlockRepository(db).Delete(context.Background(), 3)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, -1, indexOf(rec.Statements(), `UPDATE "lessons"`))
+}
@@ -11,6 +11,7 @@ import (
 type LessonUserRepository interface {
 	GrantAccess(userID uuid.UUID, lessonID uint) error
 	HasAccess(userID uuid.UUID, lessonID uint) (bool, error)
+	FindLessonIDsByUser(userID uuid.UUID) ([]uint, error)
 }
 
 type lessonUserRepository struct {
@@ -50,3 +51,9 @@ func (r *lessonUserRepository) HasAccess(userID uuid.UUID, lessonID uint) (bool,
 	}
 	return err == nil, err
 }
+
+func (r *lessonUserRepository) FindLessonIDsByUser(userID uuid.UUID) ([]uint, error) {
+	ids := []uint{}
+	err := r.db.Model(&entities.LessonUser{}).Where("user_id = ?", userID).Pluck("lesson_id", &ids).Error
+	return ids, err
+}
@@ -9,11 +9,24 @@ import (
 
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
-		Course:     &courseRepository{db: db},
-		Chapter:    &chapterRepository{db: db},
-		Lesson:     &lessonRepository{db: db},
-		Attachment: &attachmentRepo{db: db},
-		LessonUser: &lessonUserRepository{db: db},
+		Course:      &courseRepository{db: db},
+		Chapter:     &chapterRepository{db: db},
+		Lesson:      &lessonRepository{db: db},
+		Attachment:  &attachmentRepo{db: db},
+		LessonUser:  &lessonUserRepository{db: db},
+		Assignment:  &assignmentRepository{db: db},
+		Submission:  &submissionRepository{db: db},
+		Gradebook:   &gradebookRepository{db: db},
+		Certificate: &certificateRepository{db: db},
+		Search:      &searchRepository{db: db},
+		Taxonomy:    &taxonomyRepository{db: db},
+		LessonBlock: &lessonBlockRepository{db: db},
+		Revision:    &revisionRepository{db: db},
+		Version:     &courseVersionRepository{db: db},
+		Translation: &translationRepository{db: db},
+		UserProfile: &userProfileRepository{db: db},
+		EmailToken:  &emailTokenRepository{db: db},
+		UserImport:  &userImportRepository{db: db},
 	}
 }
 
@@ -22,38 +35,77 @@ type courseRepository struct {
 	db *gorm.DB
 }
 
-func (r *courseRepository) FindAll(ctx context.Context) ([]*entities.Course, error) {
+func (r *courseRepository) FindAll(ctx context.Context, filter CourseFilter) ([]*entities.Course, error) {
 	var courses []*entities.Course
-	err := r.db.Preload("Chapters.Lessons").WithContext(ctx).Find(&courses).Error
+	err := applyCourseFilter(r.db.Preload("Chapters.Lessons").Preload("Tags").WithContext(ctx), filter).Find(&courses).Error
 	return courses, err
 }
 
+// TagFacets считает, сколько курсов из выборки по filter отмечено каждым тегом.
+func (r *courseRepository) TagFacets(ctx context.Context, filter CourseFilter) ([]TagFacet, error) {
+	matched := applyCourseFilter(r.db.Model(&entities.Course{}).Select("courses.id"), filter)
+
+	facets := []TagFacet{}
+	err := r.db.WithContext(ctx).Table("course_tags").
+		Select("tags.name AS tag, COUNT(*) AS count").
+		Joins("JOIN tags ON tags.id = course_tags.tag_id").
+		Where("course_tags.course_id IN (?)", matched).
+		Group("tags.name").
+		Order("count DESC, tags.name").
+		Scan(&facets).Error
+	return facets, err
+}
+
+// applyCourseFilter ограничивает выборку курсов рубрикой (вместе со всеми её подрубриками) и тегами.
+func applyCourseFilter(db *gorm.DB, filter CourseFilter) *gorm.DB {
+	if filter.CategoryID != nil {
+		db = db.Where(`courses.category_id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM categories WHERE id = ?
+				UNION ALL
+				SELECT c.id FROM categories c JOIN subtree ON c.parent_id = subtree.id
+			) SELECT id FROM subtree)`, *filter.CategoryID)
+	}
+	for _, tag := range filter.Tags {
+		db = db.Where(`EXISTS (SELECT 1 FROM course_tags ct JOIN tags t ON t.id = ct.tag_id
+			WHERE ct.course_id = courses.id AND t.name = ?)`, tag)
+	}
+	return db
+}
+
 func (r *courseRepository) FindByID(ctx context.Context, id uint) (*entities.Course, error) {
 	var course entities.Course
-	err := r.db.Preload("Chapters.Lessons").WithContext(ctx).First(&course, id).Error
+	err := r.db.Preload("Chapters.Lessons").Preload("Tags").WithContext(ctx).First(&course, id).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, ErrNotFound
 	}
 	return &course, err
 }
 
+// Теги курса меняются только через TaxonomyRepository.SetCourseTaxonomy, поэтому здесь они не сохраняются.
 func (r *courseRepository) Save(ctx context.Context, course *entities.Course) error {
-	return r.db.WithContext(ctx).Create(course).Error
+	return r.db.WithContext(ctx).Omit("Tags").Create(course).Error
 }
 
 func (r *courseRepository) Update(ctx context.Context, course *entities.Course) error {
-	return r.db.WithContext(ctx).Save(course).Error
+	return r.db.WithContext(ctx).Omit("Tags", "Chapters").Save(course).Error
 }
 
+// Delete удаляет курс вместе с привязками к тегам: на course_tags ссылается внешний ключ.
 func (r *courseRepository) Delete(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(&entities.Course{}, id)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return ErrNotFound
-	}
-	return nil
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Course{ID: id}).Association("Tags").Clear(); err != nil {
+			return err
+		}
+		result := tx.Delete(&entities.Course{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
 }
 
 // Chapter Repository
@@ -120,28 +172,84 @@ func (r *lessonRepository) FindByChapterID(ctx context.Context, chapterID uint)
 
 func (r *lessonRepository) FindByID(ctx context.Context, id uint) (*entities.Lesson, error) {
 	var lesson entities.Lesson
-	err := r.db.WithContext(ctx).First(&lesson, id).Error
+	err := r.db.Preload("Tags").Preload("Blocks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("\"order\"")
+	}).WithContext(ctx).First(&lesson, id).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, ErrNotFound
 	}
 	return &lesson, err
 }
 
+// Теги и вложения урока хранятся через свои репозитории. Блоки, заданные при создании,
+// сохраняются вместе с уроком в одной транзакции.
 func (r *lessonRepository) Save(ctx context.Context, lesson *entities.Lesson) error {
-	return r.db.WithContext(ctx).Create(lesson).Error
+	return r.db.WithContext(ctx).Omit("Tags", "Attachments").Create(lesson).Error
 }
 
 func (r *lessonRepository) Update(ctx context.Context, lesson *entities.Lesson) error {
-	return r.db.WithContext(ctx).Save(lesson).Error
-}
-
+	return r.db.WithContext(ctx).Omit("Tags", "Blocks", "Attachments").Save(lesson).Error
+}
+
+// UpdateWithBlocks сохраняет урок и приводит его блоки к lesson.Blocks одной транзакцией:
+// блоки с ID обновляются, без ID — создаются, остальные блоки урока удаляются.
+func (r *lessonRepository) UpdateWithBlocks(ctx context.Context, lesson *entities.Lesson) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tags", "Blocks", "Attachments").Save(lesson).Error; err != nil {
+			return err
+		}
+		keep := []uint{0} // 0 не бывает ID, зато IN не окажется пустым
+		for _, block := range lesson.Blocks {
+			if block.ID != 0 {
+				keep = append(keep, block.ID)
+			}
+		}
+		err := tx.Where("lesson_id = ? AND id NOT IN ?", lesson.ID, keep).Delete(&entities.LessonBlock{}).Error
+		if err != nil {
+			return err
+		}
+		for i := range lesson.Blocks {
+			lesson.Blocks[i].LessonID = lesson.ID
+			if err := tx.Save(&lesson.Blocks[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete удаляет урок одной транзакцией вместе со всем, что от него зависит: привязками
+// к тегам, блоками и записями вложений (на них ссылаются внешние ключи), а также переводами
+// урока и его блоков и историей правок урока. Файлы вложений удаляет сервис.
 func (r *lessonRepository) Delete(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(&entities.Lesson{}, id)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return ErrNotFound
-	}
-	return nil
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Lesson{ID: id}).Association("Tags").Clear(); err != nil {
+			return err
+		}
+		err := tx.Where("(entity_type = ? AND entity_id = ?) OR (entity_type = ? AND entity_id IN (?))",
+			entities.RevisionEntityLesson, id,
+			entities.TranslationEntityBlock, tx.Model(&entities.LessonBlock{}).Select("id").Where("lesson_id = ?", id),
+		).Delete(&entities.Translation{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("entity_type = ? AND entity_id = ?", entities.RevisionEntityLesson, id).Delete(&entities.Revision{}).Error
+		if err != nil {
+			return err
+		}
+		if err = tx.Where("lesson_id = ?", id).Delete(&entities.LessonBlock{}).Error; err != nil {
+			return err
+		}
+		if err = tx.Where("lesson_id = ?", id).Delete(&entities.Attachment{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entities.Lesson{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
 }
@@ -8,10 +8,24 @@ import (
 
 var (
 	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate — запись нарушает уникальный индекс.
+	ErrDuplicate = errors.New("record already exists")
 )
 
+// CourseFilter — условия выборки каталога. CategoryID включает и подрубрики; Tags — курс должен иметь все теги.
+type CourseFilter struct {
+	CategoryID *uint
+	Tags       []string
+}
+
+type TagFacet struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
 type CourseRepository interface {
-	FindAll(ctx context.Context) ([]*entities.Course, error)
+	FindAll(ctx context.Context, filter CourseFilter) ([]*entities.Course, error)
+	TagFacets(ctx context.Context, filter CourseFilter) ([]TagFacet, error)
 	FindByID(ctx context.Context, id uint) (*entities.Course, error)
 	Save(ctx context.Context, course *entities.Course) error
 	Update(ctx context.Context, course *entities.Course) error
@@ -33,13 +47,27 @@ type LessonRepository interface {
 	FindByID(ctx context.Context, id uint) (*entities.Lesson, error)
 	Save(ctx context.Context, lesson *entities.Lesson) error
 	Update(ctx context.Context, lesson *entities.Lesson) error
+	UpdateWithBlocks(ctx context.Context, lesson *entities.Lesson) error
 	Delete(ctx context.Context, id uint) error
 }
 
 type Repository struct {
-	Course     CourseRepository
-	Chapter    ChapterRepository
-	Lesson     LessonRepository
-	Attachment AttachmentRepository
-	LessonUser LessonUserRepository
+	Course      CourseRepository
+	Chapter     ChapterRepository
+	Lesson      LessonRepository
+	Attachment  AttachmentRepository
+	LessonUser  LessonUserRepository
+	Assignment  AssignmentRepository
+	Submission  SubmissionRepository
+	Gradebook   GradebookRepository
+	Certificate CertificateRepository
+	Search      SearchRepository
+	Taxonomy    TaxonomyRepository
+	LessonBlock LessonBlockRepository
+	Revision    RevisionRepository
+	Version     CourseVersionRepository
+	Translation TranslationRepository
+	UserProfile UserProfileRepository
+	EmailToken  EmailTokenRepository
+	UserImport  UserImportRepository
 }
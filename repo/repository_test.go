@@ -0,0 +1,230 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"lms-system-internship/entities"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// recordingDriver — драйвер database/sql без базы: запоминает выполненные запросы, на SELECT
+// отдаёт пустой результат, а на изменения — число строк из recorder.affected. Так тесты видят
+// SQL, который репозиторий действительно отправил бы в Postgres, и его порядок в транзакции.
+type recordingDriver struct{}
+
+var (
+	recordersMu sync.Mutex
+	recorders   = map[string]*recorder{}
+)
+
+func init() {
+	sql.Register("lms-recording", recordingDriver{})
+}
+
+type recorder struct {
+	mu         sync.Mutex
+	statements []string
+	// affected возвращает число изменённых строк для запроса; nil — 1.
+	affected func(query string) int64
+	// fail, если задан, возвращает ошибку выполнения запроса.
+	fail func(query string) error
+}
+
+func (r *recorder) record(query string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, query)
+	if r.fail != nil {
+		if err := r.fail(query); err != nil {
+			return 0, err
+		}
+	}
+	if r.affected == nil {
+		return 1, nil
+	}
+	return r.affected(query), nil
+}
+
+// Statements возвращает выполненные запросы без BEGIN, COMMIT и ROLLBACK.
+func (r *recorder) Statements() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []string
+	for _, statement := range r.statements {
+		if statement != "BEGIN" && statement != "COMMIT" && statement != "ROLLBACK" {
+			list = append(list, statement)
+		}
+	}
+	return list
+}
+
+func (r *recorder) All() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.statements...)
+}
+
+// newRecordingDB открывает gorm с диалектом Postgres поверх recordingDriver.
+func newRecordingDB(t *testing.T) (*gorm.DB, *recorder) {
+	t.Helper()
+	rec := &recorder{}
+	recordersMu.Lock()
+	recorders[t.Name()] = rec
+	recordersMu.Unlock()
+	t.Cleanup(func() {
+		recordersMu.Lock()
+		delete(recorders, t.Name())
+		recordersMu.Unlock()
+	})
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DriverName: "lms-recording", DSN: t.Name()}), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	return db, rec
+}
+
+func (recordingDriver) Open(name string) (driver.Conn, error) {
+	recordersMu.Lock()
+	defer recordersMu.Unlock()
+	return &recordingConn{rec: recorders[name]}, nil
+}
+
+type recordingConn struct {
+	rec *recorder
+}
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *recordingConn) Close() error                        { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *recordingConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	_, _ = c.rec.record("BEGIN")
+	return recordingTx{rec: c.rec}, nil
+}
+
+func (c *recordingConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	affected, err := c.rec.record(query)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(affected), nil
+}
+
+func (c *recordingConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if _, err := c.rec.record(query); err != nil {
+		return nil, err
+	}
+	return emptyRows{}, nil
+}
+
+type recordingTx struct {
+	rec *recorder
+}
+
+func (tx recordingTx) Commit() error {
+	_, _ = tx.rec.record("COMMIT")
+	return nil
+}
+
+func (tx recordingTx) Rollback() error {
+	_, _ = tx.rec.record("ROLLBACK")
+	return nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return nil }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+// indexOf возвращает позицию первого запроса, содержащего fragment, или -1.
+func indexOf(statements []string, fragment string) int {
+	for i, statement := range statements {
+		if strings.Contains(statement, fragment) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestCourseRepository_Delete(t *testing.T) {
+	db, rec := newRecordingDB(t)
+
+	err := (&courseRepository{db: db}).Delete(context.Background(), 7)
+
+	assert.NoError(t, err)
+	statements := rec.Statements()
+	tags, course := indexOf(statements, `DELETE FROM "course_tags"`), indexOf(statements, `DELETE FROM "courses"`)
+	assert.GreaterOrEqual(t, tags, 0, "tag links are removed: %v", statements)
+	assert.Less(t, tags, course, "tag links are removed before the course: %v", statements)
+	assert.Equal(t, "COMMIT", rec.All()[len(rec.All())-1])
+}
+
+func TestCourseRepository_DeleteMissing(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.affected = func(string) int64 { return 0 }
+
+	err := (&courseRepository{db: db}).Delete(context.Background(), 7)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, "ROLLBACK", rec.All()[len(rec.All())-1])
+}
+
+func TestLessonRepository_Delete(t *testing.T) {
+	db, rec := newRecordingDB(t)
+
+	err := (&lessonRepository{db: db}).Delete(context.Background(), 5)
+
+	assert.NoError(t, err)
+	statements := rec.Statements()
+	lesson := indexOf(statements, `DELETE FROM "lessons"`)
+	tags := indexOf(statements, `DELETE FROM "lesson_tags"`)
+	assert.GreaterOrEqual(t, tags, 0, "tag links are removed: %v", statements)
+	assert.Less(t, tags, lesson, "tag links are removed before the lesson: %v", statements)
+}
+
+func TestTaxonomyRepository_SaveTagDuplicate(t *testing.T) {
+	db, rec := newRecordingDB(t)
+	rec.fail = func(query string) error {
+		if strings.Contains(query, `INSERT INTO "tags"`) {
+			return &pgconn.PgError{Code: "23505", ConstraintName: "idx_tags_name"}
+		}
+		return nil
+	}
+
+	err := NewTaxonomyRepository(db).SaveTag(context.Background(), &entities.Tag{Name: "go"})
+
+	assert.ErrorIs(t, err, ErrDuplicate)
+}
+
+func TestLessonRepository_DeleteDependents(t *testing.T) {
+	db, rec := newRecordingDB(t)
+
+	err := (&lessonRepository{db: db}).Delete(context.Background(), 5)
+
+	assert.NoError(t, err)
+	statements := rec.Statements()
+	lesson := indexOf(statements, `DELETE FROM "lessons"`)
+	for _, dependent := range []string{`DELETE FROM "lesson_tags"`, `DELETE FROM "translations"`, `DELETE FROM "revisions"`, `DELETE FROM "lesson_blocks"`, `DELETE FROM "attachments"`} {
+		i := indexOf(statements, dependent)
+		assert.GreaterOrEqual(t, i, 0, "%s is executed: %v", dependent, statements)
+		assert.Less(t, i, lesson, "%s runs before the lesson is deleted: %v", dependent, statements)
+	}
+	assert.Less(t, indexOf(statements, `DELETE FROM "translations"`), indexOf(statements, `DELETE FROM "lesson_blocks"`),
+		"block translations are found before the blocks are gone: %v", statements)
+	assert.Equal(t, "COMMIT", rec.All()[len(rec.All())-1])
+}
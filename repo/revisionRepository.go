@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"lms-system-internship/entities"
+	"time"
+)
+
+type RevisionRepository interface {
+	FindByEntity(ctx context.Context, entityType string, entityID uint) ([]*entities.Revision, error)
+	FindByNumber(ctx context.Context, entityType string, entityID uint, number int) (*entities.Revision, error)
+	FindLatest(ctx context.Context, entityType string, entityID uint) (*entities.Revision, error)
+	Save(ctx context.Context, revision *entities.Revision) error
+	Prune(ctx context.Context, entityType string, entityID uint, keep int, olderThan *time.Time) error
+}
+
+type revisionRepository struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+func (r *revisionRepository) FindByEntity(ctx context.Context, entityType string, entityID uint) ([]*entities.Revision, error) {
+	var list []*entities.Revision
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("number DESC").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *revisionRepository) FindByNumber(ctx context.Context, entityType string, entityID uint, number int) (*entities.Revision, error) {
+	var revision entities.Revision
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ? AND number = ?", entityType, entityID, number).
+		First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &revision, err
+}
+
+func (r *revisionRepository) FindLatest(ctx context.Context, entityType string, entityID uint) (*entities.Revision, error) {
+	var revision entities.Revision
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("number DESC").
+		First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &revision, err
+}
+
+// Save присваивает ревизии следующий номер для её сущности. Уникальный индекс
+// не даст двум параллельным правкам получить один номер.
+func (r *revisionRepository) Save(ctx context.Context, revision *entities.Revision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var last int
+		err := tx.Model(&entities.Revision{}).
+			Where("entity_type = ? AND entity_id = ?", revision.EntityType, revision.EntityID).
+			Select("coalesce(max(number), 0)").
+			Scan(&last).Error
+		if err != nil {
+			return err
+		}
+		revision.Number = last + 1
+		return tx.Create(revision).Error
+	})
+}
+
+// Prune удаляет ревизии сверх keep последних и созданные раньше olderThan.
+// keep == 0 и olderThan == nil отключают соответствующее ограничение; последняя ревизия остаётся всегда.
+func (r *revisionRepository) Prune(ctx context.Context, entityType string, entityID uint, keep int, olderThan *time.Time) error {
+	if keep == 0 && olderThan == nil {
+		return nil
+	}
+
+	latest := r.db.Model(&entities.Revision{}).
+		Select("max(number)").
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID)
+
+	expired := r.db.Where("1 = 0")
+	if keep > 0 {
+		expired = expired.Or("number <= (?) - ?", latest, keep)
+	}
+	if olderThan != nil {
+		expired = expired.Or("created_at < ?", *olderThan)
+	}
+
+	return r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Where("number < (?)", latest).
+		Where(expired).
+		Delete(&entities.Revision{}).Error
+}
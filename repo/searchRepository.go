@@ -0,0 +1,188 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const (
+	SearchTypeCourse     = "course"
+	SearchTypeChapter    = "chapter"
+	SearchTypeLesson     = "lesson"
+	SearchTypeAttachment = "attachment"
+)
+
+// SearchQuery — параметры полнотекстового поиска.
+// LessonIDs ограничивает уроки и вложения доступными пользователю; nil — без ограничений.
+type SearchQuery struct {
+	Text      string
+	Types     []string
+	CourseID  *uint
+	LessonIDs []uint
+	Limit     int
+	Offset    int
+}
+
+type SearchHit struct {
+	Type     string  `json:"type"`
+	ID       uint    `json:"id"`
+	CourseID uint    `json:"course_id"`
+	LessonID *uint   `json:"lesson_id,omitempty"`
+	Title    string  `json:"title"`
+	Snippet  string  `json:"snippet"` // HTML: исходный текст экранирован, совпадения обёрнуты в <mark>
+	Rank     float64 `json:"rank"`
+}
+
+type SearchRepository interface {
+	Search(ctx context.Context, query SearchQuery) ([]*SearchHit, error)
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// searchVectors — вычисляемые tsvector-колонки. Название весит больше описания и содержимого,
+// каждое поле индексируется и английской, и русской конфигурацией.
+var searchVectors = map[string]string{
+	"courses": `setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('russian', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+		setweight(to_tsvector('russian', coalesce(description, '')), 'B')`,
+	"chapters": `setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('russian', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+		setweight(to_tsvector('russian', coalesce(description, '')), 'B')`,
+	"lessons": `setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('russian', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(description, '')), 'B') ||
+		setweight(to_tsvector('russian', coalesce(description, '')), 'B') ||
+		setweight(to_tsvector('english', coalesce(content, '')), 'C') ||
+		setweight(to_tsvector('russian', coalesce(content, '')), 'C')`,
+	"attachments": `setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('russian', coalesce(name, '')), 'A')`,
+	// Содержимое урока хранится в блоках; поле lessons.content — лишь копия для старых клиентов
+	"lesson_blocks": `setweight(to_tsvector('english', coalesce(text, '') || ' ' || coalesce(caption, '')), 'C') ||
+		setweight(to_tsvector('russian', coalesce(text, '') || ' ' || coalesce(caption, '')), 'C')`,
+}
+
+// MigrateSearch добавляет tsvector-колонки и GIN-индексы. AutoMigrate о них не знает,
+// поэтому вызывается отдельно после него; повторный вызов ничего не меняет.
+func MigrateSearch(db *gorm.DB) error {
+	for table, vector := range searchVectors {
+		statements := []string{
+			fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (%s) STORED`, table, vector),
+			fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_search_vector ON %s USING GIN (search_vector)`, table, table),
+		}
+		for _, stmt := range statements {
+			if err := db.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("failed to migrate search for %s: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
+
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
+
+// headline строит сниппет по выражению source. Текст экранируется до ts_headline, поэтому
+// единственная разметка в результате — <mark>, и сниппет можно вставлять как HTML.
+// HTML-сущности парсер tsvector пропускает, так что на совпадения экранирование не влияет.
+func headline(source string) string {
+	escaped := source
+	for _, r := range [][2]string{{"&", "&amp;"}, {"<", "&lt;"}, {">", "&gt;"}, {`"`, "&quot;"}, {"''", "&#39;"}} {
+		escaped = fmt.Sprintf("replace(%s, '%s', '%s')", escaped, r[0], r[1])
+	}
+	return fmt.Sprintf("ts_headline('russian', %s, q.query, @headline)", escaped)
+}
+
+func (r *searchRepository) Search(ctx context.Context, query SearchQuery) ([]*SearchHit, error) {
+	var parts []string
+
+	for _, t := range query.Types {
+		var sql string
+		switch t {
+		case SearchTypeCourse:
+			sql = `SELECT 'course' AS type, c.id, c.id AS course_id, NULL::bigint AS lesson_id, c.name AS title,
+				` + headline("coalesce(c.description, '')") + ` AS snippet,
+				ts_rank(c.search_vector, q.query) AS rank
+				FROM courses c, q WHERE c.search_vector @@ q.query`
+			sql += courseFilter("c.id", query.CourseID)
+		case SearchTypeChapter:
+			sql = `SELECT 'chapter' AS type, ch.id, ch.course_id, NULL::bigint AS lesson_id, ch.name AS title,
+				` + headline("coalesce(ch.description, '')") + ` AS snippet,
+				ts_rank(ch.search_vector, q.query) AS rank
+				FROM chapters ch, q WHERE ch.search_vector @@ q.query`
+			sql += courseFilter("ch.course_id", query.CourseID)
+		case SearchTypeLesson:
+			// Урок находится и по своим полям, и по тексту блоков; сниппет строится по описанию и блокам
+			sql = `SELECT 'lesson' AS type, l.id, ch.course_id, l.id AS lesson_id, l.name AS title,
+				` + headline("coalesce(l.description, '') || ' ' || coalesce(b.body, '')") + ` AS snippet,
+				ts_rank(l.search_vector, q.query) + coalesce(b.rank, 0) AS rank
+				FROM lessons l JOIN chapters ch ON ch.id = l.chapter_id CROSS JOIN q
+				LEFT JOIN LATERAL (
+					SELECT string_agg(coalesce(lb.text, '') || ' ' || coalesce(lb.caption, ''), ' ' ORDER BY lb."order") AS body,
+						max(ts_rank(lb.search_vector, q.query)) FILTER (WHERE lb.search_vector @@ q.query) AS rank
+					FROM lesson_blocks lb WHERE lb.lesson_id = l.id
+				) b ON TRUE
+				WHERE (l.search_vector @@ q.query OR b.rank IS NOT NULL)`
+			sql += courseFilter("ch.course_id", query.CourseID)
+			sql += lessonFilter("l.id", query.LessonIDs)
+		case SearchTypeAttachment:
+			sql = `SELECT 'attachment' AS type, a.id, ch.course_id, a.lesson_id, a.name AS title,
+				` + headline("a.name") + ` AS snippet,
+				ts_rank(a.search_vector, q.query) AS rank
+				FROM attachments a JOIN lessons l ON l.id = a.lesson_id JOIN chapters ch ON ch.id = l.chapter_id, q
+				WHERE a.search_vector @@ q.query`
+			sql += courseFilter("ch.course_id", query.CourseID)
+			sql += lessonFilter("a.lesson_id", query.LessonIDs)
+		default:
+			return nil, fmt.Errorf("unknown search type %q", t)
+		}
+		parts = append(parts, sql)
+	}
+	if len(parts) == 0 {
+		return []*SearchHit{}, nil
+	}
+
+	sql := `WITH q AS (SELECT websearch_to_tsquery('english', @text) || websearch_to_tsquery('russian', @text) AS query) ` +
+		strings.Join(parts, " UNION ALL ") +
+		` ORDER BY rank DESC, type, id LIMIT @limit OFFSET @offset`
+
+	named := map[string]interface{}{
+		"text":     query.Text,
+		"headline": searchHeadlineOptions,
+		"limit":    query.Limit,
+		"offset":   query.Offset,
+		"lessons":  query.LessonIDs,
+	}
+	if query.CourseID != nil {
+		named["course"] = *query.CourseID
+	}
+
+	hits := []*SearchHit{}
+	err := r.db.WithContext(ctx).Raw(sql, named).Scan(&hits).Error
+	return hits, err
+}
+
+func courseFilter(column string, courseID *uint) string {
+	if courseID == nil {
+		return ""
+	}
+	return " AND " + column + " = @course"
+}
+
+func lessonFilter(column string, lessonIDs []uint) string {
+	if lessonIDs == nil {
+		return ""
+	}
+	if len(lessonIDs) == 0 {
+		return " AND FALSE"
+	}
+	return " AND " + column + " IN @lessons"
+}
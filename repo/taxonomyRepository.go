@@ -0,0 +1,184 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"lms-system-internship/entities"
+)
+
+type TaxonomyRepository interface {
+	FindAllCategories(ctx context.Context) ([]*entities.Category, error)
+	FindCategoryByID(ctx context.Context, id uint) (*entities.Category, error)
+	CountChildCategories(ctx context.Context, id uint) (int64, error)
+	SaveCategory(ctx context.Context, category *entities.Category) error
+	UpdateCategory(ctx context.Context, category *entities.Category) error
+	DeleteCategory(ctx context.Context, id uint) error
+
+	FindAllTags(ctx context.Context) ([]*entities.Tag, error)
+	FindTagByID(ctx context.Context, id uint) (*entities.Tag, error)
+	SaveTag(ctx context.Context, tag *entities.Tag) error
+	UpdateTag(ctx context.Context, tag *entities.Tag) error
+	DeleteTag(ctx context.Context, id uint) error
+
+	SetCourseTaxonomy(ctx context.Context, courseID uint, categoryID *uint, tags []string) error
+	SetLessonTaxonomy(ctx context.Context, lessonID uint, categoryID *uint, tags []string) error
+}
+
+type taxonomyRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxonomyRepository(db *gorm.DB) TaxonomyRepository {
+	return &taxonomyRepository{db: db}
+}
+
+func (r *taxonomyRepository) FindAllCategories(ctx context.Context) ([]*entities.Category, error) {
+	var categories []*entities.Category
+	err := r.db.WithContext(ctx).Order("name").Find(&categories).Error
+	return categories, err
+}
+
+func (r *taxonomyRepository) FindCategoryByID(ctx context.Context, id uint) (*entities.Category, error) {
+	var category entities.Category
+	err := r.db.WithContext(ctx).First(&category, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &category, err
+}
+
+func (r *taxonomyRepository) CountChildCategories(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entities.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *taxonomyRepository) SaveCategory(ctx context.Context, category *entities.Category) error {
+	return r.db.WithContext(ctx).Create(category).Error
+}
+
+func (r *taxonomyRepository) UpdateCategory(ctx context.Context, category *entities.Category) error {
+	return r.db.WithContext(ctx).Save(category).Error
+}
+
+// DeleteCategory удаляет рубрику; курсы и уроки из неё остаются без рубрики.
+func (r *taxonomyRepository) DeleteCategory(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Course{}).Where("category_id = ?", id).Update("category_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entities.Lesson{}).Where("category_id = ?", id).Update("category_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entities.Category{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *taxonomyRepository) FindAllTags(ctx context.Context) ([]*entities.Tag, error) {
+	var tags []*entities.Tag
+	err := r.db.WithContext(ctx).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+func (r *taxonomyRepository) FindTagByID(ctx context.Context, id uint) (*entities.Tag, error) {
+	var tag entities.Tag
+	err := r.db.WithContext(ctx).First(&tag, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &tag, err
+}
+
+// SaveTag и UpdateTag возвращают ErrDuplicate, если тег с таким названием уже есть.
+func (r *taxonomyRepository) SaveTag(ctx context.Context, tag *entities.Tag) error {
+	return duplicate(r.db.WithContext(ctx).Create(tag).Error)
+}
+
+func (r *taxonomyRepository) UpdateTag(ctx context.Context, tag *entities.Tag) error {
+	return duplicate(r.db.WithContext(ctx).Save(tag).Error)
+}
+
+// DeleteTag удаляет тег вместе с его привязками к курсам и урокам.
+func (r *taxonomyRepository) DeleteTag(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM course_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM lesson_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entities.Tag{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// SetCourseTaxonomy заменяет рубрику и набор тегов курса. Несуществующие теги создаются.
+func (r *taxonomyRepository) SetCourseTaxonomy(ctx context.Context, courseID uint, categoryID *uint, tags []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		course := entities.Course{ID: courseID}
+		if err := tx.Model(&course).Update("category_id", categoryID).Error; err != nil {
+			return err
+		}
+		list, err := findOrCreateTags(tx, tags)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&course).Association("Tags").Replace(list)
+	})
+}
+
+// SetLessonTaxonomy заменяет рубрику и набор тегов урока. Несуществующие теги создаются.
+func (r *taxonomyRepository) SetLessonTaxonomy(ctx context.Context, lessonID uint, categoryID *uint, tags []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lesson := entities.Lesson{ID: lessonID}
+		if err := tx.Model(&lesson).Update("category_id", categoryID).Error; err != nil {
+			return err
+		}
+		list, err := findOrCreateTags(tx, tags)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&lesson).Association("Tags").Replace(list)
+	})
+}
+
+func findOrCreateTags(tx *gorm.DB, names []string) ([]entities.Tag, error) {
+	list := []entities.Tag{}
+	if len(names) == 0 {
+		return list, nil
+	}
+	tags := make([]entities.Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, entities.Tag{Name: name})
+	}
+	if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(&tags).Error; err != nil {
+		return nil, err
+	}
+	err := tx.Where("name IN ?", names).Find(&list).Error
+	return list, err
+}
+
+// duplicate заменяет нарушение уникального индекса Postgres на ErrDuplicate.
+func duplicate(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrDuplicate
+	}
+	return err
+}
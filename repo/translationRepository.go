@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"lms-system-internship/entities"
+)
+
+type TranslationRepository interface {
+	FindByEntity(ctx context.Context, entityType string, entityID uint) ([]*entities.Translation, error)
+	// FindByEntities возвращает переводы сущностей одного типа; пустая locale — на все локали.
+	FindByEntities(ctx context.Context, entityType string, entityIDs []uint, locale string) ([]*entities.Translation, error)
+	// Upsert создаёт перевод или заменяет существующий на ту же локаль.
+	Upsert(ctx context.Context, translation *entities.Translation) error
+	Delete(ctx context.Context, entityType string, entityID uint, locale string) error
+}
+
+type translationRepository struct {
+	db *gorm.DB
+}
+
+func NewTranslationRepository(db *gorm.DB) TranslationRepository {
+	return &translationRepository{db: db}
+}
+
+func (r *translationRepository) FindByEntity(ctx context.Context, entityType string, entityID uint) ([]*entities.Translation, error) {
+	var list []*entities.Translation
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("locale").
+		Find(&list).Error
+	return list, err
+}
+
+func (r *translationRepository) FindByEntities(ctx context.Context, entityType string, entityIDs []uint, locale string) ([]*entities.Translation, error) {
+	var list []*entities.Translation
+	if len(entityIDs) == 0 {
+		return list, nil
+	}
+	query := r.db.WithContext(ctx).Where("entity_type = ? AND entity_id IN ?", entityType, entityIDs)
+	if locale != "" {
+		query = query.Where("locale = ?", locale)
+	}
+	err := query.Find(&list).Error
+	return list, err
+}
+
+func (r *translationRepository) Upsert(ctx context.Context, translation *entities.Translation) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "content", "updated_at"}),
+	}).Create(translation).Error
+}
+
+func (r *translationRepository) Delete(ctx context.Context, entityType string, entityID uint, locale string) error {
+	result := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ? AND locale = ?", entityType, entityID, locale).
+		Delete(&entities.Translation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"lms-system-internship/entities"
+	"time"
+)
+
+type UserImportRepository interface {
+	CreateJob(ctx context.Context, job *entities.UserImportJob) error
+	// AddResult сохраняет итог строки и в той же транзакции увеличивает счётчик Succeeded или Failed задачи.
+	AddResult(ctx context.Context, result *entities.UserImportResult, succeeded bool) error
+	FinishJob(ctx context.Context, jobID uuid.UUID, status string, at time.Time) error
+	// FindJob возвращает задачу с итогами строк в порядке обработки.
+	FindJob(ctx context.Context, jobID uuid.UUID) (*entities.UserImportJob, error)
+	// DeleteFinishedBefore удаляет задачи, завершённые раньше before, вместе с итогами строк.
+	DeleteFinishedBefore(ctx context.Context, before time.Time) error
+}
+
+type userImportRepository struct {
+	db *gorm.DB
+}
+
+func (r *userImportRepository) CreateJob(ctx context.Context, job *entities.UserImportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *userImportRepository) AddResult(ctx context.Context, result *entities.UserImportResult, succeeded bool) error {
+	counter := "failed"
+	if succeeded {
+		counter = "succeeded"
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(result).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entities.UserImportJob{}).Where("id = ?", result.JobID).
+			Update(counter, gorm.Expr(counter+" + 1")).Error
+	})
+}
+
+func (r *userImportRepository) FinishJob(ctx context.Context, jobID uuid.UUID, status string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&entities.UserImportJob{}).Where("id = ?", jobID).
+		Updates(map[string]any{"status": status, "finished_at": at}).Error
+}
+
+func (r *userImportRepository) FindJob(ctx context.Context, jobID uuid.UUID) (*entities.UserImportJob, error) {
+	var job entities.UserImportJob
+	err := r.db.WithContext(ctx).
+		Preload("Results", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&job, "id = ?", jobID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &job, err
+}
+
+func (r *userImportRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		finished := tx.Model(&entities.UserImportJob{}).Select("id").Where("finished_at < ?", before)
+		if err := tx.Where("job_id IN (?)", finished).Delete(&entities.UserImportResult{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("finished_at < ?", before).Delete(&entities.UserImportJob{}).Error
+	})
+}
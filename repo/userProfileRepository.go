@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"lms-system-internship/entities"
+	"strings"
+	"time"
+)
+
+// UserFilter — условия выборки пользователей. Query ищет подстроку в логине, email, имени
+// и фамилии без учёта регистра; Enabled == nil — любые учётные записи.
+type UserFilter struct {
+	Query   string
+	Enabled *bool
+	Limit   int
+	Offset  int
+}
+
+type UserProfileRepository interface {
+	// Search возвращает страницу профилей по filter и общее число подходящих профилей.
+	Search(ctx context.Context, filter UserFilter) ([]*entities.UserProfile, int64, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.UserProfile, error)
+	// Upsert создаёт профили или заменяет существующие с теми же ID.
+	Upsert(ctx context.Context, profiles []*entities.UserProfile) error
+	// DeleteSyncedBefore удаляет профили, не встретившиеся при сверке, начатой в момент t.
+	DeleteSyncedBefore(ctx context.Context, t time.Time) error
+	// DeleteUserData в одной транзакции удаляет профиль и данные пользователя в LMS: доступы
+	// к урокам, сдачи, оценки и их историю, сертификаты, закрепления версий курсов и токены
+	// из писем. Ссылки на пользователя как на автора действий (проверил, выдал, опубликовал) остаются.
+	// Возвращает объекты хранилища, которые больше не нужны.
+	DeleteUserData(ctx context.Context, userID uuid.UUID) ([]string, error)
+}
+
+type userProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewUserProfileRepository(db *gorm.DB) UserProfileRepository {
+	return &userProfileRepository{db: db}
+}
+
+func (r *userProfileRepository) Search(ctx context.Context, filter UserFilter) ([]*entities.UserProfile, int64, error) {
+	query := r.db.WithContext(ctx).Model(&entities.UserProfile{})
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		pattern := "%" + escapeLike(strings.ToLower(q)) + "%"
+		query = query.Where("LOWER(username) LIKE ? OR LOWER(email) LIKE ? OR LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ?",
+			pattern, pattern, pattern, pattern)
+	}
+	if filter.Enabled != nil {
+		query = query.Where("enabled = ?", *filter.Enabled)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	profiles := []*entities.UserProfile{}
+	err := query.Order("username").Limit(filter.Limit).Offset(filter.Offset).Find(&profiles).Error
+	return profiles, total, err
+}
+
+func (r *userProfileRepository) FindByID(ctx context.Context, id uuid.UUID) (*entities.UserProfile, error) {
+	var profile entities.UserProfile
+	err := r.db.WithContext(ctx).First(&profile, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return &profile, err
+}
+
+func (r *userProfileRepository) Upsert(ctx context.Context, profiles []*entities.UserProfile) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"username", "email", "first_name", "last_name", "enabled", "created_at", "synced_at"}),
+	}).Create(profiles).Error
+}
+
+func (r *userProfileRepository) DeleteSyncedBefore(ctx context.Context, t time.Time) error {
+	return r.db.WithContext(ctx).Where("synced_at < ?", t).Delete(&entities.UserProfile{}).Error
+}
+
+func (r *userProfileRepository) DeleteUserData(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var objects []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var submissionFiles, certificateFiles []string
+		if err := tx.Model(&entities.Submission{}).Where("user_id = ?", userID).Pluck("file_url", &submissionFiles).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entities.Certificate{}).Where("user_id = ?", userID).Pluck("file_url", &certificateFiles).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{
+			&entities.LessonUser{}, &entities.Submission{}, &entities.Grade{}, &entities.GradeAudit{},
+			&entities.Certificate{}, &entities.CourseVersionPin{}, &entities.EmailToken{},
+		} {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("id = ?", userID).Delete(&entities.UserProfile{}).Error; err != nil {
+			return err
+		}
+		objects = append(submissionFiles, certificateFiles...)
+		return nil
+	})
+	return objects, err
+}
+
+// escapeLike экранирует символы шаблона LIKE, чтобы они искались буквально.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
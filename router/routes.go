@@ -1,44 +1,37 @@
 package router
 
 import (
-	"fmt"
+	"context"
+	"crypto/rand"
+	"errors"
+	"lms-system-internship/config"
+	"lms-system-internship/entities"
 	"lms-system-internship/files"
 	"lms-system-internship/handler"
+	"lms-system-internship/health"
+	"lms-system-internship/mailer"
 	"lms-system-internship/middleware"
+	"lms-system-internship/pkg"
 	"lms-system-internship/repo"
 	"lms-system-internship/service"
-	"log"
-	"net/http"
 	"os"
+	"sync"
 	"time"
 
-	"github.com/MicahParks/keyfunc"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupRoutes(db *gorm.DB, r *gin.Engine) {
-	jwksURL := "http://keycloak:8080/realms/lms/protocol/openid-connect/certs"
+var errDatabaseNotReady = errors.New("database is not connected and migrated yet")
 
-	var jwks *keyfunc.JWKS
-	var err error
-
-	for i := 1; i <= 10; i++ {
-		jwks, err = keyfunc.Get(jwksURL, keyfunc.Options{
-			RefreshInterval: time.Hour,
-			RefreshErrorHandler: func(err error) {
-				fmt.Printf("JWKS refresh error: %v\n", err)
-			},
-		})
-		if err == nil {
-			break
-		}
-		fmt.Printf("[Retry %d/10] Failed to connect to Keycloak JWKS: %v\n", i, err)
-		time.Sleep(5 * time.Second)
-	}
-	if err != nil {
-		panic(fmt.Sprintf("Failed to get JWKS from Keycloak: %v", err))
-	}
+// SetupRoutes собирает зависимости и регистрирует маршруты. Ключи Keycloak загружаются и
+// обновляются в фоне до отмены ctx: сервер стартует сразу, а /readyz сообщает, когда все
+// зависимости доступны. База считается недоступной, пока dbReady возвращает false — например,
+// пока идут миграции. Фоновые задачи, которые нужно дождаться при остановке (импорт пользователей),
+// учитываются в tasks. Возвращает проверку готовности, чтобы при остановке перевести её в draining.
+func SetupRoutes(ctx context.Context, db *gorm.DB, dbReady func() bool, tasks *sync.WaitGroup, r *gin.Engine) *health.Checker {
+	keycloakAdmin := service.NewKeycloakAdmin(config.GetKeycloakBaseURL(), config.GetKeycloakRealm(), config.GetKeycloakAdmin(), config.GetKeycloakPassword())
+	identity := newIdentityProvider(ctx, keycloakAdmin)
 
 	repository := repo.NewRepository(db)
 
@@ -49,27 +42,108 @@ func SetupRoutes(db *gorm.DB, r *gin.Engine) {
 		os.Getenv("MINIO_BUCKET"),
 	)
 	if err != nil {
-		log.Fatalf("failed to init MinioStorage: %v", err)
+		pkg.Logger.WithError(err).Fatal("Failed to init MinIO storage")
+	}
+
+	certificateRenderer, err := service.NewPDFCertificateRenderer(
+		config.GetCertificateTitle(),
+		config.GetCertificateBodyTemplate(),
+		config.GetCertificateFontPath(),
+	)
+	if err != nil {
+		pkg.Logger.WithError(err).Fatal("Failed to init certificate renderer")
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		pkg.Logger.WithError(err).Fatal("Failed to get database handle")
 	}
+	checker := health.NewChecker(2*time.Second,
+		health.Check{Name: "database", Run: func(ctx context.Context) error {
+			if !dbReady() {
+				return errDatabaseNotReady
+			}
+			return sqlDB.PingContext(ctx)
+		}},
+		health.Check{Name: "storage", Run: minioStorage.Check},
+		health.Check{Name: "identity", Run: identity.Check},
+	)
+	healthH := handler.NewHealthHandler(checker)
+	authH := handler.NewAuthHandler(identity)
+	r.GET("/healthz", healthH.Liveness)
+	r.GET("/readyz", healthH.Readiness)
+
+	storage := files.NewInstrumentedStorage(minioStorage)
+	svc := service.NewService(repository, storage, service.NewKeycloakUserDirectory(keycloakAdmin), certificateRenderer)
 
-	svc := service.NewService(repository, minioStorage)
+	identityService := service.NewKeycloakIdentityService(keycloakAdmin)
+	userAdmin := service.NewUserAdminService(identityService, identity, repository.UserProfile, repository.LessonUser, storage, config.GetUserCacheTTL())
+	userH := handler.NewUserHandler(identityService, userAdmin)
+
+	// Без почты ссылки для подтверждения, сброса пароля и приглашения импортированным пользователям
+	// отправить некуда
+	var registrationH *handler.RegistrationHandler
+	var userImportH *handler.UserImportHandler
+	if mail := newMailer(); mail != nil {
+		registration := service.NewRegistrationService(service.RegistrationConfig{
+			Enabled:            config.GetSelfRegistrationEnabled(),
+			EmailDomains:       config.GetSelfRegistrationEmailDomains(),
+			Roles:              config.GetSelfRegistrationRoles(),
+			VerifyEmailURL:     config.GetVerifyEmailURL(),
+			PasswordResetURL:   config.GetPasswordResetURL(),
+			VerifyTokenTTL:     config.GetVerifyEmailTokenTTL(),
+			ResetTokenTTL:      config.GetPasswordResetTokenTTL(),
+			InviteTokenTTL:     config.GetInviteTokenTTL(),
+			ResendInterval:     config.GetEmailResendInterval(),
+			EmailRequestLimit:  config.GetEmailRequestLimit(),
+			EmailRequestWindow: config.GetEmailRequestLimitWindow(),
+		}, identityService, identity, userAdmin, repository.EmailToken, mail)
+		registrationH = handler.NewRegistrationHandler(registration)
+		userImportH = handler.NewUserImportHandler(
+			service.NewUserImportService(ctx, tasks, identityService, userAdmin, registration, repository.Course, repository.LessonUser, repository.UserImport, config.GetUserImportJobRetention()),
+			config.GetUserImportMaxRows(),
+		)
+	} else if config.GetSelfRegistrationEnabled() {
+		pkg.Logger.Warn("SELF_REGISTRATION_ENABLED is set but MAILER is not, self-registration and password reset are disabled")
+	}
 
-	courseH := handler.NewCourseHandler(svc.CourseService)
+	courseH := handler.NewCourseHandler(svc.CourseService, svc.VersionService)
 	chapterH := handler.NewChapterHandler(svc.ChapterService)
-	lessonH := handler.NewLessonHandler(svc.LessonService)
-	attachmentH := handler.NewAttachmentHandler(svc.AttachmentService)
+	lessonH := handler.NewLessonHandler(svc.LessonService, svc.VersionService)
+	attachmentH := handler.NewAttachmentHandler(svc.AttachmentService, svc.VersionService)
+	assignmentH := handler.NewAssignmentHandler(svc.AssignmentService)
+	gradebookH := handler.NewGradebookHandler(svc.GradebookService)
+	certificateH := handler.NewCertificateHandler(svc.CertificateService)
+	searchH := handler.NewSearchHandler(svc.SearchService)
+	taxonomyH := handler.NewTaxonomyHandler(svc.TaxonomyService)
+	blockH := handler.NewLessonBlockHandler(svc.LessonBlockService, svc.VersionService)
+	revisionH := handler.NewRevisionHandler(svc.RevisionService)
+	versionH := handler.NewCourseVersionHandler(svc.VersionService)
+	translationH := handler.NewTranslationHandler(svc.TranslationService)
 
+	// Группа копирует middleware движка при создании, поэтому Locale подключается до неё
+	r.Use(middleware.Locale(config.GetDefaultLocale(), config.GetSupportedLocales()))
 	api := r.Group("/api")
 	{
-		api.GET("", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"status": "ok"})
-		})
-		api.POST("/auth/login", handler.LoginHandler)
-		api.POST("/auth/refresh", handler.RefreshTokenHandler)
+		api.GET("", healthH.Liveness)
+		api.POST("/auth/login", authH.Login)
+		api.POST("/auth/refresh", authH.Refresh)
+		api.POST("/auth/logout", authH.Logout)
+		api.GET("/certificates/verify/:code", certificateH.VerifyCertificate)
+
+		if registrationH != nil {
+			selfService := api.Group("/auth", middleware.RateLimit(config.GetAuthRateLimit(), config.GetAuthRateLimitWindow()))
+			selfService.POST("/register", registrationH.SignUp)
+			selfService.POST("/verify-email", registrationH.VerifyEmail)
+			selfService.GET("/verify-email", registrationH.VerifyEmailLink)
+			selfService.POST("/verify-email/resend", registrationH.ResendVerification)
+			selfService.POST("/password/forgot", registrationH.ForgotPassword)
+			selfService.POST("/password/reset", registrationH.ResetPassword)
+		}
 
 		// Защищённая группа (требует JWT)
 		protected := api.Group("")
-		protected.Use(middleware.TokenAuthMiddleware(jwks))
+		protected.Use(middleware.TokenAuthMiddleware(identity))
 
 		// Courses
 		courses := protected.Group("/courses")
@@ -80,8 +154,63 @@ func SetupRoutes(db *gorm.DB, r *gin.Engine) {
 			courses.GET("/:course_id", courseH.GetCourse)
 			courses.PUT("/:course_id", middleware.RequireRoles("ROLE_ADMIN"), courseH.UpdateCourse)
 			courses.DELETE("/:course_id", middleware.RequireRoles("ROLE_ADMIN"), courseH.DeleteCourse)
+			courses.PUT("/:course_id/taxonomy", middleware.RequireRoles("ROLE_ADMIN"), taxonomyH.SetCourseTaxonomy)
+
+			teacher := middleware.RequireRoles("ROLE_ADMIN", "ROLE_TEACHER")
+			courses.GET("/:course_id/gradebook", teacher, gradebookH.GetCourseGradebook)
+			courses.GET("/:course_id/gradebook/export", teacher, gradebookH.ExportCourseGradebook)
+			courses.GET("/:course_id/grade-categories", teacher, gradebookH.GetCategories)
+			courses.POST("/:course_id/grade-categories", teacher, gradebookH.CreateCategory)
+			courses.GET("/:course_id/grade-items", teacher, gradebookH.GetItems)
+			courses.POST("/:course_id/grade-items", teacher, gradebookH.CreateItem)
+			courses.GET("/:course_id/completion-rule", teacher, certificateH.GetRule)
+			courses.PUT("/:course_id/completion-rule", teacher, certificateH.SetRule)
+			courses.POST("/:course_id/certificates", teacher, certificateH.IssueCertificate)
+			courses.POST("/:course_id/certificates/claim", certificateH.ClaimCertificate)
+			courses.GET("/:course_id/revisions", teacher, revisionH.GetRevisions(entities.RevisionEntityCourse, "course_id"))
+			courses.GET("/:course_id/revisions/:rev", teacher, revisionH.GetRevision(entities.RevisionEntityCourse, "course_id"))
+			courses.GET("/:course_id/revisions/:rev/diff", teacher, revisionH.Diff(entities.RevisionEntityCourse, "course_id"))
+			courses.POST("/:course_id/revisions/:rev/restore", middleware.RequireRoles("ROLE_ADMIN"), revisionH.Restore(entities.RevisionEntityCourse, "course_id"))
+			courses.GET("/:course_id/versions", teacher, versionH.GetVersions)
+			courses.POST("/:course_id/versions", teacher, versionH.Publish)
+			courses.POST("/:course_id/versions/:version/migrate", teacher, versionH.MigrateLearners)
+			courses.GET("/:course_id/translations", teacher, translationH.GetTranslations(entities.RevisionEntityCourse, "course_id"))
+			courses.GET("/:course_id/translations/missing", teacher, translationH.MissingTranslations)
+			courses.PUT("/:course_id/translations/:locale", middleware.RequireRoles("ROLE_ADMIN"), translationH.SetTranslation(entities.RevisionEntityCourse, "course_id"))
+			courses.DELETE("/:course_id/translations/:locale", middleware.RequireRoles("ROLE_ADMIN"), translationH.DeleteTranslation(entities.RevisionEntityCourse, "course_id"))
 		}
 
+		// Gradebook
+		gradebook := protected.Group("", middleware.RequireRoles("ROLE_ADMIN", "ROLE_TEACHER"))
+		{
+			gradebook.PUT("/grade-categories/:category_id", gradebookH.UpdateCategory)
+			gradebook.DELETE("/grade-categories/:category_id", gradebookH.DeleteCategory)
+			gradebook.PUT("/grade-items/:item_id", gradebookH.UpdateItem)
+			gradebook.DELETE("/grade-items/:item_id", gradebookH.DeleteItem)
+			gradebook.PUT("/grade-items/:item_id/grades/:user_id", gradebookH.OverrideGrade)
+			gradebook.GET("/grade-items/:item_id/grades/:user_id/history", gradebookH.GetGradeHistory)
+		}
+		// Catalog
+		categories := protected.Group("/categories")
+		{
+			categories.GET("", taxonomyH.GetCategories)
+			categories.POST("", middleware.RequireRoles("ROLE_ADMIN"), taxonomyH.CreateCategory)
+			categories.PUT("/:category_id", middleware.RequireRoles("ROLE_ADMIN"), taxonomyH.UpdateCategory)
+			categories.DELETE("/:category_id", middleware.RequireRoles("ROLE_ADMIN"), taxonomyH.DeleteCategory)
+		}
+		tags := protected.Group("/tags")
+		{
+			tags.GET("", taxonomyH.GetTags)
+			tags.POST("", middleware.RequireRoles("ROLE_ADMIN"), taxonomyH.CreateTag)
+			tags.PUT("/:tag_id", middleware.RequireRoles("ROLE_ADMIN"), taxonomyH.UpdateTag)
+			tags.DELETE("/:tag_id", middleware.RequireRoles("ROLE_ADMIN"), taxonomyH.DeleteTag)
+		}
+
+		protected.GET("/search", searchH.Search)
+		protected.GET("/me/grades", gradebookH.GetMyGrades)
+		protected.GET("/me/certificates", certificateH.GetMyCertificates)
+		protected.GET("/certificates/:certificate_id/download", certificateH.DownloadCertificate)
+
 		// Chapters
 		chapters := protected.Group("/chapters")
 		{
@@ -90,6 +219,16 @@ func SetupRoutes(db *gorm.DB, r *gin.Engine) {
 			chapters.GET("/:chapter_id", chapterH.GetChapter)
 			chapters.PUT("/:chapter_id", middleware.RequireRoles("ROLE_ADMIN"), chapterH.UpdateChapterOrder)
 			chapters.DELETE("/:chapter_id", middleware.RequireRoles("ROLE_ADMIN"), chapterH.DeleteChapter)
+			chapters.PUT("/:chapter_id/details", middleware.RequireRoles("ROLE_ADMIN"), chapterH.UpdateChapterDetails)
+
+			teacher := middleware.RequireRoles("ROLE_ADMIN", "ROLE_TEACHER")
+			chapters.GET("/:chapter_id/revisions", teacher, revisionH.GetRevisions(entities.RevisionEntityChapter, "chapter_id"))
+			chapters.GET("/:chapter_id/revisions/:rev", teacher, revisionH.GetRevision(entities.RevisionEntityChapter, "chapter_id"))
+			chapters.GET("/:chapter_id/revisions/:rev/diff", teacher, revisionH.Diff(entities.RevisionEntityChapter, "chapter_id"))
+			chapters.POST("/:chapter_id/revisions/:rev/restore", middleware.RequireRoles("ROLE_ADMIN"), revisionH.Restore(entities.RevisionEntityChapter, "chapter_id"))
+			chapters.GET("/:chapter_id/translations", teacher, translationH.GetTranslations(entities.RevisionEntityChapter, "chapter_id"))
+			chapters.PUT("/:chapter_id/translations/:locale", middleware.RequireRoles("ROLE_ADMIN"), translationH.SetTranslation(entities.RevisionEntityChapter, "chapter_id"))
+			chapters.DELETE("/:chapter_id/translations/:locale", middleware.RequireRoles("ROLE_ADMIN"), translationH.DeleteTranslation(entities.RevisionEntityChapter, "chapter_id"))
 		}
 
 		// Lessons
@@ -100,24 +239,150 @@ func SetupRoutes(db *gorm.DB, r *gin.Engine) {
 			lessons.GET("/:lesson_id", lessonH.GetLesson)
 			lessons.PUT("/:lesson_id", middleware.RequireRoles("ROLE_ADMIN"), lessonH.UpdateLessonContent)
 			lessons.DELETE("/:lesson_id", middleware.RequireRoles("ROLE_ADMIN"), lessonH.DeleteLesson)
+			lessons.PUT("/:lesson_id/taxonomy", middleware.RequireRoles("ROLE_ADMIN"), taxonomyH.SetLessonTaxonomy)
+			lessons.GET("/:lesson_id/blocks", blockH.GetBlocks)
+			lessons.POST("/:lesson_id/blocks", middleware.RequireRoles("ROLE_ADMIN"), blockH.CreateBlock)
+			lessons.PUT("/:lesson_id/blocks/reorder", middleware.RequireRoles("ROLE_ADMIN"), blockH.ReorderBlocks)
+
+			teacher := middleware.RequireRoles("ROLE_ADMIN", "ROLE_TEACHER")
+			lessons.GET("/:lesson_id/revisions", teacher, revisionH.GetRevisions(entities.RevisionEntityLesson, "lesson_id"))
+			lessons.GET("/:lesson_id/revisions/:rev", teacher, revisionH.GetRevision(entities.RevisionEntityLesson, "lesson_id"))
+			lessons.GET("/:lesson_id/revisions/:rev/diff", teacher, revisionH.Diff(entities.RevisionEntityLesson, "lesson_id"))
+			lessons.POST("/:lesson_id/revisions/:rev/restore", middleware.RequireRoles("ROLE_ADMIN"), revisionH.Restore(entities.RevisionEntityLesson, "lesson_id"))
+			lessons.GET("/:lesson_id/translations", teacher, translationH.GetTranslations(entities.RevisionEntityLesson, "lesson_id"))
+			lessons.PUT("/:lesson_id/translations/:locale", middleware.RequireRoles("ROLE_ADMIN"), translationH.SetTranslation(entities.RevisionEntityLesson, "lesson_id"))
+			lessons.DELETE("/:lesson_id/translations/:locale", middleware.RequireRoles("ROLE_ADMIN"), translationH.DeleteTranslation(entities.RevisionEntityLesson, "lesson_id"))
 			lessons.POST("/grant-access", lessonH.GrantLessonAccess)
 		}
 
+		lessonBlocks := protected.Group("/lesson-blocks", middleware.RequireRoles("ROLE_ADMIN"))
+		{
+			lessonBlocks.PUT("/:block_id", blockH.UpdateBlock)
+			lessonBlocks.DELETE("/:block_id", blockH.DeleteBlock)
+			lessonBlocks.PUT("/:block_id/translations/:locale", translationH.SetTranslation(entities.TranslationEntityBlock, "block_id"))
+			lessonBlocks.DELETE("/:block_id/translations/:locale", translationH.DeleteTranslation(entities.TranslationEntityBlock, "block_id"))
+		}
+		protected.GET("/lesson-blocks/:block_id/translations", middleware.RequireRoles("ROLE_ADMIN", "ROLE_TEACHER"), translationH.GetTranslations(entities.TranslationEntityBlock, "block_id"))
+
 		attachments := protected.Group("/attachments")
 		{
 			attachments.POST("/upload", middleware.RequireRoles("ROLE_ADMIN", "ROLE_TEACHER"), attachmentH.UploadFile)
 			attachments.GET("/download/:attachment_id", attachmentH.DownloadFile)
 		}
 
+		// Assignments
+		assignments := protected.Group("/assignments")
+		{
+			assignments.POST("", middleware.RequireRoles("ROLE_ADMIN", "ROLE_TEACHER"), assignmentH.CreateAssignment)
+			assignments.GET("", assignmentH.GetAssignmentsByLesson)
+			assignments.GET("/:assignment_id", assignmentH.GetAssignment)
+			assignments.DELETE("/:assignment_id", middleware.RequireRoles("ROLE_ADMIN", "ROLE_TEACHER"), assignmentH.DeleteAssignment)
+			assignments.POST("/:assignment_id/submissions", assignmentH.Submit)
+			assignments.GET("/:assignment_id/submissions", middleware.RequireRoles("ROLE_ADMIN", "ROLE_TEACHER"), assignmentH.GetSubmissions)
+			assignments.GET("/:assignment_id/submissions/me", assignmentH.GetMySubmission)
+		}
+
+		submissions := protected.Group("/submissions", middleware.RequireRoles("ROLE_ADMIN", "ROLE_TEACHER"))
+		{
+			submissions.GET("/:submission_id/download", assignmentH.DownloadSubmission)
+			submissions.PUT("/:submission_id/grade", assignmentH.GradeSubmission)
+		}
+
 		admin := protected.Group("/admin", middleware.RequireRoles("ROLE_ADMIN"))
 		{
-			admin.POST("/update-roles", handler.UpdateUserRolesHandler)
-			admin.POST("register", handler.RegisterUser)
+			admin.POST("/update-roles", userH.UpdateUserRoles)
+			admin.POST("register", userH.RegisterUser)
+			admin.GET("/users", userH.ListUsers)
+			admin.GET("/users/:user_id", userH.GetUser)
+			admin.PUT("/users/:user_id/enabled", userH.SetUserEnabled)
+			admin.DELETE("/users/:user_id", userH.DeleteUser)
+			admin.DELETE("/users/:user_id/sessions", authH.RevokeUserSessions)
+			if userImportH != nil {
+				admin.POST("/user-imports", userImportH.StartImport)
+				admin.GET("/user-imports/:job_id", userImportH.GetImport)
+			}
 		}
 		protected.PUT("/chapters/:chapter_id/lessons/reorder", middleware.RequireRoles("ROLE_ADMIN"), lessonH.ReorderLessons)
 
-		//protected.POST("/user/register", middleware.RequireRoles("ROLE_ADMIN"), handler.RegisterUser)
-		protected.PUT("/user/profile", handler.UpdateUserProfile)
+		//protected.POST("/user/register", middleware.RequireRoles("ROLE_ADMIN"), userH.RegisterUser)
+		protected.PUT("/user/profile", userH.UpdateUserProfile)
+		protected.GET("/me/sessions", authH.ListMySessions)
+		protected.DELETE("/me/sessions/:session_id", authH.RevokeMySession)
 
 	}
+
+	return checker
+}
+
+// newMailer выбирает способ отправки писем по MAILER; nil — почта не настроена.
+func newMailer() mailer.Mailer {
+	switch config.GetMailer() {
+	case "":
+		return nil
+	case "smtp":
+		smtpMailer, err := mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     config.GetSMTPHost(),
+			Port:     config.GetSMTPPort(),
+			Username: config.GetSMTPUsername(),
+			Password: config.GetSMTPPassword(),
+			From:     config.GetMailFrom(),
+			TLS:      config.GetSMTPTLS(),
+		})
+		if err != nil {
+			pkg.Logger.WithError(err).Fatal("Invalid SMTP configuration")
+		}
+		return smtpMailer
+	case "file":
+		fileMailer, err := mailer.NewFileMailer(config.GetMailerDir(), config.GetMailFrom())
+		if err != nil {
+			pkg.Logger.WithError(err).Fatal("Failed to init file mailer")
+		}
+		pkg.Logger.WithField("dir", config.GetMailerDir()).Warn("Emails are written to files instead of being sent, not for production")
+		return fileMailer
+	case "memory":
+		pkg.Logger.Warn("Emails are kept in memory instead of being sent, not for production")
+		return mailer.NewMemoryMailer()
+	default:
+		pkg.Logger.WithField("mailer", config.GetMailer()).Fatal("Unknown MAILER, expected smtp, file or memory")
+		return nil
+	}
+}
+
+// newIdentityProvider выбирает провайдер идентификации по AUTH_PROVIDER.
+func newIdentityProvider(ctx context.Context, admin *service.KeycloakAdmin) service.IdentityProvider {
+	switch config.GetAuthProvider() {
+	case "keycloak":
+		return service.NewKeycloakIdentityProvider(ctx, service.KeycloakConfig{
+			RealmURL:            config.GetKeycloakRealmURL(),
+			ClientID:            config.GetKeycloakClientID(),
+			ClientSecret:        config.GetKeycloakClientSecret(),
+			Admin:               admin,
+			KeysRefreshInterval: time.Hour,
+			Validation: service.TokenValidation{
+				Issuer:     config.GetTokenIssuer(),
+				Audiences:  config.GetTokenAudiences(),
+				Algorithms: config.GetTokenAlgorithms(),
+				ClockSkew:  config.GetTokenClockSkew(),
+			},
+			IntrospectionCacheTTL: config.GetTokenIntrospectionCacheTTL(),
+		})
+	case "local":
+		users, err := service.ParseLocalUsers(config.GetLocalAuthUsers())
+		if err != nil {
+			pkg.Logger.WithError(err).Fatal("Invalid LOCAL_AUTH_USERS")
+		}
+		secret := []byte(config.GetLocalAuthSecret())
+		if len(secret) == 0 {
+			secret = make([]byte, 32)
+			if _, err := rand.Read(secret); err != nil {
+				pkg.Logger.WithError(err).Fatal("Failed to generate local auth secret")
+			}
+			pkg.Logger.Warn("LOCAL_AUTH_SECRET is not set, tokens will not survive a restart")
+		}
+		pkg.Logger.WithField("users", len(users)).Warn("Using the built-in local identity provider, not for production")
+		return service.NewLocalIdentityProvider(secret, config.GetLocalAuthAccessTTL(), config.GetLocalAuthRefreshTTL(), users)
+	default:
+		pkg.Logger.WithField("provider", config.GetAuthProvider()).Fatal("Unknown AUTH_PROVIDER, expected keycloak or local")
+		return nil
+	}
 }
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"lms-system-internship/entities"
+	"lms-system-internship/files"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type AssignmentService interface {
+	CreateAssignment(ctx context.Context, assignment *entities.Assignment) error
+	GetAssignment(ctx context.Context, assignmentID uint) (*entities.Assignment, error)
+	GetAssignmentsByLesson(ctx context.Context, lessonID uint) ([]*entities.Assignment, error)
+	DeleteAssignment(ctx context.Context, assignmentID uint) error
+	Submit(ctx context.Context, userID uuid.UUID, assignmentID uint, fileName string, fileBytes []byte) (*entities.Submission, error)
+	GetSubmissions(ctx context.Context, assignmentID uint) ([]*entities.Submission, error)
+	GetMySubmission(ctx context.Context, userID uuid.UUID, assignmentID uint) (*entities.Submission, error)
+	DownloadSubmission(ctx context.Context, submissionID uint) ([]byte, string, error)
+	GradeSubmission(ctx context.Context, graderID uuid.UUID, submissionID uint, score int, feedback string) (*entities.Submission, error)
+}
+
+type assignmentService struct {
+	repo           repo.AssignmentRepository
+	submissionRepo repo.SubmissionRepository
+	lessonRepo     repo.LessonRepository
+	lessonUserRepo repo.LessonUserRepository
+	fileStorage    files.FileStorage
+	gradebook      GradebookService
+	now            func() time.Time
+}
+
+func NewAssignmentService(repo repo.AssignmentRepository, submissionRepo repo.SubmissionRepository, lessonRepo repo.LessonRepository, lessonUserRepo repo.LessonUserRepository, fileStorage files.FileStorage, gradebook GradebookService) AssignmentService {
+	return &assignmentService{
+		repo:           repo,
+		submissionRepo: submissionRepo,
+		lessonRepo:     lessonRepo,
+		lessonUserRepo: lessonUserRepo,
+		fileStorage:    fileStorage,
+		gradebook:      gradebook,
+		now:            time.Now,
+	}
+}
+
+func (s *assignmentService) CreateAssignment(ctx context.Context, assignment *entities.Assignment) error {
+	if strings.TrimSpace(assignment.Title) == "" || assignment.MaxScore <= 0 || assignment.DueDate.IsZero() {
+		return pkg.ErrInvalidInput
+	}
+	if _, err := s.lessonRepo.FindByID(ctx, assignment.LessonID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return pkg.ErrLessonNotFound
+		}
+		return err
+	}
+	return s.repo.Save(ctx, assignment)
+}
+
+func (s *assignmentService) GetAssignment(ctx context.Context, assignmentID uint) (*entities.Assignment, error) {
+	assignment, err := s.repo.FindByID(ctx, assignmentID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkg.ErrAssignmentNotFound
+	}
+	return assignment, err
+}
+
+func (s *assignmentService) GetAssignmentsByLesson(ctx context.Context, lessonID uint) ([]*entities.Assignment, error) {
+	return s.repo.FindByLessonID(ctx, lessonID)
+}
+
+func (s *assignmentService) DeleteAssignment(ctx context.Context, assignmentID uint) error {
+	err := s.repo.Delete(ctx, assignmentID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkg.ErrAssignmentNotFound
+	}
+	return err
+}
+
+// Submit сохраняет работу студента. Пока срок не истёк, работу можно пересдавать —
+// предыдущий файл и оценка заменяются. После срока принимается только первая сдача,
+// и она помечается как просроченная.
+func (s *assignmentService) Submit(ctx context.Context, userID uuid.UUID, assignmentID uint, fileName string, fileBytes []byte) (*entities.Submission, error) {
+	assignment, err := s.GetAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasAccess, err := s.lessonUserRepo.HasAccess(userID, assignment.LessonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check lesson access: %w", err)
+	}
+	if !hasAccess {
+		return nil, pkg.ErrForbidden
+	}
+
+	if !isFileTypeAllowed(assignment.AllowedTypes, fileName) {
+		return nil, pkg.ErrFileTypeNotAllowed
+	}
+
+	existing, err := s.submissionRepo.FindByAssignmentAndUser(ctx, assignmentID, userID)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return nil, err
+	}
+
+	now := s.now()
+	late := now.After(assignment.DueDate)
+	if existing != nil && late {
+		return nil, pkg.ErrDeadlinePassed
+	}
+
+	safeName := "submissions/" + uuid.New().String() + filepath.Ext(fileName)
+	if _, err := s.fileStorage.UploadFile(ctx, safeName, fileBytes); err != nil {
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	if existing != nil {
+		previousFile := existing.FileURL
+		existing.FileName = fileName
+		existing.FileURL = safeName
+		existing.SubmittedAt = now
+		existing.IsLate = false
+		existing.Score = nil
+		existing.Feedback = ""
+		existing.GradedBy = nil
+		existing.GradedAt = nil
+		if err := s.submissionRepo.Update(ctx, existing); err != nil {
+			return nil, fmt.Errorf("failed to save submission: %w", err)
+		}
+		// Предыдущий файл больше ни на что не ссылается; если удалить не удалось, сдача всё равно принята
+		if previousFile != "" {
+			if err := s.fileStorage.DeleteFile(ctx, previousFile); err != nil {
+				pkg.LoggerFromContext(ctx).WithError(err).WithField("file_url", previousFile).Warn("Failed to delete replaced submission file")
+			}
+		}
+		return existing, nil
+	}
+
+	submission := &entities.Submission{
+		AssignmentID: assignmentID,
+		UserID:       userID,
+		FileName:     fileName,
+		FileURL:      safeName,
+		SubmittedAt:  now,
+		IsLate:       late,
+	}
+	if err := s.submissionRepo.Save(ctx, submission); err != nil {
+		return nil, fmt.Errorf("failed to save submission: %w", err)
+	}
+	return submission, nil
+}
+
+func (s *assignmentService) GetSubmissions(ctx context.Context, assignmentID uint) ([]*entities.Submission, error) {
+	if _, err := s.GetAssignment(ctx, assignmentID); err != nil {
+		return nil, err
+	}
+	return s.submissionRepo.FindByAssignmentID(ctx, assignmentID)
+}
+
+func (s *assignmentService) GetMySubmission(ctx context.Context, userID uuid.UUID, assignmentID uint) (*entities.Submission, error) {
+	submission, err := s.submissionRepo.FindByAssignmentAndUser(ctx, assignmentID, userID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkg.ErrSubmissionNotFound
+	}
+	return submission, err
+}
+
+func (s *assignmentService) DownloadSubmission(ctx context.Context, submissionID uint) ([]byte, string, error) {
+	submission, err := s.getSubmission(ctx, submissionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := s.fileStorage.DownloadFile(ctx, submission.FileURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download from storage: %w", err)
+	}
+	return data, submission.FileName, nil
+}
+
+func (s *assignmentService) GradeSubmission(ctx context.Context, graderID uuid.UUID, submissionID uint, score int, feedback string) (*entities.Submission, error) {
+	submission, err := s.getSubmission(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.GetAssignment(ctx, submission.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if score < 0 || score > assignment.MaxScore {
+		return nil, pkg.ErrInvalidInput
+	}
+
+	gradedAt := s.now()
+	submission.Score = &score
+	submission.Feedback = feedback
+	submission.GradedBy = &graderID
+	submission.GradedAt = &gradedAt
+	if err := s.submissionRepo.Update(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	// Оценка уже сохранена в работе, поэтому сбой журнала только логируем
+	err = s.gradebook.PostAssignmentScore(ctx, assignment.ID, submission.UserID, float64(score), float64(assignment.MaxScore))
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithError(err).WithField("submission_id", submission.ID).Warn("Failed to post score to gradebook")
+	}
+	return submission, nil
+}
+
+func (s *assignmentService) getSubmission(ctx context.Context, submissionID uint) (*entities.Submission, error) {
+	submission, err := s.submissionRepo.FindByID(ctx, submissionID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkg.ErrSubmissionNotFound
+	}
+	return submission, err
+}
+
+// isFileTypeAllowed проверяет расширение файла по списку вида ".pdf,.zip".
+// Пустой список разрешает любые файлы.
+func isFileTypeAllowed(allowedTypes, fileName string) bool {
+	if strings.TrimSpace(allowedTypes) == "" {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, t := range strings.Split(allowedTypes, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if !strings.HasPrefix(t, ".") {
+			t = "." + t
+		}
+		if t == ext {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// stubGradebook запоминает оценки, переданные в журнал.
+type stubGradebook struct {
+	GradebookService
+	posted []float64
+	err    error
+}
+
+func (g *stubGradebook) PostAssignmentScore(_ context.Context, _ uint, _ uuid.UUID, score, _ float64) error {
+	g.posted = append(g.posted, score)
+	return g.err
+}
+
+func TestAssignmentService_CreateAssignment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		assignments := new(mocks.AssignmentRepository)
+		lessons := new(mocks.LessonRepository)
+		assignment := &entities.Assignment{Title: "Essay", LessonID: 1, MaxScore: 10, DueDate: time.Now().Add(time.Hour)}
+
+		lessons.On("FindByID", mock.Anything, uint(1)).Return(&entities.Lesson{ID: 1}, nil)
+		assignments.On("Save", mock.Anything, assignment).Return(nil)
+
+		service := NewAssignmentService(assignments, nil, lessons, nil, nil, &stubGradebook{})
+		err := service.CreateAssignment(context.Background(), assignment)
+
+		assert.NoError(t, err)
+		assignments.AssertExpectations(t)
+	})
+
+	t.Run("lesson not found", func(t *testing.T) {
+		assignments := new(mocks.AssignmentRepository)
+		lessons := new(mocks.LessonRepository)
+		assignment := &entities.Assignment{Title: "Essay", LessonID: 99, MaxScore: 10, DueDate: time.Now()}
+
+		lessons.On("FindByID", mock.Anything, uint(99)).Return(nil, repo.ErrNotFound)
+
+		service := NewAssignmentService(assignments, nil, lessons, nil, nil, &stubGradebook{})
+		err := service.CreateAssignment(context.Background(), assignment)
+
+		assert.ErrorIs(t, err, pkg.ErrLessonNotFound)
+		assignments.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid max score", func(t *testing.T) {
+		assignment := &entities.Assignment{Title: "Essay", LessonID: 1, DueDate: time.Now()}
+
+		service := NewAssignmentService(new(mocks.AssignmentRepository), nil, new(mocks.LessonRepository), nil, nil, &stubGradebook{})
+		err := service.CreateAssignment(context.Background(), assignment)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+}
+
+func TestAssignmentService_Submit(t *testing.T) {
+	userID := uuid.New()
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	open := &entities.Assignment{ID: 1, LessonID: 5, MaxScore: 10, DueDate: now.Add(time.Hour), AllowedTypes: ".pdf, .zip"}
+	closed := &entities.Assignment{ID: 2, LessonID: 5, MaxScore: 10, DueDate: now.Add(-time.Hour)}
+
+	t.Run("first submission before deadline", func(t *testing.T) {
+		assignments := new(mocks.AssignmentRepository)
+		submissions := new(mocks.SubmissionRepository)
+		lessonUsers := new(mocks.LessonUserRepository)
+		storage := new(mocks.FileStorage)
+		assignments.On("FindByID", mock.Anything, uint(1)).Return(open, nil)
+		lessonUsers.On("HasAccess", userID, uint(5)).Return(true, nil)
+		submissions.On("FindByAssignmentAndUser", mock.Anything, uint(1), userID).Return(nil, repo.ErrNotFound)
+		storage.On("UploadFile", mock.Anything, mock.Anything, []byte("data")).Return("url", nil)
+		submissions.On("Save", mock.Anything, mock.AnythingOfType("*entities.Submission")).Return(nil)
+
+		service := NewAssignmentService(assignments, submissions, nil, lessonUsers, storage, &stubGradebook{}).(*assignmentService)
+		service.now = func() time.Time { return now }
+		result, err := service.Submit(context.Background(), userID, 1, "work.PDF", []byte("data"))
+
+		assert.NoError(t, err)
+		assert.False(t, result.IsLate)
+		assert.Equal(t, "work.PDF", result.FileName)
+		assert.Equal(t, now, result.SubmittedAt)
+		submissions.AssertExpectations(t)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("resubmission resets grade and deletes the old file", func(t *testing.T) {
+		assignments := new(mocks.AssignmentRepository)
+		submissions := new(mocks.SubmissionRepository)
+		lessonUsers := new(mocks.LessonUserRepository)
+		storage := new(mocks.FileStorage)
+		score := 7
+		existing := &entities.Submission{ID: 3, AssignmentID: 1, UserID: userID, FileName: "old.pdf", FileURL: "submissions/old.pdf", Score: &score, Feedback: "ok"}
+		assignments.On("FindByID", mock.Anything, uint(1)).Return(open, nil)
+		lessonUsers.On("HasAccess", userID, uint(5)).Return(true, nil)
+		submissions.On("FindByAssignmentAndUser", mock.Anything, uint(1), userID).Return(existing, nil)
+		storage.On("UploadFile", mock.Anything, mock.Anything, mock.Anything).Return("url", nil)
+		submissions.On("Update", mock.Anything, existing).Return(nil)
+		storage.On("DeleteFile", mock.Anything, "submissions/old.pdf").Return(errors.New("minio down"))
+
+		service := NewAssignmentService(assignments, submissions, nil, lessonUsers, storage, &stubGradebook{}).(*assignmentService)
+		service.now = func() time.Time { return now }
+		result, err := service.Submit(context.Background(), userID, 1, "new.zip", []byte("data"))
+
+		assert.NoError(t, err, "a file left behind does not fail the submission")
+		assert.Equal(t, "new.zip", result.FileName)
+		assert.NotEqual(t, "submissions/old.pdf", result.FileURL)
+		assert.Nil(t, result.Score)
+		assert.Empty(t, result.Feedback)
+		submissions.AssertExpectations(t)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("late first submission is flagged", func(t *testing.T) {
+		assignments := new(mocks.AssignmentRepository)
+		submissions := new(mocks.SubmissionRepository)
+		lessonUsers := new(mocks.LessonUserRepository)
+		storage := new(mocks.FileStorage)
+		assignments.On("FindByID", mock.Anything, uint(2)).Return(closed, nil)
+		lessonUsers.On("HasAccess", userID, uint(5)).Return(true, nil)
+		submissions.On("FindByAssignmentAndUser", mock.Anything, uint(2), userID).Return(nil, repo.ErrNotFound)
+		storage.On("UploadFile", mock.Anything, mock.Anything, mock.Anything).Return("url", nil)
+		submissions.On("Save", mock.Anything, mock.AnythingOfType("*entities.Submission")).Return(nil)
+
+		service := NewAssignmentService(assignments, submissions, nil, lessonUsers, storage, &stubGradebook{}).(*assignmentService)
+		service.now = func() time.Time { return now }
+		result, err := service.Submit(context.Background(), userID, 2, "work.txt", []byte("data"))
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsLate)
+	})
+
+	t.Run("resubmission after deadline", func(t *testing.T) {
+		assignments := new(mocks.AssignmentRepository)
+		submissions := new(mocks.SubmissionRepository)
+		lessonUsers := new(mocks.LessonUserRepository)
+		storage := new(mocks.FileStorage)
+		assignments.On("FindByID", mock.Anything, uint(2)).Return(closed, nil)
+		lessonUsers.On("HasAccess", userID, uint(5)).Return(true, nil)
+		submissions.On("FindByAssignmentAndUser", mock.Anything, uint(2), userID).Return(&entities.Submission{ID: 4}, nil)
+
+		service := NewAssignmentService(assignments, submissions, nil, lessonUsers, storage, &stubGradebook{}).(*assignmentService)
+		service.now = func() time.Time { return now }
+		result, err := service.Submit(context.Background(), userID, 2, "work.txt", []byte("data"))
+
+		assert.ErrorIs(t, err, pkg.ErrDeadlinePassed)
+		assert.Nil(t, result)
+		storage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("file type not allowed", func(t *testing.T) {
+		assignments := new(mocks.AssignmentRepository)
+		lessonUsers := new(mocks.LessonUserRepository)
+		assignments.On("FindByID", mock.Anything, uint(1)).Return(open, nil)
+		lessonUsers.On("HasAccess", userID, uint(5)).Return(true, nil)
+
+		service := NewAssignmentService(assignments, nil, nil, lessonUsers, nil, &stubGradebook{})
+		_, err := service.Submit(context.Background(), userID, 1, "virus.exe", []byte("data"))
+
+		assert.ErrorIs(t, err, pkg.ErrFileTypeNotAllowed)
+	})
+
+	t.Run("no lesson access", func(t *testing.T) {
+		assignments := new(mocks.AssignmentRepository)
+		lessonUsers := new(mocks.LessonUserRepository)
+		assignments.On("FindByID", mock.Anything, uint(1)).Return(open, nil)
+		lessonUsers.On("HasAccess", userID, uint(5)).Return(false, nil)
+
+		service := NewAssignmentService(assignments, nil, nil, lessonUsers, nil, &stubGradebook{})
+		_, err := service.Submit(context.Background(), userID, 1, "work.pdf", []byte("data"))
+
+		assert.ErrorIs(t, err, pkg.ErrForbidden)
+	})
+}
+
+func TestAssignmentService_GradeSubmission(t *testing.T) {
+	graderID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		assignments := new(mocks.AssignmentRepository)
+		submissions := new(mocks.SubmissionRepository)
+		submission := &entities.Submission{ID: 1, AssignmentID: 2}
+		submissions.On("FindByID", mock.Anything, uint(1)).Return(submission, nil)
+		assignments.On("FindByID", mock.Anything, uint(2)).Return(&entities.Assignment{ID: 2, MaxScore: 10}, nil)
+		submissions.On("Update", mock.Anything, submission).Return(nil)
+
+		gradebook := &stubGradebook{}
+		service := NewAssignmentService(assignments, submissions, nil, nil, nil, gradebook)
+		result, err := service.GradeSubmission(context.Background(), graderID, 1, 8, "good")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 8, *result.Score)
+		assert.Equal(t, "good", result.Feedback)
+		assert.Equal(t, graderID, *result.GradedBy)
+		assert.NotNil(t, result.GradedAt)
+		assert.Equal(t, []float64{8}, gradebook.posted)
+	})
+
+	t.Run("gradebook failure keeps the grade", func(t *testing.T) {
+		assignments := new(mocks.AssignmentRepository)
+		submissions := new(mocks.SubmissionRepository)
+		submission := &entities.Submission{ID: 1, AssignmentID: 2}
+		submissions.On("FindByID", mock.Anything, uint(1)).Return(submission, nil)
+		assignments.On("FindByID", mock.Anything, uint(2)).Return(&entities.Assignment{ID: 2, MaxScore: 10}, nil)
+		submissions.On("Update", mock.Anything, submission).Return(nil)
+
+		service := NewAssignmentService(assignments, submissions, nil, nil, nil, &stubGradebook{err: errors.New("db down")})
+		result, err := service.GradeSubmission(context.Background(), graderID, 1, 8, "good")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 8, *result.Score)
+	})
+
+	t.Run("score above max", func(t *testing.T) {
+		assignments := new(mocks.AssignmentRepository)
+		submissions := new(mocks.SubmissionRepository)
+		submissions.On("FindByID", mock.Anything, uint(1)).Return(&entities.Submission{ID: 1, AssignmentID: 2}, nil)
+		assignments.On("FindByID", mock.Anything, uint(2)).Return(&entities.Assignment{ID: 2, MaxScore: 10}, nil)
+
+		service := NewAssignmentService(assignments, submissions, nil, nil, nil, &stubGradebook{})
+		_, err := service.GradeSubmission(context.Background(), graderID, 1, 11, "")
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+		submissions.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("submission not found", func(t *testing.T) {
+		submissions := new(mocks.SubmissionRepository)
+		submissions.On("FindByID", mock.Anything, uint(1)).Return(nil, repo.ErrNotFound)
+
+		service := NewAssignmentService(nil, submissions, nil, nil, nil, &stubGradebook{})
+		_, err := service.GradeSubmission(context.Background(), graderID, 1, 5, "")
+
+		assert.ErrorIs(t, err, pkg.ErrSubmissionNotFound)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"lms-system-internship/entities"
 	"lms-system-internship/files"
+	"lms-system-internship/pkg"
 	"lms-system-internship/repo"
 	"path/filepath"
 )
@@ -13,6 +14,8 @@ import (
 type AttachmentService interface {
 	UploadFile(ctx context.Context, lessonID uint, fileName string, fileBytes []byte) (*entities.Attachment, error)
 	DownloadFile(ctx context.Context, userID uuid.UUID, attachmentID uint) ([]byte, string, error)
+	// DownloadAttachment отдаёт файл уже найденного вложения, например из опубликованной версии курса.
+	DownloadAttachment(ctx context.Context, userID uuid.UUID, attachment *entities.Attachment) ([]byte, string, error)
 	GetAttachmentsByLesson(ctx context.Context, lessonID uint) ([]*entities.Attachment, error)
 	//GrantAccess(ctx context.Context, userID uuid.UUID, lessonID uint) error
 }
@@ -36,7 +39,7 @@ func NewAttachmentService(repo repo.AttachmentRepository, lessonRepo repo.Lesson
 func (s *attachmentService) UploadFile(ctx context.Context, lessonID uint, fileName string, fileBytes []byte) (*entities.Attachment, error) {
 	_, err := s.lessonRepo.FindByID(ctx, lessonID)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, pkg.ErrLessonNotFound)
 	}
 
 	ext := filepath.Ext(fileName)
@@ -63,16 +66,19 @@ func (s *attachmentService) DownloadFile(ctx context.Context, userID uuid.UUID,
 	// Получаем attachment
 	attachment, err := s.repo.FindByID(ctx, attachmentID)
 	if err != nil {
-		return nil, "", err
+		return nil, "", notFound(err, pkg.ErrAttachmentNotFound)
 	}
+	return s.DownloadAttachment(ctx, userID, attachment)
+}
 
+func (s *attachmentService) DownloadAttachment(ctx context.Context, userID uuid.UUID, attachment *entities.Attachment) ([]byte, string, error) {
 	// Проверка доступа пользователя к уроку
 	hasAccess, err := s.lessonUserRepo.HasAccess(userID, attachment.LessonID)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to check lesson access: %w", err)
 	}
 	if !hasAccess {
-		return nil, "", fmt.Errorf("access denied: no permission to download this lesson's file")
+		return nil, "", pkg.ErrForbidden
 	}
 
 	// Скачиваем файл
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAttachmentService_DownloadFile(t *testing.T) {
+	t.Run("attachment not found", func(t *testing.T) {
+		attachments := new(mocks.AttachmentRepository)
+		storage := new(mocks.FileStorage)
+		attachments.On("FindByID", mock.Anything, uint(3)).Return(nil, repo.ErrNotFound)
+		service := NewAttachmentService(attachments, new(mocks.LessonRepository), new(mocks.LessonUserRepository), storage)
+
+		_, _, err := service.DownloadFile(context.Background(), uuid.New(), 3)
+
+		assert.ErrorIs(t, err, pkg.ErrAttachmentNotFound)
+		storage.AssertNotCalled(t, "DownloadFile", mock.Anything, mock.Anything)
+	})
+}
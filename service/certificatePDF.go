@@ -0,0 +1,77 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// CertificateData — поля, доступные в шаблоне текста сертификата.
+type CertificateData struct {
+	StudentName string
+	CourseName  string
+	Date        string
+	Code        string
+}
+
+type CertificateRenderer interface {
+	Render(data CertificateData) ([]byte, error)
+}
+
+type pdfCertificateRenderer struct {
+	title    string
+	body     *template.Template
+	fontPath string
+}
+
+// NewPDFCertificateRenderer создаёт рендерер PDF. bodyTemplate — text/template с полями CertificateData.
+// Без fontPath используется встроенный Helvetica, который не умеет кириллицу,
+// поэтому для русских имён нужно указать путь к TTF-шрифту.
+func NewPDFCertificateRenderer(title, bodyTemplate, fontPath string) (CertificateRenderer, error) {
+	body, err := template.New("certificate").Parse(bodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate template: %w", err)
+	}
+	return &pdfCertificateRenderer{title: title, body: body, fontPath: fontPath}, nil
+}
+
+func (r *pdfCertificateRenderer) Render(data CertificateData) ([]byte, error) {
+	var text bytes.Buffer
+	if err := r.body.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to execute certificate template: %w", err)
+	}
+
+	pdf := fpdf.New("L", "mm", "A4", "")
+	family := "Helvetica"
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	if r.fontPath != "" {
+		family = "certificate"
+		pdf.AddUTF8Font(family, "", r.fontPath)
+		translate = func(s string) string { return s }
+	}
+	pdf.SetTitle(r.title, true)
+	pdf.AddPage()
+
+	pdf.SetLineWidth(1.5)
+	pdf.Rect(10, 10, 277, 190, "D")
+
+	pdf.SetFont(family, "", 32)
+	pdf.SetY(45)
+	pdf.CellFormat(0, 15, translate(r.title), "", 1, "C", false, 0, "")
+
+	pdf.SetFont(family, "", 16)
+	pdf.SetXY(35, 85)
+	pdf.MultiCell(227, 9, translate(text.String()), "", "C", false)
+
+	pdf.SetFont(family, "", 10)
+	pdf.SetY(180)
+	pdf.CellFormat(0, 6, translate("Verification code: "+data.Code), "", 1, "C", false, 0, "")
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, fmt.Errorf("failed to render certificate: %w", err)
+	}
+	return out.Bytes(), nil
+}
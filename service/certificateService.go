@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"lms-system-internship/entities"
+	"lms-system-internship/files"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"time"
+)
+
+type CertificateService interface {
+	GetRule(ctx context.Context, courseID uint) (*entities.CompletionRule, error)
+	SetRule(ctx context.Context, rule *entities.CompletionRule) error
+	IssueCertificate(ctx context.Context, issuedBy uuid.UUID, courseID uint, userID uuid.UUID) (*entities.Certificate, error)
+	ClaimCertificate(ctx context.Context, userID uuid.UUID, courseID uint) (*entities.Certificate, error)
+	GetUserCertificates(ctx context.Context, userID uuid.UUID) ([]*entities.Certificate, error)
+	DownloadCertificate(ctx context.Context, userID uuid.UUID, certificateID uint, privileged bool) ([]byte, string, error)
+	VerifyCertificate(ctx context.Context, code string) (*entities.Certificate, error)
+}
+
+type certificateService struct {
+	repo           repo.CertificateRepository
+	courseRepo     repo.CourseRepository
+	lessonUserRepo repo.LessonUserRepository
+	fileStorage    files.FileStorage
+	users          UserDirectory
+	renderer       CertificateRenderer
+	now            func() time.Time
+}
+
+func NewCertificateService(repo repo.CertificateRepository, courseRepo repo.CourseRepository, lessonUserRepo repo.LessonUserRepository, fileStorage files.FileStorage, users UserDirectory, renderer CertificateRenderer) CertificateService {
+	return &certificateService{
+		repo:           repo,
+		courseRepo:     courseRepo,
+		lessonUserRepo: lessonUserRepo,
+		fileStorage:    fileStorage,
+		users:          users,
+		renderer:       renderer,
+		now:            time.Now,
+	}
+}
+
+// GetRule возвращает правило курса; если оно не настроено — выключенное правило по умолчанию.
+func (s *certificateService) GetRule(ctx context.Context, courseID uint) (*entities.CompletionRule, error) {
+	rule, err := s.repo.FindRule(ctx, courseID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return &entities.CompletionRule{CourseID: courseID, RequireAllLessons: true}, nil
+	}
+	return rule, err
+}
+
+func (s *certificateService) SetRule(ctx context.Context, rule *entities.CompletionRule) error {
+	if _, err := s.getCourse(ctx, rule.CourseID); err != nil {
+		return err
+	}
+	return s.repo.SaveRule(ctx, rule)
+}
+
+// IssueCertificate выдаёт сертификат вручную, без проверки правила. Повторная выдача возвращает уже существующий.
+func (s *certificateService) IssueCertificate(ctx context.Context, issuedBy uuid.UUID, courseID uint, userID uuid.UUID) (*entities.Certificate, error) {
+	course, err := s.getCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, course, userID, &issuedBy)
+}
+
+// ClaimCertificate выдаёт сертификат студенту, если выполнено правило завершения курса.
+func (s *certificateService) ClaimCertificate(ctx context.Context, userID uuid.UUID, courseID uint) (*entities.Certificate, error) {
+	course, err := s.getCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.GetRule(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	completed, err := s.isCompleted(course, rule, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !completed {
+		return nil, pkg.ErrCourseNotCompleted
+	}
+	return s.issue(ctx, course, userID, nil)
+}
+
+func (s *certificateService) GetUserCertificates(ctx context.Context, userID uuid.UUID) ([]*entities.Certificate, error) {
+	return s.repo.FindByUserID(ctx, userID)
+}
+
+func (s *certificateService) DownloadCertificate(ctx context.Context, userID uuid.UUID, certificateID uint, privileged bool) ([]byte, string, error) {
+	certificate, err := s.repo.FindByID(ctx, certificateID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, "", pkg.ErrCertificateNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if !privileged && certificate.UserID != userID {
+		return nil, "", pkg.ErrForbidden
+	}
+
+	data, err := s.fileStorage.DownloadFile(ctx, certificate.FileURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download from storage: %w", err)
+	}
+	return data, "certificate-" + certificate.Code + ".pdf", nil
+}
+
+func (s *certificateService) VerifyCertificate(ctx context.Context, code string) (*entities.Certificate, error) {
+	certificate, err := s.repo.FindByCode(ctx, code)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkg.ErrCertificateNotFound
+	}
+	return certificate, err
+}
+
+func (s *certificateService) issue(ctx context.Context, course *entities.Course, userID uuid.UUID, issuedBy *uuid.UUID) (*entities.Certificate, error) {
+	existing, err := s.repo.FindByCourseAndUser(ctx, course.ID, userID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, repo.ErrNotFound) {
+		return nil, err
+	}
+
+	studentName, err := s.users.GetFullName(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve student name: %w", err)
+	}
+	code, err := newVerificationCode()
+	if err != nil {
+		return nil, err
+	}
+
+	issuedAt := s.now()
+	pdf, err := s.renderer.Render(CertificateData{
+		StudentName: studentName,
+		CourseName:  course.Name,
+		Date:        issuedAt.Format("02.01.2006"),
+		Code:        code,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	objectName := "certificates/" + code + ".pdf"
+	if _, err := s.fileStorage.UploadFile(ctx, objectName, pdf); err != nil {
+		return nil, fmt.Errorf("failed to upload certificate: %w", err)
+	}
+
+	certificate := &entities.Certificate{
+		CourseID:    course.ID,
+		UserID:      userID,
+		Code:        code,
+		StudentName: studentName,
+		CourseName:  course.Name,
+		FileURL:     objectName,
+		IssuedBy:    issuedBy,
+		IssuedAt:    issuedAt,
+	}
+	if err := s.repo.Save(ctx, certificate); err != nil {
+		return nil, fmt.Errorf("failed to save certificate: %w", err)
+	}
+	return certificate, nil
+}
+
+func (s *certificateService) isCompleted(course *entities.Course, rule *entities.CompletionRule, userID uuid.UUID) (bool, error) {
+	if !rule.Enabled {
+		return false, nil
+	}
+	if rule.NotBefore != nil && s.now().Before(*rule.NotBefore) {
+		return false, nil
+	}
+	if rule.RequireAllLessons {
+		// «Все уроки» пустого курса пройдены формально; сертификат за такой курс не выдаём
+		lessons := 0
+		for _, chapter := range course.Chapters {
+			lessons += len(chapter.Lessons)
+		}
+		if lessons == 0 {
+			return false, nil
+		}
+		for _, chapter := range course.Chapters {
+			for _, lesson := range chapter.Lessons {
+				hasAccess, err := s.lessonUserRepo.HasAccess(userID, lesson.ID)
+				if err != nil {
+					return false, fmt.Errorf("failed to check lesson access: %w", err)
+				}
+				if !hasAccess {
+					return false, nil
+				}
+			}
+		}
+	}
+	return true, nil
+}
+
+func (s *certificateService) getCourse(ctx context.Context, courseID uint) (*entities.Course, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkg.ErrCourseNotFound
+	}
+	return course, err
+}
+
+// newVerificationCode генерирует случайный 16-символьный код в base32.
+func newVerificationCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf), nil
+}
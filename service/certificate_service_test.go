@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type stubRenderer struct {
+	rendered []CertificateData
+}
+
+func (r *stubRenderer) Render(data CertificateData) ([]byte, error) {
+	r.rendered = append(r.rendered, data)
+	return []byte("%PDF"), nil
+}
+
+func TestCertificateService_ClaimCertificate(t *testing.T) {
+	userID := uuid.New()
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	course := &entities.Course{
+		ID:   1,
+		Name: "Go Programming Basics",
+		Chapters: []entities.Chapter{
+			{ID: 1, Lessons: []entities.Lesson{{ID: 1}, {ID: 2}}},
+		},
+	}
+
+	t.Run("rule satisfied", func(t *testing.T) {
+		certificates := new(mocks.CertificateRepository)
+		courses := new(mocks.CourseRepository)
+		lessonUsers := new(mocks.LessonUserRepository)
+		storage := new(mocks.FileStorage)
+		users := new(mocks.UserDirectory)
+		renderer := &stubRenderer{}
+		courses.On("FindByID", mock.Anything, uint(1)).Return(course, nil)
+		certificates.On("FindRule", mock.Anything, uint(1)).Return(&entities.CompletionRule{CourseID: 1, Enabled: true, RequireAllLessons: true}, nil)
+		lessonUsers.On("HasAccess", userID, uint(1)).Return(true, nil)
+		lessonUsers.On("HasAccess", userID, uint(2)).Return(true, nil)
+		certificates.On("FindByCourseAndUser", mock.Anything, uint(1), userID).Return(nil, repo.ErrNotFound)
+		users.On("GetFullName", mock.Anything, userID).Return("Ivan Petrov", nil)
+		storage.On("UploadFile", mock.Anything, mock.Anything, []byte("%PDF")).Return("url", nil)
+		certificates.On("Save", mock.Anything, mock.AnythingOfType("*entities.Certificate")).Return(nil)
+
+		service := NewCertificateService(certificates, courses, lessonUsers, storage, users, renderer).(*certificateService)
+		service.now = func() time.Time { return now }
+		certificate, err := service.ClaimCertificate(context.Background(), userID, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Ivan Petrov", certificate.StudentName)
+		assert.Equal(t, "Go Programming Basics", certificate.CourseName)
+		assert.Len(t, certificate.Code, 16)
+		assert.Nil(t, certificate.IssuedBy)
+		assert.Equal(t, "01.06.2025", renderer.rendered[0].Date)
+		certificates.AssertExpectations(t)
+	})
+
+	t.Run("lesson without access", func(t *testing.T) {
+		certificates := new(mocks.CertificateRepository)
+		courses := new(mocks.CourseRepository)
+		lessonUsers := new(mocks.LessonUserRepository)
+		courses.On("FindByID", mock.Anything, uint(1)).Return(course, nil)
+		certificates.On("FindRule", mock.Anything, uint(1)).Return(&entities.CompletionRule{CourseID: 1, Enabled: true, RequireAllLessons: true}, nil)
+		lessonUsers.On("HasAccess", userID, uint(1)).Return(true, nil)
+		lessonUsers.On("HasAccess", userID, uint(2)).Return(false, nil)
+
+		service := NewCertificateService(certificates, courses, lessonUsers, nil, nil, nil).(*certificateService)
+		service.now = func() time.Time { return now }
+		_, err := service.ClaimCertificate(context.Background(), userID, 1)
+
+		assert.ErrorIs(t, err, pkg.ErrCourseNotCompleted)
+	})
+
+	t.Run("course without lessons", func(t *testing.T) {
+		certificates := new(mocks.CertificateRepository)
+		courses := new(mocks.CourseRepository)
+		courses.On("FindByID", mock.Anything, uint(2)).Return(&entities.Course{ID: 2, Chapters: []entities.Chapter{{ID: 3}}}, nil)
+		certificates.On("FindRule", mock.Anything, uint(2)).Return(&entities.CompletionRule{CourseID: 2, Enabled: true, RequireAllLessons: true}, nil)
+
+		service := NewCertificateService(certificates, courses, new(mocks.LessonUserRepository), nil, nil, nil).(*certificateService)
+		service.now = func() time.Time { return now }
+		_, err := service.ClaimCertificate(context.Background(), userID, 2)
+
+		assert.ErrorIs(t, err, pkg.ErrCourseNotCompleted)
+	})
+
+	t.Run("before not_before date", func(t *testing.T) {
+		certificates := new(mocks.CertificateRepository)
+		courses := new(mocks.CourseRepository)
+		notBefore := now.Add(24 * time.Hour)
+		courses.On("FindByID", mock.Anything, uint(1)).Return(course, nil)
+		certificates.On("FindRule", mock.Anything, uint(1)).Return(&entities.CompletionRule{CourseID: 1, Enabled: true, NotBefore: &notBefore}, nil)
+
+		service := NewCertificateService(certificates, courses, nil, nil, nil, nil).(*certificateService)
+		service.now = func() time.Time { return now }
+		_, err := service.ClaimCertificate(context.Background(), userID, 1)
+
+		assert.ErrorIs(t, err, pkg.ErrCourseNotCompleted)
+	})
+
+	t.Run("rule not configured", func(t *testing.T) {
+		certificates := new(mocks.CertificateRepository)
+		courses := new(mocks.CourseRepository)
+		courses.On("FindByID", mock.Anything, uint(1)).Return(course, nil)
+		certificates.On("FindRule", mock.Anything, uint(1)).Return(nil, repo.ErrNotFound)
+
+		service := NewCertificateService(certificates, courses, nil, nil, nil, nil)
+		_, err := service.ClaimCertificate(context.Background(), userID, 1)
+
+		assert.ErrorIs(t, err, pkg.ErrCourseNotCompleted)
+	})
+}
+
+func TestCertificateService_IssueCertificate(t *testing.T) {
+	userID := uuid.New()
+	teacherID := uuid.New()
+
+	t.Run("already issued", func(t *testing.T) {
+		certificates := new(mocks.CertificateRepository)
+		courses := new(mocks.CourseRepository)
+		storage := new(mocks.FileStorage)
+		existing := &entities.Certificate{ID: 3, CourseID: 1, UserID: userID, Code: "ABC"}
+		courses.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1}, nil)
+		certificates.On("FindByCourseAndUser", mock.Anything, uint(1), userID).Return(existing, nil)
+
+		service := NewCertificateService(certificates, courses, nil, storage, nil, nil)
+		certificate, err := service.IssueCertificate(context.Background(), teacherID, 1, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, existing, certificate)
+		storage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("course not found", func(t *testing.T) {
+		courses := new(mocks.CourseRepository)
+		courses.On("FindByID", mock.Anything, uint(1)).Return(nil, repo.ErrNotFound)
+
+		service := NewCertificateService(nil, courses, nil, nil, nil, nil)
+		_, err := service.IssueCertificate(context.Background(), teacherID, 1, userID)
+
+		assert.ErrorIs(t, err, pkg.ErrCourseNotFound)
+	})
+}
+
+func TestCertificateService_DownloadCertificate(t *testing.T) {
+	ownerID := uuid.New()
+	certificate := &entities.Certificate{ID: 1, UserID: ownerID, Code: "CODE", FileURL: "certificates/CODE.pdf"}
+
+	t.Run("owner", func(t *testing.T) {
+		certificates := new(mocks.CertificateRepository)
+		storage := new(mocks.FileStorage)
+		certificates.On("FindByID", mock.Anything, uint(1)).Return(certificate, nil)
+		storage.On("DownloadFile", mock.Anything, "certificates/CODE.pdf").Return([]byte("%PDF"), nil)
+
+		service := NewCertificateService(certificates, nil, nil, storage, nil, nil)
+		data, name, err := service.DownloadCertificate(context.Background(), ownerID, 1, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("%PDF"), data)
+		assert.Equal(t, "certificate-CODE.pdf", name)
+	})
+
+	t.Run("other student", func(t *testing.T) {
+		certificates := new(mocks.CertificateRepository)
+		certificates.On("FindByID", mock.Anything, uint(1)).Return(certificate, nil)
+
+		service := NewCertificateService(certificates, nil, nil, nil, nil, nil)
+		_, _, err := service.DownloadCertificate(context.Background(), uuid.New(), 1, false)
+
+		assert.ErrorIs(t, err, pkg.ErrForbidden)
+	})
+}
+
+func TestPDFCertificateRenderer_Render(t *testing.T) {
+	renderer, err := NewPDFCertificateRenderer("Certificate", "{{.StudentName}} completed {{.CourseName}} on {{.Date}}", "")
+	assert.NoError(t, err)
+
+	data, err := renderer.Render(CertificateData{StudentName: "Jane Doe", CourseName: "Go", Date: "01.06.2025", Code: "CODE"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "%PDF", string(data[:4]))
+}
+
+func TestNewPDFCertificateRenderer_InvalidTemplate(t *testing.T) {
+	_, err := NewPDFCertificateRenderer("Certificate", "{{.StudentName", "")
+
+	assert.Error(t, err)
+}
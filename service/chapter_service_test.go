@@ -9,6 +9,7 @@ import (
 	"lms-system-internship/entities"
 	"lms-system-internship/mocks"
 	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -31,7 +32,7 @@ func TestChapterService_GetAllChapters(t *testing.T) {
 
 		mockRepo.On("FindAll", mock.Anything).Return(chapters, nil)
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, new(mocks.CourseRepository), anyRevisions(), noTranslations{})
 		result, err := service.GetAllChapters(context.Background())
 
 		assert.NoError(t, err)
@@ -43,7 +44,7 @@ func TestChapterService_GetAllChapters(t *testing.T) {
 		mockRepo := new(mocks.ChapterRepository)
 		mockRepo.On("FindAll", mock.Anything).Return([]*entities.Chapter{}, nil)
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, new(mocks.CourseRepository), anyRevisions(), noTranslations{})
 		result, err := service.GetAllChapters(context.Background())
 
 		assert.NoError(t, err)
@@ -55,7 +56,7 @@ func TestChapterService_GetAllChapters(t *testing.T) {
 		mockRepo := new(mocks.ChapterRepository)
 		mockRepo.On("FindAll", mock.Anything).Return(nil, errors.New("database error"))
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, new(mocks.CourseRepository), anyRevisions(), noTranslations{})
 		result, err := service.GetAllChapters(context.Background())
 
 		assert.Error(t, err)
@@ -77,7 +78,7 @@ func TestChapterService_GetChapter(t *testing.T) {
 
 		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(chapter, nil)
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, new(mocks.CourseRepository), anyRevisions(), noTranslations{})
 		result, err := service.GetChapter(context.Background(), 1)
 
 		assert.NoError(t, err)
@@ -89,7 +90,7 @@ func TestChapterService_GetChapter(t *testing.T) {
 		mockRepo := new(mocks.ChapterRepository)
 		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(nil, pkg.ErrChapterNotFound)
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, new(mocks.CourseRepository), anyRevisions(), noTranslations{})
 		result, err := service.GetChapter(context.Background(), 1)
 
 		assert.Error(t, err)
@@ -109,8 +110,10 @@ func TestChapterService_AddChapterToCourse(t *testing.T) {
 		}
 
 		mockRepo.On("Save", mock.Anything, chapter).Return(nil)
+		mockCourseRepo := new(mocks.CourseRepository)
+		mockCourseRepo.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1}, nil)
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, mockCourseRepo, anyRevisions(), noTranslations{})
 		err := service.AddChapterToCourse(context.Background(), 1, chapter)
 
 		assert.NoError(t, err)
@@ -127,13 +130,30 @@ func TestChapterService_AddChapterToCourse(t *testing.T) {
 		}
 
 		mockRepo.On("Save", mock.Anything, chapter).Return(errors.New("database error"))
+		mockCourseRepo := new(mocks.CourseRepository)
+		mockCourseRepo.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1}, nil)
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, mockCourseRepo, anyRevisions(), noTranslations{})
 		err := service.AddChapterToCourse(context.Background(), 1, chapter)
 
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("course does not exist", func(t *testing.T) {
+		mockRepo := new(mocks.ChapterRepository)
+		mockCourseRepo := new(mocks.CourseRepository)
+		mockCourseRepo.On("FindByID", mock.Anything, uint(42)).Return(nil, repo.ErrNotFound)
+
+		service := NewChapterService(mockRepo, mockCourseRepo, anyRevisions(), noTranslations{})
+		err := service.AddChapterToCourse(context.Background(), 42, &entities.Chapter{Name: "New Chapter"})
+
+		var appErr *pkg.AppError
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, []pkg.FieldError{{Field: "course_id", Code: "exists"}}, appErr.Fields)
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
 }
 
 func TestChapterService_UpdateChapterOrder(t *testing.T) {
@@ -150,7 +170,7 @@ func TestChapterService_UpdateChapterOrder(t *testing.T) {
 		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(chapter, nil)
 		mockRepo.On("Update", mock.Anything, chapter).Return(nil)
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, new(mocks.CourseRepository), anyRevisions(), noTranslations{})
 		err := service.UpdateChapterOrder(context.Background(), 1, 2)
 
 		assert.NoError(t, err)
@@ -162,7 +182,7 @@ func TestChapterService_UpdateChapterOrder(t *testing.T) {
 		mockRepo := new(mocks.ChapterRepository)
 		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(nil, pkg.ErrChapterNotFound)
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, new(mocks.CourseRepository), anyRevisions(), noTranslations{})
 		err := service.UpdateChapterOrder(context.Background(), 1, 2)
 
 		assert.Error(t, err)
@@ -176,7 +196,7 @@ func TestChapterService_RemoveChapter(t *testing.T) {
 		mockRepo := new(mocks.ChapterRepository)
 		mockRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, new(mocks.CourseRepository), anyRevisions(), noTranslations{})
 		err := service.RemoveChapter(context.Background(), 1)
 
 		assert.NoError(t, err)
@@ -187,7 +207,7 @@ func TestChapterService_RemoveChapter(t *testing.T) {
 		mockRepo := new(mocks.ChapterRepository)
 		mockRepo.On("Delete", mock.Anything, uint(1)).Return(pkg.ErrChapterNotFound)
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, new(mocks.CourseRepository), anyRevisions(), noTranslations{})
 		err := service.RemoveChapter(context.Background(), 1)
 
 		assert.Error(t, err)
@@ -199,7 +219,7 @@ func TestChapterService_RemoveChapter(t *testing.T) {
 		mockRepo := new(mocks.ChapterRepository)
 		mockRepo.On("Delete", mock.Anything, uint(1)).Return(errors.New("database error"))
 
-		service := NewChapterService(mockRepo)
+		service := NewChapterService(mockRepo, new(mocks.CourseRepository), anyRevisions(), noTranslations{})
 		err := service.RemoveChapter(context.Background(), 1)
 
 		assert.Error(t, err)
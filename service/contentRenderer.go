@@ -0,0 +1,71 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+)
+
+// ContentRenderer превращает содержимое урока в безопасный для вставки в страницу HTML.
+type ContentRenderer interface {
+	Render(format, content string) (string, error)
+}
+
+type htmlContentRenderer struct {
+	markdown  goldmark.Markdown
+	sanitizer *bluemonday.Policy
+}
+
+// NewContentRenderer создаёт рендерер: Markdown (GFM) с подсветкой кода через inline-стили,
+// результат любого формата проходит через санитайзер, так что сохранённый в уроке HTML
+// не может выполнить скрипт у студента.
+func NewContentRenderer() ContentRenderer {
+	sanitizer := bluemonday.UGCPolicy()
+	// Подсветка синтаксиса — это span с inline-стилями; разрешаем только цветовые свойства.
+	sanitizer.AllowStyles("color", "background-color", "font-weight", "font-style", "text-decoration").
+		OnElements("span", "pre", "code")
+
+	return &htmlContentRenderer{
+		markdown: goldmark.New(
+			goldmark.WithExtensions(
+				extension.GFM,
+				highlighting.NewHighlighting(highlighting.WithStyle("github")),
+			),
+		),
+		sanitizer: sanitizer,
+	}
+}
+
+func (r *htmlContentRenderer) Render(format, content string) (string, error) {
+	var raw string
+	switch format {
+	case entities.ContentFormatPlain, "":
+		raw = "<pre>" + html.EscapeString(content) + "</pre>"
+	case entities.ContentFormatMarkdown:
+		var buf bytes.Buffer
+		if err := r.markdown.Convert([]byte(content), &buf); err != nil {
+			return "", fmt.Errorf("failed to render markdown: %w", err)
+		}
+		raw = buf.String()
+	case entities.ContentFormatHTML:
+		raw = content
+	default:
+		return "", pkg.ErrInvalidInput
+	}
+	return r.sanitizer.Sanitize(raw), nil
+}
+
+func isContentFormat(format string) bool {
+	switch format {
+	case entities.ContentFormatPlain, entities.ContentFormatMarkdown, entities.ContentFormatHTML:
+		return true
+	}
+	return false
+}
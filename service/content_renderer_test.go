@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentRenderer_Render(t *testing.T) {
+	renderer := NewContentRenderer()
+
+	t.Run("plain text is escaped", func(t *testing.T) {
+		out, err := renderer.Render(entities.ContentFormatPlain, "func main() {\n\tfmt.Println(\"<hi>\")\n}")
+
+		assert.NoError(t, err)
+		assert.Contains(t, out, "<pre>")
+		assert.Contains(t, out, "&lt;hi&gt;")
+	})
+
+	t.Run("markdown with highlighted code", func(t *testing.T) {
+		out, err := renderer.Render(entities.ContentFormatMarkdown, "## Example\n\n```go\nfunc main() {}\n```\n")
+
+		assert.NoError(t, err)
+		assert.Contains(t, out, "<h2")
+		assert.Contains(t, out, "<span style=")
+	})
+
+	t.Run("html is sanitized", func(t *testing.T) {
+		out, err := renderer.Render(entities.ContentFormatHTML, `<p onclick="steal()">Hi</p><script>alert(1)</script><a href="javascript:alert(1)">x</a>`)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, out, "onclick")
+		assert.NotContains(t, out, "<script")
+		assert.NotContains(t, out, "javascript:")
+		assert.Contains(t, out, "<p>Hi</p>")
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := renderer.Render("rtf", "text")
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"lms-system-internship/entities"
+	"lms-system-internship/files"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"path"
+	"time"
+)
+
+type CourseVersionService interface {
+	GetVersions(ctx context.Context, courseID uint) ([]*entities.CourseVersion, error)
+
+	// Publish снимает текущее состояние курса в новую версию. Файлы вложений копируются
+	// в хранилище, так что версия не зависит от дальнейших правок черновика. При migrate
+	// все студенты, закреплённые за прежними версиями, переводятся на новую.
+	Publish(ctx context.Context, authorID uuid.UUID, courseID uint, comment string, migrate bool) (*entities.CourseVersion, error)
+
+	// MigrateLearners переводит студентов на версию number; пустой userIDs — всех студентов курса.
+	MigrateLearners(ctx context.Context, courseID uint, number int, userIDs []uuid.UUID) (int64, error)
+
+	// GetCourseVersion возвращает курс так, как его должен видеть пользователь. Преподаватели
+	// без version получают текущий черновик, с version — любой снимок. Студент видит только
+	// закреплённую за ним версию; при первом обращении за ним закрепляется последняя.
+	// Пока у курса нет опубликованных версий, все получают черновик. К снимку применяются
+	// текущие переводы на локаль запроса.
+	GetCourseVersion(ctx context.Context, userID uuid.UUID, privileged bool, courseID uint, version *int) (*entities.Course, error)
+
+	// PinnedLesson и PinnedAttachment возвращают урок и вложение из версии, закреплённой за
+	// студентом, по тем же правилам, что и GetCourseVersion. (nil, nil) означает, что ни в одной
+	// опубликованной версии их нет и студенту показывается черновик.
+	PinnedLesson(ctx context.Context, userID uuid.UUID, lessonID uint) (*entities.Lesson, error)
+	PinnedAttachment(ctx context.Context, userID uuid.UUID, attachmentID uint) (*entities.Attachment, error)
+}
+
+type courseVersionService struct {
+	repo         repo.CourseVersionRepository
+	courseRepo   repo.CourseRepository
+	translations TranslationService
+	fileStorage  files.FileStorage
+	now          func() time.Time
+}
+
+func NewCourseVersionService(repo repo.CourseVersionRepository, courseRepo repo.CourseRepository, translations TranslationService, fileStorage files.FileStorage) CourseVersionService {
+	return &courseVersionService{repo: repo, courseRepo: courseRepo, translations: translations, fileStorage: fileStorage, now: time.Now}
+}
+
+func (s *courseVersionService) GetVersions(ctx context.Context, courseID uint) ([]*entities.CourseVersion, error) {
+	if _, err := s.courseRepo.FindByID(ctx, courseID); err != nil {
+		return nil, notFound(err, pkg.ErrCourseNotFound)
+	}
+	return s.repo.FindByCourseID(ctx, courseID)
+}
+
+func (s *courseVersionService) Publish(ctx context.Context, authorID uuid.UUID, courseID uint, comment string, migrate bool) (*entities.CourseVersion, error) {
+	course, err := s.repo.LoadCourseTree(ctx, courseID)
+	if err != nil {
+		return nil, notFound(err, pkg.ErrCourseNotFound)
+	}
+	copies, err := s.copyAttachments(ctx, course)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := json.Marshal(course)
+	if err != nil {
+		s.deleteCopies(ctx, copies)
+		return nil, err
+	}
+
+	version := &entities.CourseVersion{
+		CourseID:    courseID,
+		Comment:     comment,
+		Snapshot:    string(snapshot),
+		PublishedBy: authorID,
+		PublishedAt: s.now(),
+	}
+	if err := s.repo.Publish(ctx, version, migrate); err != nil {
+		s.deleteCopies(ctx, copies)
+		return nil, err
+	}
+	return version, nil
+}
+
+// copyAttachments копирует файлы вложений курса в объекты версии и подменяет URL в дереве курса.
+// Возвращает созданные копии, чтобы их можно было удалить, если версия не сохранится.
+func (s *courseVersionService) copyAttachments(ctx context.Context, course *entities.Course) ([]string, error) {
+	var copies []string
+	for i := range course.Chapters {
+		for j := range course.Chapters[i].Lessons {
+			attachments := course.Chapters[i].Lessons[j].Attachments
+			for k := range attachments {
+				dst := fmt.Sprintf("versions/%d/%s%s", course.ID, uuid.New(), path.Ext(attachments[k].URL))
+				if err := s.fileStorage.CopyFile(ctx, attachments[k].URL, dst); err != nil {
+					s.deleteCopies(ctx, copies)
+					return nil, fmt.Errorf("failed to copy attachment %d: %w", attachments[k].ID, err)
+				}
+				copies = append(copies, dst)
+				attachments[k].URL = dst
+			}
+		}
+	}
+	return copies, nil
+}
+
+func (s *courseVersionService) deleteCopies(ctx context.Context, copies []string) {
+	for _, object := range copies {
+		if err := s.fileStorage.DeleteFile(ctx, object); err != nil {
+			pkg.LoggerFromContext(ctx).WithError(err).WithField("object", object).Warn("Failed to delete attachment copy")
+		}
+	}
+}
+
+func (s *courseVersionService) MigrateLearners(ctx context.Context, courseID uint, number int, userIDs []uuid.UUID) (int64, error) {
+	if _, err := s.getVersion(ctx, courseID, number); err != nil {
+		return 0, err
+	}
+	return s.repo.MovePins(ctx, courseID, number, userIDs)
+}
+
+func (s *courseVersionService) GetCourseVersion(ctx context.Context, userID uuid.UUID, privileged bool, courseID uint, version *int) (*entities.Course, error) {
+	course, err := s.courseVersion(ctx, userID, privileged, courseID, version)
+	if err != nil {
+		return nil, err
+	}
+	localize(ctx, s.translations, func(t TranslationService) error { return t.LocalizeCourses(ctx, course) })
+	return course, nil
+}
+
+func (s *courseVersionService) PinnedLesson(ctx context.Context, userID uuid.UUID, lessonID uint) (*entities.Lesson, error) {
+	course, err := s.pinnedSnapshot(ctx, userID, s.repo.FindCourseIDByLesson, lessonID)
+	if course == nil || err != nil {
+		return nil, err
+	}
+	for i := range course.Chapters {
+		for j := range course.Chapters[i].Lessons {
+			if lesson := &course.Chapters[i].Lessons[j]; lesson.ID == lessonID {
+				localize(ctx, s.translations, func(t TranslationService) error { return t.LocalizeLessons(ctx, lesson) })
+				return lesson, nil
+			}
+		}
+	}
+	// Урок появился в версии новее той, что закреплена за студентом
+	return nil, pkg.ErrLessonNotFound
+}
+
+func (s *courseVersionService) PinnedAttachment(ctx context.Context, userID uuid.UUID, attachmentID uint) (*entities.Attachment, error) {
+	course, err := s.pinnedSnapshot(ctx, userID, s.repo.FindCourseIDByAttachment, attachmentID)
+	if course == nil || err != nil {
+		return nil, err
+	}
+	for _, chapter := range course.Chapters {
+		for _, lesson := range chapter.Lessons {
+			for i := range lesson.Attachments {
+				if lesson.Attachments[i].ID == attachmentID {
+					return &lesson.Attachments[i], nil
+				}
+			}
+		}
+	}
+	return nil, pkg.ErrAttachmentNotFound
+}
+
+// pinnedSnapshot находит курс, в версиях которого есть сущность id, и возвращает снимок,
+// закреплённый за студентом. (nil, nil) — сущность не входит ни в одну версию.
+func (s *courseVersionService) pinnedSnapshot(ctx context.Context, userID uuid.UUID, findCourse func(context.Context, uint) (uint, error), id uint) (*entities.Course, error) {
+	courseID, err := findCourse(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	pinned, err := s.pinnedVersion(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.snapshot(ctx, courseID, pinned)
+}
+
+func (s *courseVersionService) courseVersion(ctx context.Context, userID uuid.UUID, privileged bool, courseID uint, version *int) (*entities.Course, error) {
+	if privileged {
+		if version == nil {
+			return s.draft(ctx, courseID)
+		}
+		return s.snapshot(ctx, courseID, *version)
+	}
+
+	pinned, err := s.pinnedVersion(ctx, userID, courseID)
+	if errors.Is(err, pkg.ErrCourseVersionNotFound) {
+		// Курс ещё ни разу не публиковался.
+		if version != nil {
+			return nil, err
+		}
+		return s.draft(ctx, courseID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if version != nil && *version != pinned {
+		return nil, pkg.ErrForbidden
+	}
+	return s.snapshot(ctx, courseID, pinned)
+}
+
+// pinnedVersion возвращает версию, закреплённую за студентом, закрепляя последнюю при первом обращении.
+func (s *courseVersionService) pinnedVersion(ctx context.Context, userID uuid.UUID, courseID uint) (int, error) {
+	pin, err := s.repo.FindPin(ctx, courseID, userID)
+	if err == nil {
+		return pin.Version, nil
+	}
+	if !errors.Is(err, repo.ErrNotFound) {
+		return 0, err
+	}
+
+	latest, err := s.repo.FindLatest(ctx, courseID)
+	if err != nil {
+		return 0, notFound(err, pkg.ErrCourseVersionNotFound)
+	}
+	if err := s.repo.SavePin(ctx, &entities.CourseVersionPin{CourseID: courseID, UserID: userID, Version: latest.Number}); err != nil {
+		return 0, err
+	}
+	// SavePin не перезаписывает закрепление, сделанное параллельным запросом, поэтому читаем его заново.
+	pin, err = s.repo.FindPin(ctx, courseID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return pin.Version, nil
+}
+
+func (s *courseVersionService) draft(ctx context.Context, courseID uint) (*entities.Course, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, notFound(err, pkg.ErrCourseNotFound)
+	}
+	return course, nil
+}
+
+func (s *courseVersionService) snapshot(ctx context.Context, courseID uint, number int) (*entities.Course, error) {
+	version, err := s.getVersion(ctx, courseID, number)
+	if err != nil {
+		return nil, err
+	}
+	var course entities.Course
+	if err := json.Unmarshal([]byte(version.Snapshot), &course); err != nil {
+		return nil, err
+	}
+	course.Version = version.Number
+	return &course, nil
+}
+
+func (s *courseVersionService) getVersion(ctx context.Context, courseID uint, number int) (*entities.CourseVersion, error) {
+	version, err := s.repo.FindByNumber(ctx, courseID, number)
+	if errors.Is(err, repo.ErrNotFound) {
+		if _, err := s.courseRepo.FindByID(ctx, courseID); err != nil {
+			return nil, notFound(err, pkg.ErrCourseNotFound)
+		}
+		return nil, pkg.ErrCourseVersionNotFound
+	}
+	return version, err
+}
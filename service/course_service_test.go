@@ -9,11 +9,20 @@ import (
 	"lms-system-internship/entities"
 	"lms-system-internship/mocks"
 	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// anyRevisions принимает любые ревизии: история правок в этих тестах не проверяется.
+func anyRevisions() *mocks.RevisionService {
+	revisions := new(mocks.RevisionService)
+	revisions.On("RecordChange", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return revisions
+}
+
 func TestCourseService_GetAllCourses(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockRepo := new(mocks.CourseRepository)
@@ -34,10 +43,10 @@ func TestCourseService_GetAllCourses(t *testing.T) {
 			},
 		}
 
-		mockRepo.On("FindAll", mock.Anything).Return(courses, nil)
+		mockRepo.On("FindAll", mock.Anything, repo.CourseFilter{}).Return(courses, nil)
 
-		service := NewCourseService(mockRepo)
-		result, err := service.GetAllCourses(context.Background())
+		service := NewCourseService(mockRepo, anyRevisions(), noTranslations{})
+		result, err := service.GetAllCourses(context.Background(), repo.CourseFilter{})
 
 		assert.NoError(t, err)
 		assert.Equal(t, courses, result)
@@ -46,10 +55,10 @@ func TestCourseService_GetAllCourses(t *testing.T) {
 
 	t.Run("empty list", func(t *testing.T) {
 		mockRepo := new(mocks.CourseRepository)
-		mockRepo.On("FindAll", mock.Anything).Return([]*entities.Course{}, nil)
+		mockRepo.On("FindAll", mock.Anything, repo.CourseFilter{}).Return([]*entities.Course{}, nil)
 
-		service := NewCourseService(mockRepo)
-		result, err := service.GetAllCourses(context.Background())
+		service := NewCourseService(mockRepo, anyRevisions(), noTranslations{})
+		result, err := service.GetAllCourses(context.Background(), repo.CourseFilter{})
 
 		assert.NoError(t, err)
 		assert.Empty(t, result)
@@ -58,10 +67,10 @@ func TestCourseService_GetAllCourses(t *testing.T) {
 
 	t.Run("error", func(t *testing.T) {
 		mockRepo := new(mocks.CourseRepository)
-		mockRepo.On("FindAll", mock.Anything).Return(nil, errors.New("database error"))
+		mockRepo.On("FindAll", mock.Anything, repo.CourseFilter{}).Return(nil, errors.New("database error"))
 
-		service := NewCourseService(mockRepo)
-		result, err := service.GetAllCourses(context.Background())
+		service := NewCourseService(mockRepo, anyRevisions(), noTranslations{})
+		result, err := service.GetAllCourses(context.Background(), repo.CourseFilter{})
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -72,18 +81,24 @@ func TestCourseService_GetAllCourses(t *testing.T) {
 func TestCourseService_UpdateCourseDetails(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockRepo := new(mocks.CourseRepository)
+		categoryID := uint(3)
+		existing := &entities.Course{ID: 1, Name: "Course", Description: "Description", CategoryID: &categoryID}
 		course := &entities.Course{
 			ID:          1,
 			Name:        "Updated Course",
 			Description: "Updated Description",
 		}
 
-		mockRepo.On("Update", mock.Anything, course).Return(nil)
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(existing, nil)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(c *entities.Course) bool {
+			return c.Name == "Updated Course" && c.CategoryID == &categoryID
+		})).Return(nil)
 
-		service := NewCourseService(mockRepo)
-		err := service.UpdateCourseDetails(context.Background(), course)
+		service := NewCourseService(mockRepo, anyRevisions(), noTranslations{})
+		err := service.UpdateCourseDetails(context.Background(), uuid.New(), course)
 
 		assert.NoError(t, err)
+		assert.Equal(t, &categoryID, course.CategoryID)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -95,10 +110,10 @@ func TestCourseService_UpdateCourseDetails(t *testing.T) {
 			Description: "Should fail",
 		}
 
-		mockRepo.On("Update", mock.Anything, course).Return(pkg.ErrCourseNotFound)
+		mockRepo.On("FindByID", mock.Anything, uint(999)).Return(nil, repo.ErrNotFound)
 
-		service := NewCourseService(mockRepo)
-		err := service.UpdateCourseDetails(context.Background(), course)
+		service := NewCourseService(mockRepo, anyRevisions(), noTranslations{})
+		err := service.UpdateCourseDetails(context.Background(), uuid.New(), course)
 
 		assert.Error(t, err)
 		assert.Equal(t, pkg.ErrCourseNotFound, err)
@@ -113,10 +128,11 @@ func TestCourseService_UpdateCourseDetails(t *testing.T) {
 			Description: "Description",
 		}
 
-		mockRepo.On("Update", mock.Anything, course).Return(errors.New("database error"))
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1}, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(errors.New("database error"))
 
-		service := NewCourseService(mockRepo)
-		err := service.UpdateCourseDetails(context.Background(), course)
+		service := NewCourseService(mockRepo, anyRevisions(), noTranslations{})
+		err := service.UpdateCourseDetails(context.Background(), uuid.New(), course)
 
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
@@ -128,7 +144,7 @@ func TestCourseService_DeleteCourse(t *testing.T) {
 		mockRepo := new(mocks.CourseRepository)
 		mockRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
 
-		service := NewCourseService(mockRepo)
+		service := NewCourseService(mockRepo, anyRevisions(), noTranslations{})
 		err := service.DeleteCourse(context.Background(), 1)
 
 		assert.NoError(t, err)
@@ -139,7 +155,7 @@ func TestCourseService_DeleteCourse(t *testing.T) {
 		mockRepo := new(mocks.CourseRepository)
 		mockRepo.On("Delete", mock.Anything, uint(1)).Return(pkg.ErrCourseNotFound)
 
-		service := NewCourseService(mockRepo)
+		service := NewCourseService(mockRepo, anyRevisions(), noTranslations{})
 		err := service.DeleteCourse(context.Background(), 1)
 
 		assert.Error(t, err)
@@ -151,7 +167,7 @@ func TestCourseService_DeleteCourse(t *testing.T) {
 		mockRepo := new(mocks.CourseRepository)
 		mockRepo.On("Delete", mock.Anything, uint(1)).Return(errors.New("database error"))
 
-		service := NewCourseService(mockRepo)
+		service := NewCourseService(mockRepo, anyRevisions(), noTranslations{})
 		err := service.DeleteCourse(context.Background(), 1)
 
 		assert.Error(t, err)
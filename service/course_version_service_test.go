@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCourseVersionService_Publish(t *testing.T) {
+	authorID := uuid.New()
+	tree := &entities.Course{ID: 1, Name: "Go", Chapters: []entities.Chapter{{ID: 2, Name: "Basics", CourseID: 1}}}
+
+	t.Run("snapshot and migrate learners", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		service := NewCourseVersionService(versions, nil, noTranslations{}, nil).(*courseVersionService)
+		now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		service.now = func() time.Time { return now }
+		versions.On("LoadCourseTree", mock.Anything, uint(1)).Return(tree, nil)
+		versions.On("Publish", mock.Anything, mock.AnythingOfType("*entities.CourseVersion"), true).
+			Run(func(args mock.Arguments) { args.Get(1).(*entities.CourseVersion).Number = 3 }).
+			Return(nil)
+
+		version, err := service.Publish(context.Background(), authorID, 1, "spring term", true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, version.Number)
+		assert.Equal(t, authorID, version.PublishedBy)
+		assert.Equal(t, now, version.PublishedAt)
+		assert.Contains(t, version.Snapshot, `"name":"Basics"`)
+		versions.AssertExpectations(t)
+	})
+
+	t.Run("without migration pins stay", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		service := NewCourseVersionService(versions, nil, noTranslations{}, nil)
+		versions.On("LoadCourseTree", mock.Anything, uint(1)).Return(tree, nil)
+		versions.On("Publish", mock.Anything, mock.Anything, false).Return(nil)
+
+		_, err := service.Publish(context.Background(), authorID, 1, "", false)
+
+		assert.NoError(t, err)
+		versions.AssertExpectations(t)
+	})
+
+	t.Run("attachments are copied into the version", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		storage := new(mocks.FileStorage)
+		service := NewCourseVersionService(versions, nil, noTranslations{}, storage)
+		withFile := &entities.Course{ID: 1, Chapters: []entities.Chapter{{ID: 2, Lessons: []entities.Lesson{
+			{ID: 3, Attachments: []entities.Attachment{{ID: 4, Name: "slides.pdf", URL: "draft.pdf", LessonID: 3}}},
+		}}}}
+		var copied string
+		versions.On("LoadCourseTree", mock.Anything, uint(1)).Return(withFile, nil)
+		storage.On("CopyFile", mock.Anything, "draft.pdf", mock.MatchedBy(func(dst string) bool {
+			copied = dst
+			return strings.HasPrefix(dst, "versions/1/") && strings.HasSuffix(dst, ".pdf")
+		})).Return(nil)
+		versions.On("Publish", mock.Anything, mock.Anything, false).Return(nil)
+
+		version, err := service.Publish(context.Background(), authorID, 1, "", false)
+
+		assert.NoError(t, err)
+		assert.Contains(t, version.Snapshot, `"URL":"`+copied+`"`)
+		assert.NotContains(t, version.Snapshot, "draft.pdf")
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("copies are removed when the version is not saved", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		storage := new(mocks.FileStorage)
+		service := NewCourseVersionService(versions, nil, noTranslations{}, storage)
+		withFile := &entities.Course{ID: 1, Chapters: []entities.Chapter{{ID: 2, Lessons: []entities.Lesson{
+			{ID: 3, Attachments: []entities.Attachment{{ID: 4, URL: "draft.pdf", LessonID: 3}}},
+		}}}}
+		versions.On("LoadCourseTree", mock.Anything, uint(1)).Return(withFile, nil)
+		storage.On("CopyFile", mock.Anything, "draft.pdf", mock.Anything).Return(nil)
+		versions.On("Publish", mock.Anything, mock.Anything, true).Return(errors.New("db down"))
+		storage.On("DeleteFile", mock.Anything, mock.MatchedBy(func(object string) bool {
+			return strings.HasPrefix(object, "versions/1/")
+		})).Return(nil)
+
+		_, err := service.Publish(context.Background(), authorID, 1, "", true)
+
+		assert.Error(t, err)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("course not found", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		service := NewCourseVersionService(versions, nil, noTranslations{}, nil)
+		versions.On("LoadCourseTree", mock.Anything, uint(1)).Return(nil, repo.ErrNotFound)
+
+		_, err := service.Publish(context.Background(), authorID, 1, "", false)
+
+		assert.ErrorIs(t, err, pkg.ErrCourseNotFound)
+	})
+}
+
+func TestCourseVersionService_GetCourseVersion(t *testing.T) {
+	userID := uuid.New()
+	snapshot := &entities.CourseVersion{CourseID: 1, Number: 2, Snapshot: `{"id":1,"name":"Go v2","chapters":[{"id":2,"name":"Basics"}]}`}
+	two := 2
+	three := 3
+
+	t.Run("student gets latest version pinned on first access", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		service := NewCourseVersionService(versions, nil, noTranslations{}, nil)
+		pin := &entities.CourseVersionPin{CourseID: 1, UserID: userID, Version: 2}
+		versions.On("FindPin", mock.Anything, uint(1), userID).Return(nil, repo.ErrNotFound).Once()
+		versions.On("FindLatest", mock.Anything, uint(1)).Return(snapshot, nil)
+		versions.On("SavePin", mock.Anything, pin).Return(nil)
+		versions.On("FindPin", mock.Anything, uint(1), userID).Return(pin, nil).Once()
+		versions.On("FindByNumber", mock.Anything, uint(1), 2).Return(snapshot, nil)
+
+		course, err := service.GetCourseVersion(context.Background(), userID, false, 1, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Go v2", course.Name)
+		assert.Equal(t, 2, course.Version)
+		assert.Len(t, course.Chapters, 1)
+		versions.AssertExpectations(t)
+	})
+
+	t.Run("student cannot open another version", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		service := NewCourseVersionService(versions, nil, noTranslations{}, nil)
+		versions.On("FindPin", mock.Anything, uint(1), userID).Return(&entities.CourseVersionPin{Version: 2}, nil)
+
+		_, err := service.GetCourseVersion(context.Background(), userID, false, 1, &three)
+
+		assert.ErrorIs(t, err, pkg.ErrForbidden)
+	})
+
+	t.Run("unpublished course falls back to draft", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		courses := new(mocks.CourseRepository)
+		service := NewCourseVersionService(versions, courses, noTranslations{}, nil)
+		versions.On("FindPin", mock.Anything, uint(1), userID).Return(nil, repo.ErrNotFound)
+		versions.On("FindLatest", mock.Anything, uint(1)).Return(nil, repo.ErrNotFound)
+		courses.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1, Name: "Draft"}, nil)
+
+		course, err := service.GetCourseVersion(context.Background(), userID, false, 1, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Draft", course.Name)
+		assert.Zero(t, course.Version)
+	})
+
+	t.Run("teacher gets draft or any version", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		courses := new(mocks.CourseRepository)
+		service := NewCourseVersionService(versions, courses, noTranslations{}, nil)
+		courses.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1, Name: "Draft"}, nil)
+		versions.On("FindByNumber", mock.Anything, uint(1), 2).Return(snapshot, nil)
+
+		draft, err := service.GetCourseVersion(context.Background(), userID, true, 1, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Draft", draft.Name)
+
+		published, err := service.GetCourseVersion(context.Background(), userID, true, 1, &two)
+		assert.NoError(t, err)
+		assert.Equal(t, "Go v2", published.Name)
+		versions.AssertNotCalled(t, "FindPin", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		courses := new(mocks.CourseRepository)
+		service := NewCourseVersionService(versions, courses, noTranslations{}, nil)
+		versions.On("FindByNumber", mock.Anything, uint(1), 3).Return(nil, repo.ErrNotFound)
+		courses.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1}, nil)
+
+		_, err := service.GetCourseVersion(context.Background(), userID, true, 1, &three)
+
+		assert.ErrorIs(t, err, pkg.ErrCourseVersionNotFound)
+	})
+}
+
+func TestCourseVersionService_MigrateLearners(t *testing.T) {
+	versions := new(mocks.CourseVersionRepository)
+	service := NewCourseVersionService(versions, nil, noTranslations{}, nil)
+	users := []uuid.UUID{uuid.New()}
+	versions.On("FindByNumber", mock.Anything, uint(1), 2).Return(&entities.CourseVersion{Number: 2}, nil)
+	versions.On("MovePins", mock.Anything, uint(1), 2, users).Return(int64(1), nil)
+
+	migrated, err := service.MigrateLearners(context.Background(), 1, 2, users)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), migrated)
+	versions.AssertExpectations(t)
+}
+
+func TestCourseVersionService_PinnedLesson(t *testing.T) {
+	userID := uuid.New()
+	snapshot := &entities.CourseVersion{CourseID: 1, Number: 2, Snapshot: `{"id":1,"chapters":[{"id":2,"lessons":[{"id":3,"name":"Published"}]}]}`}
+
+	t.Run("lesson from pinned version", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		service := NewCourseVersionService(versions, nil, noTranslations{}, nil)
+		versions.On("FindCourseIDByLesson", mock.Anything, uint(3)).Return(uint(1), nil)
+		versions.On("FindPin", mock.Anything, uint(1), userID).Return(&entities.CourseVersionPin{Version: 2}, nil)
+		versions.On("FindByNumber", mock.Anything, uint(1), 2).Return(snapshot, nil)
+
+		lesson, err := service.PinnedLesson(context.Background(), userID, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Published", lesson.Name)
+	})
+
+	t.Run("never published lesson", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		service := NewCourseVersionService(versions, nil, noTranslations{}, nil)
+		versions.On("FindCourseIDByLesson", mock.Anything, uint(5)).Return(uint(0), repo.ErrNotFound)
+
+		lesson, err := service.PinnedLesson(context.Background(), userID, 5)
+
+		assert.NoError(t, err)
+		assert.Nil(t, lesson)
+	})
+
+	t.Run("lesson missing from pinned version", func(t *testing.T) {
+		versions := new(mocks.CourseVersionRepository)
+		service := NewCourseVersionService(versions, nil, noTranslations{}, nil)
+		versions.On("FindCourseIDByLesson", mock.Anything, uint(5)).Return(uint(1), nil)
+		versions.On("FindPin", mock.Anything, uint(1), userID).Return(&entities.CourseVersionPin{Version: 2}, nil)
+		versions.On("FindByNumber", mock.Anything, uint(1), 2).Return(snapshot, nil)
+
+		_, err := service.PinnedLesson(context.Background(), userID, 5)
+
+		assert.ErrorIs(t, err, pkg.ErrLessonNotFound)
+	})
+}
+
+func TestCourseVersionService_PinnedAttachment(t *testing.T) {
+	userID := uuid.New()
+	versions := new(mocks.CourseVersionRepository)
+	service := NewCourseVersionService(versions, nil, noTranslations{}, nil)
+	snapshot := &entities.CourseVersion{CourseID: 1, Number: 2, Snapshot: `{"id":1,"chapters":[{"id":2,"lessons":[{"id":3,"attachments":[{"ID":4,"URL":"versions/1/copy.pdf","LessonID":3}]}]}]}`}
+	versions.On("FindCourseIDByAttachment", mock.Anything, uint(4)).Return(uint(1), nil)
+	versions.On("FindPin", mock.Anything, uint(1), userID).Return(&entities.CourseVersionPin{Version: 2}, nil)
+	versions.On("FindByNumber", mock.Anything, uint(1), 2).Return(snapshot, nil)
+
+	attachment, err := service.PinnedAttachment(context.Background(), userID, 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "versions/1/copy.pdf", attachment.URL)
+}
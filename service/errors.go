@@ -0,0 +1,23 @@
+package service
+
+import (
+	"errors"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+)
+
+// notFound подменяет repo.ErrNotFound доменной ошибкой.
+func notFound(err, domainErr error) error {
+	if errors.Is(err, repo.ErrNotFound) {
+		return domainErr
+	}
+	return err
+}
+
+// missingParent превращает отсутствие родительской записи в ошибку поля field.
+func missingParent(err error, field string) error {
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: field, Code: "exists"})
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// gradebookRows раскладывает журнал в таблицу: первая строка — заголовки, далее по строке на студента.
+func gradebookRows(gradebook *Gradebook) [][]string {
+	header := []string{"user_id"}
+	for _, item := range gradebook.Items {
+		header = append(header, item.Name)
+	}
+	header = append(header, "total")
+
+	rows := [][]string{header}
+	for _, student := range gradebook.Students {
+		row := []string{student.UserID.String()}
+		for _, item := range gradebook.Items {
+			if score, ok := student.Scores[item.ID]; ok {
+				row = append(row, formatScore(score))
+			} else {
+				row = append(row, "")
+			}
+		}
+		row = append(row, formatScore(student.Total))
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func exportGradebookCSV(gradebook *Gradebook) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(gradebookRows(gradebook)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func exportGradebookXLSX(gradebook *Gradebook) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := f.GetSheetName(0)
+	for i, row := range gradebookRows(gradebook) {
+		cells := make([]interface{}, len(row))
+		for j, value := range row {
+			// Баллы пишем числами, чтобы по ним можно было считать в Excel
+			if number, err := strconv.ParseFloat(value, 64); err == nil && i > 0 && j > 0 {
+				cells[j] = number
+			} else {
+				cells[j] = value
+			}
+		}
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.SetSheetRow(sheet, cell, &cells); err != nil {
+			return nil, err
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', 2, 64)
+}
@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"sort"
+	"strings"
+)
+
+const (
+	GradebookFormatCSV  = "csv"
+	GradebookFormatXLSX = "xlsx"
+)
+
+// Gradebook — сводная таблица оценок курса: колонки — элементы оценивания, строки — студенты.
+type Gradebook struct {
+	CourseID   uint                      `json:"course_id"`
+	Categories []*entities.GradeCategory `json:"categories"`
+	Items      []*entities.GradeItem     `json:"items"`
+	Students   []*StudentGrades          `json:"students"`
+}
+
+// StudentGrades — оценки одного студента по курсу. Total — взвешенный итог в процентах (0..100).
+type StudentGrades struct {
+	UserID   uuid.UUID             `json:"user_id"`
+	CourseID uint                  `json:"course_id"`
+	Items    []*entities.GradeItem `json:"items,omitempty"`
+	Scores   map[uint]float64      `json:"scores"`
+	Total    float64               `json:"total"`
+}
+
+type GradebookService interface {
+	GetCategories(ctx context.Context, courseID uint) ([]*entities.GradeCategory, error)
+	CreateCategory(ctx context.Context, category *entities.GradeCategory) error
+	UpdateCategory(ctx context.Context, category *entities.GradeCategory) error
+	DeleteCategory(ctx context.Context, categoryID uint) error
+
+	GetItems(ctx context.Context, courseID uint) ([]*entities.GradeItem, error)
+	CreateItem(ctx context.Context, item *entities.GradeItem) error
+	UpdateItem(ctx context.Context, item *entities.GradeItem) error
+	DeleteItem(ctx context.Context, itemID uint) error
+
+	PostScore(ctx context.Context, itemID uint, userID uuid.UUID, score float64) error
+	PostAssignmentScore(ctx context.Context, assignmentID uint, userID uuid.UUID, score, maxScore float64) error
+	OverrideScore(ctx context.Context, changedBy uuid.UUID, itemID uint, userID uuid.UUID, score float64, reason string) (*entities.Grade, error)
+	GetGradeHistory(ctx context.Context, itemID uint, userID uuid.UUID) ([]*entities.GradeAudit, error)
+
+	GetCourseGradebook(ctx context.Context, courseID uint) (*Gradebook, error)
+	GetUserGrades(ctx context.Context, userID uuid.UUID) ([]*StudentGrades, error)
+	ExportCourseGradebook(ctx context.Context, courseID uint, format string) ([]byte, error)
+}
+
+type gradebookService struct {
+	repo       repo.GradebookRepository
+	courseRepo repo.CourseRepository
+}
+
+func NewGradebookService(repo repo.GradebookRepository, courseRepo repo.CourseRepository) GradebookService {
+	return &gradebookService{repo: repo, courseRepo: courseRepo}
+}
+
+func (s *gradebookService) GetCategories(ctx context.Context, courseID uint) ([]*entities.GradeCategory, error) {
+	return s.repo.FindCategoriesByCourseID(ctx, courseID)
+}
+
+func (s *gradebookService) CreateCategory(ctx context.Context, category *entities.GradeCategory) error {
+	if err := s.validateCategory(ctx, category); err != nil {
+		return err
+	}
+	return s.repo.SaveCategory(ctx, category)
+}
+
+func (s *gradebookService) UpdateCategory(ctx context.Context, category *entities.GradeCategory) error {
+	existing, err := s.getCategory(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	category.CourseID = existing.CourseID
+	if err := s.validateCategory(ctx, category); err != nil {
+		return err
+	}
+	return s.repo.UpdateCategory(ctx, category)
+}
+
+func (s *gradebookService) DeleteCategory(ctx context.Context, categoryID uint) error {
+	err := s.repo.DeleteCategory(ctx, categoryID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkg.ErrGradeCategoryNotFound
+	}
+	return err
+}
+
+func (s *gradebookService) GetItems(ctx context.Context, courseID uint) ([]*entities.GradeItem, error) {
+	return s.repo.FindItemsByCourseID(ctx, courseID)
+}
+
+func (s *gradebookService) CreateItem(ctx context.Context, item *entities.GradeItem) error {
+	if err := s.validateItem(ctx, item); err != nil {
+		return err
+	}
+	return s.repo.SaveItem(ctx, item)
+}
+
+func (s *gradebookService) UpdateItem(ctx context.Context, item *entities.GradeItem) error {
+	existing, err := s.getItem(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+	item.CourseID = existing.CourseID
+	if err := s.validateItem(ctx, item); err != nil {
+		return err
+	}
+	return s.repo.UpdateItem(ctx, item)
+}
+
+func (s *gradebookService) DeleteItem(ctx context.Context, itemID uint) error {
+	err := s.repo.DeleteItem(ctx, itemID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkg.ErrGradeItemNotFound
+	}
+	return err
+}
+
+// PostScore записывает автоматически выставленную оценку (например, из проверки задания).
+// Оценки, переопределённые преподавателем вручную, не перезаписываются.
+func (s *gradebookService) PostScore(ctx context.Context, itemID uint, userID uuid.UUID, score float64) error {
+	item, err := s.getItem(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	if score < 0 || score > item.MaxScore {
+		return pkg.ErrInvalidInput
+	}
+
+	grade, err := s.repo.FindGrade(ctx, itemID, userID)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return err
+	}
+	if grade != nil && grade.Overridden {
+		return nil
+	}
+	_, err = s.saveGrade(ctx, item, grade, userID, score, false, nil, "automatic")
+	return err
+}
+
+func (s *gradebookService) PostAssignmentScore(ctx context.Context, assignmentID uint, userID uuid.UUID, score, maxScore float64) error {
+	if maxScore <= 0 {
+		return pkg.ErrInvalidInput
+	}
+	items, err := s.repo.FindItemsByAssignmentID(ctx, assignmentID)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := s.PostScore(ctx, item.ID, userID, score/maxScore*item.MaxScore); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *gradebookService) OverrideScore(ctx context.Context, changedBy uuid.UUID, itemID uint, userID uuid.UUID, score float64, reason string) (*entities.Grade, error) {
+	item, err := s.getItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if score < 0 || score > item.MaxScore || strings.TrimSpace(reason) == "" {
+		return nil, pkg.ErrInvalidInput
+	}
+
+	grade, err := s.repo.FindGrade(ctx, itemID, userID)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return nil, err
+	}
+	return s.saveGrade(ctx, item, grade, userID, score, true, &changedBy, reason)
+}
+
+func (s *gradebookService) GetGradeHistory(ctx context.Context, itemID uint, userID uuid.UUID) ([]*entities.GradeAudit, error) {
+	return s.repo.FindAudits(ctx, itemID, userID)
+}
+
+func (s *gradebookService) GetCourseGradebook(ctx context.Context, courseID uint) (*Gradebook, error) {
+	if _, err := s.courseRepo.FindByID(ctx, courseID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, pkg.ErrCourseNotFound
+		}
+		return nil, err
+	}
+
+	categories, err := s.repo.FindCategoriesByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.FindItemsByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	grades, err := s.repo.FindGradesByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[uuid.UUID]*StudentGrades)
+	for _, g := range grades {
+		sg, ok := byUser[g.UserID]
+		if !ok {
+			sg = &StudentGrades{UserID: g.UserID, CourseID: courseID, Scores: map[uint]float64{}}
+			byUser[g.UserID] = sg
+		}
+		sg.Scores[g.GradeItemID] = g.Score
+	}
+
+	students := make([]*StudentGrades, 0, len(byUser))
+	for _, sg := range byUser {
+		sg.Total = computeWeightedTotal(categories, items, sg.Scores)
+		students = append(students, sg)
+	}
+	sort.Slice(students, func(i, j int) bool {
+		return students[i].UserID.String() < students[j].UserID.String()
+	})
+
+	return &Gradebook{
+		CourseID:   courseID,
+		Categories: categories,
+		Items:      items,
+		Students:   students,
+	}, nil
+}
+
+func (s *gradebookService) GetUserGrades(ctx context.Context, userID uuid.UUID) ([]*StudentGrades, error) {
+	grades, err := s.repo.FindGradesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Элементы оценивания загружаем одним запросом, а не по одному на каждую оценку
+	itemIDs := make([]uint, 0, len(grades))
+	for _, g := range grades {
+		itemIDs = append(itemIDs, g.GradeItemID)
+	}
+	items, err := s.repo.FindItemsByIDs(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	itemsByID := make(map[uint]*entities.GradeItem, len(items))
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+
+	// Оценки студента группируем по курсам через элементы оценивания
+	byCourse := make(map[uint]*StudentGrades)
+	var courseIDs []uint
+	for _, g := range grades {
+		item, ok := itemsByID[g.GradeItemID]
+		if !ok {
+			return nil, pkg.ErrGradeItemNotFound
+		}
+		sg, ok := byCourse[item.CourseID]
+		if !ok {
+			sg = &StudentGrades{UserID: userID, CourseID: item.CourseID, Scores: map[uint]float64{}}
+			byCourse[item.CourseID] = sg
+			courseIDs = append(courseIDs, item.CourseID)
+		}
+		sg.Scores[g.GradeItemID] = g.Score
+	}
+
+	sort.Slice(courseIDs, func(i, j int) bool { return courseIDs[i] < courseIDs[j] })
+	result := make([]*StudentGrades, 0, len(courseIDs))
+	for _, courseID := range courseIDs {
+		categories, err := s.repo.FindCategoriesByCourseID(ctx, courseID)
+		if err != nil {
+			return nil, err
+		}
+		items, err := s.repo.FindItemsByCourseID(ctx, courseID)
+		if err != nil {
+			return nil, err
+		}
+		sg := byCourse[courseID]
+		sg.Items = items
+		sg.Total = computeWeightedTotal(categories, items, sg.Scores)
+		result = append(result, sg)
+	}
+	return result, nil
+}
+
+func (s *gradebookService) ExportCourseGradebook(ctx context.Context, courseID uint, format string) ([]byte, error) {
+	gradebook, err := s.GetCourseGradebook(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case GradebookFormatCSV:
+		return exportGradebookCSV(gradebook)
+	case GradebookFormatXLSX:
+		return exportGradebookXLSX(gradebook)
+	default:
+		return nil, pkg.ErrInvalidInput
+	}
+}
+
+func (s *gradebookService) saveGrade(ctx context.Context, item *entities.GradeItem, grade *entities.Grade, userID uuid.UUID, score float64, overridden bool, changedBy *uuid.UUID, reason string) (*entities.Grade, error) {
+	audit := &entities.GradeAudit{
+		GradeItemID: item.ID,
+		UserID:      userID,
+		NewScore:    score,
+		ChangedBy:   changedBy,
+		Reason:      reason,
+	}
+	if grade == nil {
+		grade = &entities.Grade{GradeItemID: item.ID, UserID: userID}
+	} else {
+		oldScore := grade.Score
+		audit.OldScore = &oldScore
+	}
+	grade.Score = score
+	grade.Overridden = overridden
+
+	if err := s.repo.SaveGradeWithAudit(ctx, grade, audit); err != nil {
+		return nil, err
+	}
+	return grade, nil
+}
+
+func (s *gradebookService) validateCategory(ctx context.Context, category *entities.GradeCategory) error {
+	if strings.TrimSpace(category.Name) == "" || category.Weight < 0 {
+		return pkg.ErrInvalidInput
+	}
+	return s.ensureCourse(ctx, category.CourseID)
+}
+
+func (s *gradebookService) validateItem(ctx context.Context, item *entities.GradeItem) error {
+	if strings.TrimSpace(item.Name) == "" || item.MaxScore <= 0 {
+		return pkg.ErrInvalidInput
+	}
+	if err := s.ensureCourse(ctx, item.CourseID); err != nil {
+		return err
+	}
+	if item.CategoryID != nil {
+		category, err := s.getCategory(ctx, *item.CategoryID)
+		if err != nil {
+			return err
+		}
+		if category.CourseID != item.CourseID {
+			return pkg.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+func (s *gradebookService) ensureCourse(ctx context.Context, courseID uint) error {
+	_, err := s.courseRepo.FindByID(ctx, courseID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkg.ErrCourseNotFound
+	}
+	return err
+}
+
+func (s *gradebookService) getCategory(ctx context.Context, categoryID uint) (*entities.GradeCategory, error) {
+	category, err := s.repo.FindCategoryByID(ctx, categoryID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkg.ErrGradeCategoryNotFound
+	}
+	return category, err
+}
+
+func (s *gradebookService) getItem(ctx context.Context, itemID uint) (*entities.GradeItem, error) {
+	item, err := s.repo.FindItemByID(ctx, itemID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkg.ErrGradeItemNotFound
+	}
+	return item, err
+}
+
+// computeWeightedTotal считает итог в процентах. Внутри категории процент — это сумма баллов,
+// делённая на сумму максимумов оценённых элементов; категории без оценок не учитываются,
+// а веса оставшихся нормируются. Если в курсе заведены категории, элементы без категории
+// в итог не входят: их вес преподаватель не задавал. Курс без категорий считается
+// одной категорией из всех элементов.
+func computeWeightedTotal(categories []*entities.GradeCategory, items []*entities.GradeItem, scores map[uint]float64) float64 {
+	weights := map[uint]float64{}
+	for _, c := range categories {
+		weights[c.ID] = c.Weight
+	}
+	if len(categories) == 0 {
+		weights[0] = 1
+	}
+
+	earned := map[uint]float64{}
+	possible := map[uint]float64{}
+	for _, item := range items {
+		score, ok := scores[item.ID]
+		if !ok {
+			continue
+		}
+		var categoryID uint
+		if item.CategoryID != nil {
+			categoryID = *item.CategoryID
+		}
+		earned[categoryID] += score
+		possible[categoryID] += item.MaxScore
+	}
+
+	var total, totalWeight float64
+	for categoryID, max := range possible {
+		if max <= 0 {
+			continue
+		}
+		weight, ok := weights[categoryID]
+		if !ok {
+			continue
+		}
+		total += weight * earned[categoryID] / max
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+	return total / totalWeight * 100
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+func TestComputeWeightedTotal(t *testing.T) {
+	categories := []*entities.GradeCategory{
+		{ID: 1, Name: "Homework", Weight: 0.4},
+		{ID: 2, Name: "Exam", Weight: 0.6},
+	}
+	items := []*entities.GradeItem{
+		{ID: 10, CategoryID: uintPtr(1), MaxScore: 10},
+		{ID: 11, CategoryID: uintPtr(1), MaxScore: 10},
+		{ID: 20, CategoryID: uintPtr(2), MaxScore: 100},
+	}
+
+	t.Run("all categories graded", func(t *testing.T) {
+		total := computeWeightedTotal(categories, items, map[uint]float64{10: 10, 11: 5, 20: 50})
+		// homework 15/20 = 75%, exam 50%: 0.4*75 + 0.6*50 = 60
+		assert.InDelta(t, 60.0, total, 0.001)
+	})
+
+	t.Run("ungraded category is skipped", func(t *testing.T) {
+		total := computeWeightedTotal(categories, items, map[uint]float64{10: 8})
+		assert.InDelta(t, 80.0, total, 0.001)
+	})
+
+	t.Run("no grades", func(t *testing.T) {
+		assert.Zero(t, computeWeightedTotal(categories, items, map[uint]float64{}))
+	})
+
+	t.Run("uncategorized items", func(t *testing.T) {
+		total := computeWeightedTotal(nil, []*entities.GradeItem{{ID: 1, MaxScore: 4}}, map[uint]float64{1: 3})
+		assert.InDelta(t, 75.0, total, 0.001)
+	})
+
+	t.Run("uncategorized items are excluded when categories exist", func(t *testing.T) {
+		withExtra := append(items, &entities.GradeItem{ID: 30, MaxScore: 10})
+		total := computeWeightedTotal(categories, withExtra, map[uint]float64{10: 10, 11: 5, 20: 50, 30: 0})
+		assert.InDelta(t, 60.0, total, 0.001)
+	})
+}
+
+func TestGradebookService_PostScore(t *testing.T) {
+	userID := uuid.New()
+	item := &entities.GradeItem{ID: 1, CourseID: 1, MaxScore: 10}
+
+	t.Run("new grade is saved with audit", func(t *testing.T) {
+		mockRepo := new(mocks.GradebookRepository)
+		mockRepo.On("FindItemByID", mock.Anything, uint(1)).Return(item, nil)
+		mockRepo.On("FindGrade", mock.Anything, uint(1), userID).Return(nil, repo.ErrNotFound)
+		mockRepo.On("SaveGradeWithAudit", mock.Anything,
+			mock.MatchedBy(func(g *entities.Grade) bool { return g.Score == 7 && !g.Overridden }),
+			mock.MatchedBy(func(a *entities.GradeAudit) bool { return a.OldScore == nil && a.NewScore == 7 }),
+		).Return(nil)
+
+		service := NewGradebookService(mockRepo, new(mocks.CourseRepository))
+		err := service.PostScore(context.Background(), 1, userID, 7)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("overridden grade is kept", func(t *testing.T) {
+		mockRepo := new(mocks.GradebookRepository)
+		mockRepo.On("FindItemByID", mock.Anything, uint(1)).Return(item, nil)
+		mockRepo.On("FindGrade", mock.Anything, uint(1), userID).Return(&entities.Grade{Score: 9, Overridden: true}, nil)
+
+		service := NewGradebookService(mockRepo, new(mocks.CourseRepository))
+		err := service.PostScore(context.Background(), 1, userID, 3)
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "SaveGradeWithAudit", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("score out of range", func(t *testing.T) {
+		mockRepo := new(mocks.GradebookRepository)
+		mockRepo.On("FindItemByID", mock.Anything, uint(1)).Return(item, nil)
+
+		service := NewGradebookService(mockRepo, new(mocks.CourseRepository))
+		err := service.PostScore(context.Background(), 1, userID, 11)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+
+	t.Run("item not found", func(t *testing.T) {
+		mockRepo := new(mocks.GradebookRepository)
+		mockRepo.On("FindItemByID", mock.Anything, uint(1)).Return(nil, repo.ErrNotFound)
+
+		service := NewGradebookService(mockRepo, new(mocks.CourseRepository))
+		err := service.PostScore(context.Background(), 1, userID, 5)
+
+		assert.ErrorIs(t, err, pkg.ErrGradeItemNotFound)
+	})
+}
+
+func TestGradebookService_OverrideScore(t *testing.T) {
+	userID := uuid.New()
+	teacherID := uuid.New()
+	item := &entities.GradeItem{ID: 1, CourseID: 1, MaxScore: 10}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(mocks.GradebookRepository)
+		mockRepo.On("FindItemByID", mock.Anything, uint(1)).Return(item, nil)
+		mockRepo.On("FindGrade", mock.Anything, uint(1), userID).Return(&entities.Grade{ID: 5, Score: 4}, nil)
+		mockRepo.On("SaveGradeWithAudit", mock.Anything, mock.Anything,
+			mock.MatchedBy(func(a *entities.GradeAudit) bool {
+				return *a.OldScore == 4 && a.NewScore == 6 && *a.ChangedBy == teacherID && a.Reason == "appeal"
+			}),
+		).Return(nil)
+
+		service := NewGradebookService(mockRepo, new(mocks.CourseRepository))
+		grade, err := service.OverrideScore(context.Background(), teacherID, 1, userID, 6, "appeal")
+
+		assert.NoError(t, err)
+		assert.True(t, grade.Overridden)
+		assert.Equal(t, 6.0, grade.Score)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("reason is required", func(t *testing.T) {
+		mockRepo := new(mocks.GradebookRepository)
+		mockRepo.On("FindItemByID", mock.Anything, uint(1)).Return(item, nil)
+
+		service := NewGradebookService(mockRepo, new(mocks.CourseRepository))
+		_, err := service.OverrideScore(context.Background(), teacherID, 1, userID, 6, " ")
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+}
+
+func TestGradebookService_GetUserGrades(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("items are loaded in one query", func(t *testing.T) {
+		mockRepo := new(mocks.GradebookRepository)
+		items := []*entities.GradeItem{
+			{ID: 1, CourseID: 2, MaxScore: 10},
+			{ID: 2, CourseID: 2, MaxScore: 10},
+		}
+		mockRepo.On("FindGradesByUserID", mock.Anything, userID).Return([]*entities.Grade{
+			{GradeItemID: 1, UserID: userID, Score: 4},
+			{GradeItemID: 2, UserID: userID, Score: 8},
+		}, nil)
+		mockRepo.On("FindItemsByIDs", mock.Anything, []uint{1, 2}).Return(items, nil).Once()
+		mockRepo.On("FindCategoriesByCourseID", mock.Anything, uint(2)).Return([]*entities.GradeCategory{}, nil)
+		mockRepo.On("FindItemsByCourseID", mock.Anything, uint(2)).Return(items, nil)
+
+		service := NewGradebookService(mockRepo, new(mocks.CourseRepository))
+		result, err := service.GetUserGrades(context.Background(), userID)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, uint(2), result[0].CourseID)
+		assert.InDelta(t, 60.0, result[0].Total, 0.001)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "FindItemByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGradebookService_ExportCourseGradebook(t *testing.T) {
+	userID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	mockRepo := new(mocks.GradebookRepository)
+	mockCourseRepo := new(mocks.CourseRepository)
+	mockCourseRepo.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1}, nil)
+	mockRepo.On("FindCategoriesByCourseID", mock.Anything, uint(1)).Return([]*entities.GradeCategory{}, nil)
+	mockRepo.On("FindItemsByCourseID", mock.Anything, uint(1)).Return([]*entities.GradeItem{
+		{ID: 1, Name: "Quiz", MaxScore: 10},
+		{ID: 2, Name: "Essay", MaxScore: 10},
+	}, nil)
+	mockRepo.On("FindGradesByCourseID", mock.Anything, uint(1)).Return([]*entities.Grade{
+		{GradeItemID: 1, UserID: userID, Score: 5},
+	}, nil)
+
+	service := NewGradebookService(mockRepo, mockCourseRepo)
+
+	t.Run("csv", func(t *testing.T) {
+		data, err := service.ExportCourseGradebook(context.Background(), 1, GradebookFormatCSV)
+
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		assert.Equal(t, "user_id,Quiz,Essay,total", lines[0])
+		assert.Equal(t, userID.String()+",5.00,,50.00", lines[1])
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		data, err := service.ExportCourseGradebook(context.Background(), 1, GradebookFormatXLSX)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PK", string(data[:2]))
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := service.ExportCourseGradebook(context.Background(), 1, "pdf")
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+}
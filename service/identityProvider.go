@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"lms-system-internship/pkg"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// Tokens — пара токенов, выданная провайдером при входе или обновлении.
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int // время жизни access-токена в секундах
+	TokenType    string
+}
+
+// Session — активная сессия пользователя у провайдера.
+type Session struct {
+	ID           string
+	IPAddress    string
+	StartedAt    time.Time
+	LastAccessAt time.Time
+	Clients      []string
+}
+
+// IdentityProvider проверяет токены и выдаёт их по логину и паролю.
+// Ошибки — pkg.AppError: ErrInvalidCredentials, ErrInvalidToken или ErrIdentityUnavailable.
+type IdentityProvider interface {
+	// Verify проверяет access-токен и возвращает его владельца.
+	Verify(ctx context.Context, accessToken string) (*pkg.Principal, error)
+	Login(ctx context.Context, username, password string) (*Tokens, error)
+	Refresh(ctx context.Context, refreshToken string) (*Tokens, error)
+	// Logout завершает сессию, к которой относится refresh-токен.
+	Logout(ctx context.Context, refreshToken string) error
+	// Sessions возвращает активные сессии пользователя.
+	Sessions(ctx context.Context, userID uuid.UUID) ([]Session, error)
+	// RevokeSession завершает сессию пользователя; чужая или несуществующая сессия —
+	// pkg.ErrSessionNotFound.
+	RevokeSession(ctx context.Context, userID uuid.UUID, sessionID string) error
+	// RevokeAllSessions завершает все сессии пользователя, например после смены ролей.
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+	// Check — проверка готовности: провайдер может проверять токены.
+	Check(ctx context.Context) error
+}
+
+// principalFromClaims читает пользователя из claims в формате Keycloak: sub, preferred_username, sid,
+// realm_access.roles, resource_access.<client>.roles и groups. Этот же формат выпускает
+// локальный провайдер.
+func principalFromClaims(claims jwt.MapClaims) *pkg.Principal {
+	principal := &pkg.Principal{
+		Roles:       []string{},
+		ClientRoles: map[string][]string{},
+		Groups:      claimStrings(claims["groups"]),
+	}
+	if username, ok := claims["preferred_username"].(string); ok {
+		principal.Username = username
+	}
+	if sid, ok := claims["sid"].(string); ok {
+		principal.SessionID = sid
+	}
+	if sub, ok := claims["sub"].(string); ok {
+		if id, err := uuid.Parse(sub); err == nil {
+			principal.ID = id
+		}
+	}
+	if realmAccess, ok := claims["realm_access"].(map[string]interface{}); ok {
+		principal.Roles = claimStrings(realmAccess["roles"])
+	}
+	if resourceAccess, ok := claims["resource_access"].(map[string]interface{}); ok {
+		for client, access := range resourceAccess {
+			if access, ok := access.(map[string]interface{}); ok {
+				principal.ClientRoles[client] = claimStrings(access["roles"])
+			}
+		}
+	}
+	return principal
+}
+
+// claimStrings возвращает строки из claim-массива, пропуская значения другого типа.
+func claimStrings(raw interface{}) []string {
+	values := []string{}
+	list, _ := raw.([]interface{})
+	for _, item := range list {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
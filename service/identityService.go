@@ -0,0 +1,404 @@
+package service
+
+import (
+	"context"
+	"lms-system-internship/pkg"
+	"net/http"
+	"time"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/google/uuid"
+)
+
+// NewUser — данные учётной записи, которую создаёт администратор.
+type NewUser struct {
+	Username  string
+	Email     string
+	FirstName string
+	LastName  string
+	Password  string
+	// TemporaryPassword — пользователь должен сменить пароль при первом входе.
+	TemporaryPassword bool
+	// RequireEmailVerification — учётная запись создаётся выключенной, с признаком ожидания
+	// подтверждения почты, и включается ConfirmEmail.
+	RequireEmailVerification bool
+	Roles                    []string
+}
+
+// ProfileUpdate — изменения профиля; пустые поля не меняются.
+type ProfileUpdate struct {
+	Email     string
+	FirstName string
+	LastName  string
+	Password  string
+}
+
+// UserAccount — учётная запись у провайдера идентификации. Roles заполняется только в GetUser.
+type UserAccount struct {
+	ID        uuid.UUID
+	Username  string
+	Email     string
+	FirstName string
+	LastName  string
+	Enabled   bool
+	// EmailVerified — пользователь подтвердил почту или её подтвердил администратор.
+	EmailVerified bool
+	// PendingVerification — учётная запись создана с RequireEmailVerification и ещё не включена ни
+	// подтверждением почты, ни администратором. Отключённая запись без этого признака отключена администратором.
+	PendingVerification bool
+	CreatedAt           time.Time
+	Roles               []string
+}
+
+// IdentityService управляет учётными записями у провайдера идентификации.
+type IdentityService interface {
+	// RegisterUser создаёт пользователя с паролем и ролями realm и возвращает его ID.
+	RegisterUser(ctx context.Context, user NewUser) (uuid.UUID, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, update ProfileUpdate) error
+	// ReplaceRoles оставляет пользователю ровно перечисленные роли realm.
+	ReplaceRoles(ctx context.Context, userID uuid.UUID, roles []string) error
+	// ListUsers возвращает страницу из max учётных записей, начиная с first, в порядке провайдера;
+	// more == false — страница последняя. Записи с ID не в формате UUID пропускаются.
+	ListUsers(ctx context.Context, first, max int) (accounts []UserAccount, more bool, err error)
+	// GetUser возвращает учётную запись вместе с ролями realm.
+	GetUser(ctx context.Context, userID uuid.UUID) (*UserAccount, error)
+	// SetEnabled включает или отключает учётную запись и снимает признак ожидания подтверждения почты:
+	// решение администратора ConfirmEmail не отменяет.
+	SetEnabled(ctx context.Context, userID uuid.UUID, enabled bool) error
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	// FindUserByEmail ищет учётную запись по точному совпадению email; нет такой — pkg.ErrUserNotFound.
+	FindUserByEmail(ctx context.Context, email string) (*UserAccount, error)
+	// ConfirmEmail отмечает почту подтверждённой. Учётную запись он включает, только если она ждёт
+	// подтверждения (PendingVerification), поэтому отключённую администратором не включит.
+	ConfirmEmail(ctx context.Context, userID uuid.UUID) error
+}
+
+// pendingVerificationAttribute — атрибут пользователя Keycloak, которым RegisterUser отмечает
+// учётную запись, выключенную до подтверждения почты.
+const pendingVerificationAttribute = "lms_pending_email_verification"
+
+type keycloakIdentityService struct {
+	admin *KeycloakAdmin
+}
+
+func NewKeycloakIdentityService(admin *KeycloakAdmin) IdentityService {
+	return &keycloakIdentityService{admin: admin}
+}
+
+// RegisterUser сначала проверяет роли, чтобы неизвестная роль не оставила созданного
+// пользователя без ролей. Если пароль или роли назначить не удалось, созданная учётная
+// запись удаляется, чтобы повторная попытка не упёрлась в занятый логин.
+func (s *keycloakIdentityService) RegisterUser(ctx context.Context, user NewUser) (uuid.UUID, error) {
+	roles, err := s.realmRoles(ctx, user.Roles)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	created := gocloak.User{
+		Username:      gocloak.StringP(user.Username),
+		Email:         gocloak.StringP(user.Email),
+		FirstName:     gocloak.StringP(user.FirstName),
+		LastName:      gocloak.StringP(user.LastName),
+		Enabled:       gocloak.BoolP(!user.RequireEmailVerification),
+		EmailVerified: gocloak.BoolP(!user.RequireEmailVerification),
+	}
+	if user.RequireEmailVerification {
+		created.Attributes = &map[string][]string{pendingVerificationAttribute: {"true"}}
+	}
+	var userID string
+	err = s.admin.Do(ctx, "create_user", false, nil, func(client *gocloak.GoCloak, token string) error {
+		id, err := client.CreateUser(ctx, token, s.admin.Realm(), created)
+		if apiErrorStatus(err) == http.StatusConflict {
+			return pkg.ErrUserAlreadyExists.Wrap(err)
+		}
+		userID = id
+		return err
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, pkg.ErrIdentityUnavailable.Wrap(err)
+	}
+
+	err = s.admin.Do(ctx, "set_password", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		return client.SetPassword(ctx, token, userID, s.admin.Realm(), user.Password, user.TemporaryPassword)
+	})
+	if err == nil && len(roles) > 0 {
+		err = s.admin.Do(ctx, "add_roles", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+			return client.AddRealmRoleToUser(ctx, token, s.admin.Realm(), userID, roles)
+		})
+	}
+	if err != nil {
+		s.deleteIncompleteUser(ctx, id)
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+// deleteIncompleteUser удаляет учётную запись, которую не удалось довести до конца регистрации.
+// Удаление не зависит от отмены ctx: запрос мог прерваться как раз посреди регистрации.
+func (s *keycloakIdentityService) deleteIncompleteUser(ctx context.Context, userID uuid.UUID) {
+	if err := s.DeleteUser(context.WithoutCancel(ctx), userID); err != nil {
+		pkg.LoggerFromContext(ctx).WithError(err).WithField("target_user_id", userID).Error("Failed to delete partially registered user")
+	}
+}
+
+func (s *keycloakIdentityService) UpdateProfile(ctx context.Context, userID uuid.UUID, update ProfileUpdate) error {
+	var user *gocloak.User
+	err := s.admin.Do(ctx, "get_user", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		var err error
+		user, err = client.GetUserByID(ctx, token, s.admin.Realm(), userID.String())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if update.Email != "" {
+		user.Email = &update.Email
+	}
+	if update.FirstName != "" {
+		user.FirstName = &update.FirstName
+	}
+	if update.LastName != "" {
+		user.LastName = &update.LastName
+	}
+	err = s.admin.Do(ctx, "update_user", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		return client.UpdateUser(ctx, token, s.admin.Realm(), *user)
+	})
+	if err != nil {
+		return err
+	}
+
+	if update.Password != "" {
+		return s.admin.Do(ctx, "set_password", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+			return client.SetPassword(ctx, token, userID.String(), s.admin.Realm(), update.Password, false)
+		})
+	}
+	return nil
+}
+
+func (s *keycloakIdentityService) ReplaceRoles(ctx context.Context, userID uuid.UUID, roles []string) error {
+	wanted, err := s.realmRoles(ctx, roles)
+	if err != nil {
+		return err
+	}
+
+	var current []*gocloak.Role
+	err = s.admin.Do(ctx, "get_user_roles", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		current, err = client.GetRealmRolesByUserID(ctx, token, s.admin.Realm(), userID.String())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Удаляем роли, которых нет в списке новых
+	keep := map[string]bool{}
+	for _, role := range roles {
+		keep[role] = true
+	}
+	var toRemove []gocloak.Role
+	for _, role := range current {
+		if role != nil && role.Name != nil && !keep[*role.Name] {
+			toRemove = append(toRemove, *role)
+		}
+	}
+	if len(toRemove) > 0 {
+		err = s.admin.Do(ctx, "remove_roles", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+			return client.DeleteRealmRoleFromUser(ctx, token, s.admin.Realm(), userID.String(), toRemove)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(wanted) > 0 {
+		return s.admin.Do(ctx, "add_roles", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+			return client.AddRealmRoleToUser(ctx, token, s.admin.Realm(), userID.String(), wanted)
+		})
+	}
+	return nil
+}
+
+func (s *keycloakIdentityService) ListUsers(ctx context.Context, first, max int) ([]UserAccount, bool, error) {
+	var users []*gocloak.User
+	err := s.admin.Do(ctx, "list_users", true, nil, func(client *gocloak.GoCloak, token string) error {
+		var err error
+		users, err = client.GetUsers(ctx, token, s.admin.Realm(), gocloak.GetUsersParams{
+			First:               gocloak.IntP(first),
+			Max:                 gocloak.IntP(max),
+			BriefRepresentation: gocloak.BoolP(true),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	accounts := make([]UserAccount, 0, len(users))
+	for _, user := range users {
+		// Учётные записи с ID не в формате UUID (например, из внешних федераций) в LMS не используются
+		if account, ok := userAccount(user); ok {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, len(users) == max, nil
+}
+
+func (s *keycloakIdentityService) GetUser(ctx context.Context, userID uuid.UUID) (*UserAccount, error) {
+	var user *gocloak.User
+	err := s.admin.Do(ctx, "get_user", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		var err error
+		user, err = client.GetUserByID(ctx, token, s.admin.Realm(), userID.String())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	account, ok := userAccount(user)
+	if !ok {
+		return nil, pkg.ErrUserNotFound
+	}
+
+	var roles []*gocloak.Role
+	err = s.admin.Do(ctx, "get_user_roles", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		roles, err = client.GetRealmRolesByUserID(ctx, token, s.admin.Realm(), userID.String())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	account.Roles = []string{}
+	for _, role := range roles {
+		if role != nil && role.Name != nil {
+			account.Roles = append(account.Roles, *role.Name)
+		}
+	}
+	return &account, nil
+}
+
+func (s *keycloakIdentityService) SetEnabled(ctx context.Context, userID uuid.UUID, enabled bool) error {
+	var user *gocloak.User
+	err := s.admin.Do(ctx, "get_user", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		var err error
+		user, err = client.GetUserByID(ctx, token, s.admin.Realm(), userID.String())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	user.Enabled = &enabled
+	clearPendingVerification(user)
+	return s.admin.Do(ctx, "update_user", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		return client.UpdateUser(ctx, token, s.admin.Realm(), *user)
+	})
+}
+
+func (s *keycloakIdentityService) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	return s.admin.Do(ctx, "delete_user", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		return client.DeleteUser(ctx, token, s.admin.Realm(), userID.String())
+	})
+}
+
+func (s *keycloakIdentityService) FindUserByEmail(ctx context.Context, email string) (*UserAccount, error) {
+	var users []*gocloak.User
+	err := s.admin.Do(ctx, "find_user_by_email", true, nil, func(client *gocloak.GoCloak, token string) error {
+		var err error
+		users, err = client.GetUsers(ctx, token, s.admin.Realm(), gocloak.GetUsersParams{
+			Email: gocloak.StringP(email),
+			Exact: gocloak.BoolP(true),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if account, ok := userAccount(user); ok {
+			return &account, nil
+		}
+	}
+	return nil, pkg.ErrUserNotFound
+}
+
+func (s *keycloakIdentityService) ConfirmEmail(ctx context.Context, userID uuid.UUID) error {
+	var user *gocloak.User
+	err := s.admin.Do(ctx, "get_user", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		var err error
+		user, err = client.GetUserByID(ctx, token, s.admin.Realm(), userID.String())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	user.EmailVerified = gocloak.BoolP(true)
+	if pendingVerification(user) {
+		user.Enabled = gocloak.BoolP(true)
+		clearPendingVerification(user)
+	}
+	return s.admin.Do(ctx, "update_user", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		return client.UpdateUser(ctx, token, s.admin.Realm(), *user)
+	})
+}
+
+func pendingVerification(user *gocloak.User) bool {
+	if user.Attributes == nil {
+		return false
+	}
+	_, ok := (*user.Attributes)[pendingVerificationAttribute]
+	return ok
+}
+
+// clearPendingVerification убирает признак ожидания подтверждения почты. Keycloak заменяет
+// атрибуты целиком, поэтому остальные атрибуты сохраняются в том же map.
+func clearPendingVerification(user *gocloak.User) {
+	if user.Attributes != nil {
+		delete(*user.Attributes, pendingVerificationAttribute)
+	}
+}
+
+// userAccount переводит пользователя Keycloak в UserAccount; false — ID не UUID.
+func userAccount(user *gocloak.User) (UserAccount, bool) {
+	if user == nil {
+		return UserAccount{}, false
+	}
+	id, err := uuid.Parse(gocloak.PString(user.ID))
+	if err != nil {
+		return UserAccount{}, false
+	}
+	account := UserAccount{
+		ID:                  id,
+		Username:            gocloak.PString(user.Username),
+		Email:               gocloak.PString(user.Email),
+		FirstName:           gocloak.PString(user.FirstName),
+		LastName:            gocloak.PString(user.LastName),
+		Enabled:             gocloak.PBool(user.Enabled),
+		EmailVerified:       gocloak.PBool(user.EmailVerified),
+		PendingVerification: !gocloak.PBool(user.Enabled) && pendingVerification(user),
+	}
+	if user.CreatedTimestamp != nil {
+		account.CreatedAt = time.UnixMilli(*user.CreatedTimestamp)
+	}
+	return account, true
+}
+
+// realmRoles находит роли realm по именам; неизвестная роль — pkg.ErrInvalidRole.
+func (s *keycloakIdentityService) realmRoles(ctx context.Context, names []string) ([]gocloak.Role, error) {
+	roles := make([]gocloak.Role, 0, len(names))
+	for _, name := range names {
+		var role *gocloak.Role
+		err := s.admin.Do(ctx, "get_role", true, pkg.ErrInvalidRole.WithDetails(map[string]any{"role": name}), func(client *gocloak.GoCloak, token string) error {
+			var err error
+			role, err = client.GetRealmRole(ctx, token, s.admin.Realm(), name)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, *role)
+	}
+	return roles, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lms-system-internship/metrics"
+	"lms-system-internship/pkg"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/MicahParks/keyfunc"
+)
+
+const maxJWKSRetryDelay = 30 * time.Second
+
+// JWKSSource загружает ключи Keycloak в фоне, чтобы сервис мог стартовать, пока Keycloak
+// ещё поднимается. До первой успешной загрузки защищённые маршруты отвечают
+// pkg.ErrIdentityUnavailable, а проверка готовности — ошибкой.
+type JWKSSource struct {
+	url             string
+	refreshInterval time.Duration
+
+	mu          sync.RWMutex
+	jwks        *keyfunc.JWKS
+	refreshedAt time.Time
+	lastErr     error
+}
+
+func NewJWKSSource(url string, refreshInterval time.Duration) *JWKSSource {
+	return &JWKSSource{url: url, refreshInterval: refreshInterval}
+}
+
+// Start загружает JWKS, повторяя попытки с растущей паузой, пока не получится или не будет
+// отменён ctx. Отмена ctx останавливает и фоновое обновление ключей.
+func (s *JWKSSource) Start(ctx context.Context) {
+	go func() {
+		delay := time.Second
+		for attempt := 1; ; attempt++ {
+			jwks, err := keyfunc.Get(s.url, s.options(ctx))
+			if err == nil {
+				s.mu.Lock()
+				s.jwks = jwks
+				s.mu.Unlock()
+				pkg.Logger.WithField("attempt", attempt).Info("Loaded Keycloak JWKS")
+				return
+			}
+			s.recordFailure(err)
+			pkg.Logger.WithError(err).WithField("attempt", attempt).Warn("Failed to load Keycloak JWKS, retrying")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, maxJWKSRetryDelay)
+		}
+	}()
+}
+
+func (s *JWKSSource) options(ctx context.Context) keyfunc.Options {
+	return keyfunc.Options{
+		Ctx:             ctx,
+		RefreshInterval: s.refreshInterval,
+		RefreshErrorHandler: func(err error) {
+			metrics.JWKSRefreshFailures.Inc()
+			s.recordFailure(err)
+			pkg.Logger.WithError(err).Warn("JWKS refresh failed")
+		},
+		ResponseExtractor: func(ctx context.Context, resp *http.Response) (json.RawMessage, error) {
+			raw, err := keyfunc.ResponseExtractorStatusOK(ctx, resp)
+			if err == nil {
+				s.mu.Lock()
+				s.refreshedAt = time.Now()
+				s.lastErr = nil
+				s.mu.Unlock()
+			}
+			return raw, err
+		},
+	}
+}
+
+func (s *JWKSSource) recordFailure(err error) {
+	s.mu.Lock()
+	s.lastErr = err
+	s.mu.Unlock()
+}
+
+// JWKS возвращает загруженные ключи или nil, если загрузка ещё не удалась.
+func (s *JWKSSource) JWKS() *keyfunc.JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.jwks
+}
+
+// Check — проверка готовности: ключи загружены и обновлялись не дольше двух интервалов назад.
+func (s *JWKSSource) Check(context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.jwks == nil {
+		if s.lastErr != nil {
+			return fmt.Errorf("JWKS not loaded: %w", s.lastErr)
+		}
+		return errors.New("JWKS not loaded yet")
+	}
+	if age := time.Since(s.refreshedAt); s.refreshInterval > 0 && age > 2*s.refreshInterval {
+		return fmt.Errorf("JWKS is stale: last refreshed %s ago: %v", age.Round(time.Second), s.lastErr)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"lms-system-internship/metrics"
+	"lms-system-internship/pkg"
+	"lms-system-internship/tracing"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Nerzal/gocloak/v13"
+)
+
+const (
+	// keycloakAdminAttempts — сколько раз повторять вызов Admin API при сбое сети или 5xx.
+	keycloakAdminAttempts = 3
+	// adminTokenMargin — за сколько до истечения токен администратора считается просроченным,
+	// чтобы он не истёк по дороге в Keycloak.
+	adminTokenMargin = 30 * time.Second
+)
+
+// KeycloakAdmin — клиент Admin API Keycloak с общим токеном администратора. Токен получается
+// при первом вызове, обновляется по refresh-токену до истечения, а при отказе 401 — заново.
+type KeycloakAdmin struct {
+	client   *gocloak.GoCloak
+	realm    string
+	username string
+	password string
+	backoff  time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	token     *gocloak.JWT
+	expiresAt time.Time
+	refreshBy time.Time
+}
+
+// NewKeycloakAdmin создаёт клиент для realm; администратор входит в этот же realm.
+func NewKeycloakAdmin(baseURL, realm, username, password string) *KeycloakAdmin {
+	client := gocloak.NewClient(baseURL)
+	// Вызовы Admin API попадают в трассировку.
+	client.RestyClient().SetTransport(tracing.HTTPTransport())
+	return &KeycloakAdmin{
+		client:   client,
+		realm:    realm,
+		username: username,
+		password: password,
+		backoff:  200 * time.Millisecond,
+		now:      time.Now,
+	}
+}
+
+// Realm — realm, с которым работает клиент.
+func (a *KeycloakAdmin) Realm() string { return a.realm }
+
+// Do выполняет fn с токеном администратора и записывает длительность в метрики с меткой operation.
+// Для idempotent-операций сбои сети и ответы 5xx повторяются с растущей паузой; остальные после
+// такого сбоя не повторяются, потому что запрос мог быть выполнен (например, пользователь уже
+// создан). Ответ 401 повторяется с новым токеном всегда. Ответ 404 возвращается как notFound,
+// остальные ошибки — как pkg.ErrIdentityUnavailable. Ошибки *pkg.AppError из fn возвращаются как есть.
+func (a *KeycloakAdmin) Do(ctx context.Context, operation string, idempotent bool, notFound *pkg.AppError, fn func(client *gocloak.GoCloak, token string) error) error {
+	start := time.Now()
+	outcome := "error"
+	defer func() {
+		metrics.KeycloakRequestDuration.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+	}()
+
+	delay := a.backoff
+	var err error
+	for attempt := 1; attempt <= keycloakAdminAttempts; attempt++ {
+		var token string
+		sent := false
+		token, err = a.accessToken(ctx)
+		if err == nil {
+			sent = true
+			err = fn(a.client, token)
+		}
+		if err == nil {
+			outcome = "success"
+			return nil
+		}
+
+		var appErr *pkg.AppError
+		if errors.As(err, &appErr) {
+			outcome = "rejected"
+			return err
+		}
+		status := apiErrorStatus(err)
+		if status == http.StatusUnauthorized {
+			a.invalidate()
+		} else if status >= 400 && status < 500 {
+			outcome = "rejected"
+			if status == http.StatusNotFound && notFound != nil {
+				return notFound.Wrap(err)
+			}
+			return pkg.ErrIdentityUnavailable.Wrap(err)
+		}
+		if attempt == keycloakAdminAttempts || (sent && !idempotent && status != http.StatusUnauthorized) {
+			break
+		}
+
+		pkg.LoggerFromContext(ctx).WithError(err).WithField("operation", operation).WithField("attempt", attempt).Warn("Keycloak admin call failed, retrying")
+		select {
+		case <-ctx.Done():
+			return pkg.ErrIdentityUnavailable.Wrap(ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	return pkg.ErrIdentityUnavailable.Wrap(err)
+}
+
+// accessToken возвращает действующий токен администратора, при необходимости обновляя его.
+func (a *KeycloakAdmin) accessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	if a.token != nil && now.Before(a.expiresAt) {
+		return a.token.AccessToken, nil
+	}
+
+	var token *gocloak.JWT
+	var err error
+	if a.token != nil && a.token.RefreshToken != "" && now.Before(a.refreshBy) {
+		token, err = a.client.RefreshToken(ctx, a.token.RefreshToken, "admin-cli", "", a.realm)
+	}
+	if token == nil {
+		token, err = a.client.LoginAdmin(ctx, a.username, a.password, a.realm)
+	}
+	if err != nil {
+		a.token = nil
+		return "", err
+	}
+
+	a.token = token
+	a.expiresAt = now.Add(time.Duration(token.ExpiresIn)*time.Second - adminTokenMargin)
+	a.refreshBy = now.Add(time.Duration(token.RefreshExpiresIn)*time.Second - adminTokenMargin)
+	return token.AccessToken, nil
+}
+
+func (a *KeycloakAdmin) invalidate() {
+	a.mu.Lock()
+	a.token = nil
+	a.mu.Unlock()
+}
+
+// apiErrorStatus возвращает HTTP-статус ответа Keycloak или 0, если ответа не было.
+func apiErrorStatus(err error) int {
+	var apiErr *gocloak.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lms-system-internship/metrics"
+	"lms-system-internship/pkg"
+	"lms-system-internship/tracing"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// KeycloakConfig — настройки провайдера Keycloak.
+type KeycloakConfig struct {
+	// RealmURL — адрес realm, например http://keycloak:8080/realms/lms.
+	RealmURL     string
+	ClientID     string
+	ClientSecret string // нужен только для интроспекции
+	// Admin — клиент Admin API для работы с сессиями пользователей.
+	Admin *KeycloakAdmin
+	// KeysRefreshInterval — как часто обновлять ключи realm.
+	KeysRefreshInterval time.Duration
+	Validation          TokenValidation
+	// IntrospectionCacheTTL > 0 включает проверку каждого токена через introspection endpoint,
+	// чтобы отозванные сессии отклонялись до истечения токена. Ответ кэшируется на этот срок.
+	IntrospectionCacheTTL time.Duration
+}
+
+// keycloakIdentityProvider проверяет токены по ключам realm и получает их через
+// OpenID Connect endpoints Keycloak.
+type keycloakIdentityProvider struct {
+	cfg           KeycloakConfig
+	keys          *JWKSSource
+	client        *http.Client
+	introspection *introspectionCache
+}
+
+// NewKeycloakIdentityProvider создаёт провайдер Keycloak. Ключи загружаются и обновляются в фоне
+// до отмены ctx; пока они не загружены, Verify возвращает pkg.ErrIdentityUnavailable.
+func NewKeycloakIdentityProvider(ctx context.Context, cfg KeycloakConfig) IdentityProvider {
+	keys := NewJWKSSource(cfg.RealmURL+"/protocol/openid-connect/certs", cfg.KeysRefreshInterval)
+	keys.Start(ctx)
+	p := &keycloakIdentityProvider{
+		cfg:  cfg,
+		keys: keys,
+		// Клиент передаёт в Keycloak контекст трассировки и создаёт спан на каждый вызов.
+		client: &http.Client{Transport: tracing.HTTPTransport()},
+	}
+	if cfg.IntrospectionCacheTTL > 0 {
+		p.introspection = newIntrospectionCache(cfg.IntrospectionCacheTTL, p.introspect)
+	}
+	return p
+}
+
+// Verify проверяет подпись одним из разрешённых алгоритмов, срок действия с учётом расхождения
+// часов, издателя и получателя, а при включённой интроспекции — что сессия не отозвана.
+func (p *keycloakIdentityProvider) Verify(ctx context.Context, accessToken string) (*pkg.Principal, error) {
+	jwks := p.keys.JWKS()
+	if jwks == nil {
+		return nil, pkg.ErrIdentityUnavailable
+	}
+
+	parser := jwt.Parser{ValidMethods: p.cfg.Validation.Algorithms, SkipClaimsValidation: true}
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(accessToken, claims, jwks.Keyfunc); err != nil {
+		return nil, pkg.ErrInvalidToken.Wrap(err)
+	}
+	if err := p.cfg.Validation.validate(claims, time.Now()); err != nil {
+		return nil, pkg.ErrInvalidToken.Wrap(err)
+	}
+	// Тем же ключом realm подписывает ID- и refresh-токены, а доступ даёт только access-токен
+	if claims["typ"] != "Bearer" {
+		return nil, pkg.ErrInvalidToken.Wrap(fmt.Errorf("unexpected token type %v", claims["typ"]))
+	}
+
+	if p.introspection != nil {
+		active, err := p.introspection.active(ctx, accessToken)
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			return nil, pkg.ErrInvalidToken.Wrap(errors.New("token session is not active"))
+		}
+	}
+	return principalFromClaims(claims), nil
+}
+
+func (p *keycloakIdentityProvider) Login(ctx context.Context, username, password string) (*Tokens, error) {
+	form := url.Values{}
+	form.Set("grant_type", "password")
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("username", username)
+	form.Set("password", password)
+
+	var tokens keycloakTokens
+	if err := p.post(ctx, "login", "/protocol/openid-connect/token", form, pkg.ErrInvalidCredentials, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens.toTokens(), nil
+}
+
+func (p *keycloakIdentityProvider) Refresh(ctx context.Context, refreshToken string) (*Tokens, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("refresh_token", refreshToken)
+
+	var tokens keycloakTokens
+	if err := p.post(ctx, "refresh", "/protocol/openid-connect/token", form, pkg.ErrInvalidToken, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens.toTokens(), nil
+}
+
+func (p *keycloakIdentityProvider) Logout(ctx context.Context, refreshToken string) error {
+	form := url.Values{}
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("refresh_token", refreshToken)
+	return p.post(ctx, "logout", "/protocol/openid-connect/logout", form, pkg.ErrInvalidToken, nil)
+}
+
+func (p *keycloakIdentityProvider) Sessions(ctx context.Context, userID uuid.UUID) ([]Session, error) {
+	var found []*gocloak.UserSessionRepresentation
+	err := p.cfg.Admin.Do(ctx, "get_sessions", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		var err error
+		found, err = client.GetUserSessions(ctx, token, p.cfg.Admin.Realm(), userID.String())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]Session, 0, len(found))
+	for _, s := range found {
+		session := Session{
+			ID:           gocloak.PString(s.ID),
+			IPAddress:    gocloak.PString(s.IPAddress),
+			StartedAt:    time.UnixMilli(gocloak.PInt64(s.Start)),
+			LastAccessAt: time.UnixMilli(gocloak.PInt64(s.LastAccess)),
+			Clients:      []string{},
+		}
+		if s.Clients != nil {
+			for _, clientID := range *s.Clients {
+				session.Clients = append(session.Clients, clientID)
+			}
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// RevokeSession завершает сессию, только если она принадлежит userID: Admin API Keycloak
+// завершает любую сессию по ID, поэтому принадлежность проверяется по списку сессий пользователя.
+func (p *keycloakIdentityProvider) RevokeSession(ctx context.Context, userID uuid.UUID, sessionID string) error {
+	sessions, err := p.Sessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(sessions, func(s Session) bool { return s.ID == sessionID }) {
+		return pkg.ErrSessionNotFound
+	}
+	return p.cfg.Admin.Do(ctx, "logout_session", true, pkg.ErrSessionNotFound, func(client *gocloak.GoCloak, token string) error {
+		return client.LogoutUserSession(ctx, token, p.cfg.Admin.Realm(), sessionID)
+	})
+}
+
+func (p *keycloakIdentityProvider) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	return p.cfg.Admin.Do(ctx, "logout_all_sessions", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		return client.LogoutAllSessions(ctx, token, p.cfg.Admin.Realm(), userID.String())
+	})
+}
+
+func (p *keycloakIdentityProvider) Check(ctx context.Context) error {
+	return p.keys.Check(ctx)
+}
+
+// introspect спрашивает Keycloak, активна ли сессия токена. Отказ в доступе к endpoint —
+// ошибка настройки клиента, а не токена, поэтому он считается недоступностью провайдера.
+func (p *keycloakIdentityProvider) introspect(ctx context.Context, token string) (bool, error) {
+	form := url.Values{}
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("client_secret", p.cfg.ClientSecret)
+	form.Set("token", token)
+
+	var result struct {
+		Active bool `json:"active"`
+	}
+	if err := p.post(ctx, "introspect", "/protocol/openid-connect/token/introspect", form, pkg.ErrIdentityUnavailable, &result); err != nil {
+		return false, err
+	}
+	return result.Active, nil
+}
+
+// keycloakTokens — ответ token endpoint Keycloak.
+type keycloakTokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+}
+
+func (t *keycloakTokens) toTokens() *Tokens {
+	return &Tokens{AccessToken: t.AccessToken, RefreshToken: t.RefreshToken, ExpiresIn: t.ExpiresIn, TokenType: t.TokenType}
+}
+
+// post отправляет форму в endpoint realm и записывает длительность вызова в метрики с меткой
+// operation. Ответ разбирается в out, если он не nil. Отказ Keycloak переводится в ошибку API
+// через endpointError.
+func (p *keycloakIdentityProvider) post(ctx context.Context, operation, path string, form url.Values, rejected *pkg.AppError, out any) error {
+	start := time.Now()
+	outcome := "error"
+	defer func() {
+		metrics.KeycloakRequestDuration.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.RealmURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return pkg.ErrInternal.Wrap(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return pkg.ErrIdentityUnavailable.Wrap(err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+			outcome = "rejected"
+		}
+		return endpointError(resp.StatusCode, body, rejected)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return pkg.ErrIdentityUnavailable.Wrap(err)
+		}
+	}
+	outcome = "success"
+	return nil
+}
+
+// endpointError переводит отказ endpoint Keycloak в ошибку API. Ответы 400 и 401
+// означают неверные учётные данные или токен (rejected), остальное — сбой самого Keycloak.
+// Тело ответа Keycloak попадает только в лог.
+func endpointError(status int, body []byte, rejected *pkg.AppError) error {
+	cause := errors.New(http.StatusText(status) + ": " + string(body))
+	if status == http.StatusBadRequest || status == http.StatusUnauthorized {
+		return rejected.Wrap(cause)
+	}
+	return pkg.ErrIdentityUnavailable.Wrap(cause)
+}
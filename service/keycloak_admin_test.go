@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"lms-system-internship/pkg"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeKeycloak отвечает на вход администратора, на GET пользователя и на запросы регистрации.
+// userStatuses задаёт статусы ответов на GET по очереди; когда они заканчиваются, отвечает 200.
+// createStatus и passwordStatus, если заданы, возвращаются на создание пользователя и смену пароля.
+// user, если задан, отдаётся на GET вместо пользователя по умолчанию; тела запросов на создание и
+// изменение пользователя запоминаются в created и updated.
+type fakeKeycloak struct {
+	logins         atomic.Int32
+	userCalls      atomic.Int32
+	userStatuses   []int
+	user           map[string]any
+	createCalls    atomic.Int32
+	createStatus   int
+	created        map[string]any
+	updated        map[string]any
+	passwordStatus int
+	deletes        atomic.Int32
+}
+
+func (f *fakeKeycloak) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/realms/lms/protocol/openid-connect/token":
+		f.logins.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "admin-token", "expires_in": 300})
+	case r.Method == http.MethodPost && r.URL.Path == "/admin/realms/lms/users":
+		f.createCalls.Add(1)
+		_ = json.NewDecoder(r.Body).Decode(&f.created)
+		if f.createStatus != 0 {
+			w.WriteHeader(f.createStatus)
+			return
+		}
+		w.Header().Set("Location", "/admin/realms/lms/users/"+fakeKeycloakUserID.String())
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/reset-password"):
+		if f.passwordStatus != 0 {
+			w.WriteHeader(f.passwordStatus)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		_ = json.NewDecoder(r.Body).Decode(&f.updated)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		f.deletes.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet:
+		call := int(f.userCalls.Add(1)) - 1
+		if call < len(f.userStatuses) && f.userStatuses[call] != http.StatusOK {
+			w.WriteHeader(f.userStatuses[call])
+			_, _ = w.Write([]byte(`{"error":"failure"}`))
+			return
+		}
+		user := f.user
+		if user == nil {
+			user = map[string]any{"username": "alice", "firstName": "Alice", "lastName": "Smith"}
+		}
+		_ = json.NewEncoder(w).Encode(user)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+var fakeKeycloakUserID = uuid.MustParse("3f1c2b5e-7a1d-4c8e-9f00-1234567890ab")
+
+func newTestKeycloakAdmin(t *testing.T, fake *fakeKeycloak) *KeycloakAdmin {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	admin := NewKeycloakAdmin(server.URL, "lms", "admin", "admin")
+	admin.backoff = time.Millisecond
+	return admin
+}
+
+func TestKeycloakAdmin_Do(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("admin token is reused", func(t *testing.T) {
+		fake := &fakeKeycloak{}
+		directory := NewKeycloakUserDirectory(newTestKeycloakAdmin(t, fake))
+
+		for i := 0; i < 3; i++ {
+			name, err := directory.GetFullName(ctx, uuid.New())
+			assert.NoError(t, err)
+			assert.Equal(t, "Alice Smith", name)
+		}
+		assert.Equal(t, int32(1), fake.logins.Load())
+	})
+
+	t.Run("server errors are retried", func(t *testing.T) {
+		fake := &fakeKeycloak{userStatuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway}}
+		directory := NewKeycloakUserDirectory(newTestKeycloakAdmin(t, fake))
+
+		_, err := directory.GetFullName(ctx, uuid.New())
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), fake.userCalls.Load())
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		fake := &fakeKeycloak{userStatuses: []int{500, 500, 500, 500}}
+		directory := NewKeycloakUserDirectory(newTestKeycloakAdmin(t, fake))
+
+		_, err := directory.GetFullName(ctx, uuid.New())
+		assert.ErrorIs(t, err, pkg.ErrIdentityUnavailable)
+		assert.Equal(t, int32(keycloakAdminAttempts), fake.userCalls.Load())
+	})
+
+	t.Run("rejected token is replaced", func(t *testing.T) {
+		fake := &fakeKeycloak{userStatuses: []int{http.StatusUnauthorized}}
+		directory := NewKeycloakUserDirectory(newTestKeycloakAdmin(t, fake))
+
+		_, err := directory.GetFullName(ctx, uuid.New())
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), fake.logins.Load())
+	})
+
+	t.Run("not found is not retried", func(t *testing.T) {
+		fake := &fakeKeycloak{userStatuses: []int{http.StatusNotFound}}
+		directory := NewKeycloakUserDirectory(newTestKeycloakAdmin(t, fake))
+
+		_, err := directory.GetFullName(ctx, uuid.New())
+		assert.ErrorIs(t, err, pkg.ErrUserNotFound)
+		assert.Equal(t, int32(1), fake.userCalls.Load())
+	})
+
+	t.Run("expired admin token is renewed", func(t *testing.T) {
+		fake := &fakeKeycloak{}
+		admin := newTestKeycloakAdmin(t, fake)
+		directory := NewKeycloakUserDirectory(admin)
+
+		_, _ = directory.GetFullName(ctx, uuid.New())
+		admin.now = func() time.Time { return time.Now().Add(10 * time.Minute) }
+		_, err := directory.GetFullName(ctx, uuid.New())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), fake.logins.Load())
+	})
+
+	t.Run("non-idempotent call is not retried", func(t *testing.T) {
+		fake := &fakeKeycloak{createStatus: http.StatusServiceUnavailable}
+		identity := NewKeycloakIdentityService(newTestKeycloakAdmin(t, fake))
+
+		_, err := identity.RegisterUser(ctx, NewUser{Username: "alice", Password: "secret"})
+		assert.ErrorIs(t, err, pkg.ErrIdentityUnavailable)
+		assert.Equal(t, int32(1), fake.createCalls.Load())
+	})
+}
+
+func TestKeycloakIdentityService_RegisterUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		fake := &fakeKeycloak{}
+		identity := NewKeycloakIdentityService(newTestKeycloakAdmin(t, fake))
+
+		id, err := identity.RegisterUser(ctx, NewUser{Username: "alice", Password: "secret"})
+		assert.NoError(t, err)
+		assert.Equal(t, fakeKeycloakUserID, id)
+		assert.Equal(t, int32(0), fake.deletes.Load())
+	})
+
+	t.Run("user is deleted when password cannot be set", func(t *testing.T) {
+		fake := &fakeKeycloak{passwordStatus: http.StatusBadRequest}
+		identity := NewKeycloakIdentityService(newTestKeycloakAdmin(t, fake))
+
+		_, err := identity.RegisterUser(ctx, NewUser{Username: "alice", Password: "secret"})
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), fake.deletes.Load())
+	})
+}
+
+func TestKeycloakIdentityService_ConfirmEmail(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("self-registered account is marked pending and enabled on confirmation", func(t *testing.T) {
+		fake := &fakeKeycloak{}
+		identity := NewKeycloakIdentityService(newTestKeycloakAdmin(t, fake))
+
+		_, err := identity.RegisterUser(ctx, NewUser{Username: "alice", Password: "secret", RequireEmailVerification: true})
+		assert.NoError(t, err)
+		assert.Equal(t, false, fake.created["enabled"])
+		assert.Equal(t, map[string]any{pendingVerificationAttribute: []any{"true"}}, fake.created["attributes"])
+
+		fake.user = map[string]any{"id": fakeKeycloakUserID.String(), "username": "alice", "enabled": false,
+			"attributes": map[string]any{pendingVerificationAttribute: []string{"true"}, "department": []string{"math"}}}
+		assert.NoError(t, identity.ConfirmEmail(ctx, fakeKeycloakUserID))
+		assert.Equal(t, true, fake.updated["enabled"])
+		assert.Equal(t, true, fake.updated["emailVerified"])
+		assert.Equal(t, map[string]any{"department": []any{"math"}}, fake.updated["attributes"])
+	})
+
+	t.Run("account disabled by an administrator stays disabled", func(t *testing.T) {
+		fake := &fakeKeycloak{user: map[string]any{"id": fakeKeycloakUserID.String(), "username": "alice", "enabled": false}}
+		identity := NewKeycloakIdentityService(newTestKeycloakAdmin(t, fake))
+
+		assert.NoError(t, identity.ConfirmEmail(ctx, fakeKeycloakUserID))
+		assert.Equal(t, false, fake.updated["enabled"])
+		assert.Equal(t, true, fake.updated["emailVerified"])
+	})
+
+	t.Run("administrator decision clears the pending marker", func(t *testing.T) {
+		fake := &fakeKeycloak{user: map[string]any{"id": fakeKeycloakUserID.String(), "username": "alice", "enabled": false,
+			"attributes": map[string]any{pendingVerificationAttribute: []string{"true"}}}}
+		identity := NewKeycloakIdentityService(newTestKeycloakAdmin(t, fake))
+
+		assert.NoError(t, identity.SetEnabled(ctx, fakeKeycloakUserID, false))
+		assert.Equal(t, map[string]any{}, fake.updated["attributes"])
+	})
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"net/url"
+	"strings"
+)
+
+var calloutVariants = map[string]bool{"info": true, "tip": true, "warning": true, "danger": true}
+
+type LessonBlockService interface {
+	GetBlocks(ctx context.Context, lessonID uint) ([]*entities.LessonBlock, error)
+	CreateBlock(ctx context.Context, authorID uuid.UUID, block *entities.LessonBlock) error
+	UpdateBlock(ctx context.Context, authorID uuid.UUID, block *entities.LessonBlock) error
+	DeleteBlock(ctx context.Context, authorID uuid.UUID, blockID uint) error
+	ReorderBlocks(ctx context.Context, authorID uuid.UUID, lessonID uint, orderedIDs []uint) error
+}
+
+type lessonBlockService struct {
+	repo           repo.LessonBlockRepository
+	lessonRepo     repo.LessonRepository
+	attachmentRepo repo.AttachmentRepository
+	revisions      RevisionService
+	translations   TranslationService
+}
+
+// NewLessonBlockService создаёт сервис блоков. Каждое изменение блоков записывается
+// в историю правок урока, блоки отдаются с переводами на локаль запроса.
+func NewLessonBlockService(repo repo.LessonBlockRepository, lessonRepo repo.LessonRepository, attachmentRepo repo.AttachmentRepository, revisions RevisionService, translations TranslationService) LessonBlockService {
+	return &lessonBlockService{repo: repo, lessonRepo: lessonRepo, attachmentRepo: attachmentRepo, revisions: revisions, translations: translations}
+}
+
+func (s *lessonBlockService) GetBlocks(ctx context.Context, lessonID uint) ([]*entities.LessonBlock, error) {
+	if _, err := s.getLesson(ctx, lessonID); err != nil {
+		return nil, err
+	}
+	blocks, err := s.repo.FindByLessonID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	localize(ctx, s.translations, func(t TranslationService) error { return t.LocalizeBlocks(ctx, blocks...) })
+	return blocks, nil
+}
+
+// CreateBlock добавляет блок в конец урока.
+func (s *lessonBlockService) CreateBlock(ctx context.Context, authorID uuid.UUID, block *entities.LessonBlock) error {
+	lesson, err := s.getLesson(ctx, block.LessonID)
+	if err != nil {
+		return err
+	}
+	if err := s.validateBlock(ctx, block); err != nil {
+		return err
+	}
+
+	blocks, err := s.repo.FindByLessonID(ctx, block.LessonID)
+	if err != nil {
+		return err
+	}
+	block.Order = 1
+	for _, existing := range blocks {
+		if existing.Order >= block.Order {
+			block.Order = existing.Order + 1
+		}
+	}
+	if err := s.repo.Save(ctx, block); err != nil {
+		return err
+	}
+	s.recordChange(ctx, authorID, lesson)
+	return nil
+}
+
+// UpdateBlock меняет содержимое блока; урок и позиция блока сохраняются.
+func (s *lessonBlockService) UpdateBlock(ctx context.Context, authorID uuid.UUID, block *entities.LessonBlock) error {
+	existing, err := s.getBlock(ctx, block.ID)
+	if err != nil {
+		return err
+	}
+	lesson, err := s.getLesson(ctx, existing.LessonID)
+	if err != nil {
+		return err
+	}
+	block.LessonID = existing.LessonID
+	block.Order = existing.Order
+	block.CreatedAt = existing.CreatedAt
+	if err := s.validateBlock(ctx, block); err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, block); err != nil {
+		return err
+	}
+	s.recordChange(ctx, authorID, lesson)
+	return nil
+}
+
+func (s *lessonBlockService) DeleteBlock(ctx context.Context, authorID uuid.UUID, blockID uint) error {
+	block, err := s.getBlock(ctx, blockID)
+	if err != nil {
+		return err
+	}
+	lesson, err := s.getLesson(ctx, block.LessonID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, blockID); err != nil {
+		return notFound(err, pkg.ErrLessonBlockNotFound)
+	}
+	s.recordChange(ctx, authorID, lesson)
+	return nil
+}
+
+// ReorderBlocks принимает полный список блоков урока в новом порядке.
+func (s *lessonBlockService) ReorderBlocks(ctx context.Context, authorID uuid.UUID, lessonID uint, orderedIDs []uint) error {
+	lesson, err := s.getLesson(ctx, lessonID)
+	if err != nil {
+		return err
+	}
+	blocks, err := s.repo.FindByLessonID(ctx, lessonID)
+	if err != nil {
+		return err
+	}
+	if len(orderedIDs) != len(blocks) {
+		return pkg.ErrInvalidInput
+	}
+
+	known := make(map[uint]bool, len(blocks))
+	for _, block := range blocks {
+		known[block.ID] = true
+	}
+	for _, id := range orderedIDs {
+		if !known[id] {
+			return pkg.ErrInvalidInput
+		}
+		delete(known, id) // повтор ID тоже ошибка
+	}
+	if err := s.repo.UpdateOrder(ctx, lessonID, orderedIDs); err != nil {
+		return err
+	}
+	s.recordChange(ctx, authorID, lesson)
+	return nil
+}
+
+// recordChange записывает ревизию урока после изменения его блоков; before — урок,
+// загруженный до изменения. Сбой истории не отменяет уже сохранённую правку.
+func (s *lessonBlockService) recordChange(ctx context.Context, authorID uuid.UUID, before *entities.Lesson) {
+	after, err := s.lessonRepo.FindByID(ctx, before.ID)
+	if err != nil {
+		pkg.LoggerFromContext(ctx).WithError(err).WithField("lesson_id", before.ID).Warn("Failed to record revision")
+		return
+	}
+	recordRevision(ctx, s.revisions, authorID, lessonRevision(before), lessonRevision(after))
+}
+
+// validateBlock проверяет поля, обязательные для типа блока, и очищает остальные.
+func (s *lessonBlockService) validateBlock(ctx context.Context, block *entities.LessonBlock) error {
+	normalized := entities.LessonBlock{
+		ID:        block.ID,
+		LessonID:  block.LessonID,
+		Type:      block.Type,
+		Order:     block.Order,
+		CreatedAt: block.CreatedAt,
+	}
+
+	switch block.Type {
+	case entities.BlockTypeText:
+		normalized.Text = block.Text
+		normalized.Format = block.Format
+		if normalized.Format == "" {
+			normalized.Format = entities.ContentFormatMarkdown
+		}
+		if strings.TrimSpace(normalized.Text) == "" || !isContentFormat(normalized.Format) {
+			return pkg.ErrInvalidInput
+		}
+	case entities.BlockTypeCode:
+		normalized.Text = block.Text
+		normalized.Language = strings.ToLower(strings.TrimSpace(block.Language))
+		if strings.TrimSpace(normalized.Text) == "" || len(normalized.Language) > 50 {
+			return pkg.ErrInvalidInput
+		}
+	case entities.BlockTypeImage:
+		normalized.AttachmentID = block.AttachmentID
+		normalized.Caption = block.Caption
+		if block.AttachmentID == nil {
+			return pkg.ErrInvalidInput
+		}
+		if err := s.checkAttachment(ctx, block.LessonID, *block.AttachmentID); err != nil {
+			return err
+		}
+	case entities.BlockTypeVideo:
+		normalized.URL = strings.TrimSpace(block.URL)
+		normalized.Caption = block.Caption
+		parsed, err := url.Parse(normalized.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return pkg.ErrInvalidInput
+		}
+	case entities.BlockTypeCallout:
+		normalized.Text = block.Text
+		normalized.Variant = block.Variant
+		if normalized.Variant == "" {
+			normalized.Variant = "info"
+		}
+		if strings.TrimSpace(normalized.Text) == "" || !calloutVariants[normalized.Variant] {
+			return pkg.ErrInvalidInput
+		}
+	default:
+		return pkg.ErrInvalidInput
+	}
+
+	*block = normalized
+	return nil
+}
+
+// checkAttachment проверяет, что картинка загружена в тот же урок.
+func (s *lessonBlockService) checkAttachment(ctx context.Context, lessonID, attachmentID uint) error {
+	attachments, err := s.attachmentRepo.FindByLessonID(ctx, lessonID)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		if attachment.ID == attachmentID {
+			return nil
+		}
+	}
+	return pkg.ErrInvalidInput
+}
+
+func (s *lessonBlockService) getLesson(ctx context.Context, lessonID uint) (*entities.Lesson, error) {
+	lesson, err := s.lessonRepo.FindByID(ctx, lessonID)
+	if err != nil {
+		return nil, notFound(err, pkg.ErrLessonNotFound)
+	}
+	return lesson, nil
+}
+
+func (s *lessonBlockService) getBlock(ctx context.Context, id uint) (*entities.LessonBlock, error) {
+	block, err := s.repo.FindByID(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkg.ErrLessonBlockNotFound
+	}
+	return block, err
+}
+
+// renderBlock заполняет block.HTML для текстовых блоков. Картинки и видео фронтенд
+// показывает сам по attachment_id и url.
+func renderBlock(renderer ContentRenderer, block *entities.LessonBlock) error {
+	var err error
+	switch block.Type {
+	case entities.BlockTypeText:
+		block.HTML, err = renderer.Render(block.Format, block.Text)
+	case entities.BlockTypeCallout:
+		block.HTML, err = renderer.Render(entities.ContentFormatMarkdown, block.Text)
+	case entities.BlockTypeCode:
+		fence := codeFence(block.Text)
+		block.HTML, err = renderer.Render(entities.ContentFormatMarkdown, fence+block.Language+"\n"+block.Text+"\n"+fence+"\n")
+	}
+	return err
+}
+
+// codeFence подбирает ограничитель Markdown длиннее любой последовательности обратных кавычек в коде.
+func codeFence(code string) string {
+	longest, current := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			current++
+			if current > longest {
+				longest = current
+			}
+		} else {
+			current = 0
+		}
+	}
+	if longest < 3 {
+		longest = 2
+	}
+	return strings.Repeat("`", longest+1)
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLessonBlockService_CreateBlock(t *testing.T) {
+	authorID := uuid.New()
+
+	t.Run("text block appended with default format", func(t *testing.T) {
+		blocks := new(mocks.LessonBlockRepository)
+		lessons := new(mocks.LessonRepository)
+		attachments := new(mocks.AttachmentRepository)
+		revisions := new(mocks.RevisionService)
+		service := NewLessonBlockService(blocks, lessons, attachments, revisions, noTranslations{})
+		lessons.On("FindByID", mock.Anything, uint(1)).Return(&entities.Lesson{ID: 1}, nil)
+		blocks.On("FindByLessonID", mock.Anything, uint(1)).Return([]*entities.LessonBlock{{ID: 1, Order: 1}, {ID: 2, Order: 2}}, nil)
+		blocks.On("Save", mock.Anything, mock.AnythingOfType("*entities.LessonBlock")).Return(nil)
+		revisions.On("RecordChange", mock.Anything, authorID, mock.Anything, mock.MatchedBy(func(r *entities.Revision) bool {
+			return r.EntityType == entities.RevisionEntityLesson && r.EntityID == 1
+		})).Return(nil)
+
+		block := &entities.LessonBlock{LessonID: 1, Type: entities.BlockTypeText, Text: "Hello", URL: "ignored"}
+		err := service.CreateBlock(context.Background(), authorID, block)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, block.Order)
+		assert.Equal(t, entities.ContentFormatMarkdown, block.Format)
+		assert.Empty(t, block.URL)
+		revisions.AssertExpectations(t)
+	})
+
+	t.Run("image from another lesson", func(t *testing.T) {
+		blocks := new(mocks.LessonBlockRepository)
+		lessons := new(mocks.LessonRepository)
+		attachments := new(mocks.AttachmentRepository)
+		revisions := new(mocks.RevisionService)
+		service := NewLessonBlockService(blocks, lessons, attachments, revisions, noTranslations{})
+		attachmentID := uint(7)
+		lessons.On("FindByID", mock.Anything, uint(1)).Return(&entities.Lesson{ID: 1}, nil)
+		attachments.On("FindByLessonID", mock.Anything, uint(1)).Return([]*entities.Attachment{{ID: 3, LessonID: 1}}, nil)
+
+		err := service.CreateBlock(context.Background(), authorID, &entities.LessonBlock{LessonID: 1, Type: entities.BlockTypeImage, AttachmentID: &attachmentID})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+		blocks.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("video with non-http url", func(t *testing.T) {
+		blocks := new(mocks.LessonBlockRepository)
+		lessons := new(mocks.LessonRepository)
+		attachments := new(mocks.AttachmentRepository)
+		revisions := new(mocks.RevisionService)
+		service := NewLessonBlockService(blocks, lessons, attachments, revisions, noTranslations{})
+		lessons.On("FindByID", mock.Anything, uint(1)).Return(&entities.Lesson{ID: 1}, nil)
+
+		err := service.CreateBlock(context.Background(), authorID, &entities.LessonBlock{LessonID: 1, Type: entities.BlockTypeVideo, URL: "javascript:alert(1)"})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+
+	t.Run("unknown callout variant", func(t *testing.T) {
+		blocks := new(mocks.LessonBlockRepository)
+		lessons := new(mocks.LessonRepository)
+		attachments := new(mocks.AttachmentRepository)
+		revisions := new(mocks.RevisionService)
+		service := NewLessonBlockService(blocks, lessons, attachments, revisions, noTranslations{})
+		lessons.On("FindByID", mock.Anything, uint(1)).Return(&entities.Lesson{ID: 1}, nil)
+
+		err := service.CreateBlock(context.Background(), authorID, &entities.LessonBlock{LessonID: 1, Type: entities.BlockTypeCallout, Text: "Note", Variant: "purple"})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+
+	t.Run("lesson not found", func(t *testing.T) {
+		blocks := new(mocks.LessonBlockRepository)
+		lessons := new(mocks.LessonRepository)
+		attachments := new(mocks.AttachmentRepository)
+		revisions := new(mocks.RevisionService)
+		service := NewLessonBlockService(blocks, lessons, attachments, revisions, noTranslations{})
+		lessons.On("FindByID", mock.Anything, uint(1)).Return(nil, repo.ErrNotFound)
+
+		err := service.CreateBlock(context.Background(), authorID, &entities.LessonBlock{LessonID: 1, Type: entities.BlockTypeText, Text: "Hello"})
+
+		assert.ErrorIs(t, err, pkg.ErrLessonNotFound)
+	})
+}
+
+func TestLessonBlockService_GetBlocks(t *testing.T) {
+	blocks := new(mocks.LessonBlockRepository)
+	lessons := new(mocks.LessonRepository)
+	translations := new(mocks.TranslationRepository)
+	service := NewLessonBlockService(blocks, lessons, nil, anyRevisions(),
+		NewTranslationService(translations, nil, nil, lessons, blocks, "en", []string{"en", "ru"}))
+	lessons.On("FindByID", mock.Anything, uint(1)).Return(&entities.Lesson{ID: 1}, nil)
+	blocks.On("FindByLessonID", mock.Anything, uint(1)).Return([]*entities.LessonBlock{
+		{ID: 2, LessonID: 1, Type: entities.BlockTypeText, Text: "Hello"},
+		{ID: 3, LessonID: 1, Type: entities.BlockTypeText, Text: "Untranslated"},
+	}, nil)
+	translations.On("FindByEntities", mock.Anything, entities.TranslationEntityBlock, []uint{2, 3}, "ru").
+		Return([]*entities.Translation{{EntityID: 2, Content: "Привет"}}, nil)
+
+	list, err := service.GetBlocks(pkg.WithLocale(context.Background(), "ru"), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Привет", list[0].Text)
+	assert.Equal(t, "Untranslated", list[1].Text)
+}
+
+func TestLessonBlockService_ReorderBlocks(t *testing.T) {
+	authorID := uuid.New()
+	existing := []*entities.LessonBlock{{ID: 1, LessonID: 1}, {ID: 2, LessonID: 1}, {ID: 3, LessonID: 1}}
+
+	t.Run("success", func(t *testing.T) {
+		blocks := new(mocks.LessonBlockRepository)
+		lessons := new(mocks.LessonRepository)
+		attachments := new(mocks.AttachmentRepository)
+		revisions := new(mocks.RevisionService)
+		service := NewLessonBlockService(blocks, lessons, attachments, revisions, noTranslations{})
+		lessons.On("FindByID", mock.Anything, uint(1)).Return(&entities.Lesson{ID: 1}, nil)
+		blocks.On("FindByLessonID", mock.Anything, uint(1)).Return(existing, nil)
+		blocks.On("UpdateOrder", mock.Anything, uint(1), []uint{3, 1, 2}).Return(nil)
+		revisions.On("RecordChange", mock.Anything, authorID, mock.Anything, mock.Anything).Return(nil)
+
+		err := service.ReorderBlocks(context.Background(), authorID, 1, []uint{3, 1, 2})
+
+		assert.NoError(t, err)
+		blocks.AssertExpectations(t)
+		revisions.AssertExpectations(t)
+	})
+
+	t.Run("duplicate id", func(t *testing.T) {
+		blocks := new(mocks.LessonBlockRepository)
+		lessons := new(mocks.LessonRepository)
+		attachments := new(mocks.AttachmentRepository)
+		revisions := new(mocks.RevisionService)
+		service := NewLessonBlockService(blocks, lessons, attachments, revisions, noTranslations{})
+		lessons.On("FindByID", mock.Anything, uint(1)).Return(&entities.Lesson{ID: 1}, nil)
+		blocks.On("FindByLessonID", mock.Anything, uint(1)).Return(existing, nil)
+
+		err := service.ReorderBlocks(context.Background(), authorID, 1, []uint{1, 1, 2})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+}
+
+func TestLessonBlockService_DeleteBlock(t *testing.T) {
+	authorID := uuid.New()
+
+	t.Run("deletion is recorded in the lesson history", func(t *testing.T) {
+		blocks := new(mocks.LessonBlockRepository)
+		lessons := new(mocks.LessonRepository)
+		attachments := new(mocks.AttachmentRepository)
+		revisions := new(mocks.RevisionService)
+		service := NewLessonBlockService(blocks, lessons, attachments, revisions, noTranslations{})
+		before := &entities.Lesson{ID: 1, Blocks: []entities.LessonBlock{{ID: 4, LessonID: 1, Type: entities.BlockTypeText, Text: "Hello"}}}
+		blocks.On("FindByID", mock.Anything, uint(4)).Return(&entities.LessonBlock{ID: 4, LessonID: 1}, nil)
+		lessons.On("FindByID", mock.Anything, uint(1)).Return(before, nil).Once()
+		lessons.On("FindByID", mock.Anything, uint(1)).Return(&entities.Lesson{ID: 1}, nil).Once()
+		blocks.On("Delete", mock.Anything, uint(4)).Return(nil)
+		revisions.On("RecordChange", mock.Anything, authorID,
+			mock.MatchedBy(func(r *entities.Revision) bool { return len(r.Blocks) == 1 }),
+			mock.MatchedBy(func(r *entities.Revision) bool { return len(r.Blocks) == 0 }),
+		).Return(nil)
+
+		err := service.DeleteBlock(context.Background(), authorID, 4)
+
+		assert.NoError(t, err)
+		revisions.AssertExpectations(t)
+	})
+
+	t.Run("block not found", func(t *testing.T) {
+		blocks := new(mocks.LessonBlockRepository)
+		lessons := new(mocks.LessonRepository)
+		attachments := new(mocks.AttachmentRepository)
+		revisions := new(mocks.RevisionService)
+		service := NewLessonBlockService(blocks, lessons, attachments, revisions, noTranslations{})
+		blocks.On("FindByID", mock.Anything, uint(4)).Return(nil, repo.ErrNotFound)
+
+		err := service.DeleteBlock(context.Background(), authorID, 4)
+
+		assert.ErrorIs(t, err, pkg.ErrLessonBlockNotFound)
+		blocks.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRenderBlock_Code(t *testing.T) {
+	block := &entities.LessonBlock{Type: entities.BlockTypeCode, Language: "go", Text: "s := \"```\""}
+
+	err := renderBlock(NewContentRenderer(), block)
+
+	assert.NoError(t, err)
+	assert.Contains(t, block.HTML, "<pre")
+	assert.Contains(t, block.HTML, "```")
+}
@@ -9,7 +9,9 @@ import (
 	"lms-system-internship/entities"
 	"lms-system-internship/mocks"
 	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -32,7 +34,7 @@ func TestLessonService_GetAllLessons(t *testing.T) {
 
 		mockRepo.On("FindAll", mock.Anything).Return(lessons, nil)
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
 		result, err := service.GetAllLessons(context.Background())
 
 		assert.NoError(t, err)
@@ -44,7 +46,7 @@ func TestLessonService_GetAllLessons(t *testing.T) {
 		mockRepo := new(mocks.LessonRepository)
 		mockRepo.On("FindAll", mock.Anything).Return([]*entities.Lesson{}, nil)
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
 		result, err := service.GetAllLessons(context.Background())
 
 		assert.NoError(t, err)
@@ -56,7 +58,7 @@ func TestLessonService_GetAllLessons(t *testing.T) {
 		mockRepo := new(mocks.LessonRepository)
 		mockRepo.On("FindAll", mock.Anything).Return(nil, errors.New("database error"))
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
 		result, err := service.GetAllLessons(context.Background())
 
 		assert.Error(t, err)
@@ -79,7 +81,7 @@ func TestLessonService_GetLesson(t *testing.T) {
 
 		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(lesson, nil)
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
 		result, err := service.GetLesson(context.Background(), 1)
 
 		assert.NoError(t, err)
@@ -91,7 +93,7 @@ func TestLessonService_GetLesson(t *testing.T) {
 		mockRepo := new(mocks.LessonRepository)
 		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(nil, pkg.ErrLessonNotFound)
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
 		result, err := service.GetLesson(context.Background(), 1)
 
 		assert.Error(t, err)
@@ -112,12 +114,17 @@ func TestLessonService_AddLessonToChapter(t *testing.T) {
 		}
 
 		mockRepo.On("Save", mock.Anything, lesson).Return(nil)
+		mockChapterRepo := new(mocks.ChapterRepository)
+		mockChapterRepo.On("FindByID", mock.Anything, uint(1)).Return(&entities.Chapter{ID: 1}, nil)
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, mockChapterRepo, new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
 		err := service.AddLessonToChapter(context.Background(), 1, lesson)
 
 		assert.NoError(t, err)
 		assert.Equal(t, uint(1), lesson.ChapterID)
+		assert.Equal(t, []entities.LessonBlock{
+			{Type: entities.BlockTypeText, Order: 1, Text: "New Content", Format: entities.ContentFormatPlain},
+		}, lesson.Blocks)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -131,13 +138,30 @@ func TestLessonService_AddLessonToChapter(t *testing.T) {
 		}
 
 		mockRepo.On("Save", mock.Anything, lesson).Return(errors.New("database error"))
+		mockChapterRepo := new(mocks.ChapterRepository)
+		mockChapterRepo.On("FindByID", mock.Anything, uint(1)).Return(&entities.Chapter{ID: 1}, nil)
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, mockChapterRepo, new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
 		err := service.AddLessonToChapter(context.Background(), 1, lesson)
 
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("chapter does not exist", func(t *testing.T) {
+		mockRepo := new(mocks.LessonRepository)
+		mockChapterRepo := new(mocks.ChapterRepository)
+		mockChapterRepo.On("FindByID", mock.Anything, uint(42)).Return(nil, repo.ErrNotFound)
+
+		service := NewLessonService(mockRepo, mockChapterRepo, new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
+		err := service.AddLessonToChapter(context.Background(), 42, &entities.Lesson{Name: "New Lesson"})
+
+		var appErr *pkg.AppError
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, []pkg.FieldError{{Field: "chapter_id", Code: "exists"}}, appErr.Fields)
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
 }
 
 func TestLessonService_UpdateLessonContent(t *testing.T) {
@@ -153,27 +177,125 @@ func TestLessonService_UpdateLessonContent(t *testing.T) {
 		}
 
 		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(lesson, nil)
-		mockRepo.On("Update", mock.Anything, lesson).Return(nil)
+		mockRepo.On("UpdateWithBlocks", mock.Anything, lesson).Return(nil)
 
-		service := NewLessonService(mockRepo)
-		err := service.UpdateLessonContent(context.Background(), 1, "New Content")
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
+		err := service.UpdateLessonContent(context.Background(), uuid.New(), 1, "New Content", "")
 
 		assert.NoError(t, err)
 		assert.Equal(t, "New Content", lesson.Content)
+		assert.Equal(t, []entities.LessonBlock{
+			{LessonID: 1, Type: entities.BlockTypeText, Order: 1, Text: "New Content", Format: entities.ContentFormatPlain},
+		}, lesson.Blocks)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("first text block is updated", func(t *testing.T) {
+		mockRepo := new(mocks.LessonRepository)
+		lesson := &entities.Lesson{ID: 1, Content: "Old Content", ContentFormat: entities.ContentFormatMarkdown, Blocks: []entities.LessonBlock{
+			{ID: 3, LessonID: 1, Type: entities.BlockTypeCode, Order: 1, Text: "x := 1", Language: "go"},
+			{ID: 4, LessonID: 1, Type: entities.BlockTypeText, Order: 2, Text: "Old Content", Format: entities.ContentFormatMarkdown},
+		}}
+
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(lesson, nil)
+		mockRepo.On("UpdateWithBlocks", mock.Anything, lesson).Return(nil)
+
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
+		err := service.UpdateLessonContent(context.Background(), uuid.New(), 1, "New Content", "")
+
+		assert.NoError(t, err)
+		assert.Len(t, lesson.Blocks, 2)
+		assert.Equal(t, "x := 1", lesson.Blocks[0].Text)
+		assert.Equal(t, uint(4), lesson.Blocks[1].ID)
+		assert.Equal(t, "New Content", lesson.Blocks[1].Text)
+	})
+
+	t.Run("empty content removes the text block", func(t *testing.T) {
+		mockRepo := new(mocks.LessonRepository)
+		lesson := &entities.Lesson{ID: 1, Content: "Old Content", Blocks: []entities.LessonBlock{
+			{ID: 4, LessonID: 1, Type: entities.BlockTypeText, Order: 1, Text: "Old Content"},
+		}}
+
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(lesson, nil)
+		mockRepo.On("UpdateWithBlocks", mock.Anything, lesson).Return(nil)
+
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
+		err := service.UpdateLessonContent(context.Background(), uuid.New(), 1, "", "")
+
+		assert.NoError(t, err)
+		assert.Empty(t, lesson.Blocks)
+	})
+
 	t.Run("not found", func(t *testing.T) {
 		mockRepo := new(mocks.LessonRepository)
 		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(nil, pkg.ErrLessonNotFound)
 
-		service := NewLessonService(mockRepo)
-		err := service.UpdateLessonContent(context.Background(), 1, "New Content")
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
+		err := service.UpdateLessonContent(context.Background(), uuid.New(), 1, "New Content", "")
 
 		assert.Error(t, err)
 		assert.Equal(t, pkg.ErrLessonNotFound, err)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("change format", func(t *testing.T) {
+		mockRepo := new(mocks.LessonRepository)
+		lesson := &entities.Lesson{ID: 1, Content: "Old Content", ContentFormat: entities.ContentFormatPlain}
+
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(lesson, nil)
+		mockRepo.On("UpdateWithBlocks", mock.Anything, lesson).Return(nil)
+
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
+		err := service.UpdateLessonContent(context.Background(), uuid.New(), 1, "# Title", entities.ContentFormatMarkdown)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.ContentFormatMarkdown, lesson.ContentFormat)
+	})
+
+	t.Run("records revision", func(t *testing.T) {
+		mockRepo := new(mocks.LessonRepository)
+		revisions := new(mocks.RevisionService)
+		authorID := uuid.New()
+		lesson := &entities.Lesson{ID: 1, Name: "Lesson 1", Content: "Old Content", ContentFormat: entities.ContentFormatPlain}
+
+		mockRepo.On("FindByID", mock.Anything, uint(1)).Return(lesson, nil)
+		mockRepo.On("UpdateWithBlocks", mock.Anything, lesson).Return(nil)
+		revisions.On("RecordChange", mock.Anything, authorID,
+			mock.MatchedBy(func(r *entities.Revision) bool { return r.Content == "Old Content" }),
+			mock.MatchedBy(func(r *entities.Revision) bool {
+				return r.Content == "New Content" && r.EntityID == 1 && len(r.Blocks) == 1 && r.Blocks[0].Text == "New Content"
+			}),
+		).Return(nil)
+
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), revisions, noTranslations{})
+		err := service.UpdateLessonContent(context.Background(), authorID, 1, "New Content", "")
+
+		assert.NoError(t, err)
+		revisions.AssertExpectations(t)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		mockRepo := new(mocks.LessonRepository)
+
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
+		err := service.UpdateLessonContent(context.Background(), uuid.New(), 1, "New Content", "rtf")
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+		mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLessonService_GetRenderedLesson(t *testing.T) {
+	mockRepo := new(mocks.LessonRepository)
+	lesson := &entities.Lesson{ID: 1, Content: "# Intro\n\n<script>alert(1)</script>", ContentFormat: entities.ContentFormatMarkdown}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(lesson, nil)
+
+	service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
+	result, err := service.GetRenderedLesson(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Contains(t, result.ContentHTML, "<h1")
+	assert.NotContains(t, result.ContentHTML, "<script")
 }
 
 func TestLessonService_ReorderLessons(t *testing.T) {
@@ -195,7 +317,7 @@ func TestLessonService_ReorderLessons(t *testing.T) {
 		mockRepo.On("FindByChapterID", mock.Anything, uint(1)).Return(lessons, nil)
 		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entities.Lesson")).Return(nil).Twice()
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
 		err := service.ReorderLessons(context.Background(), 1, []uint{2, 1})
 
 		assert.NoError(t, err)
@@ -206,7 +328,7 @@ func TestLessonService_ReorderLessons(t *testing.T) {
 		mockRepo := new(mocks.LessonRepository)
 		mockRepo.On("FindByChapterID", mock.Anything, uint(1)).Return(nil, errors.New("database error"))
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
 		err := service.ReorderLessons(context.Background(), 1, []uint{2, 1})
 
 		assert.Error(t, err)
@@ -226,7 +348,7 @@ func TestLessonService_ReorderLessons(t *testing.T) {
 		mockRepo.On("FindByChapterID", mock.Anything, uint(1)).Return(lessons, nil)
 		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*entities.Lesson")).Return(errors.New("database error"))
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), new(mocks.AttachmentRepository), new(mocks.FileStorage), NewContentRenderer(), anyRevisions(), noTranslations{})
 		err := service.ReorderLessons(context.Background(), 1, []uint{1})
 
 		assert.Error(t, err)
@@ -237,35 +359,48 @@ func TestLessonService_ReorderLessons(t *testing.T) {
 func TestLessonService_DeleteLesson(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockRepo := new(mocks.LessonRepository)
+		attachments := new(mocks.AttachmentRepository)
+		storage := new(mocks.FileStorage)
+		attachments.On("FindByLessonID", mock.Anything, uint(1)).Return([]*entities.Attachment{
+			{ID: 2, URL: "a.pdf", LessonID: 1}, {ID: 3, URL: "b.png", LessonID: 1},
+		}, nil)
 		mockRepo.On("Delete", mock.Anything, uint(1)).Return(nil)
+		storage.On("DeleteFile", mock.Anything, "a.pdf").Return(errors.New("storage unavailable"))
+		storage.On("DeleteFile", mock.Anything, "b.png").Return(nil)
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), attachments, storage, NewContentRenderer(), anyRevisions(), noTranslations{})
 		err := service.DeleteLesson(context.Background(), 1)
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
+		storage.AssertExpectations(t)
 	})
 
 	t.Run("not found", func(t *testing.T) {
 		mockRepo := new(mocks.LessonRepository)
-		mockRepo.On("Delete", mock.Anything, uint(1)).Return(pkg.ErrLessonNotFound)
+		attachments := new(mocks.AttachmentRepository)
+		storage := new(mocks.FileStorage)
+		attachments.On("FindByLessonID", mock.Anything, uint(1)).Return([]*entities.Attachment{{ID: 2, URL: "a.pdf", LessonID: 1}}, nil)
+		mockRepo.On("Delete", mock.Anything, uint(1)).Return(repo.ErrNotFound)
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), attachments, storage, NewContentRenderer(), anyRevisions(), noTranslations{})
 		err := service.DeleteLesson(context.Background(), 1)
 
-		assert.Error(t, err)
 		assert.Equal(t, pkg.ErrLessonNotFound, err)
-		mockRepo.AssertExpectations(t)
+		storage.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything)
 	})
 
 	t.Run("error", func(t *testing.T) {
 		mockRepo := new(mocks.LessonRepository)
+		attachments := new(mocks.AttachmentRepository)
+		storage := new(mocks.FileStorage)
+		attachments.On("FindByLessonID", mock.Anything, uint(1)).Return([]*entities.Attachment{{ID: 2, URL: "a.pdf", LessonID: 1}}, nil)
 		mockRepo.On("Delete", mock.Anything, uint(1)).Return(errors.New("database error"))
 
-		service := NewLessonService(mockRepo)
+		service := NewLessonService(mockRepo, new(mocks.ChapterRepository), new(mocks.LessonUserRepository), attachments, storage, NewContentRenderer(), anyRevisions(), noTranslations{})
 		err := service.DeleteLesson(context.Background(), 1)
 
 		assert.Error(t, err)
-		mockRepo.AssertExpectations(t)
+		storage.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything)
 	})
 }
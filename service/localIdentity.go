@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"lms-system-internship/pkg"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+const (
+	localIssuer      = "lms-local"
+	tokenTypeAccess  = "Bearer"
+	tokenTypeRefresh = "Refresh"
+)
+
+// LocalUser — учётная запись встроенного провайдера. Пароль хранится как есть:
+// провайдер предназначен для тестов и локальной разработки без Keycloak.
+type LocalUser struct {
+	Username string
+	Password string
+	Roles    []string
+}
+
+// ParseLocalUsers разбирает список пользователей вида "alice:secret:ROLE_ADMIN|ROLE_TEACHER,bob:pass".
+func ParseLocalUsers(spec string) ([]LocalUser, error) {
+	var users []LocalUser
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid local user %q: expected username:password[:roles]", entry)
+		}
+		user := LocalUser{Username: parts[0], Password: parts[1], Roles: []string{}}
+		if len(parts) == 3 && parts[2] != "" {
+			user.Roles = strings.Split(parts[2], "|")
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// localIdentityProvider сам выпускает JWT, подписанные HS256, в формате claims Keycloak.
+// ID пользователя выводится из логина, поэтому не меняется между перезапусками. Сессии хранятся
+// в памяти: токены завершённой сессии сразу перестают приниматься, а перезапуск завершает все сессии.
+type localIdentityProvider struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	users      map[string]LocalUser
+	now        func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*localSession
+}
+
+type localSession struct {
+	userID       uuid.UUID
+	startedAt    time.Time
+	lastAccessAt time.Time
+	expiresAt    time.Time
+}
+
+func NewLocalIdentityProvider(secret []byte, accessTTL, refreshTTL time.Duration, users []LocalUser) IdentityProvider {
+	byName := make(map[string]LocalUser, len(users))
+	for _, user := range users {
+		byName[user.Username] = user
+	}
+	return &localIdentityProvider{
+		secret:     secret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		users:      byName,
+		now:        time.Now,
+		sessions:   map[string]*localSession{},
+	}
+}
+
+// LocalUserID — ID, под которым локальный провайдер выдаёт токены пользователю username.
+func LocalUserID(username string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("lms-local:"+username))
+}
+
+func (p *localIdentityProvider) Verify(_ context.Context, accessToken string) (*pkg.Principal, error) {
+	claims, err := p.parse(accessToken, tokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+	principal := principalFromClaims(claims)
+	if !p.sessionActive(principal.SessionID, false) {
+		return nil, pkg.ErrInvalidToken.Wrap(errors.New("session is not active"))
+	}
+	return principal, nil
+}
+
+func (p *localIdentityProvider) Login(_ context.Context, username, password string) (*Tokens, error) {
+	user, ok := p.users[username]
+	if !ok || subtle.ConstantTimeCompare([]byte(user.Password), []byte(password)) != 1 {
+		return nil, pkg.ErrInvalidCredentials
+	}
+
+	now := p.now()
+	sessionID := uuid.NewString()
+	p.mu.Lock()
+	p.sessions[sessionID] = &localSession{
+		userID:       LocalUserID(username),
+		startedAt:    now,
+		lastAccessAt: now,
+		expiresAt:    now.Add(p.refreshTTL),
+	}
+	p.mu.Unlock()
+	return p.issue(user, sessionID)
+}
+
+// Refresh выдаёт новую пару токенов с актуальными ролями пользователя.
+func (p *localIdentityProvider) Refresh(_ context.Context, refreshToken string) (*Tokens, error) {
+	claims, err := p.parse(refreshToken, tokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+	username, _ := claims["preferred_username"].(string)
+	user, ok := p.users[username]
+	sessionID, _ := claims["sid"].(string)
+	if !ok || !p.sessionActive(sessionID, true) {
+		return nil, pkg.ErrInvalidToken
+	}
+	return p.issue(user, sessionID)
+}
+
+func (p *localIdentityProvider) Logout(_ context.Context, refreshToken string) error {
+	claims, err := p.parse(refreshToken, tokenTypeRefresh)
+	if err != nil {
+		return err
+	}
+	sessionID, _ := claims["sid"].(string)
+	p.mu.Lock()
+	delete(p.sessions, sessionID)
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *localIdentityProvider) Sessions(_ context.Context, userID uuid.UUID) ([]Session, error) {
+	now := p.now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sessions := []Session{}
+	for id, s := range p.sessions {
+		if s.userID == userID && now.Before(s.expiresAt) {
+			sessions = append(sessions, Session{ID: id, StartedAt: s.startedAt, LastAccessAt: s.lastAccessAt, Clients: []string{localIssuer}})
+		}
+	}
+	slices.SortFunc(sessions, func(a, b Session) int { return a.StartedAt.Compare(b.StartedAt) })
+	return sessions, nil
+}
+
+func (p *localIdentityProvider) RevokeSession(_ context.Context, userID uuid.UUID, sessionID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	session, ok := p.sessions[sessionID]
+	if !ok || session.userID != userID {
+		return pkg.ErrSessionNotFound
+	}
+	delete(p.sessions, sessionID)
+	return nil
+}
+
+func (p *localIdentityProvider) RevokeAllSessions(_ context.Context, userID uuid.UUID) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, session := range p.sessions {
+		if session.userID == userID {
+			delete(p.sessions, id)
+		}
+	}
+	return nil
+}
+
+// sessionActive сообщает, не завершена ли сессия. touch отмечает обращение и продлевает её,
+// как при обновлении токенов; заодно удаляются истёкшие сессии.
+func (p *localIdentityProvider) sessionActive(sessionID string, touch bool) bool {
+	now := p.now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	session, ok := p.sessions[sessionID]
+	if !ok || !now.Before(session.expiresAt) {
+		return false
+	}
+	if touch {
+		session.lastAccessAt = now
+		session.expiresAt = now.Add(p.refreshTTL)
+		for id, s := range p.sessions {
+			if !now.Before(s.expiresAt) {
+				delete(p.sessions, id)
+			}
+		}
+	}
+	return true
+}
+
+func (p *localIdentityProvider) Check(context.Context) error {
+	return nil
+}
+
+func (p *localIdentityProvider) issue(user LocalUser, sessionID string) (*Tokens, error) {
+	access, err := p.sign(user, sessionID, tokenTypeAccess, p.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := p.sign(user, sessionID, tokenTypeRefresh, p.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &Tokens{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    int(p.accessTTL.Seconds()),
+		TokenType:    tokenTypeAccess,
+	}, nil
+}
+
+func (p *localIdentityProvider) sign(user LocalUser, sessionID, tokenType string, ttl time.Duration) (string, error) {
+	now := p.now()
+	roles := make([]interface{}, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roles = append(roles, role)
+	}
+	claims := jwt.MapClaims{
+		"iss":                localIssuer,
+		"sub":                LocalUserID(user.Username).String(),
+		"jti":                uuid.NewString(),
+		"iat":                now.Unix(),
+		"exp":                now.Add(ttl).Unix(),
+		"typ":                tokenType,
+		"sid":                sessionID,
+		"preferred_username": user.Username,
+		"realm_access":       map[string]interface{}{"roles": roles},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(p.secret)
+	if err != nil {
+		return "", pkg.ErrInternal.Wrap(err)
+	}
+	return signed, nil
+}
+
+// parse проверяет подпись, срок действия, издателя и тип токена. Срок сверяется с p.now,
+// а не с часами библиотеки.
+func (p *localIdentityProvider) parse(tokenString, tokenType string) (jwt.MapClaims, error) {
+	parser := jwt.Parser{ValidMethods: []string{jwt.SigningMethodHS256.Alg()}, SkipClaimsValidation: true}
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return p.secret, nil
+	})
+	if err != nil {
+		return nil, pkg.ErrInvalidToken.Wrap(err)
+	}
+	if !claims.VerifyExpiresAt(p.now().Unix(), true) {
+		return nil, pkg.ErrInvalidToken.Wrap(errors.New("token is expired"))
+	}
+	if !claims.VerifyIssuer(localIssuer, true) || claims["typ"] != tokenType {
+		return nil, pkg.ErrInvalidToken.Wrap(errors.New("unexpected token issuer or type"))
+	}
+	return claims, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lms-system-internship/pkg"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalIdentityProvider(t *testing.T) {
+	ctx := context.Background()
+	users := []LocalUser{{Username: "alice", Password: "secret", Roles: []string{"ROLE_ADMIN"}}}
+	provider := NewLocalIdentityProvider([]byte("test-secret"), time.Minute, time.Hour, users)
+
+	t.Run("login issues tokens that verify", func(t *testing.T) {
+		tokens, err := provider.Login(ctx, "alice", "secret")
+		assert.NoError(t, err)
+		assert.Equal(t, 60, tokens.ExpiresIn)
+
+		principal, err := provider.Verify(ctx, tokens.AccessToken)
+		assert.NoError(t, err)
+		assert.Equal(t, LocalUserID("alice"), principal.ID)
+		assert.Equal(t, "alice", principal.Username)
+		assert.Equal(t, []string{"ROLE_ADMIN"}, principal.Roles)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := provider.Login(ctx, "alice", "wrong")
+		assert.ErrorIs(t, err, pkg.ErrInvalidCredentials)
+
+		_, err = provider.Login(ctx, "bob", "secret")
+		assert.ErrorIs(t, err, pkg.ErrInvalidCredentials)
+	})
+
+	t.Run("refresh token is not an access token", func(t *testing.T) {
+		tokens, _ := provider.Login(ctx, "alice", "secret")
+
+		_, err := provider.Verify(ctx, tokens.RefreshToken)
+		assert.ErrorIs(t, err, pkg.ErrInvalidToken)
+
+		_, err = provider.Refresh(ctx, tokens.AccessToken)
+		assert.ErrorIs(t, err, pkg.ErrInvalidToken)
+
+		refreshed, err := provider.Refresh(ctx, tokens.RefreshToken)
+		assert.NoError(t, err)
+		_, err = provider.Verify(ctx, refreshed.AccessToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		issuer := NewLocalIdentityProvider([]byte("test-secret"), time.Minute, time.Hour, users).(*localIdentityProvider)
+		issuer.now = func() time.Time { return time.Now().Add(-2 * time.Minute) }
+		tokens, _ := issuer.Login(ctx, "alice", "secret")
+		issuer.now = time.Now
+
+		_, err := issuer.Verify(ctx, tokens.AccessToken)
+		assert.ErrorIs(t, err, pkg.ErrInvalidToken)
+	})
+
+	t.Run("token signed with another secret", func(t *testing.T) {
+		other := NewLocalIdentityProvider([]byte("other-secret"), time.Minute, time.Hour, users)
+		tokens, _ := other.Login(ctx, "alice", "secret")
+
+		_, err := provider.Verify(ctx, tokens.AccessToken)
+		assert.ErrorIs(t, err, pkg.ErrInvalidToken)
+	})
+}
+
+func TestLocalIdentityProvider_Sessions(t *testing.T) {
+	ctx := context.Background()
+	users := []LocalUser{{Username: "alice", Password: "secret"}, {Username: "bob", Password: "secret"}}
+	provider := NewLocalIdentityProvider([]byte("test-secret"), time.Minute, time.Hour, users)
+	alice, bob := LocalUserID("alice"), LocalUserID("bob")
+
+	t.Run("logout ends the session", func(t *testing.T) {
+		tokens, _ := provider.Login(ctx, "alice", "secret")
+
+		assert.NoError(t, provider.Logout(ctx, tokens.RefreshToken))
+
+		_, err := provider.Verify(ctx, tokens.AccessToken)
+		assert.ErrorIs(t, err, pkg.ErrInvalidToken)
+		_, err = provider.Refresh(ctx, tokens.RefreshToken)
+		assert.ErrorIs(t, err, pkg.ErrInvalidToken)
+	})
+
+	t.Run("list and revoke own sessions only", func(t *testing.T) {
+		laptop, _ := provider.Login(ctx, "alice", "secret")
+		phone, _ := provider.Login(ctx, "alice", "secret")
+		_, _ = provider.Login(ctx, "bob", "secret")
+
+		sessions, err := provider.Sessions(ctx, alice)
+		assert.NoError(t, err)
+		assert.Len(t, sessions, 2)
+
+		phonePrincipal, _ := provider.Verify(ctx, phone.AccessToken)
+		assert.ErrorIs(t, provider.RevokeSession(ctx, bob, phonePrincipal.SessionID), pkg.ErrSessionNotFound)
+		assert.NoError(t, provider.RevokeSession(ctx, alice, phonePrincipal.SessionID))
+
+		_, err = provider.Verify(ctx, phone.AccessToken)
+		assert.ErrorIs(t, err, pkg.ErrInvalidToken)
+		_, err = provider.Verify(ctx, laptop.AccessToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("revoke all sessions of a user", func(t *testing.T) {
+		_, _ = provider.Login(ctx, "alice", "secret")
+
+		assert.NoError(t, provider.RevokeAllSessions(ctx, alice))
+
+		sessions, _ := provider.Sessions(ctx, alice)
+		assert.Empty(t, sessions)
+		sessions, _ = provider.Sessions(ctx, bob)
+		assert.Len(t, sessions, 1)
+	})
+}
+
+func TestParseLocalUsers(t *testing.T) {
+	users, err := ParseLocalUsers("alice:secret:ROLE_ADMIN|ROLE_TEACHER, bob:pass")
+	assert.NoError(t, err)
+	assert.Equal(t, []LocalUser{
+		{Username: "alice", Password: "secret", Roles: []string{"ROLE_ADMIN", "ROLE_TEACHER"}},
+		{Username: "bob", Password: "pass", Roles: []string{}},
+	}, users)
+
+	_, err = ParseLocalUsers("alice")
+	assert.Error(t, err)
+}
+
+func TestPrincipalFromClaims(t *testing.T) {
+	id := uuid.New()
+	principal := principalFromClaims(jwt.MapClaims{
+		"sub":                id.String(),
+		"preferred_username": "alice",
+		"realm_access":       map[string]interface{}{"roles": []interface{}{"ROLE_TEACHER", 42}},
+		"resource_access": map[string]interface{}{
+			"backend-client": map[string]interface{}{"roles": []interface{}{"grader"}},
+		},
+		"groups": []interface{}{"/teachers"},
+	})
+
+	assert.Equal(t, &pkg.Principal{
+		ID:          id,
+		Username:    "alice",
+		Roles:       []string{"ROLE_TEACHER"},
+		ClientRoles: map[string][]string{"backend-client": {"grader"}},
+		Groups:      []string{"/teachers"},
+	}, principal)
+	assert.True(t, principal.HasClientRole("backend-client", "grader"))
+	assert.True(t, principal.InGroup("/teachers"))
+
+	ctx := pkg.WithPrincipal(context.Background(), principal)
+	assert.Equal(t, id, pkg.UserIDFromContext(ctx))
+	assert.Equal(t, uuid.Nil, pkg.UserIDFromContext(context.Background()))
+}
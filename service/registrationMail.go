@@ -0,0 +1,139 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"lms-system-internship/entities"
+	"lms-system-internship/mailer"
+	"text/template"
+	"time"
+)
+
+// emailData — поля шаблонов писем.
+type emailData struct {
+	Username string
+	Link     string
+	ValidFor time.Duration
+}
+
+type emailTemplate struct {
+	subject string
+	body    *template.Template
+}
+
+// emailInvitation — шаблон приглашения пользователю, созданному администратором; ссылка в нём —
+// токен сброса пароля.
+const emailInvitation = "invitation"
+
+// emailTemplates — письма по назначению токена (или emailInvitation) и локали. Для локали без перевода отправляется английское.
+var emailTemplates = map[string]map[string]emailTemplate{
+	entities.EmailTokenVerifyEmail: {
+		"en": {
+			subject: "Confirm your email",
+			body: mustEmailTemplate("en", `Hello, {{.Username}}!
+
+To finish signing up, confirm your email by opening the link:
+
+{{.Link}}
+
+The link is valid for {{validFor .ValidFor}}. If you did not sign up, ignore this email.
+`),
+		},
+		"ru": {
+			subject: "Подтвердите почту",
+			body: mustEmailTemplate("ru", `Здравствуйте, {{.Username}}!
+
+Чтобы завершить регистрацию, подтвердите почту по ссылке:
+
+{{.Link}}
+
+Ссылка действует {{validFor .ValidFor}}. Если вы не регистрировались, просто проигнорируйте письмо.
+`),
+		},
+	},
+	entities.EmailTokenResetPassword: {
+		"en": {
+			subject: "Password reset",
+			body: mustEmailTemplate("en", `Hello, {{.Username}}!
+
+Someone requested a password reset for your account. To set a new password, open the link:
+
+{{.Link}}
+
+The link is valid for {{validFor .ValidFor}}. If it was not you, ignore this email: your password stays the same.
+`),
+		},
+		"ru": {
+			subject: "Восстановление пароля",
+			body: mustEmailTemplate("ru", `Здравствуйте, {{.Username}}!
+
+Для вашей учётной записи запрошен сброс пароля. Чтобы задать новый пароль, откройте ссылку:
+
+{{.Link}}
+
+Ссылка действует {{validFor .ValidFor}}. Если это были не вы, проигнорируйте письмо: пароль останется прежним.
+`),
+		},
+	},
+	emailInvitation: {
+		"en": {
+			subject: "Your account has been created",
+			body: mustEmailTemplate("en", `Hello, {{.Username}}!
+
+An account with the username {{.Username}} has been created for you. To sign in, set a password by opening the link:
+
+{{.Link}}
+
+The link is valid for {{validFor .ValidFor}}. After that, use "Forgot password" on the sign-in page.
+`),
+		},
+		"ru": {
+			subject: "Для вас создана учётная запись",
+			body: mustEmailTemplate("ru", `Здравствуйте, {{.Username}}!
+
+Для вас создана учётная запись с логином {{.Username}}. Чтобы войти, задайте пароль по ссылке:
+
+{{.Link}}
+
+Ссылка действует {{validFor .ValidFor}}. Позже воспользуйтесь восстановлением пароля на странице входа.
+`),
+		},
+	},
+}
+
+func renderEmail(locale, templateName string, data emailData) (mailer.Message, error) {
+	templates := emailTemplates[templateName]
+	tmpl, ok := templates[locale]
+	if !ok {
+		tmpl = templates["en"]
+	}
+	var body bytes.Buffer
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return mailer.Message{}, err
+	}
+	return mailer.Message{Subject: tmpl.subject, Body: body.String()}, nil
+}
+
+func mustEmailTemplate(locale, text string) *template.Template {
+	return template.Must(template.New(locale).Funcs(template.FuncMap{
+		"validFor": func(d time.Duration) string { return formatValidFor(locale, d) },
+	}).Parse(text))
+}
+
+// formatValidFor записывает срок действия ссылки в часах или, если он меньше часа, в минутах.
+func formatValidFor(locale string, d time.Duration) string {
+	hours, minutes := int(d/time.Hour), int(d/time.Minute)
+	if locale == "ru" {
+		if hours > 0 {
+			return fmt.Sprintf("%d ч.", hours)
+		}
+		return fmt.Sprintf("%d мин.", minutes)
+	}
+	if hours == 1 {
+		return "1 hour"
+	}
+	if hours > 0 {
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
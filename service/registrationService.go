@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"lms-system-internship/entities"
+	"lms-system-internship/mailer"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RegistrationConfig — настройки самостоятельной регистрации и писем со ссылками.
+type RegistrationConfig struct {
+	// Enabled разрешает SignUp. Подтверждение почты и сброс пароля работают и без него.
+	Enabled bool
+	// EmailDomains — домены, с которых разрешена регистрация; пусто — любые.
+	EmailDomains []string
+	// Roles — роли realm нового пользователя.
+	Roles []string
+	// VerifyEmailURL и PasswordResetURL — ссылки для писем, {token} заменяется на токен.
+	VerifyEmailURL   string
+	PasswordResetURL string
+	VerifyTokenTTL   time.Duration
+	ResetTokenTTL    time.Duration
+	// InviteTokenTTL — срок действия ссылки из приглашения пользователю, созданному администратором.
+	InviteTokenTTL time.Duration
+	// ResendInterval — не чаще какого интервала пользователю отправляется письмо одного вида.
+	ResendInterval time.Duration
+	// EmailRequestLimit — сколько запросов письма одного вида принимается для одного адреса за
+	// EmailRequestWindow, зарегистрирован он или нет; 0 — без ограничения.
+	EmailRequestLimit  int
+	EmailRequestWindow time.Duration
+}
+
+// SignUp — данные пользователя, который регистрируется сам.
+type SignUp struct {
+	Username  string
+	Email     string
+	FirstName string
+	LastName  string
+	Password  string
+}
+
+// RegistrationService — регистрация без администратора, подтверждение почты и восстановление
+// пароля по ссылке из письма. Запросы писем по адресу почты всегда завершаются успешно, чтобы
+// по ответу нельзя было узнать, зарегистрирован ли адрес.
+type RegistrationService interface {
+	// SignUp создаёт выключенную учётную запись и отправляет письмо для подтверждения почты.
+	SignUp(ctx context.Context, signUp SignUp) (uuid.UUID, error)
+	// VerifyEmail подтверждает почту по токену из письма и включает учётную запись.
+	VerifyEmail(ctx context.Context, token string) error
+	// ResendVerification повторно отправляет письмо, если учётная запись ждёт подтверждения почты.
+	ResendVerification(ctx context.Context, email string) error
+	// RequestPasswordReset отправляет письмо со ссылкой для сброса пароля.
+	RequestPasswordReset(ctx context.Context, email string) error
+	// ResetPassword задаёт новый пароль по токену из письма и завершает все сессии пользователя.
+	ResetPassword(ctx context.Context, token, password string) error
+	// InviteUser отправляет пользователю, которого создал администратор, приглашение со ссылкой
+	// для установки пароля. Ссылка принимается ResetPassword.
+	InviteUser(ctx context.Context, account UserAccount) error
+}
+
+type registrationService struct {
+	cfg      RegistrationConfig
+	identity IdentityService
+	sessions IdentityProvider
+	users    UserAdminService
+	tokens   repo.EmailTokenRepository
+	mailer   mailer.Mailer
+	// requests ограничивает запросы писем по адресу почты: лимит по IP не защищает один ящик
+	// от запросов с разных адресов.
+	requests *pkg.RateLimiter
+	now      func() time.Time
+}
+
+// NewRegistrationService создаёт сервис регистрации. После сброса пароля сессии пользователя
+// завершаются через sessions, а кэш профилей обновляется через users.
+func NewRegistrationService(cfg RegistrationConfig, identity IdentityService, sessions IdentityProvider, users UserAdminService, tokens repo.EmailTokenRepository, mail mailer.Mailer) RegistrationService {
+	return &registrationService{
+		cfg:      cfg,
+		identity: identity,
+		sessions: sessions,
+		users:    users,
+		tokens:   tokens,
+		mailer:   mail,
+		requests: pkg.NewRateLimiter(cfg.EmailRequestLimit, cfg.EmailRequestWindow),
+		now:      time.Now,
+	}
+}
+
+func (s *registrationService) SignUp(ctx context.Context, signUp SignUp) (uuid.UUID, error) {
+	if !s.cfg.Enabled {
+		return uuid.Nil, pkg.ErrRegistrationDisabled
+	}
+	if !s.domainAllowed(signUp.Email) {
+		return uuid.Nil, pkg.ErrEmailDomainNotAllowed
+	}
+
+	userID, err := s.identity.RegisterUser(ctx, NewUser{
+		Username:                 signUp.Username,
+		Email:                    signUp.Email,
+		FirstName:                signUp.FirstName,
+		LastName:                 signUp.LastName,
+		Password:                 signUp.Password,
+		RequireEmailVerification: true,
+		Roles:                    s.cfg.Roles,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	s.refreshProfile(ctx, userID)
+
+	// Учётная запись уже создана: если письмо не ушло, пользователь запросит его повторно
+	account := UserAccount{ID: userID, Username: signUp.Username, Email: signUp.Email}
+	if err := s.sendToken(ctx, account, entities.EmailTokenVerifyEmail); err != nil {
+		pkg.LoggerFromContext(ctx).WithError(err).WithField("target_user_id", userID).Error("Failed to send verification email")
+	}
+	return userID, nil
+}
+
+func (s *registrationService) VerifyEmail(ctx context.Context, token string) error {
+	stored, err := s.useToken(ctx, entities.EmailTokenVerifyEmail, token)
+	if err != nil {
+		return err
+	}
+	// Если после отправки письма почту сменили, ссылка подтверждала бы уже другой адрес
+	account, err := s.identity.FindUserByEmail(ctx, stored.Email)
+	if errors.Is(err, pkg.ErrUserNotFound) || (err == nil && account.ID != stored.UserID) {
+		return pkg.ErrInvalidEmailToken
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.identity.ConfirmEmail(ctx, stored.UserID); err != nil {
+		if errors.Is(err, pkg.ErrUserNotFound) {
+			return pkg.ErrInvalidEmailToken.Wrap(err)
+		}
+		return err
+	}
+	s.refreshProfile(ctx, stored.UserID)
+	pkg.LoggerFromContext(ctx).WithField("target_user_id", stored.UserID).Info("Email verified")
+	return nil
+}
+
+func (s *registrationService) ResendVerification(ctx context.Context, email string) error {
+	if err := s.allowEmailRequest(ctx, entities.EmailTokenVerifyEmail, email); err != nil {
+		return err
+	}
+	account, err := s.identity.FindUserByEmail(ctx, email)
+	if errors.Is(err, pkg.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Подтверждённой и отключённой администратором учётной записи письмо не нужно
+	if !account.PendingVerification {
+		return nil
+	}
+	return s.resend(ctx, *account, entities.EmailTokenVerifyEmail)
+}
+
+func (s *registrationService) RequestPasswordReset(ctx context.Context, email string) error {
+	if err := s.allowEmailRequest(ctx, entities.EmailTokenResetPassword, email); err != nil {
+		return err
+	}
+	account, err := s.identity.FindUserByEmail(ctx, email)
+	if errors.Is(err, pkg.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Отключённой учётной записи новый пароль не поможет войти, а неподтверждённой нужно
+	// сначала подтвердить почту
+	if !account.Enabled {
+		return nil
+	}
+	return s.resend(ctx, *account, entities.EmailTokenResetPassword)
+}
+
+func (s *registrationService) ResetPassword(ctx context.Context, token, password string) error {
+	stored, err := s.useToken(ctx, entities.EmailTokenResetPassword, token)
+	if err != nil {
+		return err
+	}
+	if err := s.identity.UpdateProfile(ctx, stored.UserID, ProfileUpdate{Password: password}); err != nil {
+		if errors.Is(err, pkg.ErrUserNotFound) {
+			return pkg.ErrInvalidEmailToken.Wrap(err)
+		}
+		return err
+	}
+
+	logger := pkg.LoggerFromContext(ctx).WithField("target_user_id", stored.UserID)
+	// Остальные ссылки на сброс больше не нужны, а сессии могли быть открыты тем, кто узнал старый пароль
+	if err := s.tokens.InvalidateUser(ctx, stored.UserID, entities.EmailTokenResetPassword, s.now()); err != nil {
+		logger.WithError(err).Warn("Failed to invalidate password reset tokens")
+	}
+	if err := s.sessions.RevokeAllSessions(ctx, stored.UserID); err != nil {
+		logger.WithError(err).Warn("Failed to revoke sessions after password reset")
+	}
+	logger.Info("Password reset")
+	return nil
+}
+
+func (s *registrationService) InviteUser(ctx context.Context, account UserAccount) error {
+	return s.sendLink(ctx, account, entities.EmailTokenResetPassword, emailInvitation, s.cfg.InviteTokenTTL)
+}
+
+// allowEmailRequest учитывает запрос письма с назначением purpose для адреса email. Лимит
+// проверяется до поиска учётной записи, поэтому ответ одинаков для известных и неизвестных адресов.
+func (s *registrationService) allowEmailRequest(ctx context.Context, purpose, email string) error {
+	if allowed, _ := s.requests.Allow(purpose + " " + strings.ToLower(strings.TrimSpace(email))); !allowed {
+		pkg.LoggerFromContext(ctx).WithField("purpose", purpose).Warn("Email request limit exceeded")
+		return pkg.ErrTooManyRequests
+	}
+	return nil
+}
+
+// resend отправляет письмо, если предыдущее письмо того же вида ушло раньше ResendInterval назад.
+// Ошибка отправки только логируется, чтобы ответ не зависел от того, есть ли такой адрес.
+func (s *registrationService) resend(ctx context.Context, account UserAccount, purpose string) error {
+	latest, err := s.tokens.FindLatest(ctx, account.ID, purpose)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return err
+	}
+	logger := pkg.LoggerFromContext(ctx).WithField("target_user_id", account.ID).WithField("purpose", purpose)
+	if latest != nil && s.now().Sub(latest.CreatedAt) < s.cfg.ResendInterval {
+		logger.Info("Email not sent: requested too soon after the previous one")
+		return nil
+	}
+
+	if err := s.tokens.InvalidateUser(ctx, account.ID, purpose, s.now()); err != nil {
+		return err
+	}
+	if err := s.sendToken(ctx, account, purpose); err != nil {
+		logger.WithError(err).Error("Failed to send email")
+	}
+	return nil
+}
+
+// sendToken выпускает токен с назначением purpose и отправляет письмо со ссылкой.
+func (s *registrationService) sendToken(ctx context.Context, account UserAccount, purpose string) error {
+	ttl := s.cfg.VerifyTokenTTL
+	if purpose == entities.EmailTokenResetPassword {
+		ttl = s.cfg.ResetTokenTTL
+	}
+	return s.sendLink(ctx, account, purpose, purpose, ttl)
+}
+
+// sendLink выпускает токен с назначением purpose, действующий ttl, и отправляет письмо по шаблону
+// templateName со ссылкой на страницу, которая принимает токены этого назначения.
+func (s *registrationService) sendLink(ctx context.Context, account UserAccount, purpose, templateName string, ttl time.Duration) error {
+	token, err := newEmailToken()
+	if err != nil {
+		return err
+	}
+	link := s.cfg.VerifyEmailURL
+	if purpose == entities.EmailTokenResetPassword {
+		link = s.cfg.PasswordResetURL
+	}
+
+	now := s.now()
+	err = s.tokens.Save(ctx, &entities.EmailToken{
+		TokenHash: hashEmailToken(token),
+		Purpose:   purpose,
+		UserID:    account.ID,
+		Email:     account.Email,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	msg, err := renderEmail(pkg.LocaleFromContext(ctx), templateName, emailData{
+		Username: account.Username,
+		Link:     strings.ReplaceAll(link, "{token}", token),
+		ValidFor: ttl,
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = account.Email
+	return s.mailer.Send(ctx, msg)
+}
+
+// useToken находит действующий токен и отмечает его использованным; неизвестный, просроченный
+// или уже использованный токен — pkg.ErrInvalidEmailToken.
+func (s *registrationService) useToken(ctx context.Context, purpose, token string) (*entities.EmailToken, error) {
+	stored, err := s.tokens.FindByHash(ctx, purpose, hashEmailToken(token))
+	if err != nil {
+		return nil, notFound(err, pkg.ErrInvalidEmailToken)
+	}
+	now := s.now()
+	if stored.UsedAt != nil || !now.Before(stored.ExpiresAt) {
+		return nil, pkg.ErrInvalidEmailToken
+	}
+	if err := s.tokens.Use(ctx, stored.ID, now); err != nil {
+		return nil, notFound(err, pkg.ErrInvalidEmailToken)
+	}
+	return stored, nil
+}
+
+func (s *registrationService) domainAllowed(email string) bool {
+	if len(s.cfg.EmailDomains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	return at >= 0 && slices.Contains(s.cfg.EmailDomains, strings.ToLower(email[at+1:]))
+}
+
+func (s *registrationService) refreshProfile(ctx context.Context, userID uuid.UUID) {
+	if err := s.users.RefreshUser(ctx, userID); err != nil {
+		pkg.LoggerFromContext(ctx).WithError(err).WithField("target_user_id", userID).Warn("Failed to refresh cached user profile")
+	}
+}
+
+// newEmailToken создаёт случайный токен для ссылки из письма.
+func newEmailToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashEmailToken — под этим значением токен хранится в базе.
+func hashEmailToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
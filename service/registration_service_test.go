@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/mailer"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// signUpIdentityService хранит одну учётную запись и запоминает изменения.
+type signUpIdentityService struct {
+	IdentityService
+	account   *UserAccount
+	newUser   NewUser
+	confirmed []uuid.UUID
+	password  string
+}
+
+func (s *signUpIdentityService) RegisterUser(_ context.Context, user NewUser) (uuid.UUID, error) {
+	s.newUser = user
+	return s.account.ID, nil
+}
+
+func (s *signUpIdentityService) FindUserByEmail(_ context.Context, email string) (*UserAccount, error) {
+	if s.account == nil || s.account.Email != email {
+		return nil, pkg.ErrUserNotFound
+	}
+	return s.account, nil
+}
+
+func (s *signUpIdentityService) ConfirmEmail(_ context.Context, userID uuid.UUID) error {
+	s.confirmed = append(s.confirmed, userID)
+	return nil
+}
+
+func (s *signUpIdentityService) UpdateProfile(_ context.Context, _ uuid.UUID, update ProfileUpdate) error {
+	s.password = update.Password
+	return nil
+}
+
+var testRegistrationConfig = RegistrationConfig{
+	Enabled:          true,
+	EmailDomains:     []string{"example.com"},
+	Roles:            []string{"ROLE_STUDENT"},
+	VerifyEmailURL:   "https://lms.test/verify?token={token}",
+	PasswordResetURL: "https://lms.test/reset?token={token}",
+	VerifyTokenTTL:   24 * time.Hour,
+	ResetTokenTTL:    time.Hour,
+	InviteTokenTTL:   72 * time.Hour,
+	ResendInterval:   time.Minute,
+}
+
+// linkToken достаёт токен из ссылки в письме.
+func linkToken(t *testing.T, body, prefix string) string {
+	t.Helper()
+	start := strings.Index(body, prefix)
+	if !assert.GreaterOrEqual(t, start, 0, "the email contains the link") {
+		return ""
+	}
+	token, _, _ := strings.Cut(body[start+len(prefix):], "\n")
+	return token
+}
+
+func TestRegistrationService_SignUp(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	account := &UserAccount{ID: uuid.New(), Username: "ann", Email: "ann@example.com"}
+
+	t.Run("creates a disabled account and emails a link", func(t *testing.T) {
+		identity := &signUpIdentityService{account: account}
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(testRegistrationConfig, identity, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+		service.now = func() time.Time { return now }
+		var saved *entities.EmailToken
+		tokens.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*entities.EmailToken)
+		}).Return(nil)
+
+		userID, err := service.SignUp(context.Background(), SignUp{Username: "ann", Email: "ann@example.com", Password: "secret1"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, account.ID, userID)
+		assert.True(t, identity.newUser.RequireEmailVerification)
+		assert.Equal(t, []string{"ROLE_STUDENT"}, identity.newUser.Roles)
+		messages := mail.Messages()
+		if assert.Len(t, messages, 1) && assert.NotNil(t, saved) {
+			assert.Equal(t, "ann@example.com", messages[0].To)
+			token := linkToken(t, messages[0].Body, "https://lms.test/verify?token=")
+			assert.Equal(t, hashEmailToken(token), saved.TokenHash, "only the hash is stored")
+			assert.Equal(t, entities.EmailTokenVerifyEmail, saved.Purpose)
+			assert.Equal(t, now.Add(24*time.Hour), saved.ExpiresAt)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cfg := testRegistrationConfig
+		cfg.Enabled = false
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(cfg, &signUpIdentityService{account: account}, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+		service.now = func() time.Time { return now }
+
+		_, err := service.SignUp(context.Background(), SignUp{Username: "ann", Email: "ann@example.com"})
+
+		assert.ErrorIs(t, err, pkg.ErrRegistrationDisabled)
+	})
+
+	t.Run("email domain not allowed", func(t *testing.T) {
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(testRegistrationConfig, &signUpIdentityService{account: account}, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+		service.now = func() time.Time { return now }
+
+		_, err := service.SignUp(context.Background(), SignUp{Username: "ann", Email: "ann@gmail.com"})
+
+		assert.ErrorIs(t, err, pkg.ErrEmailDomainNotAllowed)
+	})
+}
+
+func TestRegistrationService_VerifyEmail(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	account := &UserAccount{ID: uuid.New(), Username: "ann", Email: "ann@example.com"}
+	stored := func() *entities.EmailToken {
+		return &entities.EmailToken{ID: 7, Purpose: entities.EmailTokenVerifyEmail, UserID: account.ID, Email: account.Email, ExpiresAt: now.Add(time.Hour)}
+	}
+
+	t.Run("confirms the email", func(t *testing.T) {
+		identity := &signUpIdentityService{account: account}
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(testRegistrationConfig, identity, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+		service.now = func() time.Time { return now }
+		tokens.On("FindByHash", mock.Anything, entities.EmailTokenVerifyEmail, hashEmailToken("tok")).Return(stored(), nil)
+		tokens.On("Use", mock.Anything, uint(7), now).Return(nil)
+
+		assert.NoError(t, service.VerifyEmail(context.Background(), "tok"))
+		assert.Equal(t, []uuid.UUID{account.ID}, identity.confirmed)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(testRegistrationConfig, &signUpIdentityService{account: account}, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+		service.now = func() time.Time { return now }
+		expired := stored()
+		expired.ExpiresAt = now
+		tokens.On("FindByHash", mock.Anything, entities.EmailTokenVerifyEmail, mock.Anything).Return(expired, nil)
+
+		assert.ErrorIs(t, service.VerifyEmail(context.Background(), "tok"), pkg.ErrInvalidEmailToken)
+	})
+
+	t.Run("token already used by a parallel request", func(t *testing.T) {
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(testRegistrationConfig, &signUpIdentityService{account: account}, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+		service.now = func() time.Time { return now }
+		tokens.On("FindByHash", mock.Anything, entities.EmailTokenVerifyEmail, mock.Anything).Return(stored(), nil)
+		tokens.On("Use", mock.Anything, uint(7), now).Return(repo.ErrNotFound)
+
+		assert.ErrorIs(t, service.VerifyEmail(context.Background(), "tok"), pkg.ErrInvalidEmailToken)
+	})
+
+	t.Run("email changed after the link was sent", func(t *testing.T) {
+		identity := &signUpIdentityService{account: &UserAccount{ID: account.ID, Email: "new@example.com"}}
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(testRegistrationConfig, identity, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+		service.now = func() time.Time { return now }
+		tokens.On("FindByHash", mock.Anything, entities.EmailTokenVerifyEmail, mock.Anything).Return(stored(), nil)
+		tokens.On("Use", mock.Anything, uint(7), now).Return(nil)
+
+		assert.ErrorIs(t, service.VerifyEmail(context.Background(), "tok"), pkg.ErrInvalidEmailToken)
+		assert.Empty(t, identity.confirmed)
+	})
+}
+
+func TestRegistrationService_RequestPasswordReset(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	account := &UserAccount{ID: uuid.New(), Username: "ann", Email: "ann@example.com", Enabled: true, EmailVerified: true}
+
+	t.Run("emails a reset link", func(t *testing.T) {
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(testRegistrationConfig, &signUpIdentityService{account: account}, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+		service.now = func() time.Time { return now }
+		tokens.On("FindLatest", mock.Anything, account.ID, entities.EmailTokenResetPassword).Return(nil, repo.ErrNotFound)
+		tokens.On("InvalidateUser", mock.Anything, account.ID, entities.EmailTokenResetPassword, now).Return(nil)
+		tokens.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+		assert.NoError(t, service.RequestPasswordReset(context.Background(), "ann@example.com"))
+		if messages := mail.Messages(); assert.Len(t, messages, 1) {
+			assert.Contains(t, messages[0].Body, "https://lms.test/reset?token=")
+			assert.Contains(t, messages[0].Body, "1 hour")
+		}
+	})
+
+	t.Run("unknown address is not revealed", func(t *testing.T) {
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(testRegistrationConfig, &signUpIdentityService{account: account}, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+		service.now = func() time.Time { return now }
+
+		assert.NoError(t, service.RequestPasswordReset(context.Background(), "nobody@example.com"))
+		assert.Empty(t, mail.Messages())
+		tokens.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("does not send again within the resend interval", func(t *testing.T) {
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(testRegistrationConfig, &signUpIdentityService{account: account}, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+		service.now = func() time.Time { return now }
+		tokens.On("FindLatest", mock.Anything, account.ID, entities.EmailTokenResetPassword).
+			Return(&entities.EmailToken{CreatedAt: now.Add(-30 * time.Second)}, nil)
+
+		assert.NoError(t, service.RequestPasswordReset(context.Background(), "ann@example.com"))
+		assert.Empty(t, mail.Messages())
+	})
+}
+
+func TestRegistrationService_ResendVerification(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("emails a new link while verification is pending", func(t *testing.T) {
+		account := &UserAccount{ID: uuid.New(), Username: "ann", Email: "ann@example.com", PendingVerification: true}
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(testRegistrationConfig, &signUpIdentityService{account: account}, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+		service.now = func() time.Time { return now }
+		tokens.On("FindLatest", mock.Anything, account.ID, entities.EmailTokenVerifyEmail).Return(nil, repo.ErrNotFound)
+		tokens.On("InvalidateUser", mock.Anything, account.ID, entities.EmailTokenVerifyEmail, now).Return(nil)
+		tokens.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+		assert.NoError(t, service.ResendVerification(context.Background(), "ann@example.com"))
+		assert.Len(t, mail.Messages(), 1)
+	})
+
+	t.Run("account disabled by an administrator gets no email", func(t *testing.T) {
+		account := &UserAccount{ID: uuid.New(), Username: "ann", Email: "ann@example.com"}
+		tokens := new(mocks.EmailTokenRepository)
+		mail := mailer.NewMemoryMailer()
+		service := NewRegistrationService(testRegistrationConfig, &signUpIdentityService{account: account}, &stubSessions{}, &stubProfiles{}, tokens, mail)
+
+		assert.NoError(t, service.ResendVerification(context.Background(), "ann@example.com"))
+		assert.Empty(t, mail.Messages())
+		tokens.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRegistrationService_EmailRequestLimit(t *testing.T) {
+	cfg := testRegistrationConfig
+	cfg.EmailRequestLimit = 2
+	cfg.EmailRequestWindow = time.Hour
+	service := NewRegistrationService(cfg, &signUpIdentityService{}, &stubSessions{}, &stubProfiles{}, new(mocks.EmailTokenRepository), mailer.NewMemoryMailer())
+	ctx := context.Background()
+
+	assert.NoError(t, service.RequestPasswordReset(ctx, "nobody@example.com"))
+	assert.NoError(t, service.RequestPasswordReset(ctx, "Nobody@example.com "))
+	assert.ErrorIs(t, service.RequestPasswordReset(ctx, "nobody@example.com"), pkg.ErrTooManyRequests,
+		"the limit applies to unknown addresses too")
+	assert.NoError(t, service.ResendVerification(ctx, "nobody@example.com"), "each kind of email has its own limit")
+	assert.NoError(t, service.RequestPasswordReset(ctx, "other@example.com"))
+}
+
+func TestRegistrationService_ResetPassword(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	identity := &signUpIdentityService{}
+	tokens := new(mocks.EmailTokenRepository)
+	mail := mailer.NewMemoryMailer()
+	sessions := &stubSessions{}
+	service := NewRegistrationService(testRegistrationConfig, identity, sessions, &stubProfiles{}, tokens, mail).(*registrationService)
+	service.now = func() time.Time { return now }
+	tokens.On("FindByHash", mock.Anything, entities.EmailTokenResetPassword, hashEmailToken("tok")).
+		Return(&entities.EmailToken{ID: 3, UserID: userID, ExpiresAt: now.Add(time.Hour)}, nil)
+	tokens.On("Use", mock.Anything, uint(3), now).Return(nil)
+	tokens.On("InvalidateUser", mock.Anything, userID, entities.EmailTokenResetPassword, now).Return(nil)
+
+	assert.NoError(t, service.ResetPassword(context.Background(), "tok", "new-secret"))
+	assert.Equal(t, "new-secret", identity.password)
+	assert.Equal(t, []uuid.UUID{userID}, sessions.revoked)
+	tokens.AssertExpectations(t)
+}
+
+func TestRegistrationService_InviteUser(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	account := UserAccount{ID: uuid.New(), Username: "ann", Email: "ann@example.com"}
+	tokens := new(mocks.EmailTokenRepository)
+	mail := mailer.NewMemoryMailer()
+	service := NewRegistrationService(testRegistrationConfig, &signUpIdentityService{}, &stubSessions{}, &stubProfiles{}, tokens, mail).(*registrationService)
+	service.now = func() time.Time { return now }
+	tokens.On("Save", mock.Anything, mock.MatchedBy(func(token *entities.EmailToken) bool {
+		return token.Purpose == entities.EmailTokenResetPassword && token.UserID == account.ID && token.ExpiresAt.Equal(now.Add(72*time.Hour))
+	})).Return(nil)
+
+	assert.NoError(t, service.InviteUser(context.Background(), account))
+	if messages := mail.Messages(); assert.Len(t, messages, 1) {
+		assert.Equal(t, "ann@example.com", messages[0].To)
+		assert.Equal(t, "Your account has been created", messages[0].Subject)
+		assert.Contains(t, messages[0].Body, "https://lms.test/reset?token=")
+		assert.Contains(t, messages[0].Body, "72 hours")
+	}
+	tokens.AssertExpectations(t)
+}
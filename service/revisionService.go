@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/pmezard/go-difflib/difflib"
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RevisionService interface {
+	GetRevisions(ctx context.Context, entityType string, entityID uint) ([]*entities.Revision, error)
+	GetRevision(ctx context.Context, entityType string, entityID uint, number int) (*entities.Revision, error)
+	Diff(ctx context.Context, entityType string, entityID uint, from, to int) (string, error)
+	Restore(ctx context.Context, authorID uuid.UUID, entityType string, entityID uint, number int) (*entities.Revision, error)
+
+	// RecordChange сохраняет новое состояние сущности. Если истории ещё нет,
+	// сначала сохраняется before — содержимое, которое было до первой правки.
+	RecordChange(ctx context.Context, authorID uuid.UUID, before, after *entities.Revision) error
+}
+
+type revisionService struct {
+	repo        repo.RevisionRepository
+	courseRepo  repo.CourseRepository
+	chapterRepo repo.ChapterRepository
+	lessonRepo  repo.LessonRepository
+	keep        int
+	maxAge      time.Duration
+	now         func() time.Time
+}
+
+// NewRevisionService создаёт сервис истории правок. keep — сколько последних ревизий хранить
+// на сущность, maxAge — сколько их хранить по времени; нулевые значения снимают ограничение.
+func NewRevisionService(repo repo.RevisionRepository, courseRepo repo.CourseRepository, chapterRepo repo.ChapterRepository, lessonRepo repo.LessonRepository, keep int, maxAge time.Duration) RevisionService {
+	return &revisionService{
+		repo:        repo,
+		courseRepo:  courseRepo,
+		chapterRepo: chapterRepo,
+		lessonRepo:  lessonRepo,
+		keep:        keep,
+		maxAge:      maxAge,
+		now:         time.Now,
+	}
+}
+
+func (s *revisionService) GetRevisions(ctx context.Context, entityType string, entityID uint) ([]*entities.Revision, error) {
+	return s.repo.FindByEntity(ctx, entityType, entityID)
+}
+
+func (s *revisionService) GetRevision(ctx context.Context, entityType string, entityID uint, number int) (*entities.Revision, error) {
+	revision, err := s.repo.FindByNumber(ctx, entityType, entityID, number)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkg.ErrRevisionNotFound
+	}
+	return revision, err
+}
+
+// Diff возвращает unified diff между ревизиями from и to.
+func (s *revisionService) Diff(ctx context.Context, entityType string, entityID uint, from, to int) (string, error) {
+	older, err := s.GetRevision(ctx, entityType, entityID, from)
+	if err != nil {
+		return "", err
+	}
+	newer, err := s.GetRevision(ctx, entityType, entityID, to)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(revisionText(older)),
+		B:        difflib.SplitLines(revisionText(newer)),
+		FromFile: "revision " + strconv.Itoa(from),
+		ToFile:   "revision " + strconv.Itoa(to),
+		Context:  3,
+	})
+}
+
+// Restore возвращает сущности содержимое ревизии number. Восстановление само становится
+// новой ревизией, так что его тоже можно откатить.
+func (s *revisionService) Restore(ctx context.Context, authorID uuid.UUID, entityType string, entityID uint, number int) (*entities.Revision, error) {
+	target, err := s.GetRevision(ctx, entityType, entityID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	var before, after *entities.Revision
+	switch entityType {
+	case entities.RevisionEntityCourse:
+		course, err := s.courseRepo.FindByID(ctx, entityID)
+		if err != nil {
+			return nil, notFound(err, pkg.ErrCourseNotFound)
+		}
+		before = courseRevision(course)
+		course.Name, course.Description = target.Name, target.Description
+		if err := s.courseRepo.Update(ctx, course); err != nil {
+			return nil, err
+		}
+		after = courseRevision(course)
+	case entities.RevisionEntityChapter:
+		chapter, err := s.chapterRepo.FindByID(ctx, entityID)
+		if err != nil {
+			return nil, notFound(err, pkg.ErrChapterNotFound)
+		}
+		before = chapterRevision(chapter)
+		chapter.Name, chapter.Description = target.Name, target.Description
+		if err := s.chapterRepo.Update(ctx, chapter); err != nil {
+			return nil, err
+		}
+		after = chapterRevision(chapter)
+	case entities.RevisionEntityLesson:
+		lesson, err := s.lessonRepo.FindByID(ctx, entityID)
+		if err != nil {
+			return nil, notFound(err, pkg.ErrLessonNotFound)
+		}
+		before = lessonRevision(lesson)
+		lesson.Name, lesson.Description = target.Name, target.Description
+		lesson.Content, lesson.ContentFormat = target.Content, target.ContentFormat
+		lesson.Blocks = restoreBlocks(lesson.Blocks, target.Blocks)
+		if err := s.lessonRepo.UpdateWithBlocks(ctx, lesson); err != nil {
+			return nil, err
+		}
+		after = lessonRevision(lesson)
+	default:
+		return nil, pkg.ErrInvalidInput
+	}
+
+	if err := s.RecordChange(ctx, authorID, before, after); err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+func (s *revisionService) RecordChange(ctx context.Context, authorID uuid.UUID, before, after *entities.Revision) error {
+	latest, err := s.repo.FindLatest(ctx, after.EntityType, after.EntityID)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		if before != nil && !sameRevisionContent(before, after) {
+			if err := s.repo.Save(ctx, before); err != nil {
+				return err
+			}
+		}
+	case err != nil:
+		return err
+	case sameRevisionContent(latest, after):
+		return nil
+	}
+
+	after.AuthorID = &authorID
+	if err := s.repo.Save(ctx, after); err != nil {
+		return err
+	}
+
+	var olderThan *time.Time
+	if s.maxAge > 0 {
+		threshold := s.now().Add(-s.maxAge)
+		olderThan = &threshold
+	}
+	return s.repo.Prune(ctx, after.EntityType, after.EntityID, s.keep, olderThan)
+}
+
+func courseRevision(course *entities.Course) *entities.Revision {
+	return &entities.Revision{
+		EntityType:  entities.RevisionEntityCourse,
+		EntityID:    course.ID,
+		Name:        course.Name,
+		Description: course.Description,
+	}
+}
+
+func chapterRevision(chapter *entities.Chapter) *entities.Revision {
+	return &entities.Revision{
+		EntityType:  entities.RevisionEntityChapter,
+		EntityID:    chapter.ID,
+		Name:        chapter.Name,
+		Description: chapter.Description,
+	}
+}
+
+// lessonRevision снимает состояние урока вместе с блоками; lesson.Blocks должны быть загружены по порядку.
+func lessonRevision(lesson *entities.Lesson) *entities.Revision {
+	var blocks []entities.RevisionBlock
+	for _, block := range lesson.Blocks {
+		blocks = append(blocks, entities.RevisionBlock{
+			ID:           block.ID,
+			Type:         block.Type,
+			Text:         block.Text,
+			Format:       block.Format,
+			Language:     block.Language,
+			AttachmentID: block.AttachmentID,
+			URL:          block.URL,
+			Caption:      block.Caption,
+			Variant:      block.Variant,
+		})
+	}
+	return &entities.Revision{
+		EntityType:    entities.RevisionEntityLesson,
+		EntityID:      lesson.ID,
+		Name:          lesson.Name,
+		Description:   lesson.Description,
+		Content:       lesson.Content,
+		ContentFormat: lesson.ContentFormat,
+		Blocks:        blocks,
+	}
+}
+
+// restoreBlocks строит блоки урока по ревизии. Блоки, которые ещё есть у урока, сохраняют
+// свой ID (и вместе с ним переводы), удалённые с тех пор создаются заново.
+func restoreBlocks(current []entities.LessonBlock, target []entities.RevisionBlock) []entities.LessonBlock {
+	existing := make(map[uint]bool, len(current))
+	for _, block := range current {
+		existing[block.ID] = true
+	}
+	blocks := make([]entities.LessonBlock, 0, len(target))
+	for i, block := range target {
+		restored := entities.LessonBlock{
+			Type:         block.Type,
+			Order:        i + 1,
+			Text:         block.Text,
+			Format:       block.Format,
+			Language:     block.Language,
+			AttachmentID: block.AttachmentID,
+			URL:          block.URL,
+			Caption:      block.Caption,
+			Variant:      block.Variant,
+		}
+		if existing[block.ID] {
+			restored.ID = block.ID
+		}
+		blocks = append(blocks, restored)
+	}
+	return blocks
+}
+
+func sameRevisionContent(a, b *entities.Revision) bool {
+	if a.Name != b.Name || a.Description != b.Description ||
+		a.Content != b.Content || a.ContentFormat != b.ContentFormat || len(a.Blocks) != len(b.Blocks) {
+		return false
+	}
+	for i := range a.Blocks {
+		x, y := a.Blocks[i], b.Blocks[i]
+		if x.Type != y.Type || x.Text != y.Text || x.Format != y.Format || x.Language != y.Language ||
+			x.URL != y.URL || x.Caption != y.Caption || x.Variant != y.Variant ||
+			(x.AttachmentID == nil) != (y.AttachmentID == nil) ||
+			(x.AttachmentID != nil && *x.AttachmentID != *y.AttachmentID) {
+			return false
+		}
+	}
+	return true
+}
+
+// revisionText — текстовое представление ревизии, по которому строится diff.
+func revisionText(revision *entities.Revision) string {
+	var b strings.Builder
+	b.WriteString("Name: " + revision.Name + "\n")
+	b.WriteString("Description: " + revision.Description + "\n")
+	if revision.EntityType == entities.RevisionEntityLesson {
+		b.WriteString("Format: " + revision.ContentFormat + "\n\n")
+		b.WriteString(revision.Content)
+		if !strings.HasSuffix(revision.Content, "\n") {
+			b.WriteString("\n")
+		}
+		for i, block := range revision.Blocks {
+			b.WriteString("\n--- block " + strconv.Itoa(i+1) + ": " + block.Type + "\n")
+			writeRevisionField(&b, "Format", block.Format)
+			writeRevisionField(&b, "Language", block.Language)
+			if block.AttachmentID != nil {
+				writeRevisionField(&b, "Attachment", strconv.FormatUint(uint64(*block.AttachmentID), 10))
+			}
+			writeRevisionField(&b, "URL", block.URL)
+			writeRevisionField(&b, "Caption", block.Caption)
+			writeRevisionField(&b, "Variant", block.Variant)
+			if block.Text != "" {
+				b.WriteString(block.Text)
+				if !strings.HasSuffix(block.Text, "\n") {
+					b.WriteString("\n")
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+func writeRevisionField(b *strings.Builder, name, value string) {
+	if value != "" {
+		b.WriteString(name + ": " + value + "\n")
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRevisionService_RecordChange(t *testing.T) {
+	authorID := uuid.New()
+	before := &entities.Revision{EntityType: entities.RevisionEntityLesson, EntityID: 1, Content: "old"}
+
+	t.Run("first change also stores the original content", func(t *testing.T) {
+		revisions := new(mocks.RevisionRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		service := NewRevisionService(revisions, courses, chapters, lessons, 10, 0)
+		after := &entities.Revision{EntityType: entities.RevisionEntityLesson, EntityID: 1, Content: "new"}
+		revisions.On("FindLatest", mock.Anything, entities.RevisionEntityLesson, uint(1)).Return(nil, repo.ErrNotFound)
+		revisions.On("Save", mock.Anything, before).Return(nil).Once()
+		revisions.On("Save", mock.Anything, after).Return(nil).Once()
+		revisions.On("Prune", mock.Anything, entities.RevisionEntityLesson, uint(1), 10, (*time.Time)(nil)).Return(nil)
+
+		err := service.RecordChange(context.Background(), authorID, before, after)
+
+		assert.NoError(t, err)
+		assert.Nil(t, before.AuthorID)
+		assert.Equal(t, authorID, *after.AuthorID)
+		revisions.AssertExpectations(t)
+	})
+
+	t.Run("unchanged content is not recorded", func(t *testing.T) {
+		revisions := new(mocks.RevisionRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		service := NewRevisionService(revisions, courses, chapters, lessons, 10, 0)
+		after := &entities.Revision{EntityType: entities.RevisionEntityLesson, EntityID: 1, Content: "same"}
+		revisions.On("FindLatest", mock.Anything, entities.RevisionEntityLesson, uint(1)).
+			Return(&entities.Revision{EntityType: entities.RevisionEntityLesson, EntityID: 1, Number: 4, Content: "same"}, nil)
+
+		err := service.RecordChange(context.Background(), authorID, before, after)
+
+		assert.NoError(t, err)
+		revisions.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("block change is recorded", func(t *testing.T) {
+		revisions := new(mocks.RevisionRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		service := NewRevisionService(revisions, courses, chapters, lessons, 10, 0)
+		after := &entities.Revision{EntityType: entities.RevisionEntityLesson, EntityID: 1, Content: "same", Blocks: []entities.RevisionBlock{
+			{ID: 7, Type: entities.BlockTypeText, Text: "new"},
+		}}
+		revisions.On("FindLatest", mock.Anything, entities.RevisionEntityLesson, uint(1)).
+			Return(&entities.Revision{EntityType: entities.RevisionEntityLesson, EntityID: 1, Number: 4, Content: "same", Blocks: []entities.RevisionBlock{
+				{ID: 7, Type: entities.BlockTypeText, Text: "old"},
+			}}, nil)
+		revisions.On("Save", mock.Anything, after).Return(nil)
+		revisions.On("Prune", mock.Anything, entities.RevisionEntityLesson, uint(1), 10, (*time.Time)(nil)).Return(nil)
+
+		err := service.RecordChange(context.Background(), authorID, before, after)
+
+		assert.NoError(t, err)
+		revisions.AssertExpectations(t)
+	})
+
+	t.Run("retention by age", func(t *testing.T) {
+		revisions := new(mocks.RevisionRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		service := NewRevisionService(revisions, courses, chapters, lessons, 0, 30*24*time.Hour).(*revisionService)
+		now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+		service.now = func() time.Time { return now }
+		threshold := now.Add(-30 * 24 * time.Hour)
+		after := &entities.Revision{EntityType: entities.RevisionEntityLesson, EntityID: 1, Content: "new"}
+		revisions.On("FindLatest", mock.Anything, entities.RevisionEntityLesson, uint(1)).
+			Return(&entities.Revision{EntityType: entities.RevisionEntityLesson, EntityID: 1, Number: 2, Content: "old"}, nil)
+		revisions.On("Save", mock.Anything, after).Return(nil)
+		revisions.On("Prune", mock.Anything, entities.RevisionEntityLesson, uint(1), 0, &threshold).Return(nil)
+
+		err := service.RecordChange(context.Background(), authorID, before, after)
+
+		assert.NoError(t, err)
+		revisions.AssertExpectations(t)
+	})
+}
+
+func TestRevisionService_Diff(t *testing.T) {
+	revisions := new(mocks.RevisionRepository)
+	courses := new(mocks.CourseRepository)
+	chapters := new(mocks.ChapterRepository)
+	lessons := new(mocks.LessonRepository)
+	service := NewRevisionService(revisions, courses, chapters, lessons, 0, 0)
+	revisions.On("FindByNumber", mock.Anything, entities.RevisionEntityLesson, uint(1), 1).
+		Return(&entities.Revision{EntityType: entities.RevisionEntityLesson, Name: "Intro", ContentFormat: "plain", Content: "line one\nline two\n"}, nil)
+	revisions.On("FindByNumber", mock.Anything, entities.RevisionEntityLesson, uint(1), 2).
+		Return(&entities.Revision{EntityType: entities.RevisionEntityLesson, Name: "Intro", ContentFormat: "plain", Content: "line one\nline 2\n", Blocks: []entities.RevisionBlock{
+			{Type: entities.BlockTypeCode, Language: "go", Text: "fmt.Println()"},
+		}}, nil)
+
+	diff, err := service.Diff(context.Background(), entities.RevisionEntityLesson, 1, 1, 2)
+
+	assert.NoError(t, err)
+	assert.Contains(t, diff, "--- revision 1")
+	assert.Contains(t, diff, "+++ revision 2")
+	assert.Contains(t, diff, "-line two")
+	assert.Contains(t, diff, "+line 2")
+	assert.Contains(t, diff, "+--- block 1: code")
+	assert.Contains(t, diff, "+fmt.Println()")
+}
+
+func TestRevisionService_Restore(t *testing.T) {
+	authorID := uuid.New()
+
+	t.Run("lesson", func(t *testing.T) {
+		revisions := new(mocks.RevisionRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		service := NewRevisionService(revisions, courses, chapters, lessons, 0, 0)
+		lesson := &entities.Lesson{ID: 1, Name: "Intro", Content: "current", ContentFormat: "markdown", Blocks: []entities.LessonBlock{
+			{ID: 5, LessonID: 1, Type: entities.BlockTypeText, Order: 1, Text: "edited", Format: "markdown"},
+			{ID: 6, LessonID: 1, Type: entities.BlockTypeCode, Order: 2, Text: "fmt.Println()", Language: "go"},
+		}}
+		revisions.On("FindByNumber", mock.Anything, entities.RevisionEntityLesson, uint(1), 1).
+			Return(&entities.Revision{EntityType: entities.RevisionEntityLesson, EntityID: 1, Number: 1, Name: "Intro", Content: "original", ContentFormat: "plain", Blocks: []entities.RevisionBlock{
+				{ID: 4, Type: entities.BlockTypeCallout, Text: "deleted since", Variant: "tip"},
+				{ID: 5, Type: entities.BlockTypeText, Text: "original", Format: "markdown"},
+			}}, nil)
+		lessons.On("FindByID", mock.Anything, uint(1)).Return(lesson, nil)
+		lessons.On("UpdateWithBlocks", mock.Anything, lesson).Return(nil)
+		revisions.On("FindLatest", mock.Anything, entities.RevisionEntityLesson, uint(1)).
+			Return(&entities.Revision{EntityType: entities.RevisionEntityLesson, EntityID: 1, Number: 3, Name: "Intro", Content: "current", ContentFormat: "markdown"}, nil)
+		revisions.On("Save", mock.Anything, mock.AnythingOfType("*entities.Revision")).Return(nil)
+		revisions.On("Prune", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		revision, err := service.Restore(context.Background(), authorID, entities.RevisionEntityLesson, 1, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "original", lesson.Content)
+		assert.Equal(t, "plain", lesson.ContentFormat)
+		assert.Equal(t, []entities.LessonBlock{
+			{Type: entities.BlockTypeCallout, Order: 1, Text: "deleted since", Variant: "tip"},
+			{ID: 5, Type: entities.BlockTypeText, Order: 2, Text: "original", Format: "markdown"},
+		}, lesson.Blocks)
+		assert.Equal(t, "original", revision.Content)
+		assert.Len(t, revision.Blocks, 2)
+		assert.Equal(t, authorID, *revision.AuthorID)
+		lessons.AssertExpectations(t)
+	})
+
+	t.Run("unknown revision", func(t *testing.T) {
+		revisions := new(mocks.RevisionRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		service := NewRevisionService(revisions, courses, chapters, lessons, 0, 0)
+		revisions.On("FindByNumber", mock.Anything, entities.RevisionEntityCourse, uint(1), 9).Return(nil, repo.ErrNotFound)
+
+		_, err := service.Restore(context.Background(), authorID, entities.RevisionEntityCourse, 1, 9)
+
+		assert.ErrorIs(t, err, pkg.ErrRevisionNotFound)
+	})
+}
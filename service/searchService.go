@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"strings"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+var allSearchTypes = []string{repo.SearchTypeCourse, repo.SearchTypeChapter, repo.SearchTypeLesson, repo.SearchTypeAttachment}
+
+type SearchParams struct {
+	Query    string
+	Types    []string
+	CourseID *uint
+	Limit    int
+	Offset   int
+}
+
+type SearchService interface {
+	Search(ctx context.Context, userID uuid.UUID, privileged bool, params SearchParams) ([]*repo.SearchHit, error)
+}
+
+type searchService struct {
+	repo           repo.SearchRepository
+	lessonUserRepo repo.LessonUserRepository
+}
+
+func NewSearchService(repo repo.SearchRepository, lessonUserRepo repo.LessonUserRepository) SearchService {
+	return &searchService{repo: repo, lessonUserRepo: lessonUserRepo}
+}
+
+// Search ищет по курсам, главам, урокам и вложениям. Уроки и вложения обычному пользователю
+// показываются только те, к которым у него есть доступ; privileged снимает это ограничение.
+func (s *searchService) Search(ctx context.Context, userID uuid.UUID, privileged bool, params SearchParams) ([]*repo.SearchHit, error) {
+	text := strings.TrimSpace(params.Query)
+	if text == "" {
+		return nil, pkg.ErrInvalidInput
+	}
+
+	types := allSearchTypes
+	if len(params.Types) > 0 {
+		types = nil
+		for _, t := range params.Types {
+			t = strings.TrimSpace(t)
+			if !isSearchType(t) {
+				return nil, pkg.ErrInvalidInput
+			}
+			types = append(types, t)
+		}
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	offset := params.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := repo.SearchQuery{
+		Text:     text,
+		Types:    types,
+		CourseID: params.CourseID,
+		Limit:    limit,
+		Offset:   offset,
+	}
+	if !privileged {
+		lessonIDs, err := s.lessonUserRepo.FindLessonIDsByUser(userID)
+		if err != nil {
+			return nil, err
+		}
+		query.LessonIDs = lessonIDs
+	}
+
+	return s.repo.Search(ctx, query)
+}
+
+func isSearchType(t string) bool {
+	for _, known := range allSearchTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSearchService_Search(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("student limited to accessible lessons", func(t *testing.T) {
+		searchRepo := new(mocks.SearchRepository)
+		lessonUsers := new(mocks.LessonUserRepository)
+		svc := NewSearchService(searchRepo, lessonUsers)
+
+		lessonUsers.On("FindLessonIDsByUser", userID).Return([]uint{1, 2}, nil)
+		hits := []*repo.SearchHit{{Type: repo.SearchTypeLesson, ID: 1, Title: "Goroutines"}}
+		searchRepo.On("Search", mock.Anything, repo.SearchQuery{
+			Text:      "goroutines",
+			Types:     allSearchTypes,
+			LessonIDs: []uint{1, 2},
+			Limit:     defaultSearchLimit,
+		}).Return(hits, nil)
+
+		result, err := svc.Search(context.Background(), userID, false, SearchParams{Query: " goroutines "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, hits, result)
+		searchRepo.AssertExpectations(t)
+	})
+
+	t.Run("teacher sees everything, limit capped", func(t *testing.T) {
+		searchRepo := new(mocks.SearchRepository)
+		lessonUsers := new(mocks.LessonUserRepository)
+		svc := NewSearchService(searchRepo, lessonUsers)
+
+		courseID := uint(3)
+		searchRepo.On("Search", mock.Anything, repo.SearchQuery{
+			Text:     "go",
+			Types:    []string{repo.SearchTypeCourse},
+			CourseID: &courseID,
+			Limit:    maxSearchLimit,
+			Offset:   10,
+		}).Return([]*repo.SearchHit{}, nil)
+
+		_, err := svc.Search(context.Background(), userID, true, SearchParams{
+			Query: "go", Types: []string{"course"}, CourseID: &courseID, Limit: 500, Offset: 10,
+		})
+
+		assert.NoError(t, err)
+		lessonUsers.AssertNotCalled(t, "FindLessonIDsByUser", mock.Anything)
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		svc := NewSearchService(new(mocks.SearchRepository), new(mocks.LessonUserRepository))
+
+		_, err := svc.Search(context.Background(), userID, true, SearchParams{Query: "  "})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		svc := NewSearchService(new(mocks.SearchRepository), new(mocks.LessonUserRepository))
+
+		_, err := svc.Search(context.Background(), userID, true, SearchParams{Query: "go", Types: []string{"user"}})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+}
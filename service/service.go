@@ -2,69 +2,133 @@ package service
 
 import (
 	"context"
-	"fmt"
 	"github.com/google/uuid"
+	"lms-system-internship/config"
 	"lms-system-internship/entities"
 	"lms-system-internship/files"
+	"lms-system-internship/pkg"
 	"lms-system-internship/repo"
+	"strings"
+	"time"
 )
 
-func NewService(repo *repo.Repository, fs files.FileStorage) *Service {
+func NewService(repo *repo.Repository, fs files.FileStorage, users UserDirectory, certificates CertificateRenderer) *Service {
+	gradebookService := NewGradebookService(repo.Gradebook, repo.Course)
+	revisionService := NewRevisionService(repo.Revision, repo.Course, repo.Chapter, repo.Lesson,
+		config.GetRevisionRetentionCount(), time.Duration(config.GetRevisionRetentionDays())*24*time.Hour)
+	translationService := NewTranslationService(repo.Translation, repo.Course, repo.Chapter, repo.Lesson, repo.LessonBlock,
+		config.GetDefaultLocale(), config.GetSupportedLocales())
 	return &Service{
-		CourseService:     NewCourseService(repo.Course),
-		ChapterService:    NewChapterService(repo.Chapter),
-		LessonService:     NewLessonService(repo.Lesson, repo.LessonUser),
-		AttachmentService: NewAttachmentService(repo.Attachment, repo.Lesson, repo.LessonUser, fs), // 👈 добавили
+		CourseService:      NewCourseService(repo.Course, revisionService, translationService),
+		ChapterService:     NewChapterService(repo.Chapter, repo.Course, revisionService, translationService),
+		LessonService:      NewLessonService(repo.Lesson, repo.Chapter, repo.LessonUser, repo.Attachment, fs, NewContentRenderer(), revisionService, translationService),
+		AttachmentService:  NewAttachmentService(repo.Attachment, repo.Lesson, repo.LessonUser, fs), // 👈 добавили
+		AssignmentService:  NewAssignmentService(repo.Assignment, repo.Submission, repo.Lesson, repo.LessonUser, fs, gradebookService),
+		GradebookService:   gradebookService,
+		CertificateService: NewCertificateService(repo.Certificate, repo.Course, repo.LessonUser, fs, users, certificates),
+		SearchService:      NewSearchService(repo.Search, repo.LessonUser),
+		TaxonomyService:    NewTaxonomyService(repo.Taxonomy, repo.Course, repo.Lesson),
+		LessonBlockService: NewLessonBlockService(repo.LessonBlock, repo.Lesson, repo.Attachment, revisionService, translationService),
+		RevisionService:    revisionService,
+		VersionService:     NewCourseVersionService(repo.Version, repo.Course, translationService, fs),
+		TranslationService: translationService,
 	}
 }
 
 // Course Service Implementation
 type courseService struct {
-	repo repo.CourseRepository
+	repo         repo.CourseRepository
+	revisions    RevisionService
+	translations TranslationService
 }
 
-func NewCourseService(repo repo.CourseRepository) CourseService {
-	return &courseService{repo: repo}
+func NewCourseService(repo repo.CourseRepository, revisions RevisionService, translations TranslationService) CourseService {
+	return &courseService{repo: repo, revisions: revisions, translations: translations}
 }
 
-func (s *courseService) GetAllCourses(ctx context.Context) ([]*entities.Course, error) {
-	return s.repo.FindAll(ctx)
+func (s *courseService) GetAllCourses(ctx context.Context, filter repo.CourseFilter) ([]*entities.Course, error) {
+	courses, err := s.repo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	localize(ctx, s.translations, func(t TranslationService) error { return t.LocalizeCourses(ctx, courses...) })
+	return courses, nil
+}
+
+func (s *courseService) GetTagFacets(ctx context.Context, filter repo.CourseFilter) ([]repo.TagFacet, error) {
+	return s.repo.TagFacets(ctx, filter)
 }
 
 func (s *courseService) GetCourse(ctx context.Context, courseID uint) (*entities.Course, error) {
-	return s.repo.FindByID(ctx, courseID)
+	course, err := s.repo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, notFound(err, pkg.ErrCourseNotFound)
+	}
+	localize(ctx, s.translations, func(t TranslationService) error { return t.LocalizeCourses(ctx, course) })
+	return course, nil
 }
 
 func (s *courseService) CreateCourse(ctx context.Context, course *entities.Course) error {
 	return s.repo.Save(ctx, course)
 }
 
-func (s *courseService) UpdateCourseDetails(ctx context.Context, course *entities.Course) error {
-	return s.repo.Update(ctx, course)
+// UpdateCourseDetails меняет название и описание курса; остальные поля берутся из базы,
+// и после обновления course содержит курс целиком.
+func (s *courseService) UpdateCourseDetails(ctx context.Context, authorID uuid.UUID, course *entities.Course) error {
+	existing, err := s.repo.FindByID(ctx, course.ID)
+	if err != nil {
+		return notFound(err, pkg.ErrCourseNotFound)
+	}
+	before := courseRevision(existing)
+	existing.Name = course.Name
+	existing.Description = course.Description
+
+	if err := s.repo.Update(ctx, existing); err != nil {
+		return err
+	}
+	recordRevision(ctx, s.revisions, authorID, before, courseRevision(existing))
+	*course = *existing
+	return nil
 }
 
 func (s *courseService) DeleteCourse(ctx context.Context, courseID uint) error {
-	return s.repo.Delete(ctx, courseID)
+	return notFound(s.repo.Delete(ctx, courseID), pkg.ErrCourseNotFound)
 }
 
 // Chapter Service Implementation
 type chapterService struct {
-	repo repo.ChapterRepository
+	repo         repo.ChapterRepository
+	courseRepo   repo.CourseRepository
+	revisions    RevisionService
+	translations TranslationService
 }
 
-func NewChapterService(repo repo.ChapterRepository) ChapterService {
-	return &chapterService{repo: repo}
+func NewChapterService(repo repo.ChapterRepository, courseRepo repo.CourseRepository, revisions RevisionService, translations TranslationService) ChapterService {
+	return &chapterService{repo: repo, courseRepo: courseRepo, revisions: revisions, translations: translations}
 }
 
 func (s *chapterService) GetAllChapters(ctx context.Context) ([]*entities.Chapter, error) {
-	return s.repo.FindAll(ctx)
+	chapters, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	localize(ctx, s.translations, func(t TranslationService) error { return t.LocalizeChapters(ctx, chapters...) })
+	return chapters, nil
 }
 
 func (s *chapterService) GetChapter(ctx context.Context, chapterID uint) (*entities.Chapter, error) {
-	return s.repo.FindByID(ctx, chapterID)
+	chapter, err := s.repo.FindByID(ctx, chapterID)
+	if err != nil {
+		return nil, notFound(err, pkg.ErrChapterNotFound)
+	}
+	localize(ctx, s.translations, func(t TranslationService) error { return t.LocalizeChapters(ctx, chapter) })
+	return chapter, nil
 }
 
 func (s *chapterService) AddChapterToCourse(ctx context.Context, courseID uint, chapter *entities.Chapter) error {
+	if _, err := s.courseRepo.FindByID(ctx, courseID); err != nil {
+		return missingParent(err, "course_id")
+	}
 	chapter.CourseID = courseID
 	return s.repo.Save(ctx, chapter)
 }
@@ -72,49 +136,171 @@ func (s *chapterService) AddChapterToCourse(ctx context.Context, courseID uint,
 func (s *chapterService) UpdateChapterOrder(ctx context.Context, chapterID uint, newOrder int) error {
 	chapter, err := s.repo.FindByID(ctx, chapterID)
 	if err != nil {
-		return err
+		return notFound(err, pkg.ErrChapterNotFound)
 	}
 	chapter.Order = newOrder
 	return s.repo.Update(ctx, chapter)
 }
 
+func (s *chapterService) UpdateChapterDetails(ctx context.Context, authorID uuid.UUID, chapterID uint, name, description string) error {
+	if strings.TrimSpace(name) == "" {
+		return pkg.ErrInvalidInput
+	}
+	chapter, err := s.repo.FindByID(ctx, chapterID)
+	if err != nil {
+		return notFound(err, pkg.ErrChapterNotFound)
+	}
+	before := chapterRevision(chapter)
+	chapter.Name = name
+	chapter.Description = description
+	if err := s.repo.Update(ctx, chapter); err != nil {
+		return err
+	}
+	recordRevision(ctx, s.revisions, authorID, before, chapterRevision(chapter))
+	return nil
+}
+
 func (s *chapterService) RemoveChapter(ctx context.Context, chapterID uint) error {
-	return s.repo.Delete(ctx, chapterID)
+	return notFound(s.repo.Delete(ctx, chapterID), pkg.ErrChapterNotFound)
 }
 
 // Lesson Service Implementation
 type lessonService struct {
 	repo           repo.LessonRepository
+	chapterRepo    repo.ChapterRepository
 	lessonUserRepo repo.LessonUserRepository
+	attachmentRepo repo.AttachmentRepository
+	fileStorage    files.FileStorage
+	renderer       ContentRenderer
+	revisions      RevisionService
+	translations   TranslationService
 }
 
-func NewLessonService(repo repo.LessonRepository, lessonUserRepo repo.LessonUserRepository) LessonService {
+func NewLessonService(repo repo.LessonRepository, chapterRepo repo.ChapterRepository, lessonUserRepo repo.LessonUserRepository, attachmentRepo repo.AttachmentRepository, fileStorage files.FileStorage, renderer ContentRenderer, revisions RevisionService, translations TranslationService) LessonService {
 	return &lessonService{
 		repo:           repo,
+		chapterRepo:    chapterRepo,
 		lessonUserRepo: lessonUserRepo,
+		attachmentRepo: attachmentRepo,
+		fileStorage:    fileStorage,
+		renderer:       renderer,
+		revisions:      revisions,
+		translations:   translations,
 	}
 }
 
 func (s *lessonService) GetAllLessons(ctx context.Context) ([]*entities.Lesson, error) {
-	return s.repo.FindAll(ctx)
+	lessons, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	localize(ctx, s.translations, func(t TranslationService) error { return t.LocalizeLessons(ctx, lessons...) })
+	return lessons, nil
 }
 
 func (s *lessonService) GetLesson(ctx context.Context, lessonID uint) (*entities.Lesson, error) {
-	return s.repo.FindByID(ctx, lessonID)
+	lesson, err := s.repo.FindByID(ctx, lessonID)
+	if err != nil {
+		return nil, notFound(err, pkg.ErrLessonNotFound)
+	}
+	localize(ctx, s.translations, func(t TranslationService) error { return t.LocalizeLessons(ctx, lesson) })
+	return lesson, nil
+}
+
+// GetRenderedLesson возвращает урок с заполненным ContentHTML и HTML текстовых блоков.
+// Рендерится уже переведённое содержимое.
+func (s *lessonService) GetRenderedLesson(ctx context.Context, lessonID uint) (*entities.Lesson, error) {
+	lesson, err := s.GetLesson(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.RenderLesson(lesson); err != nil {
+		return nil, err
+	}
+	return lesson, nil
+}
+
+func (s *lessonService) RenderLesson(lesson *entities.Lesson) error {
+	var err error
+	lesson.ContentHTML, err = s.renderer.Render(lesson.ContentFormat, lesson.Content)
+	if err != nil {
+		return err
+	}
+	for i := range lesson.Blocks {
+		if err := renderBlock(s.renderer, &lesson.Blocks[i]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *lessonService) AddLessonToChapter(ctx context.Context, chapterID uint, lesson *entities.Lesson) error {
+	if lesson.ContentFormat == "" {
+		lesson.ContentFormat = entities.ContentFormatPlain
+	}
+	if !isContentFormat(lesson.ContentFormat) {
+		return pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "content_format", Code: "oneof", Param: "plain markdown html"})
+	}
+	if _, err := s.chapterRepo.FindByID(ctx, chapterID); err != nil {
+		return missingParent(err, "chapter_id")
+	}
 	lesson.ChapterID = chapterID
+	lesson.Blocks = nil
+	if strings.TrimSpace(lesson.Content) != "" {
+		lesson.Blocks = []entities.LessonBlock{{Type: entities.BlockTypeText, Order: 1, Text: lesson.Content, Format: lesson.ContentFormat}}
+	}
 	return s.repo.Save(ctx, lesson)
 }
 
-func (s *lessonService) UpdateLessonContent(ctx context.Context, lessonID uint, content string) error {
+// UpdateLessonContent — запись через устаревшее поле content. Источник содержимого урока —
+// блоки, поэтому текст попадает в первый текстовый блок (или в новый блок в конце урока),
+// а Content остаётся его копией для старых клиентов. Пустой format оставляет прежний формат.
+func (s *lessonService) UpdateLessonContent(ctx context.Context, authorID uuid.UUID, lessonID uint, content, format string) error {
+	if format != "" && !isContentFormat(format) {
+		return pkg.ErrInvalidInput
+	}
 	lesson, err := s.repo.FindByID(ctx, lessonID)
 	if err != nil {
-		return err
+		return notFound(err, pkg.ErrLessonNotFound)
 	}
+	before := lessonRevision(lesson)
 	lesson.Content = content
-	return s.repo.Update(ctx, lesson)
+	if format != "" {
+		lesson.ContentFormat = format
+	}
+	if lesson.ContentFormat == "" {
+		lesson.ContentFormat = entities.ContentFormatPlain
+	}
+
+	// Пустой текст, как и раньше, очищает содержимое: текстовый блок удаляется
+	blank := strings.TrimSpace(content) == ""
+	updated := false
+	for i := range lesson.Blocks {
+		if lesson.Blocks[i].Type != entities.BlockTypeText {
+			continue
+		}
+		if blank {
+			lesson.Blocks = append(lesson.Blocks[:i], lesson.Blocks[i+1:]...)
+		} else {
+			lesson.Blocks[i].Text, lesson.Blocks[i].Format = content, lesson.ContentFormat
+		}
+		updated = true
+		break
+	}
+	if !updated && !blank {
+		order := 1
+		if n := len(lesson.Blocks); n > 0 {
+			order = lesson.Blocks[n-1].Order + 1
+		}
+		lesson.Blocks = append(lesson.Blocks, entities.LessonBlock{
+			LessonID: lesson.ID, Type: entities.BlockTypeText, Order: order, Text: content, Format: lesson.ContentFormat,
+		})
+	}
+	if err := s.repo.UpdateWithBlocks(ctx, lesson); err != nil {
+		return err
+	}
+	recordRevision(ctx, s.revisions, authorID, before, lessonRevision(lesson))
+	return nil
 }
 
 func (s *lessonService) ReorderLessons(ctx context.Context, chapterID uint, orderedLessonIDs []uint) error {
@@ -141,16 +327,37 @@ func (s *lessonService) ReorderLessons(ctx context.Context, chapterID uint, orde
 	return nil
 }
 
+// DeleteLesson удаляет урок вместе с вложениями. Файлы вложений убираются из хранилища уже
+// после удаления записей; если это не удалось, урок всё равно считается удалённым.
 func (s *lessonService) DeleteLesson(ctx context.Context, lessonID uint) error {
-	return s.repo.Delete(ctx, lessonID)
+	attachments, err := s.attachmentRepo.FindByLessonID(ctx, lessonID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, lessonID); err != nil {
+		return notFound(err, pkg.ErrLessonNotFound)
+	}
+	for _, attachment := range attachments {
+		if err := s.fileStorage.DeleteFile(ctx, attachment.URL); err != nil {
+			pkg.LoggerFromContext(ctx).WithError(err).WithField("file_url", attachment.URL).Warn("Failed to delete attachment file of deleted lesson")
+		}
+	}
+	return nil
 }
 
 func (s *lessonService) GrantAccess(ctx context.Context, userID uuid.UUID, lessonID uint) error {
-	// можно добавить проверку, существует ли Lesson
 	_, err := s.repo.FindByID(ctx, lessonID)
 	if err != nil {
-		return fmt.Errorf("lesson not found: %w", err)
+		return notFound(err, pkg.ErrLessonNotFound)
 	}
 
 	return s.lessonUserRepo.GrantAccess(userID, lessonID)
 }
+
+// recordRevision сохраняет ревизию после успешной правки. Ошибка истории не отменяет саму правку,
+// поэтому только логируется.
+func recordRevision(ctx context.Context, revisions RevisionService, authorID uuid.UUID, before, after *entities.Revision) {
+	if err := revisions.RecordChange(ctx, authorID, before, after); err != nil {
+		pkg.LoggerFromContext(ctx).WithError(err).WithField("entity_type", after.EntityType).WithField("entity_id", after.EntityID).Warn("Failed to record revision")
+	}
+}
@@ -4,13 +4,15 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"lms-system-internship/entities"
+	"lms-system-internship/repo"
 )
 
 type CourseService interface {
-	GetAllCourses(ctx context.Context) ([]*entities.Course, error)
+	GetAllCourses(ctx context.Context, filter repo.CourseFilter) ([]*entities.Course, error)
+	GetTagFacets(ctx context.Context, filter repo.CourseFilter) ([]repo.TagFacet, error)
 	GetCourse(ctx context.Context, courseID uint) (*entities.Course, error)
 	CreateCourse(ctx context.Context, course *entities.Course) error
-	UpdateCourseDetails(ctx context.Context, course *entities.Course) error
+	UpdateCourseDetails(ctx context.Context, authorID uuid.UUID, course *entities.Course) error
 	DeleteCourse(ctx context.Context, courseID uint) error
 }
 
@@ -19,22 +21,35 @@ type ChapterService interface {
 	GetChapter(ctx context.Context, chapterID uint) (*entities.Chapter, error)
 	AddChapterToCourse(ctx context.Context, courseID uint, chapter *entities.Chapter) error
 	UpdateChapterOrder(ctx context.Context, chapterID uint, newOrder int) error
+	UpdateChapterDetails(ctx context.Context, authorID uuid.UUID, chapterID uint, name, description string) error
 	RemoveChapter(ctx context.Context, chapterID uint) error
 }
 
 type LessonService interface {
 	GetAllLessons(ctx context.Context) ([]*entities.Lesson, error)
 	GetLesson(ctx context.Context, lessonID uint) (*entities.Lesson, error)
+	GetRenderedLesson(ctx context.Context, lessonID uint) (*entities.Lesson, error)
+	// RenderLesson заполняет ContentHTML и HTML текстовых блоков уже загруженного урока.
+	RenderLesson(lesson *entities.Lesson) error
 	AddLessonToChapter(ctx context.Context, chapterID uint, lesson *entities.Lesson) error
-	UpdateLessonContent(ctx context.Context, lessonID uint, content string) error
+	UpdateLessonContent(ctx context.Context, authorID uuid.UUID, lessonID uint, content, format string) error
 	ReorderLessons(ctx context.Context, chapterID uint, orderedLessonIDs []uint) error
 	DeleteLesson(ctx context.Context, lessonID uint) error
 	GrantAccess(ctx context.Context, userID uuid.UUID, lessonID uint) error
 }
 
 type Service struct {
-	CourseService     CourseService
-	ChapterService    ChapterService
-	LessonService     LessonService
-	AttachmentService AttachmentService
+	CourseService      CourseService
+	ChapterService     ChapterService
+	LessonService      LessonService
+	AttachmentService  AttachmentService
+	AssignmentService  AssignmentService
+	GradebookService   GradebookService
+	CertificateService CertificateService
+	SearchService      SearchService
+	TaxonomyService    TaxonomyService
+	LessonBlockService LessonBlockService
+	RevisionService    RevisionService
+	VersionService     CourseVersionService
+	TranslationService TranslationService
 }
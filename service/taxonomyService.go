@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxTagLength = 100
+
+type TaxonomyService interface {
+	GetCategoryTree(ctx context.Context) ([]*entities.Category, error)
+	CreateCategory(ctx context.Context, category *entities.Category) error
+	UpdateCategory(ctx context.Context, category *entities.Category) error
+	DeleteCategory(ctx context.Context, categoryID uint) error
+
+	GetTags(ctx context.Context) ([]*entities.Tag, error)
+	CreateTag(ctx context.Context, tag *entities.Tag) error
+	UpdateTag(ctx context.Context, tag *entities.Tag) error
+	DeleteTag(ctx context.Context, tagID uint) error
+
+	SetCourseTaxonomy(ctx context.Context, courseID uint, categoryID *uint, tags []string) error
+	SetLessonTaxonomy(ctx context.Context, lessonID uint, categoryID *uint, tags []string) error
+}
+
+type taxonomyService struct {
+	repo       repo.TaxonomyRepository
+	courseRepo repo.CourseRepository
+	lessonRepo repo.LessonRepository
+}
+
+func NewTaxonomyService(repo repo.TaxonomyRepository, courseRepo repo.CourseRepository, lessonRepo repo.LessonRepository) TaxonomyService {
+	return &taxonomyService{repo: repo, courseRepo: courseRepo, lessonRepo: lessonRepo}
+}
+
+// GetCategoryTree возвращает корневые рубрики с вложенными подрубриками в Children.
+func (s *taxonomyService) GetCategoryTree(ctx context.Context) ([]*entities.Category, error) {
+	categories, err := s.repo.FindAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*entities.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	roots := []*entities.Category{}
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+			continue
+		}
+		if parent, ok := byID[*category.ParentID]; ok {
+			parent.Children = append(parent.Children, category)
+		}
+	}
+	return roots, nil
+}
+
+func (s *taxonomyService) CreateCategory(ctx context.Context, category *entities.Category) error {
+	if err := s.validateCategory(ctx, category); err != nil {
+		return err
+	}
+	return s.repo.SaveCategory(ctx, category)
+}
+
+// UpdateCategory меняет только название и родителя; остальные поля берутся из сохранённой рубрики.
+func (s *taxonomyService) UpdateCategory(ctx context.Context, category *entities.Category) error {
+	existing, err := s.getCategory(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.validateCategory(ctx, category); err != nil {
+		return err
+	}
+	existing.Name = category.Name
+	existing.ParentID = category.ParentID
+	if err := s.repo.UpdateCategory(ctx, existing); err != nil {
+		return err
+	}
+	*category = *existing
+	return nil
+}
+
+// DeleteCategory удаляет только рубрики без подрубрик, чтобы случайно не потерять целую ветку.
+func (s *taxonomyService) DeleteCategory(ctx context.Context, categoryID uint) error {
+	children, err := s.repo.CountChildCategories(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return pkg.ErrCategoryNotEmpty
+	}
+
+	err = s.repo.DeleteCategory(ctx, categoryID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkg.ErrCategoryNotFound
+	}
+	return err
+}
+
+func (s *taxonomyService) GetTags(ctx context.Context) ([]*entities.Tag, error) {
+	return s.repo.FindAllTags(ctx)
+}
+
+func (s *taxonomyService) CreateTag(ctx context.Context, tag *entities.Tag) error {
+	name, ok := normalizeTag(tag.Name)
+	if !ok {
+		return pkg.ErrInvalidInput
+	}
+	tag.Name = name
+	return tagConflict(s.repo.SaveTag(ctx, tag))
+}
+
+func (s *taxonomyService) UpdateTag(ctx context.Context, tag *entities.Tag) error {
+	existing, err := s.repo.FindTagByID(ctx, tag.ID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkg.ErrTagNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	name, ok := normalizeTag(tag.Name)
+	if !ok {
+		return pkg.ErrInvalidInput
+	}
+	tag.Name = name
+	tag.CreatedAt = existing.CreatedAt
+	return tagConflict(s.repo.UpdateTag(ctx, tag))
+}
+
+// tagConflict сообщает клиенту о занятом названии тега вместо внутренней ошибки.
+func tagConflict(err error) error {
+	if errors.Is(err, repo.ErrDuplicate) {
+		return pkg.ErrTagAlreadyExists
+	}
+	return err
+}
+
+func (s *taxonomyService) DeleteTag(ctx context.Context, tagID uint) error {
+	err := s.repo.DeleteTag(ctx, tagID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return pkg.ErrTagNotFound
+	}
+	return err
+}
+
+func (s *taxonomyService) SetCourseTaxonomy(ctx context.Context, courseID uint, categoryID *uint, tags []string) error {
+	if _, err := s.courseRepo.FindByID(ctx, courseID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return pkg.ErrCourseNotFound
+		}
+		return err
+	}
+	names, err := s.prepareTaxonomy(ctx, categoryID, tags)
+	if err != nil {
+		return err
+	}
+	return s.repo.SetCourseTaxonomy(ctx, courseID, categoryID, names)
+}
+
+func (s *taxonomyService) SetLessonTaxonomy(ctx context.Context, lessonID uint, categoryID *uint, tags []string) error {
+	if _, err := s.lessonRepo.FindByID(ctx, lessonID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return pkg.ErrLessonNotFound
+		}
+		return err
+	}
+	names, err := s.prepareTaxonomy(ctx, categoryID, tags)
+	if err != nil {
+		return err
+	}
+	return s.repo.SetLessonTaxonomy(ctx, lessonID, categoryID, names)
+}
+
+// prepareTaxonomy проверяет рубрику и приводит теги к нормальной форме без повторов.
+func (s *taxonomyService) prepareTaxonomy(ctx context.Context, categoryID *uint, tags []string) ([]string, error) {
+	if categoryID != nil {
+		if _, err := s.getCategory(ctx, *categoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		name, ok := normalizeTag(tag)
+		if !ok {
+			return nil, pkg.ErrInvalidInput
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// validateCategory проверяет имя и родителя. Родителем не может быть сама рубрика или её потомок —
+// иначе дерево превратится в цикл.
+func (s *taxonomyService) validateCategory(ctx context.Context, category *entities.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return pkg.ErrInvalidInput
+	}
+	if category.ParentID == nil {
+		return nil
+	}
+
+	parentID := *category.ParentID
+	for {
+		if category.ID != 0 && parentID == category.ID {
+			return pkg.ErrInvalidInput
+		}
+		parent, err := s.getCategory(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		if parent.ParentID == nil {
+			return nil
+		}
+		parentID = *parent.ParentID
+	}
+}
+
+func (s *taxonomyService) getCategory(ctx context.Context, id uint) (*entities.Category, error) {
+	category, err := s.repo.FindCategoryByID(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, pkg.ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// normalizeTag приводит тег к нижнему регистру без пробелов по краям.
+func normalizeTag(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || utf8.RuneCountInString(name) > maxTagLength {
+		return "", false
+	}
+	return name, true
+}
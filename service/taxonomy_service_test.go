@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTaxonomyService_GetCategoryTree(t *testing.T) {
+	taxonomyRepo := new(mocks.TaxonomyRepository)
+	svc := NewTaxonomyService(taxonomyRepo, new(mocks.CourseRepository), new(mocks.LessonRepository))
+
+	taxonomyRepo.On("FindAllCategories", mock.Anything).Return([]*entities.Category{
+		{ID: 1, Name: "Programming"},
+		{ID: 2, Name: "Go", ParentID: uintPtr(1)},
+		{ID: 3, Name: "Concurrency", ParentID: uintPtr(2)},
+		{ID: 4, Name: "Design"},
+	}, nil)
+
+	tree, err := svc.GetCategoryTree(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "Programming", tree[0].Name)
+	assert.Equal(t, "Go", tree[0].Children[0].Name)
+	assert.Equal(t, "Concurrency", tree[0].Children[0].Children[0].Name)
+}
+
+func TestTaxonomyService_UpdateCategory(t *testing.T) {
+	t.Run("created_at is kept", func(t *testing.T) {
+		taxonomyRepo := new(mocks.TaxonomyRepository)
+		svc := NewTaxonomyService(taxonomyRepo, new(mocks.CourseRepository), new(mocks.LessonRepository))
+		createdAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		taxonomyRepo.On("FindCategoryByID", mock.Anything, uint(1)).Return(&entities.Category{ID: 1, Name: "Old", CreatedAt: createdAt}, nil)
+		taxonomyRepo.On("UpdateCategory", mock.Anything, mock.MatchedBy(func(c *entities.Category) bool {
+			return c.Name == "Programming" && c.CreatedAt.Equal(createdAt)
+		})).Return(nil)
+
+		category := &entities.Category{ID: 1, Name: " Programming "}
+		err := svc.UpdateCategory(context.Background(), category)
+
+		assert.NoError(t, err)
+		assert.Equal(t, createdAt, category.CreatedAt)
+		taxonomyRepo.AssertExpectations(t)
+	})
+
+	t.Run("parent is a descendant", func(t *testing.T) {
+		taxonomyRepo := new(mocks.TaxonomyRepository)
+		svc := NewTaxonomyService(taxonomyRepo, new(mocks.CourseRepository), new(mocks.LessonRepository))
+
+		taxonomyRepo.On("FindCategoryByID", mock.Anything, uint(1)).Return(&entities.Category{ID: 1, Name: "Programming"}, nil)
+		taxonomyRepo.On("FindCategoryByID", mock.Anything, uint(3)).Return(&entities.Category{ID: 3, ParentID: uintPtr(2)}, nil)
+		taxonomyRepo.On("FindCategoryByID", mock.Anything, uint(2)).Return(&entities.Category{ID: 2, ParentID: uintPtr(1)}, nil)
+
+		err := svc.UpdateCategory(context.Background(), &entities.Category{ID: 1, Name: "Programming", ParentID: uintPtr(3)})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+		taxonomyRepo.AssertNotCalled(t, "UpdateCategory", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown parent", func(t *testing.T) {
+		taxonomyRepo := new(mocks.TaxonomyRepository)
+		svc := NewTaxonomyService(taxonomyRepo, new(mocks.CourseRepository), new(mocks.LessonRepository))
+
+		taxonomyRepo.On("FindCategoryByID", mock.Anything, uint(1)).Return(&entities.Category{ID: 1}, nil)
+		taxonomyRepo.On("FindCategoryByID", mock.Anything, uint(9)).Return(nil, repo.ErrNotFound)
+
+		err := svc.UpdateCategory(context.Background(), &entities.Category{ID: 1, Name: "Programming", ParentID: uintPtr(9)})
+
+		assert.ErrorIs(t, err, pkg.ErrCategoryNotFound)
+	})
+}
+
+func TestTaxonomyService_DeleteCategory(t *testing.T) {
+	taxonomyRepo := new(mocks.TaxonomyRepository)
+	svc := NewTaxonomyService(taxonomyRepo, new(mocks.CourseRepository), new(mocks.LessonRepository))
+
+	taxonomyRepo.On("CountChildCategories", mock.Anything, uint(1)).Return(int64(2), nil)
+
+	err := svc.DeleteCategory(context.Background(), 1)
+
+	assert.ErrorIs(t, err, pkg.ErrCategoryNotEmpty)
+	taxonomyRepo.AssertNotCalled(t, "DeleteCategory", mock.Anything, mock.Anything)
+}
+
+func TestTaxonomyService_CreateTag(t *testing.T) {
+	t.Run("duplicate name", func(t *testing.T) {
+		taxonomyRepo := new(mocks.TaxonomyRepository)
+		svc := NewTaxonomyService(taxonomyRepo, new(mocks.CourseRepository), new(mocks.LessonRepository))
+		taxonomyRepo.On("SaveTag", mock.Anything, mock.Anything).Return(repo.ErrDuplicate)
+
+		err := svc.CreateTag(context.Background(), &entities.Tag{Name: " Go "})
+
+		assert.ErrorIs(t, err, pkg.ErrTagAlreadyExists)
+	})
+}
+
+func TestTaxonomyService_SetCourseTaxonomy(t *testing.T) {
+	t.Run("tags normalized and deduplicated", func(t *testing.T) {
+		taxonomyRepo := new(mocks.TaxonomyRepository)
+		courseRepo := new(mocks.CourseRepository)
+		svc := NewTaxonomyService(taxonomyRepo, courseRepo, new(mocks.LessonRepository))
+
+		courseRepo.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1}, nil)
+		taxonomyRepo.On("FindCategoryByID", mock.Anything, uint(2)).Return(&entities.Category{ID: 2}, nil)
+		taxonomyRepo.On("SetCourseTaxonomy", mock.Anything, uint(1), uintPtr(2), []string{"go", "web"}).Return(nil)
+
+		err := svc.SetCourseTaxonomy(context.Background(), 1, uintPtr(2), []string{" Go ", "web", "go"})
+
+		assert.NoError(t, err)
+		taxonomyRepo.AssertExpectations(t)
+	})
+
+	t.Run("empty tag", func(t *testing.T) {
+		courseRepo := new(mocks.CourseRepository)
+		svc := NewTaxonomyService(new(mocks.TaxonomyRepository), courseRepo, new(mocks.LessonRepository))
+
+		courseRepo.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1}, nil)
+
+		err := svc.SetCourseTaxonomy(context.Background(), 1, nil, []string{"go", "  "})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+
+	t.Run("course not found", func(t *testing.T) {
+		courseRepo := new(mocks.CourseRepository)
+		svc := NewTaxonomyService(new(mocks.TaxonomyRepository), courseRepo, new(mocks.LessonRepository))
+
+		courseRepo.On("FindByID", mock.Anything, uint(1)).Return(nil, repo.ErrNotFound)
+
+		err := svc.SetCourseTaxonomy(context.Background(), 1, nil, nil)
+
+		assert.ErrorIs(t, err, pkg.ErrCourseNotFound)
+	})
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// maxIntrospectionCacheSize ограничивает кэш, чтобы поток разных токенов не занял всю память.
+const maxIntrospectionCacheSize = 10000
+
+// introspectFunc спрашивает у провайдера, активна ли ещё сессия токена.
+type introspectFunc func(ctx context.Context, token string) (bool, error)
+
+// introspectionCache запоминает ответы интроспекции на короткое время: отозванная сессия
+// перестаёт приниматься не позже чем через ttl, а провайдер не получает запрос на каждый вызов API.
+type introspectionCache struct {
+	ttl        time.Duration
+	introspect introspectFunc
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]introspectionEntry
+}
+
+type introspectionEntry struct {
+	active    bool
+	expiresAt time.Time
+}
+
+func newIntrospectionCache(ttl time.Duration, introspect introspectFunc) *introspectionCache {
+	return &introspectionCache{
+		ttl:        ttl,
+		introspect: introspect,
+		now:        time.Now,
+		entries:    map[[sha256.Size]byte]introspectionEntry{},
+	}
+}
+
+// active возвращает ответ из кэша или спрашивает провайдера. Ошибки не кэшируются.
+func (c *introspectionCache) active(ctx context.Context, token string) (bool, error) {
+	key := sha256.Sum256([]byte(token))
+	now := c.now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.active, nil
+	}
+
+	active, err := c.introspect(ctx, token)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxIntrospectionCacheSize {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxIntrospectionCacheSize {
+			c.entries = map[[sha256.Size]byte]introspectionEntry{}
+		}
+	}
+	c.entries[key] = introspectionEntry{active: active, expiresAt: now.Add(c.ttl)}
+	return active, nil
+}
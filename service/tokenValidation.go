@@ -0,0 +1,76 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// TokenValidation — требования к claims access-токена помимо подписи.
+type TokenValidation struct {
+	// Issuer — ожидаемый iss; пусто — не проверяется.
+	Issuer string
+	// Audiences — допустимые получатели: токен принимается, если aud или azp совпадает
+	// хотя бы с одним. Пусто — не проверяется.
+	Audiences []string
+	// Algorithms — допустимые алгоритмы подписи, например RS256.
+	Algorithms []string
+	// ClockSkew — допустимое расхождение часов при проверке exp, nbf и iat.
+	ClockSkew time.Duration
+}
+
+// validate проверяет срок действия, издателя и получателя токена на момент now.
+func (v TokenValidation) validate(claims jwt.MapClaims, now time.Time) error {
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return errors.New("token has no expiration")
+	}
+	if now.Add(-v.ClockSkew).After(exp) {
+		return errors.New("token is expired")
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(v.ClockSkew).Before(nbf) {
+		return errors.New("token is not valid yet")
+	}
+	if iat, ok := numericClaim(claims, "iat"); ok && now.Add(v.ClockSkew).Before(iat) {
+		return errors.New("token is issued in the future")
+	}
+	if v.Issuer != "" && claims["iss"] != v.Issuer {
+		return fmt.Errorf("unexpected issuer %v", claims["iss"])
+	}
+	if len(v.Audiences) > 0 && !v.acceptsAudience(claims) {
+		return fmt.Errorf("unexpected audience %v (azp %v)", claims["aud"], claims["azp"])
+	}
+	return nil
+}
+
+func (v TokenValidation) acceptsAudience(claims jwt.MapClaims) bool {
+	if azp, ok := claims["azp"].(string); ok && slices.Contains(v.Audiences, azp) {
+		return true
+	}
+	switch aud := claims["aud"].(type) {
+	case string:
+		return slices.Contains(v.Audiences, aud)
+	case []interface{}:
+		for _, value := range claimStrings(aud) {
+			if slices.Contains(v.Audiences, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// numericClaim читает claim с временем в секундах Unix.
+func numericClaim(claims jwt.MapClaims, name string) (time.Time, bool) {
+	switch value := claims[name].(type) {
+	case float64:
+		return time.Unix(int64(value), 0), true
+	case int64:
+		return time.Unix(value, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
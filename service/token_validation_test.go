@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"lms-system-internship/pkg"
+
+	"github.com/MicahParks/keyfunc"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenValidation_Validate(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	validation := TokenValidation{
+		Issuer:    "http://keycloak:8080/realms/lms",
+		Audiences: []string{"backend-client"},
+		ClockSkew: 30 * time.Second,
+	}
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": "http://keycloak:8080/realms/lms",
+			"aud": "account",
+			"azp": "backend-client",
+			"exp": float64(now.Add(time.Minute).Unix()),
+			"iat": float64(now.Unix()),
+		}
+	}
+
+	cases := []struct {
+		name    string
+		mutate  func(jwt.MapClaims)
+		wantErr bool
+	}{
+		{"valid", func(jwt.MapClaims) {}, false},
+		{"expired within skew", func(c jwt.MapClaims) { c["exp"] = float64(now.Add(-10 * time.Second).Unix()) }, false},
+		{"expired beyond skew", func(c jwt.MapClaims) { c["exp"] = float64(now.Add(-time.Minute).Unix()) }, true},
+		{"no expiration", func(c jwt.MapClaims) { delete(c, "exp") }, true},
+		{"not valid yet", func(c jwt.MapClaims) { c["nbf"] = float64(now.Add(time.Minute).Unix()) }, true},
+		{"issued in the future within skew", func(c jwt.MapClaims) { c["iat"] = float64(now.Add(10 * time.Second).Unix()) }, false},
+		{"foreign issuer", func(c jwt.MapClaims) { c["iss"] = "http://evil/realms/lms" }, true},
+		{"audience in aud list", func(c jwt.MapClaims) { delete(c, "azp"); c["aud"] = []interface{}{"account", "backend-client"} }, false},
+		{"foreign audience", func(c jwt.MapClaims) { c["azp"] = "other-client" }, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims := valid()
+			tc.mutate(claims)
+
+			err := validation.validate(claims, now)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("empty issuer and audiences are not checked", func(t *testing.T) {
+		claims := valid()
+		claims["iss"] = "anything"
+		claims["azp"] = "anyone"
+		assert.NoError(t, TokenValidation{}.validate(claims, now))
+	})
+}
+
+func TestKeycloakIdentityProvider_Verify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwksJSON, _ := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA", "kid": "test", "alg": "RS256", "use": "sig",
+		"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	jwks, err := keyfunc.NewJSON(jwksJSON)
+	require.NoError(t, err)
+
+	var active atomic.Bool
+	var introspections atomic.Int32
+	keycloak := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		introspections.Add(1)
+		assert.Equal(t, "/protocol/openid-connect/token/introspect", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]bool{"active": active.Load()})
+	}))
+	defer keycloak.Close()
+
+	newProvider := func(cacheTTL time.Duration) *keycloakIdentityProvider {
+		p := &keycloakIdentityProvider{
+			cfg: KeycloakConfig{
+				RealmURL: keycloak.URL,
+				ClientID: "backend-client",
+				Validation: TokenValidation{
+					Issuer:     keycloak.URL,
+					Audiences:  []string{"backend-client"},
+					Algorithms: []string{"RS256"},
+				},
+			},
+			keys:   &JWKSSource{jwks: jwks},
+			client: keycloak.Client(),
+		}
+		if cacheTTL > 0 {
+			p.introspection = newIntrospectionCache(cacheTTL, p.introspect)
+		}
+		return p
+	}
+	userID := uuid.New()
+	signTyped := func(method jwt.SigningMethod, signingKey any, typ string) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{
+			"iss":                keycloak.URL,
+			"azp":                "backend-client",
+			"typ":                typ,
+			"sub":                userID.String(),
+			"preferred_username": "alice",
+			"exp":                time.Now().Add(time.Minute).Unix(),
+		})
+		token.Header["kid"] = "test"
+		signed, err := token.SignedString(signingKey)
+		require.NoError(t, err)
+		return signed
+	}
+	sign := func(method jwt.SigningMethod, signingKey any) string {
+		return signTyped(method, signingKey, "Bearer")
+	}
+	ctx := context.Background()
+
+	t.Run("valid token", func(t *testing.T) {
+		principal, err := newProvider(0).Verify(ctx, sign(jwt.SigningMethodRS256, key))
+		assert.NoError(t, err)
+		assert.Equal(t, userID, principal.ID)
+	})
+
+	t.Run("algorithm not allowed", func(t *testing.T) {
+		_, err := newProvider(0).Verify(ctx, sign(jwt.SigningMethodRS512, key))
+		assert.ErrorIs(t, err, pkg.ErrInvalidToken)
+	})
+
+	t.Run("id and refresh tokens are rejected", func(t *testing.T) {
+		for _, typ := range []string{"ID", "Refresh", ""} {
+			_, err := newProvider(0).Verify(ctx, signTyped(jwt.SigningMethodRS256, key, typ))
+			assert.ErrorIs(t, err, pkg.ErrInvalidToken, typ)
+		}
+	})
+
+	t.Run("revoked session is rejected and the answer is cached", func(t *testing.T) {
+		provider := newProvider(time.Minute)
+		token := sign(jwt.SigningMethodRS256, key)
+		introspections.Store(0)
+
+		active.Store(true)
+		_, err := provider.Verify(ctx, token)
+		assert.NoError(t, err)
+		_, err = provider.Verify(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), introspections.Load())
+
+		active.Store(false)
+		_, err = newProvider(time.Minute).Verify(ctx, token)
+		assert.ErrorIs(t, err, pkg.ErrInvalidToken)
+	})
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"slices"
+	"strings"
+)
+
+// MissingTranslation — сущность курса, у которой на локали нет перевода части непустых полей.
+type MissingTranslation struct {
+	EntityType string   `json:"entity_type"`
+	EntityID   uint     `json:"entity_id"`
+	Name       string   `json:"name"`
+	Fields     []string `json:"fields"`
+}
+
+// TranslationReport — недостающие переводы курса, его глав, уроков и блоков по каждой локали.
+type TranslationReport struct {
+	CourseID      uint                            `json:"course_id"`
+	DefaultLocale string                          `json:"default_locale"`
+	Missing       map[string][]MissingTranslation `json:"missing"`
+}
+
+type TranslationService interface {
+	GetTranslations(ctx context.Context, entityType string, entityID uint) ([]*entities.Translation, error)
+	SetTranslation(ctx context.Context, translation *entities.Translation) error
+	DeleteTranslation(ctx context.Context, entityType string, entityID uint, locale string) error
+	MissingTranslations(ctx context.Context, courseID uint) (*TranslationReport, error)
+
+	// LocalizeCourses, LocalizeChapters, LocalizeLessons и LocalizeBlocks подставляют переводы на локаль
+	// из контекста (pkg.LocaleFromContext) вместе с вложенными главами, уроками и блоками. Поля без
+	// перевода не меняются.
+	LocalizeCourses(ctx context.Context, courses ...*entities.Course) error
+	LocalizeChapters(ctx context.Context, chapters ...*entities.Chapter) error
+	LocalizeLessons(ctx context.Context, lessons ...*entities.Lesson) error
+	LocalizeBlocks(ctx context.Context, blocks ...*entities.LessonBlock) error
+}
+
+type translationService struct {
+	repo          repo.TranslationRepository
+	courseRepo    repo.CourseRepository
+	chapterRepo   repo.ChapterRepository
+	lessonRepo    repo.LessonRepository
+	blockRepo     repo.LessonBlockRepository
+	defaultLocale string
+	locales       []string
+}
+
+// NewTranslationService создаёт сервис переводов. locales — поддерживаемые локали, включая defaultLocale.
+func NewTranslationService(repo repo.TranslationRepository, courseRepo repo.CourseRepository, chapterRepo repo.ChapterRepository, lessonRepo repo.LessonRepository, blockRepo repo.LessonBlockRepository, defaultLocale string, locales []string) TranslationService {
+	return &translationService{
+		repo:          repo,
+		courseRepo:    courseRepo,
+		chapterRepo:   chapterRepo,
+		lessonRepo:    lessonRepo,
+		blockRepo:     blockRepo,
+		defaultLocale: defaultLocale,
+		locales:       locales,
+	}
+}
+
+func (s *translationService) GetTranslations(ctx context.Context, entityType string, entityID uint) ([]*entities.Translation, error) {
+	if err := s.checkEntity(ctx, entityType, entityID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByEntity(ctx, entityType, entityID)
+}
+
+// SetTranslation сохраняет перевод. Переводить на локаль по умолчанию нельзя: её текст
+// хранится в самой сущности. Содержимое переводится только у уроков и блоков, описания у блоков нет.
+func (s *translationService) SetTranslation(ctx context.Context, translation *entities.Translation) error {
+	translation.Locale = strings.ToLower(strings.TrimSpace(translation.Locale))
+	if !s.isTranslatable(translation.Locale) {
+		return pkg.ErrInvalidInput
+	}
+	hasContent := translation.EntityType == entities.RevisionEntityLesson || translation.EntityType == entities.TranslationEntityBlock
+	if !hasContent && translation.Content != "" {
+		return pkg.ErrInvalidInput
+	}
+	if translation.EntityType == entities.TranslationEntityBlock && translation.Description != "" {
+		return pkg.ErrInvalidInput
+	}
+	if translation.Name == "" && translation.Description == "" && translation.Content == "" {
+		return pkg.ErrInvalidInput
+	}
+	if err := s.checkEntity(ctx, translation.EntityType, translation.EntityID); err != nil {
+		return err
+	}
+	return s.repo.Upsert(ctx, translation)
+}
+
+func (s *translationService) DeleteTranslation(ctx context.Context, entityType string, entityID uint, locale string) error {
+	return notFound(s.repo.Delete(ctx, entityType, entityID, strings.ToLower(locale)), pkg.ErrTranslationNotFound)
+}
+
+func (s *translationService) MissingTranslations(ctx context.Context, courseID uint) (*TranslationReport, error) {
+	course, err := s.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, notFound(err, pkg.ErrCourseNotFound)
+	}
+
+	var chapterIDs, lessonIDs []uint
+	for _, chapter := range course.Chapters {
+		chapterIDs = append(chapterIDs, chapter.ID)
+		for _, lesson := range chapter.Lessons {
+			lessonIDs = append(lessonIDs, lesson.ID)
+		}
+	}
+	courseTr, err := s.translationsByLocale(ctx, entities.RevisionEntityCourse, []uint{course.ID})
+	if err != nil {
+		return nil, err
+	}
+	chapterTr, err := s.translationsByLocale(ctx, entities.RevisionEntityChapter, chapterIDs)
+	if err != nil {
+		return nil, err
+	}
+	lessonTr, err := s.translationsByLocale(ctx, entities.RevisionEntityLesson, lessonIDs)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := s.blockRepo.FindByLessonIDs(ctx, lessonIDs)
+	if err != nil {
+		return nil, err
+	}
+	blockIDs := make([]uint, 0, len(blocks))
+	blocksByLesson := map[uint][]*entities.LessonBlock{}
+	for _, block := range blocks {
+		blockIDs = append(blockIDs, block.ID)
+		blocksByLesson[block.LessonID] = append(blocksByLesson[block.LessonID], block)
+	}
+	blockTr, err := s.translationsByLocale(ctx, entities.TranslationEntityBlock, blockIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TranslationReport{CourseID: courseID, DefaultLocale: s.defaultLocale, Missing: map[string][]MissingTranslation{}}
+	for _, locale := range s.locales {
+		if locale == s.defaultLocale {
+			continue
+		}
+		missing := []MissingTranslation{}
+		// source — исходные тексты в полях перевода, label — название для отчёта.
+		add := func(entityType string, id uint, label string, source entities.Translation, byID map[uint]*entities.Translation) {
+			translation := byID[id]
+			if translation == nil {
+				translation = &entities.Translation{}
+			}
+			var fields []string
+			if source.Name != "" && translation.Name == "" {
+				fields = append(fields, "name")
+			}
+			if source.Description != "" && translation.Description == "" {
+				fields = append(fields, "description")
+			}
+			if source.Content != "" && translation.Content == "" {
+				fields = append(fields, "content")
+			}
+			if len(fields) > 0 {
+				missing = append(missing, MissingTranslation{EntityType: entityType, EntityID: id, Name: label, Fields: fields})
+			}
+		}
+
+		add(entities.RevisionEntityCourse, course.ID, course.Name, entities.Translation{Name: course.Name, Description: course.Description}, courseTr[locale])
+		for _, chapter := range course.Chapters {
+			add(entities.RevisionEntityChapter, chapter.ID, chapter.Name, entities.Translation{Name: chapter.Name, Description: chapter.Description}, chapterTr[locale])
+			for _, lesson := range chapter.Lessons {
+				add(entities.RevisionEntityLesson, lesson.ID, lesson.Name, entities.Translation{Name: lesson.Name, Description: lesson.Description, Content: lesson.Content}, lessonTr[locale])
+				for _, block := range blocksByLesson[lesson.ID] {
+					add(entities.TranslationEntityBlock, block.ID, lesson.Name, blockSource(block), blockTr[locale])
+				}
+			}
+		}
+		report.Missing[locale] = missing
+	}
+	return report, nil
+}
+
+func (s *translationService) LocalizeCourses(ctx context.Context, courses ...*entities.Course) error {
+	locale := s.requestLocale(ctx)
+	if locale == "" {
+		return nil
+	}
+
+	var courseIDs []uint
+	var chapters []*entities.Chapter
+	for _, course := range courses {
+		courseIDs = append(courseIDs, course.ID)
+		for i := range course.Chapters {
+			chapters = append(chapters, &course.Chapters[i])
+		}
+	}
+	byID, err := s.translations(ctx, entities.RevisionEntityCourse, courseIDs, locale)
+	if err != nil {
+		return err
+	}
+	for _, course := range courses {
+		if translation := byID[course.ID]; translation != nil {
+			course.Name = translated(course.Name, translation.Name)
+			course.Description = translated(course.Description, translation.Description)
+		}
+	}
+	return s.LocalizeChapters(ctx, chapters...)
+}
+
+func (s *translationService) LocalizeChapters(ctx context.Context, chapters ...*entities.Chapter) error {
+	locale := s.requestLocale(ctx)
+	if locale == "" {
+		return nil
+	}
+
+	var chapterIDs []uint
+	var lessons []*entities.Lesson
+	for _, chapter := range chapters {
+		chapterIDs = append(chapterIDs, chapter.ID)
+		for i := range chapter.Lessons {
+			lessons = append(lessons, &chapter.Lessons[i])
+		}
+	}
+	byID, err := s.translations(ctx, entities.RevisionEntityChapter, chapterIDs, locale)
+	if err != nil {
+		return err
+	}
+	for _, chapter := range chapters {
+		if translation := byID[chapter.ID]; translation != nil {
+			chapter.Name = translated(chapter.Name, translation.Name)
+			chapter.Description = translated(chapter.Description, translation.Description)
+		}
+	}
+	return s.LocalizeLessons(ctx, lessons...)
+}
+
+func (s *translationService) LocalizeLessons(ctx context.Context, lessons ...*entities.Lesson) error {
+	locale := s.requestLocale(ctx)
+	if locale == "" {
+		return nil
+	}
+
+	lessonIDs := make([]uint, 0, len(lessons))
+	var blocks []*entities.LessonBlock
+	for _, lesson := range lessons {
+		lessonIDs = append(lessonIDs, lesson.ID)
+		for i := range lesson.Blocks {
+			blocks = append(blocks, &lesson.Blocks[i])
+		}
+	}
+	byID, err := s.translations(ctx, entities.RevisionEntityLesson, lessonIDs, locale)
+	if err != nil {
+		return err
+	}
+	for _, lesson := range lessons {
+		if translation := byID[lesson.ID]; translation != nil {
+			lesson.Name = translated(lesson.Name, translation.Name)
+			lesson.Description = translated(lesson.Description, translation.Description)
+			lesson.Content = translated(lesson.Content, translation.Content)
+		}
+	}
+	return s.LocalizeBlocks(ctx, blocks...)
+}
+
+func (s *translationService) LocalizeBlocks(ctx context.Context, blocks ...*entities.LessonBlock) error {
+	locale := s.requestLocale(ctx)
+	if locale == "" || len(blocks) == 0 {
+		return nil
+	}
+
+	blockIDs := make([]uint, 0, len(blocks))
+	for _, block := range blocks {
+		blockIDs = append(blockIDs, block.ID)
+	}
+	byID, err := s.translations(ctx, entities.TranslationEntityBlock, blockIDs, locale)
+	if err != nil {
+		return err
+	}
+	for _, block := range blocks {
+		if translation := byID[block.ID]; translation != nil {
+			if blockSource(block).Content != "" {
+				block.Text = translated(block.Text, translation.Content)
+			}
+			block.Caption = translated(block.Caption, translation.Name)
+		}
+	}
+	return nil
+}
+
+// blockSource возвращает переводимые тексты блока в полях перевода. Текст переводится только
+// у текстовых блоков и выносок: код и ссылки на медиа от языка не зависят.
+func blockSource(block *entities.LessonBlock) entities.Translation {
+	source := entities.Translation{Name: block.Caption}
+	if block.Type == entities.BlockTypeText || block.Type == entities.BlockTypeCallout {
+		source.Content = block.Text
+	}
+	return source
+}
+
+// requestLocale возвращает локаль запроса, если для неё нужно искать переводы.
+func (s *translationService) requestLocale(ctx context.Context) string {
+	locale := pkg.LocaleFromContext(ctx)
+	if !s.isTranslatable(locale) {
+		return ""
+	}
+	return locale
+}
+
+func (s *translationService) isTranslatable(locale string) bool {
+	return locale != s.defaultLocale && slices.Contains(s.locales, locale)
+}
+
+func (s *translationService) translations(ctx context.Context, entityType string, ids []uint, locale string) (map[uint]*entities.Translation, error) {
+	list, err := s.repo.FindByEntities(ctx, entityType, ids, locale)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*entities.Translation, len(list))
+	for _, translation := range list {
+		byID[translation.EntityID] = translation
+	}
+	return byID, nil
+}
+
+// translationsByLocale группирует переводы сущностей по локали и ID.
+func (s *translationService) translationsByLocale(ctx context.Context, entityType string, ids []uint) (map[string]map[uint]*entities.Translation, error) {
+	list, err := s.repo.FindByEntities(ctx, entityType, ids, "")
+	if err != nil {
+		return nil, err
+	}
+	byLocale := map[string]map[uint]*entities.Translation{}
+	for _, translation := range list {
+		if byLocale[translation.Locale] == nil {
+			byLocale[translation.Locale] = map[uint]*entities.Translation{}
+		}
+		byLocale[translation.Locale][translation.EntityID] = translation
+	}
+	return byLocale, nil
+}
+
+func (s *translationService) checkEntity(ctx context.Context, entityType string, entityID uint) error {
+	var err error
+	switch entityType {
+	case entities.RevisionEntityCourse:
+		_, err = s.courseRepo.FindByID(ctx, entityID)
+		return notFound(err, pkg.ErrCourseNotFound)
+	case entities.RevisionEntityChapter:
+		_, err = s.chapterRepo.FindByID(ctx, entityID)
+		return notFound(err, pkg.ErrChapterNotFound)
+	case entities.RevisionEntityLesson:
+		_, err = s.lessonRepo.FindByID(ctx, entityID)
+		return notFound(err, pkg.ErrLessonNotFound)
+	case entities.TranslationEntityBlock:
+		_, err = s.blockRepo.FindByID(ctx, entityID)
+		return notFound(err, pkg.ErrLessonBlockNotFound)
+	}
+	return pkg.ErrInvalidInput
+}
+
+func translated(original, translation string) string {
+	if translation == "" {
+		return original
+	}
+	return translation
+}
+
+// localize применяет переводы. Ошибка перевода не мешает отдать исходный текст, поэтому
+// только логируется.
+func localize(ctx context.Context, translations TranslationService, apply func(TranslationService) error) {
+	if err := apply(translations); err != nil {
+		pkg.LoggerFromContext(ctx).WithError(err).Warn("Failed to apply translations")
+	}
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// noTranslations — сервис переводов без переводов: тексты остаются исходными.
+type noTranslations struct {
+	TranslationService
+}
+
+func (noTranslations) LocalizeCourses(context.Context, ...*entities.Course) error     { return nil }
+func (noTranslations) LocalizeChapters(context.Context, ...*entities.Chapter) error   { return nil }
+func (noTranslations) LocalizeLessons(context.Context, ...*entities.Lesson) error     { return nil }
+func (noTranslations) LocalizeBlocks(context.Context, ...*entities.LessonBlock) error { return nil }
+
+func TestTranslationService_LocalizeCourses(t *testing.T) {
+	course := func() *entities.Course {
+		return &entities.Course{ID: 1, Name: "Go", Description: "Intro", Chapters: []entities.Chapter{
+			{ID: 2, Name: "Basics", Lessons: []entities.Lesson{{ID: 3, Name: "Variables", Content: "var x int", Blocks: []entities.LessonBlock{
+				{ID: 4, Type: entities.BlockTypeText, Text: "Variables hold values"},
+				{ID: 5, Type: entities.BlockTypeCode, Text: "var x int", Caption: "Example"},
+			}}}},
+		}}
+	}
+
+	t.Run("applies translations with field fallback", func(t *testing.T) {
+		translations := new(mocks.TranslationRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		blocks := new(mocks.LessonBlockRepository)
+		service := NewTranslationService(translations, courses, chapters, lessons, blocks, "en", []string{"en", "ru", "de"})
+		translations.On("FindByEntities", mock.Anything, entities.RevisionEntityCourse, []uint{1}, "ru").
+			Return([]*entities.Translation{{EntityID: 1, Name: "Го"}}, nil)
+		translations.On("FindByEntities", mock.Anything, entities.RevisionEntityChapter, []uint{2}, "ru").
+			Return([]*entities.Translation{}, nil)
+		translations.On("FindByEntities", mock.Anything, entities.RevisionEntityLesson, []uint{3}, "ru").
+			Return([]*entities.Translation{{EntityID: 3, Name: "Переменные", Content: "var x int // целое"}}, nil)
+		translations.On("FindByEntities", mock.Anything, entities.TranslationEntityBlock, []uint{4, 5}, "ru").
+			Return([]*entities.Translation{
+				{EntityID: 4, Content: "Переменные хранят значения"},
+				{EntityID: 5, Name: "Пример", Content: "ignored"},
+			}, nil)
+		c := course()
+
+		err := service.LocalizeCourses(pkg.WithLocale(context.Background(), "ru"), c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Го", c.Name)
+		assert.Equal(t, "Intro", c.Description)
+		assert.Equal(t, "Basics", c.Chapters[0].Name)
+		assert.Equal(t, "Переменные", c.Chapters[0].Lessons[0].Name)
+		assert.Equal(t, "var x int // целое", c.Chapters[0].Lessons[0].Content)
+		assert.Equal(t, "Переменные хранят значения", c.Chapters[0].Lessons[0].Blocks[0].Text)
+		assert.Equal(t, "var x int", c.Chapters[0].Lessons[0].Blocks[1].Text)
+		assert.Equal(t, "Пример", c.Chapters[0].Lessons[0].Blocks[1].Caption)
+		translations.AssertExpectations(t)
+	})
+
+	t.Run("default locale is not looked up", func(t *testing.T) {
+		translations := new(mocks.TranslationRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		blocks := new(mocks.LessonBlockRepository)
+		service := NewTranslationService(translations, courses, chapters, lessons, blocks, "en", []string{"en", "ru", "de"})
+		c := course()
+
+		err := service.LocalizeCourses(pkg.WithLocale(context.Background(), "en"), c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Go", c.Name)
+		translations.AssertNotCalled(t, "FindByEntities", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTranslationService_SetTranslation(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		translations := new(mocks.TranslationRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		blocks := new(mocks.LessonBlockRepository)
+		service := NewTranslationService(translations, courses, chapters, lessons, blocks, "en", []string{"en", "ru", "de"})
+		translation := &entities.Translation{EntityType: entities.RevisionEntityLesson, EntityID: 3, Locale: " RU ", Name: "Переменные"}
+		lessons.On("FindByID", mock.Anything, uint(3)).Return(&entities.Lesson{ID: 3}, nil)
+		translations.On("Upsert", mock.Anything, translation).Return(nil)
+
+		err := service.SetTranslation(context.Background(), translation)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ru", translation.Locale)
+		translations.AssertExpectations(t)
+	})
+
+	t.Run("default or unsupported locale", func(t *testing.T) {
+		translations := new(mocks.TranslationRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		blocks := new(mocks.LessonBlockRepository)
+		service := NewTranslationService(translations, courses, chapters, lessons, blocks, "en", []string{"en", "ru", "de"})
+
+		for _, locale := range []string{"en", "fr", ""} {
+			err := service.SetTranslation(context.Background(), &entities.Translation{EntityType: entities.RevisionEntityCourse, EntityID: 1, Locale: locale, Name: "x"})
+			assert.ErrorIs(t, err, pkg.ErrInvalidInput, locale)
+		}
+	})
+
+	t.Run("content only for lessons", func(t *testing.T) {
+		translations := new(mocks.TranslationRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		blocks := new(mocks.LessonBlockRepository)
+		service := NewTranslationService(translations, courses, chapters, lessons, blocks, "en", []string{"en", "ru", "de"})
+
+		err := service.SetTranslation(context.Background(), &entities.Translation{EntityType: entities.RevisionEntityChapter, EntityID: 1, Locale: "ru", Content: "x"})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+
+	t.Run("block caption and text", func(t *testing.T) {
+		translations := new(mocks.TranslationRepository)
+		blocks := new(mocks.LessonBlockRepository)
+		service := NewTranslationService(translations, nil, nil, nil, blocks, "en", []string{"en", "ru", "de"})
+		translation := &entities.Translation{EntityType: entities.TranslationEntityBlock, EntityID: 4, Locale: "ru", Name: "Схема", Content: "Текст"}
+		blocks.On("FindByID", mock.Anything, uint(4)).Return(&entities.LessonBlock{ID: 4}, nil)
+		translations.On("Upsert", mock.Anything, translation).Return(nil)
+
+		err := service.SetTranslation(context.Background(), translation)
+
+		assert.NoError(t, err)
+		translations.AssertExpectations(t)
+	})
+
+	t.Run("blocks have no description", func(t *testing.T) {
+		service := NewTranslationService(new(mocks.TranslationRepository), nil, nil, nil, new(mocks.LessonBlockRepository), "en", []string{"en", "ru", "de"})
+
+		err := service.SetTranslation(context.Background(), &entities.Translation{EntityType: entities.TranslationEntityBlock, EntityID: 4, Locale: "ru", Description: "x"})
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+
+	t.Run("entity not found", func(t *testing.T) {
+		translations := new(mocks.TranslationRepository)
+		courses := new(mocks.CourseRepository)
+		chapters := new(mocks.ChapterRepository)
+		lessons := new(mocks.LessonRepository)
+		blocks := new(mocks.LessonBlockRepository)
+		service := NewTranslationService(translations, courses, chapters, lessons, blocks, "en", []string{"en", "ru", "de"})
+		courses.On("FindByID", mock.Anything, uint(1)).Return(nil, repo.ErrNotFound)
+
+		err := service.SetTranslation(context.Background(), &entities.Translation{EntityType: entities.RevisionEntityCourse, EntityID: 1, Locale: "ru", Name: "x"})
+
+		assert.ErrorIs(t, err, pkg.ErrCourseNotFound)
+	})
+}
+
+func TestTranslationService_MissingTranslations(t *testing.T) {
+	translations := new(mocks.TranslationRepository)
+	courses := new(mocks.CourseRepository)
+	chapters := new(mocks.ChapterRepository)
+	lessons := new(mocks.LessonRepository)
+	blocks := new(mocks.LessonBlockRepository)
+	service := NewTranslationService(translations, courses, chapters, lessons, blocks, "en", []string{"en", "ru", "de"})
+	courses.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1, Name: "Go", Chapters: []entities.Chapter{
+		{ID: 2, Name: "Basics", Lessons: []entities.Lesson{{ID: 3, Name: "Variables", Description: "Declaring", Content: "var x int"}}},
+	}}, nil)
+	translations.On("FindByEntities", mock.Anything, entities.RevisionEntityCourse, []uint{1}, "").
+		Return([]*entities.Translation{{EntityID: 1, Locale: "ru", Name: "Го"}}, nil)
+	translations.On("FindByEntities", mock.Anything, entities.RevisionEntityChapter, []uint{2}, "").
+		Return([]*entities.Translation{{EntityID: 2, Locale: "ru", Name: "Основы"}}, nil)
+	translations.On("FindByEntities", mock.Anything, entities.RevisionEntityLesson, []uint{3}, "").
+		Return([]*entities.Translation{{EntityID: 3, Locale: "ru", Name: "Переменные", Content: "var x int"}}, nil)
+	blocks.On("FindByLessonIDs", mock.Anything, []uint{3}).Return([]*entities.LessonBlock{
+		{ID: 4, LessonID: 3, Type: entities.BlockTypeText, Text: "var x int"},
+		{ID: 5, LessonID: 3, Type: entities.BlockTypeImage, URL: "https://example.com/x.png", Caption: "Memory layout"},
+		{ID: 6, LessonID: 3, Type: entities.BlockTypeCode, Text: "x := 1"},
+	}, nil)
+	translations.On("FindByEntities", mock.Anything, entities.TranslationEntityBlock, []uint{4, 5, 6}, "").
+		Return([]*entities.Translation{{EntityID: 4, Locale: "ru", Content: "var x int"}}, nil)
+
+	report, err := service.MissingTranslations(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "en", report.DefaultLocale)
+	assert.Equal(t, []MissingTranslation{
+		{EntityType: entities.RevisionEntityLesson, EntityID: 3, Name: "Variables", Fields: []string{"description"}},
+		{EntityType: entities.TranslationEntityBlock, EntityID: 5, Name: "Variables", Fields: []string{"name"}},
+	}, report.Missing["ru"])
+	assert.Len(t, report.Missing["de"], 5)
+	assert.NotContains(t, report.Missing, "en")
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"lms-system-internship/entities"
+	"lms-system-internship/files"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultUserListLimit = 20
+	maxUserListLimit     = 100
+	// userSyncPageSize — сколько учётных записей запрашивать у провайдера за один вызов при сверке кэша.
+	userSyncPageSize = 100
+)
+
+// UserDetails — пользователь с ролями realm и уроками, к которым ему выдан доступ.
+type UserDetails struct {
+	Profile   entities.UserProfile
+	Roles     []string
+	LessonIDs []uint
+}
+
+// UserAdminService — справочник пользователей для администратора. Списки отдаются из локального
+// кэша профилей, который сверяется с провайдером не чаще раза в cacheTTL.
+type UserAdminService interface {
+	ListUsers(ctx context.Context, filter repo.UserFilter) ([]*entities.UserProfile, int64, error)
+	// GetUser читает пользователя у провайдера и обновляет его профиль в кэше.
+	GetUser(ctx context.Context, userID uuid.UUID) (*UserDetails, error)
+	// SetEnabled включает или отключает учётную запись; при отключении сессии пользователя завершаются.
+	SetEnabled(ctx context.Context, userID uuid.UUID, enabled bool) error
+	// DeleteUser удаляет учётную запись у провайдера, а затем все данные пользователя в LMS.
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	// RefreshUser перечитывает профиль пользователя после изменения учётной записи.
+	RefreshUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type userAdminService struct {
+	identity    IdentityService
+	sessions    IdentityProvider
+	profiles    repo.UserProfileRepository
+	lessonUsers repo.LessonUserRepository
+	fileStorage files.FileStorage
+	cacheTTL    time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	syncedAt time.Time
+}
+
+// NewUserAdminService создаёт справочник пользователей. Сессии отключённых пользователей
+// завершаются через sessions.
+func NewUserAdminService(identity IdentityService, sessions IdentityProvider, profiles repo.UserProfileRepository, lessonUsers repo.LessonUserRepository, fileStorage files.FileStorage, cacheTTL time.Duration) UserAdminService {
+	return &userAdminService{
+		identity:    identity,
+		sessions:    sessions,
+		profiles:    profiles,
+		lessonUsers: lessonUsers,
+		fileStorage: fileStorage,
+		cacheTTL:    cacheTTL,
+		now:         time.Now,
+	}
+}
+
+// ListUsers при устаревшем кэше сначала сверяет его с провайдером. Если провайдер недоступен,
+// отдаётся то, что уже есть в кэше.
+func (s *userAdminService) ListUsers(ctx context.Context, filter repo.UserFilter) ([]*entities.UserProfile, int64, error) {
+	if err := s.syncIfStale(ctx); err != nil {
+		pkg.LoggerFromContext(ctx).WithError(err).Warn("Failed to sync user profiles, serving cached list")
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultUserListLimit
+	}
+	if filter.Limit > maxUserListLimit {
+		filter.Limit = maxUserListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.profiles.Search(ctx, filter)
+}
+
+func (s *userAdminService) GetUser(ctx context.Context, userID uuid.UUID) (*UserDetails, error) {
+	account, err := s.identity.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	profile := userProfile(account, s.now())
+	if err := s.profiles.Upsert(ctx, []*entities.UserProfile{profile}); err != nil {
+		pkg.LoggerFromContext(ctx).WithError(err).WithField("target_user_id", userID).Warn("Failed to cache user profile")
+	}
+
+	lessonIDs, err := s.lessonUsers.FindLessonIDsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &UserDetails{Profile: *profile, Roles: account.Roles, LessonIDs: lessonIDs}, nil
+}
+
+func (s *userAdminService) SetEnabled(ctx context.Context, userID uuid.UUID, enabled bool) error {
+	if err := s.identity.SetEnabled(ctx, userID, enabled); err != nil {
+		return err
+	}
+	// Отключённый пользователь не сможет обновить токены, но уже выданные действуют до истечения
+	if !enabled {
+		if err := s.sessions.RevokeAllSessions(ctx, userID); err != nil {
+			pkg.LoggerFromContext(ctx).WithError(err).WithField("target_user_id", userID).Warn("Failed to revoke sessions of disabled user")
+		}
+	}
+	s.refreshQuietly(ctx, userID)
+	return nil
+}
+
+// DeleteUser продолжает очистку, если учётной записи у провайдера уже нет, но в LMS остался
+// её профиль, — например, после прерванного удаления. Файлы из хранилища удаляются после
+// фиксации транзакции; ошибка удаления файла только логируется.
+func (s *userAdminService) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	if err := s.identity.DeleteUser(ctx, userID); err != nil {
+		if !errors.Is(err, pkg.ErrUserNotFound) {
+			return err
+		}
+		if _, findErr := s.profiles.FindByID(ctx, userID); findErr != nil {
+			return notFound(findErr, pkg.ErrUserNotFound)
+		}
+	}
+
+	objects, err := s.profiles.DeleteUserData(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		if err := s.fileStorage.DeleteFile(ctx, object); err != nil {
+			pkg.LoggerFromContext(ctx).WithError(err).WithField("object", object).Warn("Failed to delete file of deleted user")
+		}
+	}
+	return nil
+}
+
+func (s *userAdminService) RefreshUser(ctx context.Context, userID uuid.UUID) error {
+	account, err := s.identity.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.profiles.Upsert(ctx, []*entities.UserProfile{userProfile(account, s.now())})
+}
+
+// refreshQuietly обновляет профиль в кэше; при ошибке кэш догонит провайдера при следующей сверке.
+func (s *userAdminService) refreshQuietly(ctx context.Context, userID uuid.UUID) {
+	if err := s.RefreshUser(ctx, userID); err != nil {
+		pkg.LoggerFromContext(ctx).WithError(err).WithField("target_user_id", userID).Warn("Failed to refresh cached user profile")
+	}
+}
+
+// syncIfStale постранично перечитывает всех пользователей провайдера и удаляет из кэша тех,
+// кого там больше нет. Параллельные запросы ждут одну сверку.
+func (s *userAdminService) syncIfStale(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := s.now()
+	if !s.syncedAt.IsZero() && start.Sub(s.syncedAt) < s.cacheTTL {
+		return nil
+	}
+
+	for first := 0; ; first += userSyncPageSize {
+		accounts, more, err := s.identity.ListUsers(ctx, first, userSyncPageSize)
+		if err != nil {
+			return err
+		}
+		profiles := make([]*entities.UserProfile, 0, len(accounts))
+		for i := range accounts {
+			profiles = append(profiles, userProfile(&accounts[i], start))
+		}
+		if err := s.profiles.Upsert(ctx, profiles); err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+	}
+	if err := s.profiles.DeleteSyncedBefore(ctx, start); err != nil {
+		return err
+	}
+	s.syncedAt = start
+	return nil
+}
+
+func userProfile(account *UserAccount, syncedAt time.Time) *entities.UserProfile {
+	return &entities.UserProfile{
+		ID:        account.ID,
+		Username:  account.Username,
+		Email:     account.Email,
+		FirstName: account.FirstName,
+		LastName:  account.LastName,
+		Enabled:   account.Enabled,
+		CreatedAt: account.CreatedAt,
+		SyncedAt:  syncedAt,
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"lms-system-internship/pkg"
+	"strings"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/google/uuid"
+)
+
+// UserDirectory отдаёт сведения о пользователях из провайдера идентификации.
+type UserDirectory interface {
+	GetFullName(ctx context.Context, userID uuid.UUID) (string, error)
+}
+
+type keycloakUserDirectory struct {
+	admin *KeycloakAdmin
+}
+
+func NewKeycloakUserDirectory(admin *KeycloakAdmin) UserDirectory {
+	return &keycloakUserDirectory{admin: admin}
+}
+
+// GetFullName возвращает "Имя Фамилия" пользователя, а если они не заполнены — его логин.
+func (d *keycloakUserDirectory) GetFullName(ctx context.Context, userID uuid.UUID) (string, error) {
+	var user *gocloak.User
+	err := d.admin.Do(ctx, "get_user", true, pkg.ErrUserNotFound, func(client *gocloak.GoCloak, token string) error {
+		var err error
+		user, err = client.GetUserByID(ctx, token, d.admin.Realm(), userID.String())
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	name := strings.TrimSpace(gocloak.PString(user.FirstName) + " " + gocloak.PString(user.LastName))
+	if name == "" {
+		name = gocloak.PString(user.Username)
+	}
+	return name, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"lms-system-internship/pkg"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+// Колонки CSV для массового импорта. Обязательны username и email; роли и ID курсов
+// перечисляются внутри ячейки через "|".
+const (
+	importColumnUsername  = "username"
+	importColumnEmail     = "email"
+	importColumnName      = "name"
+	importColumnRoles     = "roles"
+	importColumnCourseIDs = "course_ids"
+)
+
+// UserImportRow — строка файла импорта. Если Err != nil, строка не прошла проверку и
+// попадёт в отчёт как ошибочная, не затрагивая остальные строки.
+type UserImportRow struct {
+	Line      int
+	Username  string
+	Email     string
+	FirstName string
+	LastName  string
+	Roles     []string
+	CourseIDs []uint
+	Err       error
+}
+
+// ParseUserImportCSV читает файл импорта. Первая строка — заголовок с названиями колонок в любом
+// порядке; лишние колонки игнорируются. Разделитель — запятая или точка с запятой (так сохраняет
+// CSV Excel с русской локалью), он определяется по заголовку. Ошибка возвращается, только если файл
+// нельзя разобрать целиком; ошибки отдельных строк записываются в UserImportRow.Err.
+func ParseUserImportCSV(r io.Reader, maxRows int) ([]UserImportRow, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(br.Size())
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, pkg.ErrInvalidInput.Wrap(err)
+	}
+	firstLine, _, _ := bytes.Cut(header, []byte("\n"))
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	names, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "file", Code: "required"})
+	}
+	if err != nil {
+		return nil, pkg.ErrInvalidInput.Wrap(err).WithFields(pkg.FieldError{Field: "file", Code: "csv"})
+	}
+	columns := map[string]int{}
+	for i, name := range names {
+		// Excel добавляет BOM в начало файла
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	for _, required := range []string{importColumnUsername, importColumnEmail} {
+		if _, ok := columns[required]; !ok {
+			return nil, pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "file", Code: "column", Param: required})
+		}
+	}
+
+	rows := []UserImportRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, pkg.ErrInvalidInput.Wrap(err).WithFields(pkg.FieldError{Field: "file", Code: "csv"})
+		}
+		line, _ := reader.FieldPos(0)
+		cell := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue // пустые строки в конце файла
+		}
+		if len(rows) == maxRows {
+			return nil, pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "file", Code: "max_rows", Param: strconv.Itoa(maxRows)})
+		}
+		rows = append(rows, parseUserImportRow(line, cell))
+	}
+	if len(rows) == 0 {
+		return nil, pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "file", Code: "required"})
+	}
+	return rows, nil
+}
+
+func parseUserImportRow(line int, cell func(column string) string) UserImportRow {
+	row := UserImportRow{
+		Line:     line,
+		Username: cell(importColumnUsername),
+		Email:    cell(importColumnEmail),
+		Roles:    splitImportList(cell(importColumnRoles)),
+	}
+	// Имя — всё до первого пробела, фамилия — остальное
+	first, last, _ := strings.Cut(cell(importColumnName), " ")
+	row.FirstName, row.LastName = first, strings.TrimSpace(last)
+
+	if row.Username == "" {
+		row.Err = pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: importColumnUsername, Code: "required"})
+		return row
+	}
+	if address, err := mail.ParseAddress(row.Email); err != nil || address.Address != row.Email {
+		row.Err = pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: importColumnEmail, Code: "email"})
+		return row
+	}
+	for _, raw := range splitImportList(cell(importColumnCourseIDs)) {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || id == 0 {
+			row.Err = pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: importColumnCourseIDs, Code: "number"})
+			return row
+		}
+		row.CourseIDs = append(row.CourseIDs, uint(id))
+	}
+	return row
+}
+
+// splitImportList делит значение ячейки по "|", пропуская пустые элементы.
+func splitImportList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, "|") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
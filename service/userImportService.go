@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"lms-system-internship/entities"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Состояния задачи импорта.
+const (
+	UserImportRunning   = "running"
+	UserImportCompleted = "completed"
+	// UserImportCancelled — сервер остановился до конца файла; необработанные строки в отчёт не попали.
+	UserImportCancelled = "cancelled"
+)
+
+// Итог строки импорта.
+const (
+	UserImportRowCreated = "created"
+	UserImportRowFailed  = "failed"
+)
+
+// UserImportService создаёт пользователей из файла импорта: учётная запись с ролями у провайдера
+// идентификации, доступ ко всем урокам перечисленных курсов и приглашение со ссылкой для установки
+// пароля. Пароли не попадают ни в отчёт, ни к администратору.
+type UserImportService interface {
+	// StartImport сохраняет задачу, обрабатывает строки по очереди в фоне и сразу возвращает задачу
+	// для опроса через GetJob.
+	StartImport(ctx context.Context, createdBy uuid.UUID, rows []UserImportRow) (*entities.UserImportJob, error)
+	GetJob(ctx context.Context, jobID uuid.UUID) (*entities.UserImportJob, error)
+}
+
+type userImportService struct {
+	identity    IdentityService
+	users       UserAdminService
+	invitations RegistrationService
+	courses     repo.CourseRepository
+	lessonUsers repo.LessonUserRepository
+	jobs        repo.UserImportRepository
+	// background отменяется при остановке сервера: задача дорабатывает текущую строку и
+	// завершается. tasks учитывает работающие задачи, чтобы сервер мог их дождаться.
+	background context.Context
+	tasks      *sync.WaitGroup
+	retention  time.Duration
+	now        func() time.Time
+}
+
+// NewUserImportService создаёт сервис импорта. Задачи хранятся в базе retention после завершения.
+// Кэш профилей созданных пользователей обновляется через users, приглашения отправляются через invitations.
+func NewUserImportService(background context.Context, tasks *sync.WaitGroup, identity IdentityService, users UserAdminService, invitations RegistrationService, courses repo.CourseRepository, lessonUsers repo.LessonUserRepository, jobs repo.UserImportRepository, retention time.Duration) UserImportService {
+	return &userImportService{
+		identity:    identity,
+		users:       users,
+		invitations: invitations,
+		courses:     courses,
+		lessonUsers: lessonUsers,
+		jobs:        jobs,
+		background:  background,
+		tasks:       tasks,
+		retention:   retention,
+		now:         time.Now,
+	}
+}
+
+// StartImport выполняет задачу с логгером и трассировкой запроса, но без его отмены: задача
+// продолжается после ответа клиенту и останавливается только вместе с сервером.
+func (s *userImportService) StartImport(ctx context.Context, createdBy uuid.UUID, rows []UserImportRow) (*entities.UserImportJob, error) {
+	if err := s.jobs.DeleteFinishedBefore(ctx, s.now().Add(-s.retention)); err != nil {
+		pkg.LoggerFromContext(ctx).WithError(err).Warn("Failed to delete expired import jobs")
+	}
+	job := &entities.UserImportJob{
+		ID:        uuid.New(),
+		Status:    UserImportRunning,
+		CreatedBy: createdBy,
+		CreatedAt: s.now(),
+		Total:     len(rows),
+		Results:   []entities.UserImportResult{},
+	}
+	if err := s.jobs.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	jobCtx := context.WithoutCancel(ctx)
+	s.tasks.Add(1)
+	go func() {
+		defer s.tasks.Done()
+		logger := pkg.LoggerFromContext(jobCtx).WithField("job_id", job.ID)
+		logger.WithField("rows", len(rows)).Info("User import started")
+
+		lessonsByCourse := map[uint][]uint{}
+		processed, succeeded := 0, 0
+		for _, row := range rows {
+			// Отмена проверяется только между строками: прерванная посреди строка оставила бы
+			// учётную запись без доступа к курсам или без приглашения
+			if s.background.Err() != nil {
+				break
+			}
+			result := s.importRow(jobCtx, row, lessonsByCourse)
+			result.JobID = job.ID
+			created := result.Status == UserImportRowCreated
+			if err := s.jobs.AddResult(jobCtx, &result, created); err != nil {
+				logger.WithError(err).WithField("line", row.Line).Error("Failed to save import result")
+			}
+			processed++
+			if created {
+				succeeded++
+			}
+		}
+
+		status := UserImportCompleted
+		if processed < len(rows) {
+			status = UserImportCancelled
+		}
+		if err := s.jobs.FinishJob(jobCtx, job.ID, status, s.now()); err != nil {
+			logger.WithError(err).Error("Failed to finish import job")
+		}
+		logger.WithField("status", status).WithField("succeeded", succeeded).WithField("failed", processed-succeeded).Info("User import finished")
+	}()
+	return job, nil
+}
+
+func (s *userImportService) GetJob(ctx context.Context, jobID uuid.UUID) (*entities.UserImportJob, error) {
+	job, err := s.jobs.FindJob(ctx, jobID)
+	if err != nil {
+		return nil, notFound(err, pkg.ErrImportJobNotFound)
+	}
+	return job, nil
+}
+
+// importRow обрабатывает строку. Уроки курсов запоминаются на время задачи: в одном файле курсы
+// обычно повторяются.
+func (s *userImportService) importRow(ctx context.Context, row UserImportRow, lessonsByCourse map[uint][]uint) entities.UserImportResult {
+	result := entities.UserImportResult{Line: row.Line, Username: row.Username, Status: UserImportRowFailed}
+	if row.Err != nil {
+		result.ErrorCode, result.Error = importError(row.Err)
+		return result
+	}
+
+	// Курсы проверяются до создания учётной записи, чтобы опечатка в ID не оставила пользователя без доступа
+	var lessonIDs []uint
+	for _, courseID := range row.CourseIDs {
+		lessons, err := s.courseLessons(ctx, courseID, lessonsByCourse)
+		if err != nil {
+			result.ErrorCode, result.Error = importError(err)
+			return result
+		}
+		lessonIDs = append(lessonIDs, lessons...)
+	}
+
+	// Пароль никому не сообщается: пользователь задаст свой по ссылке из приглашения
+	password, err := randomPassword()
+	if err != nil {
+		result.ErrorCode, result.Error = importError(err)
+		return result
+	}
+	userID, err := s.identity.RegisterUser(ctx, NewUser{
+		Username:  row.Username,
+		Email:     row.Email,
+		FirstName: row.FirstName,
+		LastName:  row.LastName,
+		Password:  password,
+		Roles:     row.Roles,
+	})
+	if err != nil {
+		result.ErrorCode, result.Error = importError(err)
+		return result
+	}
+	result.UserID = &userID
+	logger := pkg.LoggerFromContext(ctx).WithField("target_user_id", userID)
+	if err := s.users.RefreshUser(ctx, userID); err != nil {
+		logger.WithError(err).Warn("Failed to refresh cached user profile")
+	}
+
+	for _, lessonID := range lessonIDs {
+		if err := s.lessonUsers.GrantAccess(userID, lessonID); err != nil {
+			logger.WithError(err).WithField("lesson_id", lessonID).Error("Failed to grant lesson access during import")
+			result.ErrorCode, result.Error = importError(err)
+			return result
+		}
+	}
+
+	// Если приглашение не ушло, пользователь может сам запросить сброс пароля на странице входа
+	if err := s.invitations.InviteUser(ctx, UserAccount{ID: userID, Username: row.Username, Email: row.Email}); err != nil {
+		logger.WithError(err).Error("Failed to send invitation during import")
+		result.ErrorCode, result.Error = importError(err)
+		return result
+	}
+	result.Status = UserImportRowCreated
+	return result
+}
+
+func (s *userImportService) courseLessons(ctx context.Context, courseID uint, cache map[uint][]uint) ([]uint, error) {
+	if lessons, ok := cache[courseID]; ok {
+		return lessons, nil
+	}
+	course, err := s.courses.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, notFound(err, pkg.ErrCourseNotFound.WithDetails(map[string]any{"course_id": courseID}))
+	}
+	lessons := []uint{}
+	for _, chapter := range course.Chapters {
+		for _, lesson := range chapter.Lessons {
+			lessons = append(lessons, lesson.ID)
+		}
+	}
+	cache[courseID] = lessons
+	return lessons, nil
+}
+
+// importError переводит ошибку строки в код и текст для отчёта. Подробности ошибок вне
+// таксономии в отчёт не попадают, как и в ответы API.
+func importError(err error) (string, string) {
+	var appErr *pkg.AppError
+	if !errors.As(err, &appErr) {
+		appErr = pkg.ErrInternal
+	}
+	message := appErr.Message
+	for _, field := range appErr.Fields {
+		message += fmt.Sprintf(": %s (%s)", field.Field, field.Code)
+	}
+	keys := make([]string, 0, len(appErr.Details))
+	for key := range appErr.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		message += fmt.Sprintf(", %s=%v", key, appErr.Details[key])
+	}
+	return appErr.Code, message
+}
+
+// randomPassword создаёт случайный пароль, который заменит ссылка из приглашения.
+func randomPassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
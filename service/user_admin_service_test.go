@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// stubIdentityService отдаёт учётные записи из accounts постранично и считает обращения.
+type stubIdentityService struct {
+	IdentityService
+	accounts  []UserAccount
+	listErr   error
+	listCalls int
+	deleteErr error
+	deleted   []uuid.UUID
+}
+
+func (s *stubIdentityService) ListUsers(_ context.Context, first, max int) ([]UserAccount, bool, error) {
+	s.listCalls++
+	if s.listErr != nil {
+		return nil, false, s.listErr
+	}
+	end := min(first+max, len(s.accounts))
+	return s.accounts[first:end], end < len(s.accounts), nil
+}
+
+func (s *stubIdentityService) DeleteUser(_ context.Context, userID uuid.UUID) error {
+	s.deleted = append(s.deleted, userID)
+	return s.deleteErr
+}
+
+// stubSessions запоминает пользователей, чьи сессии были завершены.
+type stubSessions struct {
+	IdentityProvider
+	revoked []uuid.UUID
+}
+
+func (s *stubSessions) RevokeAllSessions(_ context.Context, userID uuid.UUID) error {
+	s.revoked = append(s.revoked, userID)
+	return nil
+}
+
+// stubProfiles запоминает пользователей, чьи профили перечитывались.
+type stubProfiles struct {
+	UserAdminService
+	refreshed []uuid.UUID
+}
+
+func (s *stubProfiles) RefreshUser(_ context.Context, userID uuid.UUID) error {
+	s.refreshed = append(s.refreshed, userID)
+	return nil
+}
+
+func TestUserAdminService_ListUsers(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	accounts := make([]UserAccount, userSyncPageSize+1)
+	for i := range accounts {
+		accounts[i] = UserAccount{ID: uuid.New(), Username: "user", Enabled: true}
+	}
+
+	t.Run("syncs all pages once per TTL", func(t *testing.T) {
+		identity := &stubIdentityService{accounts: accounts}
+		profiles := new(mocks.UserProfileRepository)
+		storage := new(mocks.FileStorage)
+		service := NewUserAdminService(identity, &stubSessions{}, profiles, new(mocks.LessonUserRepository), storage, 5*time.Minute).(*userAdminService)
+		service.now = func() time.Time { return now }
+		profiles.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+		profiles.On("DeleteSyncedBefore", mock.Anything, now).Return(nil).Once()
+		profiles.On("Search", mock.Anything, repo.UserFilter{Query: "ann", Limit: defaultUserListLimit}).Return([]*entities.UserProfile{}, int64(0), nil)
+
+		_, _, err := service.ListUsers(context.Background(), repo.UserFilter{Query: "ann"})
+		assert.NoError(t, err)
+		_, _, err = service.ListUsers(context.Background(), repo.UserFilter{Query: "ann"})
+		assert.NoError(t, err)
+
+		assert.Equal(t, 2, identity.listCalls, "two pages on the first call, none on the second")
+		profiles.AssertExpectations(t)
+	})
+
+	t.Run("serves the cache when the provider is down", func(t *testing.T) {
+		identity := &stubIdentityService{listErr: pkg.ErrIdentityUnavailable}
+		profiles := new(mocks.UserProfileRepository)
+		storage := new(mocks.FileStorage)
+		service := NewUserAdminService(identity, &stubSessions{}, profiles, new(mocks.LessonUserRepository), storage, 5*time.Minute).(*userAdminService)
+		service.now = func() time.Time { return now }
+		cached := []*entities.UserProfile{{ID: uuid.New(), Username: "alice"}}
+		profiles.On("Search", mock.Anything, repo.UserFilter{Limit: maxUserListLimit}).Return(cached, int64(1), nil)
+
+		list, total, err := service.ListUsers(context.Background(), repo.UserFilter{Limit: 1000})
+
+		assert.NoError(t, err)
+		assert.Equal(t, cached, list)
+		assert.Equal(t, int64(1), total)
+		profiles.AssertNotCalled(t, "DeleteSyncedBefore", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserAdminService_DeleteUser(t *testing.T) {
+	now := time.Now()
+	userID := uuid.New()
+
+	t.Run("deletes the account, LMS data and files", func(t *testing.T) {
+		identity := &stubIdentityService{}
+		profiles := new(mocks.UserProfileRepository)
+		storage := new(mocks.FileStorage)
+		service := NewUserAdminService(identity, &stubSessions{}, profiles, new(mocks.LessonUserRepository), storage, 5*time.Minute).(*userAdminService)
+		service.now = func() time.Time { return now }
+		profiles.On("DeleteUserData", mock.Anything, userID).Return([]string{"sub.pdf", "certificates/abc.pdf"}, nil)
+		storage.On("DeleteFile", mock.Anything, "sub.pdf").Return(nil)
+		storage.On("DeleteFile", mock.Anything, "certificates/abc.pdf").Return(errors.New("minio down"))
+
+		err := service.DeleteUser(context.Background(), userID)
+
+		assert.NoError(t, err, "a file left behind does not fail the deletion")
+		assert.Equal(t, []uuid.UUID{userID}, identity.deleted)
+		storage.AssertExpectations(t)
+	})
+
+	t.Run("finishes cleanup when the account is already gone", func(t *testing.T) {
+		identity := &stubIdentityService{deleteErr: pkg.ErrUserNotFound}
+		profiles := new(mocks.UserProfileRepository)
+		storage := new(mocks.FileStorage)
+		service := NewUserAdminService(identity, &stubSessions{}, profiles, new(mocks.LessonUserRepository), storage, 5*time.Minute).(*userAdminService)
+		service.now = func() time.Time { return now }
+		profiles.On("FindByID", mock.Anything, userID).Return(&entities.UserProfile{ID: userID}, nil)
+		profiles.On("DeleteUserData", mock.Anything, userID).Return(nil, nil)
+
+		assert.NoError(t, service.DeleteUser(context.Background(), userID))
+		profiles.AssertExpectations(t)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		identity := &stubIdentityService{deleteErr: pkg.ErrUserNotFound}
+		profiles := new(mocks.UserProfileRepository)
+		storage := new(mocks.FileStorage)
+		service := NewUserAdminService(identity, &stubSessions{}, profiles, new(mocks.LessonUserRepository), storage, 5*time.Minute).(*userAdminService)
+		service.now = func() time.Time { return now }
+		profiles.On("FindByID", mock.Anything, userID).Return(nil, repo.ErrNotFound)
+
+		err := service.DeleteUser(context.Background(), userID)
+
+		assert.ErrorIs(t, err, pkg.ErrUserNotFound)
+		profiles.AssertNotCalled(t, "DeleteUserData", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"lms-system-internship/entities"
+	"lms-system-internship/mocks"
+	"lms-system-internship/pkg"
+	"lms-system-internship/repo"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseUserImportCSV(t *testing.T) {
+	t.Run("columns in any order, lists and names", func(t *testing.T) {
+		file := "\ufeffEmail,username,course_ids,roles,name\n" +
+			"ann@example.com,ann,1|2,ROLE_STUDENT|ROLE_TEACHER,Ann Lee Smith\n" +
+			"bob@example.com,bob,,,\n"
+
+		rows, err := ParseUserImportCSV(strings.NewReader(file), 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []UserImportRow{
+			{Line: 2, Username: "ann", Email: "ann@example.com", FirstName: "Ann", LastName: "Lee Smith",
+				Roles: []string{"ROLE_STUDENT", "ROLE_TEACHER"}, CourseIDs: []uint{1, 2}},
+			{Line: 3, Username: "bob", Email: "bob@example.com", Roles: []string{}},
+		}, rows)
+	})
+
+	t.Run("semicolon delimiter", func(t *testing.T) {
+		rows, err := ParseUserImportCSV(strings.NewReader("username;email\nann;ann@example.com\n"), 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ann@example.com", rows[0].Email)
+	})
+
+	t.Run("invalid rows are reported, not rejected", func(t *testing.T) {
+		rows, err := ParseUserImportCSV(strings.NewReader("username,email,course_ids\nann,not-an-email,\nbob,bob@example.com,x\n"), 10)
+
+		assert.NoError(t, err)
+		assert.ErrorIs(t, rows[0].Err, pkg.ErrInvalidInput)
+		assert.ErrorIs(t, rows[1].Err, pkg.ErrInvalidInput)
+	})
+
+	t.Run("missing required column", func(t *testing.T) {
+		_, err := ParseUserImportCSV(strings.NewReader("username,name\nann,Ann\n"), 10)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+
+	t.Run("too many rows", func(t *testing.T) {
+		_, err := ParseUserImportCSV(strings.NewReader("username,email\na,a@example.com\nb,b@example.com\n"), 1)
+
+		assert.ErrorIs(t, err, pkg.ErrInvalidInput)
+	})
+}
+
+// registeringIdentityService создаёт учётные записи, кроме логинов из taken.
+type registeringIdentityService struct {
+	IdentityService
+	taken      map[string]bool
+	registered []NewUser
+}
+
+func (s *registeringIdentityService) RegisterUser(_ context.Context, user NewUser) (uuid.UUID, error) {
+	if s.taken[user.Username] {
+		return uuid.Nil, pkg.ErrUserAlreadyExists
+	}
+	s.registered = append(s.registered, user)
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(user.Username)), nil
+}
+
+// memoryImportJobs хранит задачи импорта в памяти вместо базы.
+type memoryImportJobs struct {
+	mu   sync.Mutex
+	jobs map[uuid.UUID]*entities.UserImportJob
+}
+
+func (r *memoryImportJobs) CreateJob(_ context.Context, job *entities.UserImportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *job
+	r.jobs[job.ID] = &stored
+	return nil
+}
+
+func (r *memoryImportJobs) AddResult(_ context.Context, result *entities.UserImportResult, succeeded bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[result.JobID]
+	job.Results = append(job.Results, *result)
+	if succeeded {
+		job.Succeeded++
+	} else {
+		job.Failed++
+	}
+	return nil
+}
+
+func (r *memoryImportJobs) FinishJob(_ context.Context, jobID uuid.UUID, status string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[jobID].Status = status
+	r.jobs[jobID].FinishedAt = &at
+	return nil
+}
+
+func (r *memoryImportJobs) FindJob(_ context.Context, jobID uuid.UUID) (*entities.UserImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[jobID]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	copied := *job
+	copied.Results = append([]entities.UserImportResult{}, job.Results...)
+	return &copied, nil
+}
+
+func (r *memoryImportJobs) DeleteFinishedBefore(context.Context, time.Time) error { return nil }
+
+// stubInvitations запоминает, кому отправлены приглашения.
+type stubInvitations struct {
+	RegistrationService
+	invited []UserAccount
+}
+
+func (s *stubInvitations) InviteUser(_ context.Context, account UserAccount) error {
+	s.invited = append(s.invited, account)
+	return nil
+}
+
+func TestUserImportService_StartImport(t *testing.T) {
+	identity := &registeringIdentityService{taken: map[string]bool{"taken": true}}
+	invitations := &stubInvitations{}
+	courses := new(mocks.CourseRepository)
+	lessonUsers := new(mocks.LessonUserRepository)
+	var tasks sync.WaitGroup
+	svc := NewUserImportService(context.Background(), &tasks, identity, &stubProfiles{}, invitations, courses, lessonUsers,
+		&memoryImportJobs{jobs: map[uuid.UUID]*entities.UserImportJob{}}, time.Hour)
+
+	courses.On("FindByID", mock.Anything, uint(1)).Return(&entities.Course{ID: 1, Chapters: []entities.Chapter{
+		{Lessons: []entities.Lesson{{ID: 10}, {ID: 11}}},
+	}}, nil).Once()
+	courses.On("FindByID", mock.Anything, uint(9)).Return(nil, repo.ErrNotFound)
+	annID := uuid.NewSHA1(uuid.NameSpaceOID, []byte("ann"))
+	bobID := uuid.NewSHA1(uuid.NameSpaceOID, []byte("bob"))
+	for _, userID := range []uuid.UUID{annID, bobID} {
+		lessonUsers.On("GrantAccess", userID, uint(10)).Return(nil).Once()
+		lessonUsers.On("GrantAccess", userID, uint(11)).Return(nil).Once()
+	}
+
+	rows := []UserImportRow{
+		{Line: 2, Username: "ann", Email: "ann@example.com", Roles: []string{"ROLE_STUDENT"}, CourseIDs: []uint{1}},
+		{Line: 3, Username: "bob", Email: "bob@example.com", CourseIDs: []uint{1}},
+		{Line: 4, Username: "taken", Email: "taken@example.com"},
+		{Line: 5, Username: "eve", Email: "eve@example.com", CourseIDs: []uint{9}},
+		{Line: 6, Username: "bad", Err: pkg.ErrInvalidInput.WithFields(pkg.FieldError{Field: "email", Code: "email"})},
+	}
+	started, err := svc.StartImport(context.Background(), uuid.New(), rows)
+	assert.NoError(t, err)
+	assert.Equal(t, UserImportRunning, started.Status)
+
+	tasks.Wait()
+	job, err := svc.GetJob(context.Background(), started.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, UserImportCompleted, job.Status)
+	assert.Equal(t, 2, job.Succeeded)
+	assert.Equal(t, 3, job.Failed)
+	if assert.Len(t, job.Results, 5) {
+		assert.Equal(t, UserImportRowCreated, job.Results[0].Status)
+		assert.Equal(t, annID, *job.Results[0].UserID)
+		assert.Equal(t, "user_already_exists", job.Results[2].ErrorCode)
+		assert.Equal(t, "course_not_found", job.Results[3].ErrorCode)
+		assert.Contains(t, job.Results[3].Error, "course_id=9")
+		assert.Nil(t, job.Results[3].UserID, "no account is created for an unknown course")
+		assert.Equal(t, "invalid input: email (email)", job.Results[4].Error)
+	}
+	if assert.Len(t, identity.registered, 2) {
+		assert.Equal(t, []string{"ROLE_STUDENT"}, identity.registered[0].Roles)
+	}
+	assert.Equal(t, []UserAccount{
+		{ID: annID, Username: "ann", Email: "ann@example.com"},
+		{ID: bobID, Username: "bob", Email: "bob@example.com"},
+	}, invitations.invited)
+	courses.AssertExpectations(t)
+	lessonUsers.AssertExpectations(t)
+}
+
+func TestUserImportService_StartImport_Cancelled(t *testing.T) {
+	background, stop := context.WithCancel(context.Background())
+	stop()
+	var tasks sync.WaitGroup
+	identity := &registeringIdentityService{}
+	svc := NewUserImportService(background, &tasks, identity, &stubProfiles{}, &stubInvitations{}, nil, nil,
+		&memoryImportJobs{jobs: map[uuid.UUID]*entities.UserImportJob{}}, time.Hour)
+
+	started, err := svc.StartImport(context.Background(), uuid.New(), []UserImportRow{{Line: 2, Username: "ann", Email: "ann@example.com"}})
+	assert.NoError(t, err)
+	tasks.Wait()
+	job, err := svc.GetJob(context.Background(), started.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, UserImportCancelled, job.Status)
+	assert.Empty(t, job.Results)
+	assert.Empty(t, identity.registered)
+}
+
+func TestUserImportService_GetJob(t *testing.T) {
+	svc := NewUserImportService(context.Background(), &sync.WaitGroup{}, &registeringIdentityService{}, &stubProfiles{}, &stubInvitations{}, nil, nil,
+		&memoryImportJobs{jobs: map[uuid.UUID]*entities.UserImportJob{}}, time.Hour)
+
+	_, err := svc.GetJob(context.Background(), uuid.New())
+
+	assert.ErrorIs(t, err, pkg.ErrImportJobNotFound)
+}